@@ -1,23 +1,29 @@
 package bot
 
 import (
+	"cmp"
 	"context"
-	"database/sql"
 	"errors"
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/disgoorg/disgo"
 	disbot "github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/cache"
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/events"
 	"github.com/disgoorg/disgo/gateway"
 	"github.com/disgoorg/disgo/rest"
 	"github.com/disgoorg/disgo/sharding"
+	"github.com/disgoorg/snowflake/v2"
 	"github.com/gofiber/fiber/v3"
 	"github.com/lvlcn-t/loggerhead/logger"
 	"github.com/lvlcn-t/raid-mate/app/bot/colors"
 	"github.com/lvlcn-t/raid-mate/app/bot/commands"
+	"github.com/lvlcn-t/raid-mate/app/bot/i18n"
 	"github.com/lvlcn-t/raid-mate/app/services"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
 )
 
 var _ Bot = (*bot)(nil)
@@ -38,6 +44,8 @@ type Config struct {
 	Token string `yaml:"token" mapstructure:"token" validate:"required"`
 	// Intents is the list of intents the bot should use.
 	Intents IntentsConfig `yaml:"intents" mapstructure:"intents"`
+	// Commands is the configuration for the commands.
+	Commands commands.Config `yaml:"commands" mapstructure:"commands"`
 }
 
 // bot is the implementation of the Bot interface.
@@ -60,7 +68,7 @@ type bot struct {
 func New(cfg Config, svcs *services.Collection) Bot {
 	return &bot{
 		cfg:      cfg,
-		commands: commands.NewCollection(svcs),
+		commands: commands.NewCollection(&cfg.Commands, svcs),
 		services: svcs,
 		conn:     nil,
 		app:      nil,
@@ -140,6 +148,7 @@ func (b *bot) launchBot(ctx context.Context) error {
 		return err
 	}
 
+	go b.runLeaderboards(ctx)
 	return nil
 }
 
@@ -168,8 +177,12 @@ func (b *bot) newConnection(ctx context.Context) (err error) {
 
 // registerCommands registers the bot's commands with Discord.
 func (b *bot) registerCommands(ctx context.Context) error {
-	infos := b.commands.Infos()
-	_, err := b.conn.Rest().SetGlobalCommands(b.conn.ApplicationID(), infos, rest.WithCtx(ctx))
+	infos, err := b.commands.Infos()
+	if err != nil {
+		return fmt.Errorf("invalid commands: %w", err)
+	}
+
+	_, err = b.conn.Rest().SetGlobalCommands(b.conn.ApplicationID(), infos, rest.WithCtx(ctx))
 	if err != nil {
 		for _, info := range infos {
 			_, cErr := b.conn.Rest().CreateGlobalCommand(b.conn.ApplicationID(), info, rest.WithCtx(ctx))
@@ -187,46 +200,51 @@ func (b *bot) newEventListeners(ctx context.Context) *events.ListenerAdapter {
 	return &events.ListenerAdapter{
 		OnGuildReady: func(event *events.GuildReady) {
 			log.InfoContext(ctx, "Guild ready", "guild", event.Guild.ID.String())
+			b.handleGuildRejoin(ctx, event.GuildID)
 		},
 		OnGuildsReady: func(event *events.GuildsReady) {
 			log.InfoContext(ctx, "Guilds on shard ready", "shard", event.ShardID())
+			b.handleGuildsReady(ctx, event)
 		},
 		OnApplicationCommandInteraction: func(event *events.ApplicationCommandInteractionCreate) {
 			log.DebugContext(ctx, "Command interaction", "command", event.Data.CommandName())
-			cmd := b.commands.GetAppCommand(event.Data.CommandName())
-			if cmd != nil {
-				cmd.Handle(ctx, event)
-			}
+			b.commands.HandleApplicationCommand(ctx, event)
+		},
+		OnAutocompleteInteraction: func(event *events.AutocompleteInteractionCreate) {
+			log.DebugContext(ctx, "Autocomplete interaction", "command", event.Data.CommandName)
+			b.commands.HandleAutocomplete(ctx, event)
 		},
 		OnGuildJoin: func(event *events.GuildJoin) {
 			log.DebugContext(ctx, "Guild join", "guild", event.Guild.ID.String())
 			b.handleGuildJoin(ctx, event)
 		},
+		OnGuildLeave: func(event *events.GuildLeave) {
+			log.DebugContext(ctx, "Guild leave", "guild", event.GuildID.String())
+			b.handleGuildLeave(ctx, event.GuildID)
+		},
 		OnComponentInteraction: func(event *events.ComponentInteractionCreate) {
 			log.DebugContext(ctx, "Component interaction", "custom_id", event.Data.CustomID())
-			cmd := b.commands.GetComponentCommand(event.Data.CustomID())
-			if cmd != nil {
-				cmd.Handle(ctx, event)
-			}
+			b.commands.HandleComponent(ctx, event)
 		},
 		OnModalSubmit: func(event *events.ModalSubmitInteractionCreate) {
 			log.DebugContext(ctx, "Modal submit", "custom_id", event.Data.CustomID)
-			cmd := b.commands.GetComponentCommand(event.Data.CustomID)
-			if cmd != nil {
-				cmd.HandleSubmission(ctx, event)
-			}
+			b.commands.HandleModalSubmit(ctx, event)
 		},
 	}
 }
 
 // handleGuildJoin sends a welcome message to a guild when the bot joins it.
+// The message is posted in the first channel found by [onboardingChannel].
+// If the bot cannot write in any channel, the owner of the guild is asked to set it up via direct message.
 func (b *bot) handleGuildJoin(ctx context.Context, event *events.GuildJoin) {
-	log := logger.FromContext(ctx)
+	log := logger.FromContext(ctx).With("guild", event.GuildID.String())
 	_, err := b.services.Guild.Get(ctx, event.GuildID)
-	if !errors.Is(err, sql.ErrNoRows) {
+	if !errors.Is(err, svcerr.ErrNotConfigured) {
 		if err != nil {
 			log.ErrorContext(ctx, "Failed to get guild", "error", err)
+			return
 		}
+		b.handleGuildRejoin(ctx, event.GuildID)
 		return
 	}
 
@@ -236,22 +254,128 @@ func (b *bot) handleGuildJoin(ctx context.Context, event *events.GuildJoin) {
 		guildIcon = &appIcon
 	}
 
-	log.InfoContext(ctx, "Joined new guild", "guild", event.Guild.ID.String(), "app_icon", appIcon, "guild_icon", *guildIcon)
+	log.InfoContext(ctx, "Joined new guild", "app_icon", appIcon, "guild_icon", *guildIcon)
+	// The welcome message is sent before anyone interacts, so it is written in the preferred language of the server.
+	locale := discord.Locale(event.Guild.PreferredLocale)
+	description := i18n.T(locale, "welcome.description")
+	customID := "guild"
+	channelID, ok := onboardingChannel(event.Client().Caches(), event.Guild)
+	if !ok {
+		log.InfoContext(ctx, "No writable channel found, asking the owner via direct message", "owner", event.Guild.OwnerID.String())
+		dm, dErr := event.Client().Rest().CreateDMChannel(event.Guild.OwnerID, rest.WithCtx(ctx))
+		if dErr != nil {
+			log.ErrorContext(ctx, "Failed to open direct message to the owner", "error", dErr)
+			return
+		}
+		customID, err = b.commands.SetupCustomID(ctx, event.GuildID)
+		if err != nil {
+			log.ErrorContext(ctx, "Failed to encode custom ID", "error", err)
+			return
+		}
+		channelID = dm.ID()
+		description = i18n.T(locale, "welcome.description_dm", event.Guild.Name)
+	}
+
 	embed := discord.NewEmbedBuilder().
-		SetTitle("Welcome to Raid Mate!").
-		SetDescription("Hello! I'm Raid Mate, your friendly raid bot. Let's get your guild set up. Click the button below to get started.").
+		SetTitle(i18n.T(locale, "welcome.title")).
+		SetDescription(description).
 		SetColor(colors.Blue.Int()).
 		SetThumbnail(*guildIcon).
-		AddField("Getting Started", "Click the button below to configure your guild.", false).
+		AddField(i18n.T(locale, "welcome.getting_started"), i18n.T(locale, "welcome.getting_started_value"), false).
 		SetFooter(b.app.Name, b.app.Bot.EffectiveAvatarURL()).
 		SetTimestamp(time.Now()).
 		Build()
 
-	_, err = event.Client().Rest().CreateMessage(*event.Guild.SystemChannelID, discord.NewMessageCreateBuilder().
+	_, err = event.Client().Rest().CreateMessage(channelID, discord.NewMessageCreateBuilder().
 		AddEmbeds(embed).
-		AddActionRow(discord.NewPrimaryButton("Set me up!", "guild")).
+		AddActionRow(discord.NewPrimaryButton(i18n.T(locale, "welcome.button"), customID)).
 		Build(), rest.WithCtx(ctx))
 	if err != nil {
 		log.ErrorContext(ctx, "Failed to send message", "error", err)
 	}
 }
+
+// handleGuildLeave marks a guild as left when the bot leaves it, so its data is purged after the retention period.
+func (b *bot) handleGuildLeave(ctx context.Context, guildID snowflake.ID) {
+	log := logger.FromContext(ctx).With("guild", guildID.String())
+	err := b.services.Guild.Leave(ctx, guildID)
+	if errors.Is(err, svcerr.ErrNotConfigured) {
+		return
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "Failed to mark guild as left", "error", err)
+		return
+	}
+	log.InfoContext(ctx, "Left guild, its data will be purged after the retention period")
+}
+
+// handleGuildsReady marks the stored guilds of a shard as left if the bot is no longer in them,
+// e.g. because it was removed while it was offline and therefore missed the leave event.
+// Guilds that are only unavailable due to a Discord outage are kept.
+func (b *bot) handleGuildsReady(ctx context.Context, event *events.GuildsReady) {
+	log := logger.FromContext(ctx).With("shard", event.ShardID())
+	shard := event.Client().ShardManager().Shard(event.ShardID())
+	if shard == nil {
+		return
+	}
+
+	guilds, err := b.services.Guild.List(ctx)
+	if err != nil {
+		log.ErrorContext(ctx, "Failed to list guilds", "error", err)
+		return
+	}
+
+	caches := event.Client().Caches()
+	for _, g := range guilds {
+		id := snowflake.ID(g.ID) //nolint:gosec // Snowflake cannot overflow AFAIK
+		if g.LeftAt.Valid || sharding.ShardIDByGuild(id, shard.ShardCount()) != event.ShardID() {
+			continue
+		}
+		if _, ok := caches.Guild(id); ok || caches.IsGuildUnavailable(id) {
+			continue
+		}
+		b.handleGuildLeave(ctx, id)
+	}
+}
+
+// handleGuildRejoin keeps the data of a guild the bot left before but has joined again.
+func (b *bot) handleGuildRejoin(ctx context.Context, guildID snowflake.ID) {
+	err := b.services.Guild.Rejoin(ctx, guildID)
+	if err != nil && !errors.Is(err, svcerr.ErrNotConfigured) {
+		logger.FromContext(ctx).ErrorContext(ctx, "Failed to mark guild as joined", "guild", guildID.String(), "error", err)
+	}
+}
+
+// onboardingChannel returns the channel of the guild the welcome message is posted in.
+// It prefers the system channel and falls back to the first text channel the bot can write in.
+func onboardingChannel(caches cache.Caches, guild discord.GatewayGuild) (snowflake.ID, bool) {
+	self, ok := caches.SelfMember(guild.ID)
+	if !ok {
+		return 0, false
+	}
+	writable := func(channel discord.GuildChannel) bool {
+		return caches.MemberPermissionsInChannel(channel, self).Has(discord.PermissionViewChannel, discord.PermissionSendMessages, discord.PermissionEmbedLinks)
+	}
+
+	if guild.SystemChannelID != nil {
+		if channel, ok := caches.GuildTextChannel(*guild.SystemChannelID); ok && writable(channel) {
+			return channel.ID(), true
+		}
+	}
+
+	var channels []discord.GuildChannel
+	for _, channel := range guild.Channels {
+		if channel.Type() == discord.ChannelTypeGuildText {
+			channels = append(channels, channel)
+		}
+	}
+	slices.SortFunc(channels, func(a, b discord.GuildChannel) int {
+		return cmp.Compare(a.Position(), b.Position())
+	})
+	for _, channel := range channels {
+		if writable(channel) {
+			return channel.ID(), true
+		}
+	}
+	return 0, false
+}
@@ -1,6 +1,10 @@
 package colors
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 // Color is a color.
 type Color uint
@@ -39,6 +43,39 @@ var colors = map[Color]string{
 	Black:  "Black",
 }
 
+// classes is a map of the World of Warcraft classes in lowercase to their colors.
+var classes = map[string]Color{
+	"death knight": 0xc41e3a,
+	"demon hunter": 0xa330c9,
+	"druid":        0xff7c0a,
+	"evoker":       0x33937f,
+	"hunter":       0xaad372,
+	"mage":         0x3fc7eb,
+	"monk":         0x00ff98,
+	"paladin":      0xf48cba,
+	"priest":       White,
+	"rogue":        0xfff468,
+	"shaman":       0x0070dd,
+	"warlock":      0x8788ee,
+	"warrior":      0xc69b6d,
+}
+
+// Class returns the color of the World of Warcraft class with the given name, e.g. "Death Knight".
+// It returns false if the class is unknown.
+func Class(name string) (Color, bool) {
+	c, ok := classes[strings.ToLower(strings.TrimSpace(name))]
+	return c, ok
+}
+
+// Parse parses a color from a hex string like "#ff8000".
+func Parse(s string) (Color, error) {
+	v, err := strconv.ParseUint(strings.TrimPrefix(s, "#"), 16, 24)
+	if err != nil {
+		return 0, fmt.Errorf("invalid color %q: %w", s, err)
+	}
+	return Color(v), nil
+}
+
 // String returns the color as a (hex) string.
 func (c Color) String() string {
 	return fmt.Sprintf("#%06x", int(c)) //nolint:gosec // Cannot overflow as we have a fixed size (0xffffff = 24 bits)
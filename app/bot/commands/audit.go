@@ -0,0 +1,235 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/gofiber/fiber/v3"
+	"github.com/lvlcn-t/go-kit/apimanager/fiberutils"
+	"github.com/lvlcn-t/loggerhead/logger"
+	"github.com/lvlcn-t/raid-mate/app/bot/colors"
+	"github.com/lvlcn-t/raid-mate/app/bot/i18n"
+	"github.com/lvlcn-t/raid-mate/app/services/analysis"
+	"github.com/lvlcn-t/raid-mate/app/services/guild"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+)
+
+var (
+	_ Command[*events.ApplicationCommandInteractionCreate] = (*Audit)(nil)
+	_ AutocompleteCommand                                  = (*Audit)(nil)
+)
+
+const (
+	// auditConsumables is the subcommand to audit the consumables of the raiders.
+	auditConsumables = "consumables"
+	// defaultAuditWeeks is the default number of weeks the repeat offenders are listed for.
+	defaultAuditWeeks = 4
+	// maxAuditWeeks is the maximum number of weeks the repeat offenders can be listed for.
+	maxAuditWeeks = 52
+	// week is the duration of a week.
+	week = 7 * 24 * time.Hour
+)
+
+// errInvalidWeeks is returned when the number of weeks of an HTTP request is invalid.
+var errInvalidWeeks = svcerr.Invalid(svcerr.Msg("errors.invalid.weeks", maxAuditWeeks))
+
+// Audit is a command to audit the preparation of the raiders.
+type Audit struct {
+	// Base is the common base for all commands.
+	*Base[*events.ApplicationCommandInteractionCreate]
+	// service is the guild service.
+	service guild.Service
+	// paginator sends the findings on multiple pages.
+	paginator *Paginator
+}
+
+// newAudit creates a new audit command.
+func newAudit(svc guild.Service, paginator *Paginator) *Audit {
+	return &Audit{
+		Base:      NewBase[*events.ApplicationCommandInteractionCreate]("audit"),
+		service:   svc,
+		paginator: paginator,
+	}
+}
+
+// Handle is the handler for the command that is called when the event is triggered.
+// With a report it audits the consumables of the raiders on its boss pulls,
+// without one it lists the repeat offenders of the last weeks.
+func (c *Audit) Handle(ctx context.Context, event *events.ApplicationCommandInteractionCreate) {
+	log := logger.FromContext(ctx).With("command", c.Name())
+	data := event.SlashCommandInteractionData()
+	if data.SubCommandName == nil || *data.SubCommandName != auditConsumables {
+		replyError(ctx, log, event, fmt.Errorf("unknown subcommand %v", data.SubCommandName))
+		return
+	}
+	ctx = withLinkedGuild(ctx, data)
+
+	var pages []discord.Embed
+	if code, ok := data.OptString("report"); ok {
+		audit, err := c.service.AuditConsumables(ctx, *event.GuildID(), code)
+		if err != nil {
+			replyError(ctx, log, event, err)
+			return
+		}
+		pages = consumableAuditPages(ctx, event, audit)
+	} else {
+		weeks, ok := data.OptInt("weeks")
+		if !ok {
+			weeks = defaultAuditWeeks
+		}
+		offenders, err := c.service.GetConsumableOffenders(ctx, *event.GuildID(), time.Now().Add(-time.Duration(weeks)*week))
+		if err != nil {
+			replyError(ctx, log, event, err)
+			return
+		}
+		pages = offendersPages(ctx, event, weeks, offenders)
+	}
+
+	err := c.paginator.Send(ctx, event, pages, false)
+	if err != nil {
+		log.ErrorContext(ctx, "Error replying to interaction", "error", err)
+	}
+}
+
+// consumableAuditPages returns the pages listing the consumables each player missed on the boss pulls of the report.
+func consumableAuditPages(ctx context.Context, event localized, audit *guild.ConsumableAudit) []discord.Embed {
+	description := tr(ctx, event, "audit.report", audit.Pulls, len(audit.Players))
+	var fields []discord.EmbedField
+	for _, p := range audit.Players {
+		var missing []string
+		for _, consumable := range analysis.Consumables {
+			if n := p.Missing[consumable]; n > 0 {
+				missing = append(missing, tr(ctx, event, "audit.missing", consumableName(ctx, event, consumable), n, p.Pulls))
+			}
+		}
+		if len(missing) > 0 {
+			fields = append(fields, discord.EmbedField{Name: p.Player, Value: strings.Join(missing, "\n")})
+		}
+	}
+	if len(fields) == 0 && audit.Pulls > 0 {
+		description += "\n\n" + tr(ctx, event, "audit.all_prepared")
+	}
+
+	template := discord.NewEmbedBuilder().
+		SetTitle(tr(ctx, event, "audit.report_title", audit.Title)).
+		SetURL(audit.URL).
+		SetDescription(description).
+		SetColor(colors.Purple.Int()).
+		Build()
+	return fieldPages(template, fields, maxEmbedFields)
+}
+
+// offendersPages returns the pages listing the players who missed consumables in the audited reports.
+func offendersPages(ctx context.Context, event localized, weeks int, offenders *guild.ConsumableOffenders) []discord.Embed {
+	description := tr(ctx, event, "audit.offenders", offenders.Audits)
+	switch {
+	case offenders.Audits == 0:
+		description = tr(ctx, event, "audit.no_audits")
+	case len(offenders.Players) == 0:
+		description += "\n\n" + tr(ctx, event, "audit.all_prepared")
+	}
+
+	fields := make([]discord.EmbedField, 0, len(offenders.Players))
+	for _, p := range offenders.Players {
+		var missing []string
+		for _, consumable := range analysis.Consumables {
+			if n := p.Missing[consumable]; n > 0 {
+				missing = append(missing, tr(ctx, event, "audit.missing_pulls", consumableName(ctx, event, consumable), n))
+			}
+		}
+		fields = append(fields, discord.EmbedField{
+			Name:  tr(ctx, event, "audit.offender", p.Player, p.Reports, offenders.Audits),
+			Value: strings.Join(missing, "\n"),
+		})
+	}
+
+	template := discord.NewEmbedBuilder().
+		SetTitle(tr(ctx, event, "audit.offenders_title", weeks)).
+		SetDescription(description).
+		SetColor(colors.Purple.Int()).
+		Build()
+	return fieldPages(template, fields, maxEmbedFields)
+}
+
+// consumableName returns the localized name of the consumable.
+func consumableName(ctx context.Context, event localized, consumable analysis.Consumable) string {
+	return tr(ctx, event, "audit.consumables."+string(consumable))
+}
+
+// HandleAutocomplete suggests the linked guilds.
+func (c *Audit) HandleAutocomplete(ctx context.Context, event *events.AutocompleteInteractionCreate) {
+	log := logger.FromContext(ctx).With("command", c.Name())
+	if event.Data.Focused().Name != linkedGuildOption {
+		respondSuggestions(ctx, log, event, nil)
+		return
+	}
+	respondSuggestions(ctx, log, event, suggestLinkedGuilds(ctx, log, c.service, event))
+}
+
+// HandleHTTP is the handler for the command that is called when the HTTP request is triggered.
+// A POST request audits the report with the given ID, a GET request returns the repeat offenders of the given weeks.
+func (c *Audit) HandleHTTP(ctx fiber.Ctx) error {
+	log := logger.FromContext(ctx.Context()).With("command", c.Name())
+	gid, err := fiberutils.Params(ctx, "guildID", snowflake.Parse)
+	if err != nil {
+		return errorResponse(ctx, log, errors.Join(errInvalidGuildID, err))
+	}
+
+	if ctx.Method() == http.MethodPost {
+		audit, err := c.service.AuditConsumables(ctx.Context(), gid, ctx.Params("reportID"))
+		if err != nil {
+			return errorResponse(ctx, log, err)
+		}
+		return ctx.Status(http.StatusOK).JSON(audit)
+	}
+
+	weeks := defaultAuditWeeks
+	if q := ctx.Query("weeks"); q != "" {
+		weeks, err = strconv.Atoi(q)
+		if err != nil || weeks < 1 || weeks > maxAuditWeeks {
+			return errorResponse(ctx, log, errors.Join(errInvalidWeeks, err))
+		}
+	}
+	offenders, err := c.service.GetConsumableOffenders(ctx.Context(), gid, time.Now().Add(-time.Duration(weeks)*week))
+	if err != nil {
+		return errorResponse(ctx, log, err)
+	}
+	return ctx.Status(http.StatusOK).JSON(offenders)
+}
+
+// Route returns the route for the command.
+func (c *Audit) Route() (methods []string, path string) {
+	return []string{http.MethodGet, http.MethodPost}, "/guilds/:guildID/audit/consumables/:reportID?"
+}
+
+// Info returns the interaction command information.
+func (c *Audit) Info() (discord.ApplicationCommandCreate, error) {
+	return NewInfoBuilder().
+		Name(c.Name(), nil).
+		Description(i18n.Text("commands.audit.description")).
+		SubCommand(NewSubCommandBuilder().
+			Name(auditConsumables, i18n.Localizations("commands.audit.consumables.name")).
+			Description(i18n.Text("commands.audit.consumables.description")).
+			Option(NewStringOptionBuilder().
+				Name("report", i18n.Localizations("commands.audit.consumables.options.report.name")).
+				Description(i18n.Text("commands.audit.consumables.options.report.description")).
+				Required(false),
+			).
+			Option(NewIntOptionBuilder().
+				Name("weeks", i18n.Localizations("commands.audit.consumables.options.weeks.name")).
+				Description(i18n.Text("commands.audit.consumables.options.weeks.description")).
+				Required(false).
+				MinValue(1).
+				MaxValue(maxAuditWeeks),
+			).
+			Option(newLinkedGuildOption()),
+		).Build()
+}
@@ -0,0 +1,125 @@
+package commands_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands/commandstest"
+	"github.com/lvlcn-t/raid-mate/app/services/analysis"
+	"github.com/lvlcn-t/raid-mate/app/services/guild"
+)
+
+func TestAudit(t *testing.T) {
+	tests := []commandTest{
+		{
+			name: "audit - consumables of a report",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				AuditConsumablesFunc: func(_ context.Context, _ snowflake.ID, code string) (*guild.ConsumableAudit, error) {
+					return &guild.ConsumableAudit{
+						Code:  code,
+						Title: "Nerub-ar Palace Heroic",
+						URL:   "https://www.warcraftlogs.com/reports/" + code,
+						Pulls: 4,
+						Players: []analysis.ConsumableAudit{
+							{Player: "Bjorn", Pulls: 4, Missing: map[analysis.Consumable]int{analysis.WeaponOil: 2, analysis.AugmentRune: 4}},
+							{Player: "Aerith", Pulls: 4, Missing: map[analysis.Consumable]int{}},
+						},
+					}, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "audit consumables", commandstest.Options{"report": "a1B2c3D4e5F6g7H8"})
+			},
+			want: want{responded: true, embeds: []string{"Consumables in Nerub-ar Palace Heroic"}},
+			check: func(t *testing.T, h *commandstest.Harness, rec *commandstest.Recorder) {
+				embed := rec.Embeds()[0]
+				if want := "Checked 4 boss pulls of 2 players."; embed.Description != want {
+					t.Errorf("description = %q, want %q", embed.Description, want)
+				}
+				if len(embed.Fields) != 1 {
+					t.Fatalf("got %d fields, want only the unprepared player", len(embed.Fields))
+				}
+				if f, want := embed.Fields[0], "Augment rune missing on 4 of 4 pulls\nWeapon oil missing on 2 of 4 pulls"; f.Name != "Bjorn" || f.Value != want {
+					t.Errorf("field = %q: %q, want %q: %q", f.Name, f.Value, "Bjorn", want)
+				}
+				if calls := h.Services.Guild.Called("GetConsumableOffenders"); len(calls) != 0 {
+					t.Errorf("GetConsumableOffenders called for a report: %v", calls)
+				}
+			},
+		},
+		{
+			name: "audit - everyone prepared",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				AuditConsumablesFunc: func(_ context.Context, _ snowflake.ID, _ string) (*guild.ConsumableAudit, error) {
+					return &guild.ConsumableAudit{Title: "Nerub-ar Palace Heroic", Pulls: 2, Players: []analysis.ConsumableAudit{
+						{Player: "Aerith", Pulls: 2, Missing: map[analysis.Consumable]int{}},
+					}}, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "audit consumables", commandstest.Options{"report": "a1B2c3D4e5F6g7H8"}, commandstest.WithLocale(discord.LocaleGerman))
+			},
+			want: want{responded: true, embeds: []string{"Verbrauchsgüter in Nerub-ar Palace Heroic"}},
+			check: func(t *testing.T, _ *commandstest.Harness, rec *commandstest.Recorder) {
+				if got, want := rec.Embeds()[0].Description, "2 Bosspulls von 1 Spielern geprüft.\n\nAlle waren vorbereitet. Gut gemacht!"; got != want {
+					t.Errorf("description = %q, want %q", got, want)
+				}
+			},
+		},
+		{
+			name: "audit - repeat offenders",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetConsumableOffendersFunc: func(_ context.Context, _ snowflake.ID, since time.Time) (*guild.ConsumableOffenders, error) {
+					return &guild.ConsumableOffenders{Since: since, Audits: 3, Players: []guild.ConsumableOffender{
+						{Player: "Bjorn", Reports: 2, Missing: map[analysis.Consumable]int{analysis.AugmentRune: 6, analysis.Flask: 2}},
+					}}, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "audit consumables", commandstest.Options{"weeks": 2})
+			},
+			want: want{responded: true, embeds: []string{"Repeat offenders of the last 2 weeks"}},
+			check: func(t *testing.T, h *commandstest.Harness, rec *commandstest.Recorder) {
+				embed := rec.Embeds()[0]
+				if want := "3 reports were audited."; embed.Description != want {
+					t.Errorf("description = %q, want %q", embed.Description, want)
+				}
+				want := discord.EmbedField{Name: "Bjorn, in 2 of 3 reports", Value: "Flask or phial missing on 2 pulls\nAugment rune missing on 6 pulls"}
+				if len(embed.Fields) != 1 || embed.Fields[0].Name != want.Name || embed.Fields[0].Value != want.Value {
+					t.Errorf("fields = %+v, want %+v", embed.Fields, want)
+				}
+
+				calls := h.Services.Guild.Called("GetConsumableOffenders")
+				if len(calls) != 1 {
+					t.Fatalf("GetConsumableOffenders called %d times, want 1", len(calls))
+				}
+				if since := calls[0].Args[1].(time.Time); time.Since(since).Round(time.Hour) != 14*24*time.Hour {
+					t.Errorf("since = %v, want two weeks ago", since)
+				}
+			},
+		},
+		{
+			name: "audit - no audited reports",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetConsumableOffendersFunc: func(_ context.Context, _ snowflake.ID, since time.Time) (*guild.ConsumableOffenders, error) {
+					return &guild.ConsumableOffenders{Since: since}, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "audit consumables", nil)
+			},
+			want: want{responded: true, embeds: []string{"Repeat offenders of the last 4 weeks"}},
+			check: func(t *testing.T, _ *commandstest.Harness, rec *commandstest.Recorder) {
+				want := "No reports were audited in this period. Audit one with `/audit consumables report`."
+				if got := rec.Embeds()[0].Description; got != want {
+					t.Errorf("description = %q, want %q", got, want)
+				}
+			},
+		},
+	}
+
+	runCommandTests(t, tests)
+}
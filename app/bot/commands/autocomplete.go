@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/lvlcn-t/loggerhead/logger"
+)
+
+// autocompleteTimeout is the duration a command has to suggest values.
+// Discord discards suggestions that are not sent within 3 seconds.
+const autocompleteTimeout = 2500 * time.Millisecond
+
+// suggest returns the values matching what the user typed so far as autocomplete choices.
+// Values starting with the query are suggested before values only containing it.
+// Duplicates are removed and at most 25 values are suggested, as Discord does not allow more.
+func suggest(query string, values []string) []discord.AutocompleteChoice {
+	query = strings.ToLower(strings.TrimSpace(query))
+	var prefixed, contained []string
+	seen := map[string]struct{}{}
+	for _, v := range values {
+		lower := strings.ToLower(v)
+		if _, ok := seen[lower]; ok || v == "" || len(v) > maxChoiceLength {
+			continue
+		}
+		seen[lower] = struct{}{}
+
+		switch {
+		case strings.HasPrefix(lower, query):
+			prefixed = append(prefixed, v)
+		case strings.Contains(lower, query):
+			contained = append(contained, v)
+		}
+	}
+
+	choices := make([]discord.AutocompleteChoice, 0, min(len(prefixed)+len(contained), maxOptions))
+	for _, v := range append(prefixed, contained...) {
+		if len(choices) == maxOptions {
+			break
+		}
+		choices = append(choices, discord.AutocompleteChoiceString{Name: v, Value: v})
+	}
+	return choices
+}
+
+// respondSuggestions sends the suggestions for the focused option of the autocomplete interaction.
+// Commands that fail to look up the values should respond with no suggestions instead of leaving the interaction unanswered.
+func respondSuggestions(ctx context.Context, log logger.Logger, event *events.AutocompleteInteractionCreate, choices []discord.AutocompleteChoice) {
+	err := event.AutocompleteResult(choices)
+	if err != nil {
+		log.ErrorContext(ctx, "Error sending autocomplete suggestions", "error", err)
+	}
+}
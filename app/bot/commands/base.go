@@ -25,6 +25,9 @@ type Command[T Event] interface {
 	HandleHTTP(ctx fiber.Ctx) error
 	// Route returns the route for the command.
 	Route() (methods []string, path string)
+	// Ephemeral reports whether the responses of the command are only visible to the invoking user.
+	// It decides the visibility of the loading state if the command is deferred automatically.
+	Ephemeral() bool
 }
 
 // Base is a common base for all commands.
@@ -71,6 +74,12 @@ func (c *Base[T]) Route() (methods []string, path string) {
 	return nil, fmt.Sprintf("/%s", c.Name())
 }
 
+// Ephemeral reports whether the responses of the command are only visible to the invoking user.
+// This is a default implementation that returns false.
+func (c *Base[T]) Ephemeral() bool {
+	return false
+}
+
 // NewBase creates the common base for all commands.
 // The name is the name of the command.
 // The name should be unique and should not contain spaces.
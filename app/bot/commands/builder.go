@@ -1,10 +1,15 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"regexp"
+	"strings"
 	"unicode/utf8"
 
 	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/json"
 )
 
 const (
@@ -12,11 +17,127 @@ const (
 	maxNameLength int = 32
 	// maxDescriptionLength is the maximum length of a description that discord allows.
 	maxDescriptionLength int = 100
+	// maxOptions is the maximum number of options, choices and subcommands that discord allows per level.
+	maxOptions int = 25
+	// maxChoiceLength is the maximum length of the name and string value of a choice that discord allows.
+	maxChoiceLength int = 100
+	// maxStringLength is the maximum length constraint of a string option that discord allows.
+	maxStringLength int = 6000
 )
 
+// errInvalidInfo is returned if the information of a command violates the constraints of Discord.
+var errInvalidInfo = errors.New("invalid command info")
+
+// namePattern is the pattern the names of slash commands and their options must match.
+var namePattern = regexp.MustCompile(`^[-_'\p{L}\p{N}]{1,32}$`)
+
+// invalid returns an error wrapping [errInvalidInfo] with the formatted message.
+func invalid(format string, a ...any) error {
+	return fmt.Errorf("%w: %s", errInvalidInfo, fmt.Sprintf(format, a...))
+}
+
+// validateName validates the name of a slash command, subcommand or option and its localizations.
+func validateName(name string, localizations map[discord.Locale]string) error {
+	var errs []error
+	check := func(locale discord.Locale, n string) {
+		switch {
+		case utf8.RuneCountInString(n) > maxNameLength:
+			errs = append(errs, invalid("name %q for locale %q is too long: %d > %d", n, locale, utf8.RuneCountInString(n), maxNameLength))
+		case !namePattern.MatchString(n) || strings.ToLower(n) != n:
+			errs = append(errs, invalid("name %q for locale %q must be lower case without spaces", n, locale))
+		}
+	}
+
+	check(discord.LocaleEnglishUS, name)
+	for locale, n := range localizations {
+		check(locale, n)
+	}
+	return errors.Join(errs...)
+}
+
+// validateDescription validates the description of a slash command, subcommand or option and its localizations.
+func validateDescription(description string, localizations map[discord.Locale]string) error {
+	var errs []error
+	check := func(locale discord.Locale, desc string) {
+		switch n := utf8.RuneCountInString(desc); {
+		case n == 0:
+			errs = append(errs, invalid("description for locale %q is empty", locale))
+		case n > maxDescriptionLength:
+			errs = append(errs, invalid("description for locale %q is too long: %d > %d", locale, n, maxDescriptionLength))
+		}
+	}
+
+	check(discord.LocaleEnglishUS, description)
+	for locale, desc := range localizations {
+		check(locale, desc)
+	}
+	return errors.Join(errs...)
+}
+
+// validateOptions validates the number and order of the options of a slash command or subcommand.
+func validateOptions(options []discord.ApplicationCommandOption) error {
+	var errs []error
+	if len(options) > maxOptions {
+		errs = append(errs, invalid("too many options: %d > %d", len(options), maxOptions))
+	}
+
+	optional := ""
+	names := map[string]struct{}{}
+	for _, option := range options {
+		if _, ok := names[option.OptionName()]; ok {
+			errs = append(errs, invalid("option %q is defined twice", option.OptionName()))
+		}
+		names[option.OptionName()] = struct{}{}
+
+		switch {
+		case !isRequired(option):
+			optional = option.OptionName()
+		case optional != "":
+			errs = append(errs, invalid("required option %q must be placed before optional option %q", option.OptionName(), optional))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// isRequired reports whether the option is required.
+func isRequired(option discord.ApplicationCommandOption) bool {
+	switch o := option.(type) {
+	case discord.ApplicationCommandOptionString:
+		return o.Required
+	case discord.ApplicationCommandOptionInt:
+		return o.Required
+	case discord.ApplicationCommandOptionFloat:
+		return o.Required
+	case discord.ApplicationCommandOptionBool:
+		return o.Required
+	case discord.ApplicationCommandOptionUser:
+		return o.Required
+	case discord.ApplicationCommandOptionRole:
+		return o.Required
+	case discord.ApplicationCommandOptionMentionable:
+		return o.Required
+	case discord.ApplicationCommandOptionChannel:
+		return o.Required
+	case discord.ApplicationCommandOptionAttachment:
+		return o.Required
+	default:
+		return false
+	}
+}
+
 // InfoBuilder is a builder for a slash command info. It is used to create a slash command.
+//
+// Violations of the constraints of Discord do not panic but are collected and returned by [InfoBuilder.Build],
+// so all of them can be reported at once on startup.
 type InfoBuilder struct {
+	// c is the slash command being built.
 	c discord.SlashCommandCreate
+	// err are the errors collected while building.
+	err error
+	// subcommands is whether subcommands or subcommand groups were added.
+	subcommands bool
+	// options is whether options other than subcommands were added.
+	options bool
 }
 
 // NewInfoBuilder creates a new info builder.
@@ -29,16 +150,7 @@ func NewInfoBuilder() InfoBuilder {
 //
 // Provide nil for localizations if the name should not be localized.
 func (i InfoBuilder) Name(name string, localizations map[discord.Locale]string) InfoBuilder { //nolint:gocritic // builder pattern
-	if len(name) > maxNameLength {
-		panic(fmt.Sprintf("name is too long: %d > %d", len(name), maxNameLength))
-	}
-
-	for locale, n := range localizations {
-		if utf8.RuneCountInString(n) > maxNameLength {
-			panic(fmt.Sprintf("name for locale %q is too long: %d > %d", locale, utf8.RuneCountInString(n), maxNameLength))
-		}
-	}
-
+	i.err = errors.Join(i.err, validateName(name, localizations))
 	i.c.Name = name
 	i.c.NameLocalizations = localizations
 	return i
@@ -49,16 +161,7 @@ func (i InfoBuilder) Name(name string, localizations map[discord.Locale]string)
 //
 // Provide nil for localizations if the description should not be localized.
 func (i InfoBuilder) Description(description string, localizations map[discord.Locale]string) InfoBuilder { //nolint:gocritic // builder pattern
-	if len(description) > maxDescriptionLength {
-		panic(fmt.Sprintf("description is too long: %d > %d", len(description), maxDescriptionLength))
-	}
-
-	for locale, desc := range localizations {
-		if utf8.RuneCountInString(desc) > maxDescriptionLength {
-			panic(fmt.Sprintf("description for locale %q is too long: %d > %d", locale, utf8.RuneCountInString(desc), maxDescriptionLength))
-		}
-	}
-
+	i.err = errors.Join(i.err, validateDescription(description, localizations))
 	i.c.Description = description
 	i.c.DescriptionLocalizations = localizations
 	return i
@@ -70,103 +173,662 @@ func (i InfoBuilder) NSFW(nsfw bool) InfoBuilder { //nolint:gocritic // builder
 	return i
 }
 
+// DefaultMemberPermissions sets the permissions a member needs to use the slash command by default.
+// Server administrators can override them in the integration settings.
+func (i InfoBuilder) DefaultMemberPermissions(perms discord.Permissions) InfoBuilder { //nolint:gocritic // builder pattern
+	i.c.DefaultMemberPermissions = json.NewNullablePtr(perms)
+	return i
+}
+
 // Option adds an option to the slash command.
-func (i InfoBuilder) Option(option discord.ApplicationCommandOption) InfoBuilder { //nolint:gocritic // builder pattern
-	i.c.Options = append(i.c.Options, option)
+// Options cannot be combined with subcommands.
+func (i InfoBuilder) Option(option OptionBuilder) InfoBuilder { //nolint:gocritic // builder pattern
+	o, err := option.Build()
+	i.err = errors.Join(i.err, err)
+	i.options = true
+	if o != nil {
+		i.c.Options = append(i.c.Options, o)
+	}
+	return i
+}
+
+// SubCommand adds a subcommand to the slash command.
+func (i InfoBuilder) SubCommand(subcommand SubCommandBuilder) InfoBuilder { //nolint:gocritic // builder pattern
+	s, err := subcommand.Build()
+	i.err = errors.Join(i.err, err)
+	i.subcommands = true
+	i.c.Options = append(i.c.Options, s)
+	return i
+}
+
+// SubCommandGroup adds a group of subcommands to the slash command.
+func (i InfoBuilder) SubCommandGroup(group SubCommandGroupBuilder) InfoBuilder { //nolint:gocritic // builder pattern
+	g, err := group.Build()
+	i.err = errors.Join(i.err, err)
+	i.subcommands = true
+	i.c.Options = append(i.c.Options, g)
 	return i
 }
 
 // Build builds the slash command.
-func (i InfoBuilder) Build() discord.SlashCommandCreate { //nolint:gocritic // builder pattern
-	return i.c
+// It returns all errors collected while building the command and its options.
+func (i InfoBuilder) Build() (discord.SlashCommandCreate, error) { //nolint:gocritic // builder pattern
+	err := i.err
+	if i.options && i.subcommands {
+		err = errors.Join(err, invalid("options cannot be combined with subcommands"))
+	}
+	if i.subcommands && len(i.c.Options) > maxOptions {
+		err = errors.Join(err, invalid("too many subcommands: %d > %d", len(i.c.Options), maxOptions))
+	}
+	if !i.subcommands {
+		err = errors.Join(err, validateOptions(i.c.Options))
+	}
+
+	if err != nil {
+		return i.c, fmt.Errorf("command %q: %w", i.c.Name, err)
+	}
+	return i.c, nil
 }
 
-// StringOptionBuilder is a builder for a string option. It is used to create a string option.
-type StringOptionBuilder struct {
-	o discord.ApplicationCommandOptionString
+// ContextMenuBuilder is a builder for a command in the context menu of users or messages.
+type ContextMenuBuilder struct {
+	// typ is the type of the context menu command.
+	typ discord.ApplicationCommandType
+	// name is the name of the command shown in the context menu.
+	name string
+	// nameLocalizations are the localized names of the command.
+	nameLocalizations map[discord.Locale]string
+	// permissions are the permissions a member needs to use the command by default.
+	permissions *discord.Permissions
+	// contexts are the contexts the command is available in.
+	contexts []discord.InteractionContextType
+	// err are the errors collected while building.
+	err error
 }
 
-// NewStringOptionBuilder creates a new string option builder.
-func NewStringOptionBuilder() StringOptionBuilder {
-	return StringOptionBuilder{o: discord.ApplicationCommandOptionString{}}
+// NewUserCommandBuilder creates a new builder for a command in the context menu of users.
+func NewUserCommandBuilder() ContextMenuBuilder {
+	return ContextMenuBuilder{typ: discord.ApplicationCommandTypeUser}
 }
 
-// Name sets the name of the string option and its localizations.
-// The name should not be longer than 32 characters.
+// NewMessageCommandBuilder creates a new builder for a command in the context menu of messages.
+func NewMessageCommandBuilder() ContextMenuBuilder {
+	return ContextMenuBuilder{typ: discord.ApplicationCommandTypeMessage}
+}
+
+// Name sets the name of the context menu command and its localizations.
+// Unlike the names of slash commands, it may contain upper case letters and spaces but must not be longer than 32 characters.
 //
 // Provide nil for localizations if the name should not be localized.
-func (so StringOptionBuilder) Name(name string, localization map[discord.Locale]string) StringOptionBuilder { //nolint:gocritic // builder pattern
-	if len(name) > maxNameLength {
-		panic(fmt.Sprintf("name is too long: %d > %d", len(name), maxNameLength))
+func (cb ContextMenuBuilder) Name(name string, localizations map[discord.Locale]string) ContextMenuBuilder { //nolint:gocritic // builder pattern
+	check := func(locale discord.Locale, n string) {
+		if l := utf8.RuneCountInString(n); l == 0 || l > maxNameLength {
+			cb.err = errors.Join(cb.err, invalid("name %q for locale %q is not between 1 and %d characters", n, locale, maxNameLength))
+		}
+	}
+	check(discord.LocaleEnglishUS, name)
+	for locale, n := range localizations {
+		check(locale, n)
 	}
 
-	for locale, n := range localization {
-		if utf8.RuneCountInString(n) > maxNameLength {
-			panic(fmt.Sprintf("name for locale %q is too long: %d > %d", locale, utf8.RuneCountInString(n), maxNameLength))
-		}
+	cb.name = name
+	cb.nameLocalizations = localizations
+	return cb
+}
+
+// DefaultMemberPermissions sets the permissions a member needs to use the context menu command by default.
+func (cb ContextMenuBuilder) DefaultMemberPermissions(perms discord.Permissions) ContextMenuBuilder { //nolint:gocritic // builder pattern
+	cb.permissions = &perms
+	return cb
+}
+
+// Contexts restricts the context menu command to the given contexts, e.g. to guilds only.
+func (cb ContextMenuBuilder) Contexts(contexts ...discord.InteractionContextType) ContextMenuBuilder { //nolint:gocritic // builder pattern
+	cb.contexts = contexts
+	return cb
+}
+
+// Build builds the context menu command.
+// It returns all errors collected while building the command.
+func (cb ContextMenuBuilder) Build() (discord.ApplicationCommandCreate, error) { //nolint:gocritic // builder pattern
+	if cb.err != nil {
+		return nil, fmt.Errorf("command %q: %w", cb.name, cb.err)
 	}
 
-	so.o.Name = name
-	return so
+	var perms *json.Nullable[discord.Permissions]
+	if cb.permissions != nil {
+		perms = json.NewNullablePtr(*cb.permissions)
+	}
+	if cb.typ == discord.ApplicationCommandTypeMessage {
+		return discord.MessageCommandCreate{
+			Name:                     cb.name,
+			NameLocalizations:        cb.nameLocalizations,
+			DefaultMemberPermissions: perms,
+			Contexts:                 cb.contexts,
+		}, nil
+	}
+	return discord.UserCommandCreate{
+		Name:                     cb.name,
+		NameLocalizations:        cb.nameLocalizations,
+		DefaultMemberPermissions: perms,
+		Contexts:                 cb.contexts,
+	}, nil
+}
+
+// SubCommandBuilder is a builder for a subcommand of a slash command.
+type SubCommandBuilder struct {
+	// s is the subcommand being built.
+	s discord.ApplicationCommandOptionSubCommand
+	// err are the errors collected while building.
+	err error
+}
+
+// NewSubCommandBuilder creates a new subcommand builder.
+func NewSubCommandBuilder() SubCommandBuilder {
+	return SubCommandBuilder{s: discord.ApplicationCommandOptionSubCommand{}}
+}
+
+// Name sets the name of the subcommand and its localizations.
+//
+// Provide nil for localizations if the name should not be localized.
+func (sb SubCommandBuilder) Name(name string, localizations map[discord.Locale]string) SubCommandBuilder { //nolint:gocritic // builder pattern
+	sb.err = errors.Join(sb.err, validateName(name, localizations))
+	sb.s.Name = name
+	sb.s.NameLocalizations = localizations
+	return sb
+}
+
+// Description sets the description of the subcommand and its localizations.
+//
+// Provide nil for localizations if the description should not be localized.
+func (sb SubCommandBuilder) Description(description string, localizations map[discord.Locale]string) SubCommandBuilder { //nolint:gocritic // builder pattern
+	sb.err = errors.Join(sb.err, validateDescription(description, localizations))
+	sb.s.Description = description
+	sb.s.DescriptionLocalizations = localizations
+	return sb
+}
+
+// Option adds an option to the subcommand.
+func (sb SubCommandBuilder) Option(option OptionBuilder) SubCommandBuilder { //nolint:gocritic // builder pattern
+	o, err := option.Build()
+	sb.err = errors.Join(sb.err, err)
+	if o != nil {
+		sb.s.Options = append(sb.s.Options, o)
+	}
+	return sb
+}
+
+// Build builds the subcommand.
+// It returns all errors collected while building the subcommand and its options.
+func (sb SubCommandBuilder) Build() (discord.ApplicationCommandOptionSubCommand, error) { //nolint:gocritic // builder pattern
+	err := errors.Join(sb.err, validateOptions(sb.s.Options))
+	if err != nil {
+		return sb.s, fmt.Errorf("subcommand %q: %w", sb.s.Name, err)
+	}
+	return sb.s, nil
+}
+
+// SubCommandGroupBuilder is a builder for a group of subcommands of a slash command.
+type SubCommandGroupBuilder struct {
+	// g is the subcommand group being built.
+	g discord.ApplicationCommandOptionSubCommandGroup
+	// err are the errors collected while building.
+	err error
+}
+
+// NewSubCommandGroupBuilder creates a new subcommand group builder.
+func NewSubCommandGroupBuilder() SubCommandGroupBuilder {
+	return SubCommandGroupBuilder{g: discord.ApplicationCommandOptionSubCommandGroup{}}
+}
+
+// Name sets the name of the subcommand group and its localizations.
+//
+// Provide nil for localizations if the name should not be localized.
+func (gb SubCommandGroupBuilder) Name(name string, localizations map[discord.Locale]string) SubCommandGroupBuilder { //nolint:gocritic // builder pattern
+	gb.err = errors.Join(gb.err, validateName(name, localizations))
+	gb.g.Name = name
+	gb.g.NameLocalizations = localizations
+	return gb
+}
+
+// Description sets the description of the subcommand group and its localizations.
+//
+// Provide nil for localizations if the description should not be localized.
+func (gb SubCommandGroupBuilder) Description(description string, localizations map[discord.Locale]string) SubCommandGroupBuilder { //nolint:gocritic // builder pattern
+	gb.err = errors.Join(gb.err, validateDescription(description, localizations))
+	gb.g.Description = description
+	gb.g.DescriptionLocalizations = localizations
+	return gb
 }
 
-// Description sets the description of the string option and its localizations.
+// SubCommand adds a subcommand to the group.
+func (gb SubCommandGroupBuilder) SubCommand(subcommand SubCommandBuilder) SubCommandGroupBuilder { //nolint:gocritic // builder pattern
+	s, err := subcommand.Build()
+	gb.err = errors.Join(gb.err, err)
+	gb.g.Options = append(gb.g.Options, s)
+	return gb
+}
+
+// Build builds the subcommand group.
+// It returns all errors collected while building the group and its subcommands.
+func (gb SubCommandGroupBuilder) Build() (discord.ApplicationCommandOptionSubCommandGroup, error) { //nolint:gocritic // builder pattern
+	err := gb.err
+	switch n := len(gb.g.Options); {
+	case n == 0:
+		err = errors.Join(err, invalid("a subcommand group needs at least one subcommand"))
+	case n > maxOptions:
+		err = errors.Join(err, invalid("too many subcommands: %d > %d", n, maxOptions))
+	}
+
+	if err != nil {
+		return gb.g, fmt.Errorf("subcommand group %q: %w", gb.g.Name, err)
+	}
+	return gb.g, nil
+}
+
+// OptionBuilder is a builder for an option of a slash command or subcommand.
+// Create it with the constructor for the type of the option, e.g. [NewStringOptionBuilder].
+//
+// Constraints that do not apply to the type of the option are reported as error by [OptionBuilder.Build].
+type OptionBuilder struct {
+	// typ is the type of the option.
+	typ discord.ApplicationCommandOptionType
+	// name is the name of the option.
+	name string
+	// nameLocalizations are the localized names of the option.
+	nameLocalizations map[discord.Locale]string
+	// description is the description of the option.
+	description string
+	// descriptionLocalizations are the localized descriptions of the option.
+	descriptionLocalizations map[discord.Locale]string
+	// required is whether the option is required.
+	required bool
+	// choices are the predefined values the user can pick from.
+	choices []discord.ApplicationCommandOptionChoice
+	// autocomplete is whether the values are suggested by the bot while the user types.
+	autocomplete bool
+	// minValue is the minimum value of an integer or number option.
+	minValue *float64
+	// maxValue is the maximum value of an integer or number option.
+	maxValue *float64
+	// minLength is the minimum length of a string option.
+	minLength *int
+	// maxLength is the maximum length of a string option.
+	maxLength *int
+	// channelTypes are the types of channels a channel option is restricted to.
+	channelTypes []discord.ChannelType
+	// err are the errors collected while building.
+	err error
+}
+
+// newOptionBuilder creates a new option builder for the given type.
+func newOptionBuilder(typ discord.ApplicationCommandOptionType) OptionBuilder {
+	return OptionBuilder{typ: typ}
+}
+
+// NewStringOptionBuilder creates a new string option builder.
+func NewStringOptionBuilder() OptionBuilder {
+	return newOptionBuilder(discord.ApplicationCommandOptionTypeString)
+}
+
+// NewIntOptionBuilder creates a new integer option builder.
+func NewIntOptionBuilder() OptionBuilder {
+	return newOptionBuilder(discord.ApplicationCommandOptionTypeInt)
+}
+
+// NewNumberOptionBuilder creates a new floating point number option builder.
+func NewNumberOptionBuilder() OptionBuilder {
+	return newOptionBuilder(discord.ApplicationCommandOptionTypeFloat)
+}
+
+// NewBoolOptionBuilder creates a new boolean option builder.
+func NewBoolOptionBuilder() OptionBuilder {
+	return newOptionBuilder(discord.ApplicationCommandOptionTypeBool)
+}
+
+// NewUserOptionBuilder creates a new user option builder.
+func NewUserOptionBuilder() OptionBuilder {
+	return newOptionBuilder(discord.ApplicationCommandOptionTypeUser)
+}
+
+// NewRoleOptionBuilder creates a new role option builder.
+func NewRoleOptionBuilder() OptionBuilder {
+	return newOptionBuilder(discord.ApplicationCommandOptionTypeRole)
+}
+
+// NewMentionableOptionBuilder creates a new option builder for users and roles.
+func NewMentionableOptionBuilder() OptionBuilder {
+	return newOptionBuilder(discord.ApplicationCommandOptionTypeMentionable)
+}
+
+// NewChannelOptionBuilder creates a new channel option builder.
+func NewChannelOptionBuilder() OptionBuilder {
+	return newOptionBuilder(discord.ApplicationCommandOptionTypeChannel)
+}
+
+// NewAttachmentOptionBuilder creates a new attachment option builder.
+func NewAttachmentOptionBuilder() OptionBuilder {
+	return newOptionBuilder(discord.ApplicationCommandOptionTypeAttachment)
+}
+
+// Name sets the name of the option and its localizations.
+// The name should not be longer than 32 characters.
+//
+// Provide nil for localizations if the name should not be localized.
+func (ob OptionBuilder) Name(name string, localizations map[discord.Locale]string) OptionBuilder { //nolint:gocritic // builder pattern
+	ob.err = errors.Join(ob.err, validateName(name, localizations))
+	ob.name = name
+	ob.nameLocalizations = localizations
+	return ob
+}
+
+// Description sets the description of the option and its localizations.
 // The description should not be longer than 100 characters.
 //
 // Provide nil for localizations if the description should not be localized.
-func (so StringOptionBuilder) Description(description string, localization map[discord.Locale]string) StringOptionBuilder { //nolint:gocritic // builder pattern
-	if len(description) > maxDescriptionLength {
-		panic(fmt.Sprintf("description is too long: %d > %d", len(description), maxDescriptionLength))
+func (ob OptionBuilder) Description(description string, localizations map[discord.Locale]string) OptionBuilder { //nolint:gocritic // builder pattern
+	ob.err = errors.Join(ob.err, validateDescription(description, localizations))
+	ob.description = description
+	ob.descriptionLocalizations = localizations
+	return ob
+}
+
+// Required sets whether the option is required.
+func (ob OptionBuilder) Required(required bool) OptionBuilder { //nolint:gocritic // builder pattern
+	ob.required = required
+	return ob
+}
+
+// Choices sets the choices of a string, integer or number option.
+// The type of the choices must match the type of the option, see [NewStringOptionChoice].
+func (ob OptionBuilder) Choices(choices ...discord.ApplicationCommandOptionChoice) OptionBuilder { //nolint:gocritic // builder pattern
+	ob.choices = choices
+	return ob
+}
+
+// Autocomplete sets whether the values of a string, integer or number option are suggested while the user types.
+// Autocomplete cannot be combined with choices.
+func (ob OptionBuilder) Autocomplete(autocomplete bool) OptionBuilder { //nolint:gocritic // builder pattern
+	ob.autocomplete = autocomplete
+	return ob
+}
+
+// MinValue sets the minimum value of an integer or number option.
+func (ob OptionBuilder) MinValue(v float64) OptionBuilder { //nolint:gocritic // builder pattern
+	ob.minValue = &v
+	return ob
+}
+
+// MaxValue sets the maximum value of an integer or number option.
+func (ob OptionBuilder) MaxValue(v float64) OptionBuilder { //nolint:gocritic // builder pattern
+	ob.maxValue = &v
+	return ob
+}
+
+// MinLength sets the minimum length of a string option.
+func (ob OptionBuilder) MinLength(n int) OptionBuilder { //nolint:gocritic // builder pattern
+	ob.minLength = &n
+	return ob
+}
+
+// MaxLength sets the maximum length of a string option.
+func (ob OptionBuilder) MaxLength(n int) OptionBuilder { //nolint:gocritic // builder pattern
+	ob.maxLength = &n
+	return ob
+}
+
+// ChannelTypes restricts a channel option to the given types of channels.
+func (ob OptionBuilder) ChannelTypes(types ...discord.ChannelType) OptionBuilder { //nolint:gocritic // builder pattern
+	ob.channelTypes = types
+	return ob
+}
+
+// Build builds the option.
+// It returns all errors collected while building the option.
+func (ob OptionBuilder) Build() (discord.ApplicationCommandOption, error) { //nolint:gocritic // builder pattern
+	err := errors.Join(ob.err, ob.validate())
+	if err != nil {
+		return nil, fmt.Errorf("option %q: %w", ob.name, err)
 	}
 
-	for locale, desc := range localization {
-		if utf8.RuneCountInString(desc) > maxDescriptionLength {
-			panic(fmt.Sprintf("description for locale %q is too long: %d > %d", locale, utf8.RuneCountInString(desc), maxDescriptionLength))
+	switch ob.typ {
+	case discord.ApplicationCommandOptionTypeString:
+		return discord.ApplicationCommandOptionString{
+			Name:                     ob.name,
+			NameLocalizations:        ob.nameLocalizations,
+			Description:              ob.description,
+			DescriptionLocalizations: ob.descriptionLocalizations,
+			Required:                 ob.required,
+			Choices:                  choicesOf[discord.ApplicationCommandOptionChoiceString](ob.choices),
+			Autocomplete:             ob.autocomplete,
+			MinLength:                ob.minLength,
+			MaxLength:                ob.maxLength,
+		}, nil
+	case discord.ApplicationCommandOptionTypeInt:
+		return discord.ApplicationCommandOptionInt{
+			Name:                     ob.name,
+			NameLocalizations:        ob.nameLocalizations,
+			Description:              ob.description,
+			DescriptionLocalizations: ob.descriptionLocalizations,
+			Required:                 ob.required,
+			Choices:                  choicesOf[discord.ApplicationCommandOptionChoiceInt](ob.choices),
+			Autocomplete:             ob.autocomplete,
+			MinValue:                 toInt(ob.minValue),
+			MaxValue:                 toInt(ob.maxValue),
+		}, nil
+	case discord.ApplicationCommandOptionTypeFloat:
+		return discord.ApplicationCommandOptionFloat{
+			Name:                     ob.name,
+			NameLocalizations:        ob.nameLocalizations,
+			Description:              ob.description,
+			DescriptionLocalizations: ob.descriptionLocalizations,
+			Required:                 ob.required,
+			Choices:                  choicesOf[discord.ApplicationCommandOptionChoiceFloat](ob.choices),
+			Autocomplete:             ob.autocomplete,
+			MinValue:                 ob.minValue,
+			MaxValue:                 ob.maxValue,
+		}, nil
+	case discord.ApplicationCommandOptionTypeBool:
+		return discord.ApplicationCommandOptionBool{
+			Name:                     ob.name,
+			NameLocalizations:        ob.nameLocalizations,
+			Description:              ob.description,
+			DescriptionLocalizations: ob.descriptionLocalizations,
+			Required:                 ob.required,
+		}, nil
+	case discord.ApplicationCommandOptionTypeUser:
+		return discord.ApplicationCommandOptionUser{
+			Name:                     ob.name,
+			NameLocalizations:        ob.nameLocalizations,
+			Description:              ob.description,
+			DescriptionLocalizations: ob.descriptionLocalizations,
+			Required:                 ob.required,
+		}, nil
+	case discord.ApplicationCommandOptionTypeRole:
+		return discord.ApplicationCommandOptionRole{
+			Name:                     ob.name,
+			NameLocalizations:        ob.nameLocalizations,
+			Description:              ob.description,
+			DescriptionLocalizations: ob.descriptionLocalizations,
+			Required:                 ob.required,
+		}, nil
+	case discord.ApplicationCommandOptionTypeMentionable:
+		return discord.ApplicationCommandOptionMentionable{
+			Name:                     ob.name,
+			NameLocalizations:        ob.nameLocalizations,
+			Description:              ob.description,
+			DescriptionLocalizations: ob.descriptionLocalizations,
+			Required:                 ob.required,
+		}, nil
+	case discord.ApplicationCommandOptionTypeChannel:
+		return discord.ApplicationCommandOptionChannel{
+			Name:                     ob.name,
+			NameLocalizations:        ob.nameLocalizations,
+			Description:              ob.description,
+			DescriptionLocalizations: ob.descriptionLocalizations,
+			Required:                 ob.required,
+			ChannelTypes:             ob.channelTypes,
+		}, nil
+	case discord.ApplicationCommandOptionTypeAttachment:
+		return discord.ApplicationCommandOptionAttachment{
+			Name:                     ob.name,
+			NameLocalizations:        ob.nameLocalizations,
+			Description:              ob.description,
+			DescriptionLocalizations: ob.descriptionLocalizations,
+			Required:                 ob.required,
+		}, nil
+	default:
+		return nil, fmt.Errorf("option %q: %w", ob.name, invalid("unsupported option type %d", ob.typ))
+	}
+}
+
+// validate validates the constraints of the option against its type.
+func (ob OptionBuilder) validate() error { //nolint:gocritic // builder pattern
+	var errs []error
+	str := ob.typ == discord.ApplicationCommandOptionTypeString
+	num := ob.typ == discord.ApplicationCommandOptionTypeInt || ob.typ == discord.ApplicationCommandOptionTypeFloat
+
+	if len(ob.choices) > 0 || ob.autocomplete {
+		if !str && !num {
+			errs = append(errs, invalid("choices and autocomplete are only supported by string, integer and number options"))
+		}
+		if len(ob.choices) > 0 && ob.autocomplete {
+			errs = append(errs, invalid("choices cannot be combined with autocomplete"))
+		}
+	}
+	errs = append(errs, ob.validateChoices())
+
+	if (ob.minValue != nil || ob.maxValue != nil) && !num {
+		errs = append(errs, invalid("minimum and maximum values are only supported by integer and number options"))
+	}
+	if ob.minValue != nil && ob.maxValue != nil && *ob.minValue > *ob.maxValue {
+		errs = append(errs, invalid("minimum value %v is greater than maximum value %v", *ob.minValue, *ob.maxValue))
+	}
+	if ob.typ == discord.ApplicationCommandOptionTypeInt {
+		for _, v := range []*float64{ob.minValue, ob.maxValue} {
+			if v != nil && *v != math.Trunc(*v) {
+				errs = append(errs, invalid("integer option bound %v is not a whole number", *v))
+			}
 		}
 	}
 
-	so.o.Description = description
-	so.o.DescriptionLocalizations = localization
-	return so
+	if (ob.minLength != nil || ob.maxLength != nil) && !str {
+		errs = append(errs, invalid("minimum and maximum lengths are only supported by string options"))
+	}
+	if ob.minLength != nil && (*ob.minLength < 0 || *ob.minLength > maxStringLength) {
+		errs = append(errs, invalid("minimum length %d is not between 0 and %d", *ob.minLength, maxStringLength))
+	}
+	if ob.maxLength != nil && (*ob.maxLength < 1 || *ob.maxLength > maxStringLength) {
+		errs = append(errs, invalid("maximum length %d is not between 1 and %d", *ob.maxLength, maxStringLength))
+	}
+	if ob.minLength != nil && ob.maxLength != nil && *ob.minLength > *ob.maxLength {
+		errs = append(errs, invalid("minimum length %d is greater than maximum length %d", *ob.minLength, *ob.maxLength))
+	}
+
+	if len(ob.channelTypes) > 0 && ob.typ != discord.ApplicationCommandOptionTypeChannel {
+		errs = append(errs, invalid("channel types are only supported by channel options"))
+	}
+	return errors.Join(errs...)
 }
 
-// Required sets whether the string option is required.
-func (so StringOptionBuilder) Required(required bool) StringOptionBuilder { //nolint:gocritic // builder pattern
-	so.o.Required = required
-	return so
+// validateChoices validates the number, types and lengths of the choices.
+func (ob OptionBuilder) validateChoices() error { //nolint:gocritic // builder pattern
+	var errs []error
+	if len(ob.choices) > maxOptions {
+		errs = append(errs, invalid("too many choices: %d > %d", len(ob.choices), maxOptions))
+	}
+
+	for _, choice := range ob.choices {
+		var ok bool
+		switch c := choice.(type) {
+		case discord.ApplicationCommandOptionChoiceString:
+			ok = ob.typ == discord.ApplicationCommandOptionTypeString
+			if utf8.RuneCountInString(c.Value) > maxChoiceLength {
+				errs = append(errs, invalid("value of choice %q is too long: %d > %d", c.Name, utf8.RuneCountInString(c.Value), maxChoiceLength))
+			}
+		case discord.ApplicationCommandOptionChoiceInt:
+			ok = ob.typ == discord.ApplicationCommandOptionTypeInt
+		case discord.ApplicationCommandOptionChoiceFloat:
+			ok = ob.typ == discord.ApplicationCommandOptionTypeFloat
+		}
+		if !ok {
+			errs = append(errs, invalid("choice %q of type %T does not match the option type", choice.ChoiceName(), choice))
+		}
+
+		for _, name := range append([]string{choice.ChoiceName()}, choiceLocalizations(choice)...) {
+			if n := utf8.RuneCountInString(name); n == 0 || n > maxChoiceLength {
+				errs = append(errs, invalid("name %q of choice %q is not between 1 and %d characters", name, choice.ChoiceName(), maxChoiceLength))
+			}
+		}
+	}
+	return errors.Join(errs...)
 }
 
-// Choices sets the choices of the string option.
-func (so StringOptionBuilder) Choices(choices ...discord.ApplicationCommandOptionChoiceString) StringOptionBuilder { //nolint:gocritic // builder pattern
-	so.o.Choices = choices
-	return so
+// choiceLocalizations returns the localized names of the choice.
+func choiceLocalizations(choice discord.ApplicationCommandOptionChoice) []string {
+	var localizations map[discord.Locale]string
+	switch c := choice.(type) {
+	case discord.ApplicationCommandOptionChoiceString:
+		localizations = c.NameLocalizations
+	case discord.ApplicationCommandOptionChoiceInt:
+		localizations = c.NameLocalizations
+	case discord.ApplicationCommandOptionChoiceFloat:
+		localizations = c.NameLocalizations
+	}
+
+	names := make([]string, 0, len(localizations))
+	for _, name := range localizations {
+		names = append(names, name)
+	}
+	return names
 }
 
-// Build builds the string option.
-func (so StringOptionBuilder) Build() discord.ApplicationCommandOption { //nolint:gocritic // builder pattern
-	return so.o
+// choicesOf returns the choices of the given type.
+func choicesOf[T discord.ApplicationCommandOptionChoice](choices []discord.ApplicationCommandOptionChoice) []T {
+	var res []T
+	for _, choice := range choices {
+		if c, ok := choice.(T); ok {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// toInt converts the bound of an integer option.
+func toInt(v *float64) *int {
+	if v == nil {
+		return nil
+	}
+	return toPtr(int(*v))
 }
 
 // NewStringOptionChoice creates a new string option choice.
-// The name and value should not be longer than 32 characters.
 //
 // Provide nil for localizedNames if the name should not be localized.
 func NewStringOptionChoice(name, value string, localizedNames map[discord.Locale]string) discord.ApplicationCommandOptionChoiceString {
-	if len(name) > maxNameLength {
-		panic(fmt.Sprintf("name is too long: %d > %d", len(name), maxNameLength))
-	}
-
-	if len(value) > maxNameLength {
-		panic(fmt.Sprintf("value is too long: %d > %d", len(value), maxNameLength))
+	return discord.ApplicationCommandOptionChoiceString{
+		Name:              name,
+		NameLocalizations: localizedNames,
+		Value:             value,
 	}
+}
 
-	for locale, n := range localizedNames {
-		if utf8.RuneCountInString(n) > maxNameLength {
-			panic(fmt.Sprintf("name for locale %q is too long: %d > %d", locale, utf8.RuneCountInString(n), maxNameLength))
-		}
+// NewIntOptionChoice creates a new integer option choice.
+//
+// Provide nil for localizedNames if the name should not be localized.
+func NewIntOptionChoice(name string, value int, localizedNames map[discord.Locale]string) discord.ApplicationCommandOptionChoiceInt {
+	return discord.ApplicationCommandOptionChoiceInt{
+		Name:              name,
+		NameLocalizations: localizedNames,
+		Value:             value,
 	}
+}
 
-	return discord.ApplicationCommandOptionChoiceString{
+// NewNumberOptionChoice creates a new floating point number option choice.
+//
+// Provide nil for localizedNames if the name should not be localized.
+func NewNumberOptionChoice(name string, value float64, localizedNames map[discord.Locale]string) discord.ApplicationCommandOptionChoiceFloat {
+	return discord.ApplicationCommandOptionChoiceFloat{
 		Name:              name,
 		NameLocalizations: localizedNames,
 		Value:             value,
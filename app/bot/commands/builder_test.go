@@ -0,0 +1,118 @@
+package commands_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands/commandstest"
+)
+
+func TestInfoBuilder(t *testing.T) {
+	info := func() commands.InfoBuilder {
+		return commands.NewInfoBuilder().Name("raid", nil).Description("Manage raids.", nil)
+	}
+	option := func(ob commands.OptionBuilder) commands.OptionBuilder {
+		return ob.Name("value", nil).Description("A value.", nil)
+	}
+	subcommand := func(name string) commands.SubCommandBuilder {
+		return commands.NewSubCommandBuilder().Name(name, nil).Description("A subcommand.", nil)
+	}
+
+	tests := []struct {
+		name    string
+		builder commands.InfoBuilder
+		// wantErr are the substrings the error must contain. Empty if no error is expected.
+		wantErr []string
+	}{
+		{
+			name: "typed options",
+			builder: info().
+				DefaultMemberPermissions(discord.PermissionManageGuild).
+				Option(option(commands.NewIntOptionBuilder()).Required(true).MinValue(1).MaxValue(40)).
+				Option(option(commands.NewChannelOptionBuilder()).Name("channel", nil).ChannelTypes(discord.ChannelTypeGuildText)).
+				Option(option(commands.NewStringOptionBuilder()).Name("note", nil).MaxLength(200).Autocomplete(true)),
+		},
+		{
+			name: "subcommand groups",
+			builder: info().
+				SubCommand(subcommand("list")).
+				SubCommandGroup(commands.NewSubCommandGroupBuilder().
+					Name("signup", nil).
+					Description("Manage signups.", nil).
+					SubCommand(subcommand("add").Option(option(commands.NewUserOptionBuilder()).Required(true))),
+				),
+		},
+		{
+			name: "invalid names and descriptions",
+			builder: commands.NewInfoBuilder().
+				Name("Raid Planner", map[discord.Locale]string{discord.LocaleGerman: strings.Repeat("r", 33)}).
+				Description("", nil),
+			wantErr: []string{`name "Raid Planner"`, `locale "German" is too long`, "description for locale"},
+		},
+		{
+			name: "constraints of other option types",
+			builder: info().
+				Option(option(commands.NewBoolOptionBuilder()).MinValue(1).ChannelTypes(discord.ChannelTypeGuildText)),
+			wantErr: []string{"minimum and maximum values", "channel types"},
+		},
+		{
+			name: "invalid bounds and choices",
+			builder: info().
+				Option(option(commands.NewIntOptionBuilder()).MinValue(5).MaxValue(1.5).Autocomplete(true).
+					Choices(commands.NewStringOptionChoice("tank", "tank", nil))),
+			wantErr: []string{"greater than maximum", "not a whole number", "combined with autocomplete", "does not match"},
+		},
+		{
+			name: "required option after optional option",
+			builder: info().
+				Option(option(commands.NewStringOptionBuilder())).
+				Option(option(commands.NewStringOptionBuilder()).Name("name", nil).Required(true)),
+			wantErr: []string{`required option "name"`},
+		},
+		{
+			name: "options mixed with subcommands",
+			builder: info().
+				SubCommand(subcommand("list")).
+				Option(option(commands.NewStringOptionBuilder())),
+			wantErr: []string{"cannot be combined with subcommands"},
+		},
+		{
+			name: "errors of nested subcommands",
+			builder: info().
+				SubCommandGroup(commands.NewSubCommandGroupBuilder().
+					Name("signup", nil).
+					Description("Manage signups.", nil).
+					SubCommand(subcommand("add").Option(option(commands.NewUserOptionBuilder()).Description("", nil))),
+				),
+			wantErr: []string{`subcommand group "signup"`, `subcommand "add"`, `option "value"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.builder.Build()
+			if (err != nil) != (len(tt.wantErr) > 0) {
+				t.Fatalf("Build() error = %v, want error: %v", err, len(tt.wantErr) > 0)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Build() error = %v, want it to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCollection_Infos(t *testing.T) {
+	h := commandstest.New(t, commandstest.Services{})
+	infos, err := h.Commands.Infos()
+	if err != nil {
+		t.Fatalf("Infos() error = %v", err)
+	}
+	want := len(h.Commands.ApplicationInteractionCommands()) + len(h.Commands.ContextMenuCommands())
+	if len(infos) != want {
+		t.Errorf("Infos() returned %d infos, want %d", len(infos), want)
+	}
+}
@@ -0,0 +1,112 @@
+package commands_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands/commandstest"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands/customid"
+)
+
+// want are the expected responses of a command.
+type want struct {
+	// responded is whether an initial response is expected.
+	responded bool
+	// deferred is whether a deferred response is expected.
+	deferred bool
+	// ephemeral is whether the response should only be visible to the invoking user.
+	ephemeral bool
+	// content is the expected content of the response.
+	content string
+	// embeds are the expected titles of the embeds of the response.
+	embeds []string
+	// modal is the expected route of the custom ID of the modal the command responds with, e.g. "guild:edit:2".
+	modal string
+	// rejected is whether the command is expected to send a response Discord rejects.
+	rejected bool
+	// suggestions are the expected values of the autocomplete choices.
+	suggestions []string
+}
+
+var (
+	// errBoom is an unexpected error of a service.
+	errBoom = errors.New("boom")
+	// manager grants the invoking member the permission to manage the server.
+	manager = commandstest.WithPermissions(discord.PermissionManageGuild)
+)
+
+// commandTest is a test case of an interaction handled by the commands.
+type commandTest struct {
+	// name is the name of the test case.
+	name string
+	// services are the stubbed services of the commands.
+	services commandstest.Services
+	// opts configure the harness.
+	opts []commandstest.Option
+	// run sends the interaction and returns the recorded responses.
+	run func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder
+	// want are the expected responses.
+	want want
+	// check checks the responses further, it is optional.
+	check func(t *testing.T, h *commandstest.Harness, rec *commandstest.Recorder)
+}
+
+// runCommandTests runs every test case against a new harness and checks the recorded responses.
+func runCommandTests(t *testing.T, tests []commandTest) {
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := commandstest.New(t, tt.services, tt.opts...)
+			rec := tt.run(t.Context(), h)
+
+			if err := rec.Err(); (err != nil) != tt.want.rejected {
+				t.Errorf("Err() = %v, want rejected responses: %v", err, tt.want.rejected)
+			}
+			if got := rec.Responded(); got != tt.want.responded {
+				t.Errorf("Responded() = %v, want %v", got, tt.want.responded)
+			}
+			if got := rec.Deferred(); got != tt.want.deferred {
+				t.Errorf("Deferred() = %v, want %v", got, tt.want.deferred)
+			}
+			if got := rec.Ephemeral(); got != tt.want.ephemeral {
+				t.Errorf("Ephemeral() = %v, want %v", got, tt.want.ephemeral)
+			}
+			if got := rec.Content(); got != tt.want.content {
+				t.Errorf("Content() = %q, want %q", got, tt.want.content)
+			}
+
+			embeds := rec.Embeds()
+			if len(embeds) != len(tt.want.embeds) {
+				t.Fatalf("got %d embeds, want %d", len(embeds), len(tt.want.embeds))
+			}
+			for i, title := range tt.want.embeds {
+				if embeds[i].Title != title {
+					t.Errorf("embed %d has title %q, want %q", i, embeds[i].Title, title)
+				}
+			}
+
+			if got := rec.Suggestions(); !slices.Equal(got, tt.want.suggestions) {
+				t.Errorf("Suggestions() = %q, want %q", got, tt.want.suggestions)
+			}
+
+			var route string
+			if modal, ok := rec.Modal(); ok {
+				segments, err := h.Commands.CustomIDs().Decode(t.Context(), modal.CustomID)
+				if err != nil {
+					t.Fatalf("Modal() has an invalid custom ID %q: %v", modal.CustomID, err)
+				}
+				route = strings.Join(segments, customid.Separator)
+			}
+			if route != tt.want.modal {
+				t.Errorf("Modal() = %q, want %q", route, tt.want.modal)
+			}
+
+			if tt.check != nil {
+				tt.check(t, h, rec)
+			}
+		})
+	}
+}
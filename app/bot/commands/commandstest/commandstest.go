@@ -0,0 +1,176 @@
+// Package commandstest provides utilities to test interaction commands without Discord.
+//
+// A [Harness] builds synthetic slash command, component and modal submit events,
+// runs them against a command collection backed by stub services and captures
+// everything the command responds with: the initial interaction response as well
+// as follow-up messages and edits sent via the REST API.
+//
+// Example:
+//
+//	h := commandstest.New(t, commandstest.Services{Guild: &commandstest.GuildService{
+//		GetReportsFunc: func(context.Context, snowflake.ID, string) (*guild.Reports, error) {
+//			return &guild.Reports{Reports: []guild.Report{{Title: "Nerub-ar Palace"}}}, nil
+//		},
+//	}})
+//	rec := h.Slash(ctx, "logs", commandstest.Options{"date": "2024-05-07"})
+//	if got := rec.Embeds()[0].Title; got != "Nerub-ar Palace" {
+//		t.Errorf("unexpected title %q", got)
+//	}
+package commandstest
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/disgo"
+	disbot "github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/cache"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands"
+	"github.com/lvlcn-t/raid-mate/app/services"
+)
+
+const (
+	// ApplicationID is the ID of the fake application.
+	ApplicationID snowflake.ID = 100000000000000001
+	// GuildID is the ID of the guild events are created in by default.
+	GuildID snowflake.ID = 200000000000000002
+	// ChannelID is the ID of the channel events are created in by default.
+	ChannelID snowflake.ID = 300000000000000003
+	// UserID is the ID of the user invoking the events by default.
+	UserID snowflake.ID = 400000000000000004
+	// Username is the name of the user invoking the events by default.
+	Username = "tester"
+)
+
+// Services are the services the commands under test are run against.
+// Nil services are replaced with stubs that fail every call.
+type Services struct {
+	// Guild is the guild service.
+	Guild *GuildService
+	// Feedback is the feedback service.
+	Feedback *FeedbackService
+	// Character is the character service.
+	Character *CharacterService
+	// State is the state service.
+	// If nil, a stub keeping the state in memory is used.
+	State *StateService
+}
+
+// Option configures the commands under test.
+type Option func(*commands.Config)
+
+// WithDeferAfter sets the duration after which commands are deferred automatically.
+func WithDeferAfter(d time.Duration) Option {
+	return func(c *commands.Config) {
+		c.DeferAfter = d
+	}
+}
+
+// WithPageTimeout sets the duration after which the navigation buttons of paginated messages expire.
+func WithPageTimeout(d time.Duration) Option {
+	return func(c *commands.Config) {
+		c.PageTimeout = d
+	}
+}
+
+// WithCustomIDSecret sets the key custom IDs are signed with.
+func WithCustomIDSecret(secret string) Option {
+	return func(c *commands.Config) {
+		c.CustomIDs.Secret = secret
+	}
+}
+
+// Harness runs interaction commands without Discord.
+type Harness struct {
+	// t is the test the harness is used in.
+	t testing.TB
+	// Client is the bot client passed to the events.
+	// Its REST client talks to a fake Discord API that records all requests.
+	Client disbot.Client
+	// Commands is the collection of commands under test.
+	Commands *commands.Collection
+	// Services are the stub services the commands are run against.
+	Services Services
+	// discord is the fake Discord API.
+	discord *fakeDiscord
+	// nextID is used to generate unique snowflakes.
+	nextID atomic.Int64
+}
+
+// New creates a new test harness.
+// All resources of the harness are released when the test finishes.
+func New(t testing.TB, svcs Services, opts ...Option) *Harness {
+	t.Helper()
+	if svcs.Guild == nil {
+		svcs.Guild = &GuildService{}
+	}
+	if svcs.Feedback == nil {
+		svcs.Feedback = &FeedbackService{}
+	}
+	if svcs.Character == nil {
+		svcs.Character = &CharacterService{}
+	}
+	if svcs.State == nil {
+		svcs.State = &StateService{}
+	}
+
+	fd := newFakeDiscord()
+	srv := httptest.NewServer(fd)
+	t.Cleanup(srv.Close)
+
+	client, err := disgo.New(token(ApplicationID),
+		disbot.WithRestClientConfigOpts(rest.WithURL(srv.URL)),
+		disbot.WithCacheConfigOpts(cache.WithCaches(cache.FlagsAll)),
+	)
+	if err != nil {
+		t.Fatalf("failed to create discord client: %v", err)
+	}
+	t.Cleanup(func() { client.Close(t.Context()) })
+
+	var cfg commands.Config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	h := &Harness{
+		t:      t,
+		Client: client,
+		Commands: commands.NewCollection(&cfg, &services.Collection{
+			Character: svcs.Character,
+			Guild:     svcs.Guild,
+			Feedback:  svcs.Feedback,
+			State:     svcs.State,
+		}),
+		Services: svcs,
+		discord:  fd,
+	}
+	h.nextID.Store(int64(ApplicationID) + 1)
+	return h
+}
+
+// AddGuild adds the guild to the cache of the client, so events created in it can resolve it.
+func (h *Harness) AddGuild(guild discord.Guild) {
+	h.Client.Caches().AddGuild(guild)
+}
+
+// ChannelMessages returns the messages sent to the given channel via the REST API.
+func (h *Harness) ChannelMessages(channelID snowflake.ID) []discord.MessageCreate {
+	return h.discord.channelMessages(channelID)
+}
+
+// id returns a new unique snowflake.
+func (h *Harness) id() snowflake.ID {
+	return snowflake.ID(h.nextID.Add(1)) //nolint:gosec // The counter starts at a positive value and only increases.
+}
+
+// token returns a syntactically valid bot token for the given application.
+func token(id snowflake.ID) string {
+	return fmt.Sprintf("%s.fake.token", base64.RawStdEncoding.EncodeToString([]byte(id.String())))
+}
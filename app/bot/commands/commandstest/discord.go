@@ -0,0 +1,250 @@
+package commandstest
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// Discord API error codes returned by the fake Discord API.
+const (
+	// codeUnknownWebhook is returned when a follow-up is sent for an unknown or unacknowledged interaction.
+	codeUnknownWebhook = 10015
+	// codeUnknownMessage is returned when the original response is edited before it was created.
+	codeUnknownMessage = 10008
+	// codeInvalidBody is returned when the request body cannot be decoded.
+	codeInvalidBody = 50109
+)
+
+// fakeDiscord is a fake of the Discord REST API endpoints used by the commands.
+// Interaction webhooks are routed to the recorder registered for the interaction token.
+type fakeDiscord struct {
+	// mux is the router of the fake API.
+	mux *http.ServeMux
+	// nextID is used to generate unique snowflakes for created resources.
+	nextID atomic.Int64
+	// mu guards the fields below.
+	mu sync.Mutex
+	// recorders are the recorders of the synthetic interactions mapped by their token.
+	recorders map[string]*Recorder
+	// messages are the messages sent to channels mapped by the channel ID.
+	messages map[snowflake.ID][]discord.MessageCreate
+}
+
+// newFakeDiscord creates a new fake Discord API.
+func newFakeDiscord() *fakeDiscord {
+	fd := &fakeDiscord{
+		mux:       http.NewServeMux(),
+		recorders: map[string]*Recorder{},
+		messages:  map[snowflake.ID][]discord.MessageCreate{},
+	}
+	fd.nextID.Store(int64(UserID) * 2) //nolint:mnd // Keeps created IDs apart from the fixed ones.
+
+	fd.mux.HandleFunc("PATCH /webhooks/{app}/{token}/messages/@original", fd.editOriginal)
+	fd.mux.HandleFunc("DELETE /webhooks/{app}/{token}/messages/@original", fd.deleteOriginal)
+	fd.mux.HandleFunc("POST /webhooks/{app}/{token}", fd.createFollowup)
+	fd.mux.HandleFunc("POST /channels/{channel}/messages", fd.createMessage)
+	fd.mux.HandleFunc("POST /users/@me/channels", fd.createDMChannel)
+	return fd
+}
+
+// ServeHTTP serves the fake API.
+func (fd *fakeDiscord) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fd.mux.ServeHTTP(w, r)
+}
+
+// register registers the recorder for the interaction token.
+func (fd *fakeDiscord) register(token string, rec *Recorder) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+	fd.recorders[token] = rec
+}
+
+// recorder returns the recorder for the interaction token of the request.
+func (fd *fakeDiscord) recorder(r *http.Request) (*Recorder, bool) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+	rec, ok := fd.recorders[r.PathValue("token")]
+	return rec, ok
+}
+
+// channelMessages returns the messages sent to the channel.
+func (fd *fakeDiscord) channelMessages(channelID snowflake.ID) []discord.MessageCreate {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+	return append([]discord.MessageCreate(nil), fd.messages[channelID]...)
+}
+
+// editOriginal records an edit of the original interaction response.
+func (fd *fakeDiscord) editOriginal(w http.ResponseWriter, r *http.Request) {
+	rec, ok := fd.recorder(r)
+	if !ok || !rec.acknowledged() {
+		writeError(w, http.StatusNotFound, codeUnknownMessage, "Unknown Message")
+		return
+	}
+
+	var body message
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidBody, err.Error())
+		return
+	}
+
+	mu := discord.MessageUpdate{Content: body.Content, Embeds: body.Embeds, Flags: body.Flags}
+	if body.Components != nil {
+		mu.Components = toPtr(body.containers())
+	}
+	rec.edit(mu)
+
+	msg := rec.Message()
+	fd.writeMessage(w, ChannelID, msg.Content, msg.Embeds)
+}
+
+// deleteOriginal records the deletion of the original interaction response.
+func (fd *fakeDiscord) deleteOriginal(w http.ResponseWriter, r *http.Request) {
+	rec, ok := fd.recorder(r)
+	if !ok || !rec.acknowledged() || rec.Deleted() {
+		writeError(w, http.StatusNotFound, codeUnknownMessage, "Unknown Message")
+		return
+	}
+	rec.delete()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// createFollowup records a follow-up message of an interaction.
+func (fd *fakeDiscord) createFollowup(w http.ResponseWriter, r *http.Request) {
+	rec, ok := fd.recorder(r)
+	if !ok || !rec.acknowledged() {
+		writeError(w, http.StatusNotFound, codeUnknownWebhook, "Unknown Webhook")
+		return
+	}
+
+	mc, ok := decodeMessageCreate(w, r)
+	if !ok {
+		return
+	}
+	rec.followup(mc)
+	fd.writeMessage(w, ChannelID, mc.Content, mc.Embeds)
+}
+
+// createMessage records a message sent to a channel.
+func (fd *fakeDiscord) createMessage(w http.ResponseWriter, r *http.Request) {
+	channelID, err := snowflake.Parse(r.PathValue("channel"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, codeUnknownWebhook, "Unknown Channel")
+		return
+	}
+
+	mc, ok := decodeMessageCreate(w, r)
+	if !ok {
+		return
+	}
+	fd.mu.Lock()
+	fd.messages[channelID] = append(fd.messages[channelID], mc)
+	fd.mu.Unlock()
+	fd.writeMessage(w, channelID, mc.Content, mc.Embeds)
+}
+
+// createDMChannel opens a direct message channel with the requested user.
+// The ID of the channel equals the ID of the user, so tests can look up the sent messages easily.
+func (fd *fakeDiscord) createDMChannel(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		RecipientID snowflake.ID `json:"recipient_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidBody, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"id":         body.RecipientID,
+		"type":       discord.ChannelTypeDM,
+		"recipients": []discord.User{{ID: body.RecipientID, Username: Username}},
+	})
+}
+
+// writeMessage writes a created message as response.
+func (fd *fakeDiscord) writeMessage(w http.ResponseWriter, channelID snowflake.ID, content string, embeds []discord.Embed) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"id":         snowflake.ID(fd.nextID.Add(1)), //nolint:gosec // The counter starts at a positive value and only increases.
+		"type":       discord.MessageTypeDefault,
+		"channel_id": channelID,
+		"content":    content,
+		"embeds":     embeds,
+		"author":     discord.User{ID: ApplicationID, Username: "raid-mate", Bot: true},
+		"timestamp":  time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// message is the body of a message create or update request.
+// Components are decoded separately since they are interfaces.
+type message struct {
+	// Content is the content of the message.
+	Content *string `json:"content"`
+	// Embeds are the embeds of the message.
+	Embeds *[]discord.Embed `json:"embeds"`
+	// Components are the components of the message.
+	Components *[]discord.UnmarshalComponent `json:"components"`
+	// Flags are the flags of the message.
+	Flags *discord.MessageFlags `json:"flags"`
+}
+
+// containers returns the top level components of the message.
+func (m *message) containers() []discord.ContainerComponent {
+	var res []discord.ContainerComponent
+	for _, c := range *m.Components {
+		if cc, ok := c.Component.(discord.ContainerComponent); ok {
+			res = append(res, cc)
+		}
+	}
+	return res
+}
+
+// decodeMessageCreate decodes the message of the request.
+// If the body is invalid, an error response is written and false is returned.
+func decodeMessageCreate(w http.ResponseWriter, r *http.Request) (discord.MessageCreate, bool) {
+	var body message
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidBody, err.Error())
+		return discord.MessageCreate{}, false
+	}
+
+	var mc discord.MessageCreate
+	if body.Content != nil {
+		mc.Content = *body.Content
+	}
+	if body.Embeds != nil {
+		mc.Embeds = *body.Embeds
+	}
+	if body.Components != nil {
+		mc.Components = body.containers()
+	}
+	if body.Flags != nil {
+		mc.Flags = *body.Flags
+	}
+	return mc, true
+}
+
+// writeError writes a Discord API error.
+func writeError(w http.ResponseWriter, status, code int, msg string) {
+	writeJSON(w, status, map[string]any{
+		"code":    code,
+		"message": msg,
+	})
+}
+
+// writeJSON writes v as JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// toPtr returns a pointer to the given value.
+func toPtr[T any](v T) *T {
+	return &v
+}
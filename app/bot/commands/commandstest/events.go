@@ -0,0 +1,431 @@
+package commandstest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands/customid"
+)
+
+// Options are the options of a slash command mapped by their name.
+// Strings, integers, booleans and floats are sent as the respective option type.
+type Options map[string]any
+
+// EventOption configures a synthetic interaction.
+type EventOption func(*interaction)
+
+// InGuild sets the guild the interaction happened in.
+func InGuild(id snowflake.ID) EventOption {
+	return func(i *interaction) {
+		i.GuildID = &id
+	}
+}
+
+// InDM makes the interaction happen in a direct message instead of a guild.
+func InDM() EventOption {
+	return func(i *interaction) {
+		i.GuildID = nil
+		i.Context = discord.InteractionContextTypeBotDM
+	}
+}
+
+// InChannel sets the channel the interaction happened in.
+func InChannel(id snowflake.ID) EventOption {
+	return func(i *interaction) {
+		i.ChannelID = id
+	}
+}
+
+// AsUser sets the user invoking the interaction.
+func AsUser(id snowflake.ID, username string) EventOption {
+	return func(i *interaction) {
+		i.user.ID = id
+		i.user.Username = username
+	}
+}
+
+// WithLocale sets the locale of the user invoking the interaction.
+func WithLocale(locale discord.Locale) EventOption {
+	return func(i *interaction) {
+		i.Locale = locale
+	}
+}
+
+// WithPermissions sets the permissions of the member invoking the interaction.
+func WithPermissions(perms discord.Permissions) EventOption {
+	return func(i *interaction) {
+		i.permissions = perms
+	}
+}
+
+// interaction is the raw payload of a synthetic interaction as Discord sends it.
+type interaction struct {
+	// ID is the ID of the interaction.
+	ID snowflake.ID `json:"id"`
+	// Type is the type of the interaction.
+	Type discord.InteractionType `json:"type"`
+	// ApplicationID is the ID of the application the interaction is for.
+	ApplicationID snowflake.ID `json:"application_id"`
+	// Token is the token to respond to the interaction.
+	Token string `json:"token"`
+	// Version is always 1.
+	Version int `json:"version"`
+	// GuildID is the ID of the guild the interaction happened in.
+	GuildID *snowflake.ID `json:"guild_id,omitempty"`
+	// ChannelID is the ID of the channel the interaction happened in.
+	ChannelID snowflake.ID `json:"channel_id"`
+	// Locale is the locale of the invoking user.
+	Locale discord.Locale `json:"locale,omitempty"`
+	// Member is the invoking member if the interaction happened in a guild.
+	Member *discord.ResolvedMember `json:"member,omitempty"`
+	// User is the invoking user if the interaction happened in a direct message.
+	User *discord.User `json:"user,omitempty"`
+	// Context is the context the interaction was triggered from.
+	Context discord.InteractionContextType `json:"context"`
+	// Data is the type specific data of the interaction.
+	Data any `json:"data"`
+
+	// user is the invoking user.
+	user discord.User
+	// permissions are the permissions of the invoking member.
+	permissions discord.Permissions
+}
+
+// newInteraction creates the raw payload of a synthetic interaction.
+func (h *Harness) newInteraction(typ discord.InteractionType, data any, opts ...EventOption) *interaction {
+	guildID := GuildID
+	i := &interaction{
+		ID:            h.id(),
+		Type:          typ,
+		ApplicationID: ApplicationID,
+		Version:       1,
+		GuildID:       &guildID,
+		ChannelID:     ChannelID,
+		Locale:        discord.LocaleEnglishUS,
+		Context:       discord.InteractionContextTypeGuild,
+		Data:          data,
+		user:          discord.User{ID: UserID, Username: Username},
+		permissions:   discord.PermissionsNone,
+	}
+	for _, opt := range opts {
+		opt(i)
+	}
+	i.Token = fmt.Sprintf("token-%d", i.ID)
+
+	if i.GuildID != nil {
+		i.User = nil
+		i.Member = &discord.ResolvedMember{
+			Member: discord.Member{
+				User:    i.user,
+				GuildID: *i.GuildID,
+			},
+			Permissions: i.permissions,
+		}
+	} else {
+		i.User = &i.user
+	}
+	return i
+}
+
+// unmarshal decodes the payload into the disgo representation of the interaction.
+func (h *Harness) unmarshal(i *interaction) discord.Interaction {
+	h.t.Helper()
+	b, err := json.Marshal(i)
+	if err != nil {
+		h.t.Fatalf("failed to encode interaction: %v", err)
+	}
+	res, err := discord.UnmarshalInteraction(b)
+	if err != nil {
+		h.t.Fatalf("failed to decode interaction: %v", err)
+	}
+	return res
+}
+
+// slashData returns the data of a slash command interaction with the given options.
+// The name may contain the names of a subcommand group and subcommand separated by spaces,
+// e.g. "settings edit", in which case the options are nested into them.
+func (h *Harness) slashData(name string, options []map[string]any) map[string]any {
+	path := strings.Fields(name)
+	for i := len(path) - 1; i > 0; i-- {
+		typ := discord.ApplicationCommandOptionTypeSubCommand
+		if i < len(path)-1 {
+			typ = discord.ApplicationCommandOptionTypeSubCommandGroup
+		}
+		options = []map[string]any{{"name": path[i], "type": typ, "options": options}}
+	}
+
+	return map[string]any{
+		"id":      h.id(),
+		"type":    discord.ApplicationCommandTypeSlash,
+		"name":    path[0],
+		"options": options,
+	}
+}
+
+// SlashEvent creates a synthetic slash command event and a recorder for its responses.
+// Subcommands are addressed by their full name, e.g. "settings edit".
+func (h *Harness) SlashEvent(name string, options Options, opts ...EventOption) (*events.ApplicationCommandInteractionCreate, *Recorder) {
+	h.t.Helper()
+	names := make([]string, 0, len(options))
+	for n := range options {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	opt := make([]map[string]any, 0, len(options))
+	for _, n := range names {
+		opt = append(opt, map[string]any{
+			"name":  n,
+			"type":  h.optionType(n, options[n]),
+			"value": options[n],
+		})
+	}
+
+	i := h.newInteraction(discord.InteractionTypeApplicationCommand, h.slashData(name, opt), opts...)
+	rec := h.newRecorder(i.Token)
+	return &events.ApplicationCommandInteractionCreate{
+		GenericEvent:                  events.NewGenericEvent(h.Client, 0, 0),
+		ApplicationCommandInteraction: h.unmarshal(i).(discord.ApplicationCommandInteraction),
+		Respond:                       rec.respond,
+	}, rec
+}
+
+// AutocompleteEvent creates a synthetic autocomplete event for the focused option of the slash command
+// with the given name and a recorder for its responses.
+func (h *Harness) AutocompleteEvent(name string, options Options, focused string, opts ...EventOption) (*events.AutocompleteInteractionCreate, *Recorder) {
+	h.t.Helper()
+	names := make([]string, 0, len(options))
+	for n := range options {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	opt := make([]map[string]any, 0, len(options))
+	for _, n := range names {
+		opt = append(opt, map[string]any{
+			"name":    n,
+			"type":    h.optionType(n, options[n]),
+			"value":   options[n],
+			"focused": n == focused,
+		})
+	}
+
+	i := h.newInteraction(discord.InteractionTypeAutocomplete, h.slashData(name, opt), opts...)
+	rec := h.newRecorder(i.Token)
+	return &events.AutocompleteInteractionCreate{
+		GenericEvent:            events.NewGenericEvent(h.Client, 0, 0),
+		AutocompleteInteraction: h.unmarshal(i).(discord.AutocompleteInteraction),
+		Respond:                 rec.respond,
+	}, rec
+}
+
+// UserCommandEvent creates a synthetic event for the user context menu command with the given name used on the target user
+// and a recorder for its responses.
+func (h *Harness) UserCommandEvent(name string, target discord.User, opts ...EventOption) (*events.ApplicationCommandInteractionCreate, *Recorder) {
+	h.t.Helper()
+	i := h.newInteraction(discord.InteractionTypeApplicationCommand, map[string]any{
+		"id":        h.id(),
+		"type":      discord.ApplicationCommandTypeUser,
+		"name":      name,
+		"target_id": target.ID,
+		"resolved": map[string]any{
+			"users": map[snowflake.ID]discord.User{target.ID: target},
+		},
+	}, opts...)
+	rec := h.newRecorder(i.Token)
+	return &events.ApplicationCommandInteractionCreate{
+		GenericEvent:                  events.NewGenericEvent(h.Client, 0, 0),
+		ApplicationCommandInteraction: h.unmarshal(i).(discord.ApplicationCommandInteraction),
+		Respond:                       rec.respond,
+	}, rec
+}
+
+// MessageCommandEvent creates a synthetic event for the message context menu command with the given name used on the target message
+// and a recorder for its responses.
+func (h *Harness) MessageCommandEvent(name string, target discord.Message, opts ...EventOption) (*events.ApplicationCommandInteractionCreate, *Recorder) {
+	h.t.Helper()
+	i := h.newInteraction(discord.InteractionTypeApplicationCommand, map[string]any{
+		"id":        h.id(),
+		"type":      discord.ApplicationCommandTypeMessage,
+		"name":      name,
+		"target_id": target.ID,
+		"resolved": map[string]any{
+			"messages": map[snowflake.ID]discord.Message{target.ID: target},
+		},
+	}, opts...)
+	rec := h.newRecorder(i.Token)
+	return &events.ApplicationCommandInteractionCreate{
+		GenericEvent:                  events.NewGenericEvent(h.Client, 0, 0),
+		ApplicationCommandInteraction: h.unmarshal(i).(discord.ApplicationCommandInteraction),
+		Respond:                       rec.respond,
+	}, rec
+}
+
+// ComponentEvent creates a synthetic button click event and a recorder for its responses.
+func (h *Harness) ComponentEvent(customID string, opts ...EventOption) (*events.ComponentInteractionCreate, *Recorder) {
+	h.t.Helper()
+	i := h.newInteraction(discord.InteractionTypeComponent, map[string]any{
+		"component_type": discord.ComponentTypeButton,
+		"custom_id":      customID,
+	}, opts...)
+	rec := h.newRecorder(i.Token)
+	return &events.ComponentInteractionCreate{
+		GenericEvent:         events.NewGenericEvent(h.Client, 0, 0),
+		ComponentInteraction: h.unmarshal(i).(discord.ComponentInteraction),
+		Respond:              rec.respond,
+	}, rec
+}
+
+// SelectEvent creates a synthetic select menu event of the given type with the selected values and a recorder for its responses.
+func (h *Harness) SelectEvent(customID string, typ discord.ComponentType, values []string, opts ...EventOption) (*events.ComponentInteractionCreate, *Recorder) {
+	h.t.Helper()
+	i := h.newInteraction(discord.InteractionTypeComponent, map[string]any{
+		"component_type": typ,
+		"custom_id":      customID,
+		"values":         values,
+	}, opts...)
+	rec := h.newRecorder(i.Token)
+	return &events.ComponentInteractionCreate{
+		GenericEvent:         events.NewGenericEvent(h.Client, 0, 0),
+		ComponentInteraction: h.unmarshal(i).(discord.ComponentInteraction),
+		Respond:              rec.respond,
+	}, rec
+}
+
+// ModalEvent creates a synthetic modal submit event with the given text input values and a recorder for its responses.
+func (h *Harness) ModalEvent(customID string, values map[string]string, opts ...EventOption) (*events.ModalSubmitInteractionCreate, *Recorder) {
+	h.t.Helper()
+	ids := make([]string, 0, len(values))
+	for id := range values {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	rows := make([]map[string]any, 0, len(values))
+	for _, id := range ids {
+		rows = append(rows, map[string]any{
+			"type": discord.ComponentTypeActionRow,
+			"components": []map[string]any{{
+				"type":      discord.ComponentTypeTextInput,
+				"custom_id": id,
+				"value":     values[id],
+			}},
+		})
+	}
+
+	i := h.newInteraction(discord.InteractionTypeModalSubmit, map[string]any{
+		"custom_id":  customID,
+		"components": rows,
+	}, opts...)
+	rec := h.newRecorder(i.Token)
+	return &events.ModalSubmitInteractionCreate{
+		GenericEvent:           events.NewGenericEvent(h.Client, 0, 0),
+		ModalSubmitInteraction: h.unmarshal(i).(discord.ModalSubmitInteraction),
+		Respond:                rec.respond,
+	}, rec
+}
+
+// Slash dispatches the slash command with the given name and options and returns the recorded responses.
+// Subcommands are addressed by their full name, e.g. "settings edit".
+func (h *Harness) Slash(ctx context.Context, name string, options Options, opts ...EventOption) *Recorder {
+	h.t.Helper()
+	if h.Commands.GetAppCommand(strings.Fields(name)[0]) == nil {
+		h.t.Fatalf("no application command named %q", name)
+	}
+	event, rec := h.SlashEvent(name, options, opts...)
+	h.Commands.HandleApplicationCommand(ctx, event)
+	return rec
+}
+
+// Autocomplete dispatches the autocomplete interaction for the focused option of the slash command
+// with the given name and returns the recorded responses.
+func (h *Harness) Autocomplete(ctx context.Context, name string, options Options, focused string, opts ...EventOption) *Recorder {
+	h.t.Helper()
+	if _, ok := options[focused]; !ok {
+		h.t.Fatalf("focused option %q is missing from the options", focused)
+	}
+	event, rec := h.AutocompleteEvent(name, options, focused, opts...)
+	h.Commands.HandleAutocomplete(ctx, event)
+	return rec
+}
+
+// UserCommand dispatches the user context menu command with the given name on the target user and returns the recorded responses.
+func (h *Harness) UserCommand(ctx context.Context, name string, target discord.User, opts ...EventOption) *Recorder {
+	h.t.Helper()
+	if h.Commands.GetContextMenuCommand(discord.ApplicationCommandTypeUser, name) == nil {
+		h.t.Fatalf("no user context menu command named %q", name)
+	}
+	event, rec := h.UserCommandEvent(name, target, opts...)
+	h.Commands.HandleApplicationCommand(ctx, event)
+	return rec
+}
+
+// MessageCommand dispatches the message context menu command with the given name on the target message and returns the recorded responses.
+func (h *Harness) MessageCommand(ctx context.Context, name string, target discord.Message, opts ...EventOption) *Recorder {
+	h.t.Helper()
+	if h.Commands.GetContextMenuCommand(discord.ApplicationCommandTypeMessage, name) == nil {
+		h.t.Fatalf("no message context menu command named %q", name)
+	}
+	event, rec := h.MessageCommandEvent(name, target, opts...)
+	h.Commands.HandleApplicationCommand(ctx, event)
+	return rec
+}
+
+// Component dispatches the button click with the given custom ID and returns the recorded responses.
+// Custom IDs that are invalid or match no command are answered with an error by the collection.
+func (h *Harness) Component(ctx context.Context, customID string, opts ...EventOption) *Recorder {
+	h.t.Helper()
+	event, rec := h.ComponentEvent(customID, opts...)
+	h.Commands.HandleComponent(ctx, event)
+	return rec
+}
+
+// Select dispatches the selection of the values in the select menu with the given custom ID and returns the recorded responses.
+func (h *Harness) Select(ctx context.Context, customID string, typ discord.ComponentType, values []string, opts ...EventOption) *Recorder {
+	h.t.Helper()
+	event, rec := h.SelectEvent(customID, typ, values, opts...)
+	h.Commands.HandleComponent(ctx, event)
+	return rec
+}
+
+// Modal dispatches the submission of the modal with the given custom ID and text input values and returns the recorded responses.
+// Custom IDs that are invalid or match no command are answered with an error by the collection.
+func (h *Harness) Modal(ctx context.Context, customID string, values map[string]string, opts ...EventOption) *Recorder {
+	h.t.Helper()
+	event, rec := h.ModalEvent(customID, values, opts...)
+	h.Commands.HandleModalSubmit(ctx, event)
+	return rec
+}
+
+// CustomID returns the signed custom ID for the route and arguments as the commands under test encode it.
+func (h *Harness) CustomID(route string, args ...customid.Arg) string {
+	h.t.Helper()
+	id, err := h.Commands.CustomIDs().Encode(h.t.Context(), route, args...)
+	if err != nil {
+		h.t.Fatalf("failed to encode custom ID: %v", err)
+	}
+	return id
+}
+
+// optionType returns the Discord option type for the value of the named option.
+func (h *Harness) optionType(name string, v any) discord.ApplicationCommandOptionType {
+	switch v.(type) {
+	case string:
+		return discord.ApplicationCommandOptionTypeString
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return discord.ApplicationCommandOptionTypeInt
+	case bool:
+		return discord.ApplicationCommandOptionTypeBool
+	case float32, float64:
+		return discord.ApplicationCommandOptionTypeFloat
+	default:
+		h.t.Fatalf("unsupported type %T of option %q", v, name)
+		return 0
+	}
+}
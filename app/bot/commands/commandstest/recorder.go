@@ -0,0 +1,267 @@
+package commandstest
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+)
+
+// ErrAlreadyAcknowledged is returned when a command responds to an interaction more than once.
+// Discord rejects every initial response after the first one, follow-ups have to be sent via the REST API.
+var ErrAlreadyAcknowledged = errors.New("interaction has already been acknowledged")
+
+// Response is an initial response to an interaction.
+type Response struct {
+	// Type is the type of the response.
+	Type discord.InteractionResponseType
+	// Data is the data of the response.
+	Data discord.InteractionResponseData
+}
+
+// Recorder records all responses to a single synthetic interaction.
+type Recorder struct {
+	// mu guards the fields below.
+	mu sync.Mutex
+	// responses are the initial responses the command tried to send, including rejected ones.
+	responses []Response
+	// errs are the errors returned to the command for rejected responses.
+	errs []error
+	// edits are the edits of the original response sent via the REST API.
+	edits []discord.MessageUpdate
+	// followups are the follow-up messages sent via the REST API.
+	followups []discord.MessageCreate
+	// deleted is whether the original response was deleted via the REST API.
+	deleted bool
+}
+
+// newRecorder creates a new recorder and registers it for the interaction token at the fake Discord API.
+func (h *Harness) newRecorder(token string) *Recorder {
+	rec := &Recorder{}
+	h.discord.register(token, rec)
+	return rec
+}
+
+// respond records an initial response. It is used as the responder of the synthetic events.
+func (r *Recorder) respond(typ discord.InteractionResponseType, data discord.InteractionResponseData, _ ...rest.RequestOpt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.responses = append(r.responses, Response{Type: typ, Data: data})
+	if len(r.responses) > 1 {
+		r.errs = append(r.errs, ErrAlreadyAcknowledged)
+		return ErrAlreadyAcknowledged
+	}
+	return nil
+}
+
+// acknowledged reports whether the interaction received an initial response.
+func (r *Recorder) acknowledged() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.responses) > 0
+}
+
+// edit records an edit of the original response.
+func (r *Recorder) edit(mu discord.MessageUpdate) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.edits = append(r.edits, mu)
+}
+
+// delete records the deletion of the original response.
+func (r *Recorder) delete() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deleted = true
+}
+
+// followup records a follow-up message.
+func (r *Recorder) followup(mc discord.MessageCreate) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.followups = append(r.followups, mc)
+}
+
+// Responses returns all initial responses the command tried to send, including rejected ones.
+func (r *Recorder) Responses() []Response {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Response(nil), r.responses...)
+}
+
+// Err returns the errors of all responses Discord would have rejected.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return errors.Join(r.errs...)
+}
+
+// initial returns the accepted initial response.
+func (r *Recorder) initial() (Response, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.responses) == 0 {
+		return Response{}, false
+	}
+	return r.responses[0], true
+}
+
+// Responded reports whether the command sent an initial response.
+func (r *Recorder) Responded() bool {
+	return r.acknowledged()
+}
+
+// Deferred reports whether the command deferred its response.
+func (r *Recorder) Deferred() bool {
+	resp, ok := r.initial()
+	return ok && (resp.Type == discord.InteractionResponseTypeDeferredCreateMessage ||
+		resp.Type == discord.InteractionResponseTypeDeferredUpdateMessage)
+}
+
+// Ephemeral reports whether the original response is only visible to the invoking user.
+func (r *Recorder) Ephemeral() bool {
+	resp, ok := r.initial()
+	if !ok {
+		return false
+	}
+	mc, ok := resp.Data.(discord.MessageCreate)
+	return ok && mc.Flags.Has(discord.MessageFlagEphemeral)
+}
+
+// Modal returns the modal the command responded with.
+func (r *Recorder) Modal() (discord.ModalCreate, bool) {
+	resp, ok := r.initial()
+	if !ok || resp.Type != discord.InteractionResponseTypeModal {
+		return discord.ModalCreate{}, false
+	}
+	modal, ok := resp.Data.(discord.ModalCreate)
+	return modal, ok
+}
+
+// Suggestions returns the values of the autocomplete choices the command responded with.
+func (r *Recorder) Suggestions() []string {
+	resp, ok := r.initial()
+	if !ok || resp.Type != discord.InteractionResponseTypeAutocompleteResult {
+		return nil
+	}
+	result, ok := resp.Data.(discord.AutocompleteResult)
+	if !ok {
+		return nil
+	}
+
+	values := make([]string, 0, len(result.Choices))
+	for _, choice := range result.Choices {
+		switch c := choice.(type) {
+		case discord.AutocompleteChoiceString:
+			values = append(values, c.Value)
+		case discord.AutocompleteChoiceInt:
+			values = append(values, strconv.Itoa(c.Value))
+		}
+	}
+	return values
+}
+
+// Deleted reports whether the original response was deleted.
+func (r *Recorder) Deleted() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.deleted
+}
+
+// Message returns the original response message as the user sees it,
+// i.e. the initial message with all edits applied.
+// If the command updated the message the component is attached to, the update is returned.
+// It is empty if the original response was deleted.
+func (r *Recorder) Message() discord.MessageCreate {
+	if r.Deleted() {
+		return discord.MessageCreate{}
+	}
+
+	var (
+		msg   discord.MessageCreate
+		edits []discord.MessageUpdate
+	)
+	if resp, ok := r.initial(); ok && !r.Deferred() {
+		switch data := resp.Data.(type) {
+		case discord.MessageCreate:
+			msg = data
+		case discord.MessageUpdate:
+			edits = append(edits, data)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, mu := range append(edits, r.edits...) {
+		if mu.Content != nil {
+			msg.Content = *mu.Content
+		}
+		if mu.Embeds != nil {
+			msg.Embeds = *mu.Embeds
+		}
+		if mu.Components != nil {
+			msg.Components = *mu.Components
+		}
+	}
+	return msg
+}
+
+// Content returns the content of the original response message.
+func (r *Recorder) Content() string {
+	return r.Message().Content
+}
+
+// Embeds returns the embeds of the original response message.
+func (r *Recorder) Embeds() []discord.Embed {
+	return r.Message().Embeds
+}
+
+// Buttons returns the buttons of the original response message.
+func (r *Recorder) Buttons() []discord.ButtonComponent {
+	var buttons []discord.ButtonComponent
+	for _, row := range r.Message().Components {
+		ar, ok := row.(discord.ActionRowComponent)
+		if !ok {
+			continue
+		}
+		for _, c := range ar {
+			if b, ok := c.(discord.ButtonComponent); ok {
+				buttons = append(buttons, b)
+			}
+		}
+	}
+	return buttons
+}
+
+// SelectMenus returns the select menus of the original response message.
+func (r *Recorder) SelectMenus() []discord.SelectMenuComponent {
+	var menus []discord.SelectMenuComponent
+	for _, row := range r.Message().Components {
+		ar, ok := row.(discord.ActionRowComponent)
+		if !ok {
+			continue
+		}
+		for _, c := range ar {
+			if m, ok := c.(discord.SelectMenuComponent); ok {
+				menus = append(menus, m)
+			}
+		}
+	}
+	return menus
+}
+
+// Edits returns the edits of the original response sent via the REST API.
+func (r *Recorder) Edits() []discord.MessageUpdate {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]discord.MessageUpdate(nil), r.edits...)
+}
+
+// Followups returns the follow-up messages sent via the REST API.
+func (r *Recorder) Followups() []discord.MessageCreate {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]discord.MessageCreate(nil), r.followups...)
+}
@@ -0,0 +1,594 @@
+package commandstest
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lvlcn-t/raid-mate/app/database/repo"
+	"github.com/lvlcn-t/raid-mate/app/services/character"
+	"github.com/lvlcn-t/raid-mate/app/services/feedback"
+	"github.com/lvlcn-t/raid-mate/app/services/guild"
+	"github.com/lvlcn-t/raid-mate/app/services/state"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+)
+
+// ErrNotStubbed is returned by stub services for methods without a stub function.
+var ErrNotStubbed = errors.New("method not stubbed")
+
+var (
+	_ guild.Service     = (*GuildService)(nil)
+	_ feedback.Service  = (*FeedbackService)(nil)
+	_ character.Service = (*CharacterService)(nil)
+	_ state.Service     = (*StateService)(nil)
+)
+
+// Call is a recorded call to a stub service.
+type Call struct {
+	// Method is the name of the called method.
+	Method string
+	// Args are the arguments of the call without the context.
+	Args []any
+}
+
+// calls records the calls to a stub service.
+type calls struct {
+	// mu guards the calls.
+	mu sync.Mutex
+	// calls are the recorded calls.
+	calls []Call
+}
+
+// record records a call to the given method.
+func (c *calls) record(method string, args ...any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = append(c.calls, Call{Method: method, Args: args})
+}
+
+// Calls returns the recorded calls.
+func (c *calls) Calls() []Call {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Call(nil), c.calls...)
+}
+
+// Called returns the recorded calls to the given method.
+func (c *calls) Called(method string) []Call {
+	var res []Call
+	for _, call := range c.Calls() {
+		if call.Method == method {
+			res = append(res, call)
+		}
+	}
+	return res
+}
+
+// GuildService is a stub of [guild.Service].
+// Methods without a stub function return [ErrNotStubbed].
+type GuildService struct {
+	calls
+	// ListFunc stubs [guild.Service.List].
+	ListFunc func(ctx context.Context) ([]repo.Guild, error)
+	// GetFunc stubs [guild.Service.Get].
+	GetFunc func(ctx context.Context, id snowflake.ID) (repo.Guild, error)
+	// CreateFunc stubs [guild.Service.Create].
+	CreateFunc func(ctx context.Context, ngp repo.NewGuildParams) error
+	// UpdateFunc stubs [guild.Service.Update].
+	UpdateFunc func(ctx context.Context, ugp repo.UpdateGuildParams) error
+	// SetAnnouncementChannelFunc stubs [guild.Service.SetAnnouncementChannel].
+	SetAnnouncementChannelFunc func(ctx context.Context, guildID, channelID snowflake.ID) error
+	// SetRaiderRoleFunc stubs [guild.Service.SetRaiderRole].
+	SetRaiderRoleFunc func(ctx context.Context, guildID, roleID snowflake.ID) error
+	// ResetFunc stubs [guild.Service.Reset].
+	ResetFunc func(ctx context.Context, id snowflake.ID) error
+	// GetLocaleFunc stubs [guild.Service.GetLocale].
+	GetLocaleFunc func(ctx context.Context, guildID snowflake.ID) (string, error)
+	// SetLocaleFunc stubs [guild.Service.SetLocale].
+	SetLocaleFunc func(ctx context.Context, guildID snowflake.ID, locale string) error
+	// SetScheduleFunc stubs [guild.Service.SetSchedule].
+	SetScheduleFunc func(ctx context.Context, guildID snowflake.ID, schedule guild.Schedule) error
+	// SetGearRulesFunc stubs [guild.Service.SetGearRules].
+	SetGearRulesFunc func(ctx context.Context, guildID snowflake.ID, rules guild.GearRules) error
+	// SetRaiderRankFunc stubs [guild.Service.SetRaiderRank].
+	SetRaiderRankFunc func(ctx context.Context, guildID snowflake.ID, rank int) error
+	// LeaveFunc stubs [guild.Service.Leave].
+	LeaveFunc func(ctx context.Context, id snowflake.ID) error
+	// RejoinFunc stubs [guild.Service.Rejoin].
+	RejoinFunc func(ctx context.Context, id snowflake.ID) error
+	// PurgeFunc stubs [guild.Service.Purge].
+	PurgeFunc func(ctx context.Context, id snowflake.ID) (guild.Purged, error)
+	// PurgeLeftFunc stubs [guild.Service.PurgeLeft].
+	PurgeLeftFunc func(ctx context.Context) ([]guild.Purged, error)
+	// SnapshotLeaderboardsFunc stubs [guild.Service.SnapshotLeaderboards].
+	SnapshotLeaderboardsFunc func(ctx context.Context, now time.Time) ([]guild.WeeklyLeaderboard, error)
+	// GetCredentialsFunc stubs [guild.Service.GetCredentials].
+	GetCredentialsFunc func(ctx context.Context, gcp repo.GetCredentialsParams) (repo.Credential, error)
+	// SetCredentialsFunc stubs [guild.Service.SetCredentials].
+	SetCredentialsFunc func(ctx context.Context, scp repo.SetCredentialsParams) error
+	// ListCredentialsFunc stubs [guild.Service.ListCredentials].
+	ListCredentialsFunc func(ctx context.Context, guildID snowflake.ID) ([]string, error)
+	// GetReportsFunc stubs [guild.Service.GetReports].
+	GetReportsFunc func(ctx context.Context, guildID snowflake.ID, period string) (*guild.Reports, error)
+	// GetParsesFunc stubs [guild.Service.GetParses].
+	GetParsesFunc func(ctx context.Context, guildID snowflake.ID, character, period string) (*guild.Parses, error)
+	// GetWipesFunc stubs [guild.Service.GetWipes].
+	GetWipesFunc func(ctx context.Context, guildID snowflake.ID, code, boss string) (*guild.Wipes, error)
+	// AuditConsumablesFunc stubs [guild.Service.AuditConsumables].
+	AuditConsumablesFunc func(ctx context.Context, guildID snowflake.ID, code string) (*guild.ConsumableAudit, error)
+	// GetConsumableOffendersFunc stubs [guild.Service.GetConsumableOffenders].
+	GetConsumableOffendersFunc func(ctx context.Context, guildID snowflake.ID, since time.Time) (*guild.ConsumableOffenders, error)
+	// AuditGearFunc stubs [guild.Service.AuditGear].
+	AuditGearFunc func(ctx context.Context, guildID snowflake.ID, character string) (*guild.GearAudit, error)
+	// GetReportFunc stubs [guild.Service.GetReport].
+	GetReportFunc func(ctx context.Context, guildID snowflake.ID, code string) (*guild.Report, error)
+	// GetProfileFunc stubs [guild.Service.GetProfile].
+	GetProfileFunc func(ctx context.Context, req *guild.RequestProfile) (*guild.Profiles, error)
+	// GetAttendanceFunc stubs [guild.Service.GetAttendance].
+	GetAttendanceFunc func(ctx context.Context, guildID snowflake.ID, character string, since time.Time) (*guild.Attendance, error)
+	// GetRosterFunc stubs [guild.Service.GetRoster].
+	GetRosterFunc func(ctx context.Context, guildID snowflake.ID) ([]string, error)
+	// GetLeaderboardFunc stubs [guild.Service.GetLeaderboard].
+	GetLeaderboardFunc func(ctx context.Context, guildID snowflake.ID, role string) (*guild.Leaderboard, error)
+	// GetScoreHistoryFunc stubs [guild.Service.GetScoreHistory].
+	GetScoreHistoryFunc func(ctx context.Context, guildID snowflake.ID, character string) ([]guild.ScoreSnapshot, error)
+	// GetVaultFunc stubs [guild.Service.GetVault].
+	GetVaultFunc func(ctx context.Context, guildID snowflake.ID, character string) (*guild.Vault, error)
+	// ListWowGuildsFunc stubs [guild.Service.ListWowGuilds].
+	ListWowGuildsFunc func(ctx context.Context, guildID snowflake.ID) ([]repo.WowGuild, error)
+	// AddWowGuildFunc stubs [guild.Service.AddWowGuild].
+	AddWowGuildFunc func(ctx context.Context, awp repo.AddWowGuildParams) error
+	// UpdateWowGuildFunc stubs [guild.Service.UpdateWowGuild].
+	UpdateWowGuildFunc func(ctx context.Context, uwp repo.UpdateWowGuildParams) error
+	// RemoveWowGuildFunc stubs [guild.Service.RemoveWowGuild].
+	RemoveWowGuildFunc func(ctx context.Context, guildID snowflake.ID, id int64) error
+	// SetDefaultWowGuildFunc stubs [guild.Service.SetDefaultWowGuild].
+	SetDefaultWowGuildFunc func(ctx context.Context, guildID snowflake.ID, id int64) error
+}
+
+// List returns a list of guilds.
+func (s *GuildService) List(ctx context.Context) ([]repo.Guild, error) {
+	s.record("List")
+	if s.ListFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return s.ListFunc(ctx)
+}
+
+// Get returns the guild with the given ID.
+func (s *GuildService) Get(ctx context.Context, id snowflake.ID) (repo.Guild, error) {
+	s.record("Get", id)
+	if s.GetFunc == nil {
+		return repo.Guild{}, ErrNotStubbed
+	}
+	return s.GetFunc(ctx, id)
+}
+
+// Create creates a new guild.
+func (s *GuildService) Create(ctx context.Context, ngp repo.NewGuildParams) error {
+	s.record("Create", ngp)
+	if s.CreateFunc == nil {
+		return ErrNotStubbed
+	}
+	return s.CreateFunc(ctx, ngp)
+}
+
+// Update updates the guild with the given parameters.
+func (s *GuildService) Update(ctx context.Context, ugp repo.UpdateGuildParams) error {
+	s.record("Update", ugp)
+	if s.UpdateFunc == nil {
+		return ErrNotStubbed
+	}
+	return s.UpdateFunc(ctx, ugp)
+}
+
+// SetAnnouncementChannel sets the channel the bot posts announcements in.
+func (s *GuildService) SetAnnouncementChannel(ctx context.Context, guildID, channelID snowflake.ID) error {
+	s.record("SetAnnouncementChannel", guildID, channelID)
+	if s.SetAnnouncementChannelFunc == nil {
+		return ErrNotStubbed
+	}
+	return s.SetAnnouncementChannelFunc(ctx, guildID, channelID)
+}
+
+// SetRaiderRole sets the role of the raiders.
+func (s *GuildService) SetRaiderRole(ctx context.Context, guildID, roleID snowflake.ID) error {
+	s.record("SetRaiderRole", guildID, roleID)
+	if s.SetRaiderRoleFunc == nil {
+		return ErrNotStubbed
+	}
+	return s.SetRaiderRoleFunc(ctx, guildID, roleID)
+}
+
+// Reset resets the settings of the guild with the given ID.
+func (s *GuildService) Reset(ctx context.Context, id snowflake.ID) error {
+	s.record("Reset", id)
+	if s.ResetFunc == nil {
+		return ErrNotStubbed
+	}
+	return s.ResetFunc(ctx, id)
+}
+
+// GetLocale returns the locale the bot responds in on the Discord server.
+func (s *GuildService) GetLocale(ctx context.Context, guildID snowflake.ID) (string, error) {
+	s.record("GetLocale", guildID)
+	if s.GetLocaleFunc == nil {
+		return "", ErrNotStubbed
+	}
+	return s.GetLocaleFunc(ctx, guildID)
+}
+
+// SetLocale sets the locale the bot responds in on the Discord server.
+func (s *GuildService) SetLocale(ctx context.Context, guildID snowflake.ID, locale string) error {
+	s.record("SetLocale", guildID, locale)
+	if s.SetLocaleFunc == nil {
+		return ErrNotStubbed
+	}
+	return s.SetLocaleFunc(ctx, guildID, locale)
+}
+
+// SetSchedule sets the timezone and the raid hours of the Discord server.
+func (s *GuildService) SetSchedule(ctx context.Context, guildID snowflake.ID, schedule guild.Schedule) error {
+	s.record("SetSchedule", guildID, schedule)
+	if s.SetScheduleFunc == nil {
+		return ErrNotStubbed
+	}
+	return s.SetScheduleFunc(ctx, guildID, schedule)
+}
+
+// SetGearRules sets the rules the equipment of the raiders of the Discord server is audited against.
+func (s *GuildService) SetGearRules(ctx context.Context, guildID snowflake.ID, rules guild.GearRules) error {
+	s.record("SetGearRules", guildID, rules)
+	if s.SetGearRulesFunc == nil {
+		return ErrNotStubbed
+	}
+	return s.SetGearRulesFunc(ctx, guildID, rules)
+}
+
+// SetRaiderRank sets the lowest rank of the members of the WoW guild that count as raiders.
+func (s *GuildService) SetRaiderRank(ctx context.Context, guildID snowflake.ID, rank int) error {
+	s.record("SetRaiderRank", guildID, rank)
+	if s.SetRaiderRankFunc == nil {
+		return ErrNotStubbed
+	}
+	return s.SetRaiderRankFunc(ctx, guildID, rank)
+}
+
+// Leave marks the Discord server as left by the bot.
+func (s *GuildService) Leave(ctx context.Context, id snowflake.ID) error {
+	s.record("Leave", id)
+	if s.LeaveFunc == nil {
+		return ErrNotStubbed
+	}
+	return s.LeaveFunc(ctx, id)
+}
+
+// Rejoin marks the Discord server as joined again.
+func (s *GuildService) Rejoin(ctx context.Context, id snowflake.ID) error {
+	s.record("Rejoin", id)
+	if s.RejoinFunc == nil {
+		return ErrNotStubbed
+	}
+	return s.RejoinFunc(ctx, id)
+}
+
+// Purge deletes all data of the Discord server.
+func (s *GuildService) Purge(ctx context.Context, id snowflake.ID) (guild.Purged, error) {
+	s.record("Purge", id)
+	if s.PurgeFunc == nil {
+		return guild.Purged{}, ErrNotStubbed
+	}
+	return s.PurgeFunc(ctx, id)
+}
+
+// PurgeLeft deletes all data of the Discord servers the bot left longer than the retention period ago.
+func (s *GuildService) PurgeLeft(ctx context.Context) ([]guild.Purged, error) {
+	s.record("PurgeLeft")
+	if s.PurgeLeftFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return s.PurgeLeftFunc(ctx)
+}
+
+// SnapshotLeaderboards stores the Mythic+ scores of the raiders of the Discord servers a snapshot is due for.
+func (s *GuildService) SnapshotLeaderboards(ctx context.Context, now time.Time) ([]guild.WeeklyLeaderboard, error) {
+	s.record("SnapshotLeaderboards", now)
+	if s.SnapshotLeaderboardsFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return s.SnapshotLeaderboardsFunc(ctx, now)
+}
+
+// GetCredentials returns the credentials for the given parameters.
+func (s *GuildService) GetCredentials(ctx context.Context, gcp repo.GetCredentialsParams) (repo.Credential, error) {
+	s.record("GetCredentials", gcp)
+	if s.GetCredentialsFunc == nil {
+		return repo.Credential{}, ErrNotStubbed
+	}
+	return s.GetCredentialsFunc(ctx, gcp)
+}
+
+// SetCredentials sets the credentials for the given parameters.
+func (s *GuildService) SetCredentials(ctx context.Context, scp repo.SetCredentialsParams) error {
+	s.record("SetCredentials", scp)
+	if s.SetCredentialsFunc == nil {
+		return ErrNotStubbed
+	}
+	return s.SetCredentialsFunc(ctx, scp)
+}
+
+// ListCredentials returns the names of the accounts the guild stored credentials for.
+func (s *GuildService) ListCredentials(ctx context.Context, guildID snowflake.ID) ([]string, error) {
+	s.record("ListCredentials", guildID)
+	if s.ListCredentialsFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return s.ListCredentialsFunc(ctx, guildID)
+}
+
+// GetReports returns the reports the guild uploaded in the given period of raid days.
+func (s *GuildService) GetReports(ctx context.Context, guildID snowflake.ID, period string) (*guild.Reports, error) {
+	s.record("GetReports", guildID, period)
+	if s.GetReportsFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return s.GetReportsFunc(ctx, guildID, period)
+}
+
+// GetParses returns the parses of the raiders of the guild.
+func (s *GuildService) GetParses(ctx context.Context, guildID snowflake.ID, character, period string) (*guild.Parses, error) {
+	s.record("GetParses", guildID, character, period)
+	if s.GetParsesFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return s.GetParsesFunc(ctx, guildID, character, period)
+}
+
+// GetWipes returns the deaths of the boss pulls of the report.
+func (s *GuildService) GetWipes(ctx context.Context, guildID snowflake.ID, code, boss string) (*guild.Wipes, error) {
+	s.record("GetWipes", guildID, code, boss)
+	if s.GetWipesFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return s.GetWipesFunc(ctx, guildID, code, boss)
+}
+
+// AuditConsumables audits the consumables of the players on the boss pulls of the report.
+func (s *GuildService) AuditConsumables(ctx context.Context, guildID snowflake.ID, code string) (*guild.ConsumableAudit, error) {
+	s.record("AuditConsumables", guildID, code)
+	if s.AuditConsumablesFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return s.AuditConsumablesFunc(ctx, guildID, code)
+}
+
+// GetConsumableOffenders returns the players who missed consumables in the audited reports.
+func (s *GuildService) GetConsumableOffenders(ctx context.Context, guildID snowflake.ID, since time.Time) (*guild.ConsumableOffenders, error) {
+	s.record("GetConsumableOffenders", guildID, since)
+	if s.GetConsumableOffendersFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return s.GetConsumableOffendersFunc(ctx, guildID, since)
+}
+
+// AuditGear audits the equipment of the characters in the roster of the guild.
+func (s *GuildService) AuditGear(ctx context.Context, guildID snowflake.ID, character string) (*guild.GearAudit, error) {
+	s.record("AuditGear", guildID, character)
+	if s.AuditGearFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return s.AuditGearFunc(ctx, guildID, character)
+}
+
+// GetReport returns the report with the given code.
+func (s *GuildService) GetReport(ctx context.Context, guildID snowflake.ID, code string) (*guild.Report, error) {
+	s.record("GetReport", guildID, code)
+	if s.GetReportFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return s.GetReportFunc(ctx, guildID, code)
+}
+
+// GetAttendance returns the raid attendance of the character.
+func (s *GuildService) GetAttendance(ctx context.Context, guildID snowflake.ID, character string, since time.Time) (*guild.Attendance, error) {
+	s.record("GetAttendance", guildID, character, since)
+	if s.GetAttendanceFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return s.GetAttendanceFunc(ctx, guildID, character, since)
+}
+
+// GetProfile returns the profile for the given parameters.
+func (s *GuildService) GetProfile(ctx context.Context, req *guild.RequestProfile) (*guild.Profiles, error) {
+	s.record("GetProfile", req)
+	if s.GetProfileFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return s.GetProfileFunc(ctx, req)
+}
+
+// GetRoster returns the names of the characters in the roster of the guild.
+func (s *GuildService) GetRoster(ctx context.Context, guildID snowflake.ID) ([]string, error) {
+	s.record("GetRoster", guildID)
+	if s.GetRosterFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return s.GetRosterFunc(ctx, guildID)
+}
+
+// GetLeaderboard returns the Mythic+ leaderboard of the characters in the roster of the guild.
+func (s *GuildService) GetLeaderboard(ctx context.Context, guildID snowflake.ID, role string) (*guild.Leaderboard, error) {
+	s.record("GetLeaderboard", guildID, role)
+	if s.GetLeaderboardFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return s.GetLeaderboardFunc(ctx, guildID, role)
+}
+
+// GetScoreHistory returns the Mythic+ scores of the character in all weekly snapshots.
+func (s *GuildService) GetScoreHistory(ctx context.Context, guildID snowflake.ID, character string) ([]guild.ScoreSnapshot, error) {
+	s.record("GetScoreHistory", guildID, character)
+	if s.GetScoreHistoryFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return s.GetScoreHistoryFunc(ctx, guildID, character)
+}
+
+// GetVault returns the progress of the raiders towards the slots of the Great Vault.
+func (s *GuildService) GetVault(ctx context.Context, guildID snowflake.ID, character string) (*guild.Vault, error) {
+	s.record("GetVault", guildID, character)
+	if s.GetVaultFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return s.GetVaultFunc(ctx, guildID, character)
+}
+
+// ListWowGuilds returns the WoW guilds linked to the Discord server.
+func (s *GuildService) ListWowGuilds(ctx context.Context, guildID snowflake.ID) ([]repo.WowGuild, error) {
+	s.record("ListWowGuilds", guildID)
+	if s.ListWowGuildsFunc == nil {
+		return nil, ErrNotStubbed
+	}
+	return s.ListWowGuildsFunc(ctx, guildID)
+}
+
+// AddWowGuild links another WoW guild to the Discord server.
+func (s *GuildService) AddWowGuild(ctx context.Context, awp repo.AddWowGuildParams) error {
+	s.record("AddWowGuild", awp)
+	if s.AddWowGuildFunc == nil {
+		return ErrNotStubbed
+	}
+	return s.AddWowGuildFunc(ctx, awp)
+}
+
+// UpdateWowGuild updates the settings of a WoW guild linked to the Discord server.
+func (s *GuildService) UpdateWowGuild(ctx context.Context, uwp repo.UpdateWowGuildParams) error {
+	s.record("UpdateWowGuild", uwp)
+	if s.UpdateWowGuildFunc == nil {
+		return ErrNotStubbed
+	}
+	return s.UpdateWowGuildFunc(ctx, uwp)
+}
+
+// RemoveWowGuild unlinks a WoW guild from the Discord server.
+func (s *GuildService) RemoveWowGuild(ctx context.Context, guildID snowflake.ID, id int64) error {
+	s.record("RemoveWowGuild", guildID, id)
+	if s.RemoveWowGuildFunc == nil {
+		return ErrNotStubbed
+	}
+	return s.RemoveWowGuildFunc(ctx, guildID, id)
+}
+
+// SetDefaultWowGuild makes the WoW guild the default guild of the Discord server.
+func (s *GuildService) SetDefaultWowGuild(ctx context.Context, guildID snowflake.ID, id int64) error {
+	s.record("SetDefaultWowGuild", guildID, id)
+	if s.SetDefaultWowGuildFunc == nil {
+		return ErrNotStubbed
+	}
+	return s.SetDefaultWowGuildFunc(ctx, guildID, id)
+}
+
+// FeedbackService is a stub of [feedback.Service].
+// Methods without a stub function return [ErrNotStubbed].
+type FeedbackService struct {
+	calls
+	// SubmitFunc stubs [feedback.Service.Submit].
+	SubmitFunc func(ctx context.Context, req feedback.Request, client bot.Client) error
+}
+
+// Submit submits the feedback.
+func (s *FeedbackService) Submit(ctx context.Context, req feedback.Request, client bot.Client) error {
+	s.record("Submit", req)
+	if s.SubmitFunc == nil {
+		return ErrNotStubbed
+	}
+	return s.SubmitFunc(ctx, req, client)
+}
+
+// CharacterService is a stub of [character.Service].
+// Methods without a stub function return [ErrNotStubbed].
+type CharacterService struct {
+	calls
+	// GetMainFunc stubs [character.Service.GetMain].
+	GetMainFunc func(ctx context.Context, guildID, userID snowflake.ID) (repo.Character, error)
+	// SetMainFunc stubs [character.Service.SetMain].
+	SetMainFunc func(ctx context.Context, smp repo.SetMainCharacterParams) error
+}
+
+// GetMain returns the main character the user registered in the guild.
+func (s *CharacterService) GetMain(ctx context.Context, guildID, userID snowflake.ID) (repo.Character, error) {
+	s.record("GetMain", guildID, userID)
+	if s.GetMainFunc == nil {
+		return repo.Character{}, ErrNotStubbed
+	}
+	return s.GetMainFunc(ctx, guildID, userID)
+}
+
+// SetMain registers the character as the main character of the user in the guild.
+func (s *CharacterService) SetMain(ctx context.Context, smp repo.SetMainCharacterParams) error {
+	s.record("SetMain", smp)
+	if s.SetMainFunc == nil {
+		return ErrNotStubbed
+	}
+	return s.SetMainFunc(ctx, smp)
+}
+
+// StateService is a stub of [state.Service].
+// Unlike the other stubs, methods without a stub function keep the state in memory,
+// so components with overflowing custom IDs work without further setup.
+type StateService struct {
+	calls
+	// SaveFunc stubs [state.Service.Save].
+	SaveFunc func(ctx context.Context, payload string, ttl time.Duration) (string, error)
+	// LoadFunc stubs [state.Service.Load].
+	LoadFunc func(ctx context.Context, key string) (string, error)
+	// PurgeFunc stubs [state.Service.Purge].
+	PurgeFunc func(ctx context.Context) (int64, error)
+
+	// mu guards the payloads.
+	mu sync.Mutex
+	// payloads are the payloads stored in memory.
+	payloads []string
+}
+
+// Save stores the payload.
+func (s *StateService) Save(ctx context.Context, payload string, ttl time.Duration) (string, error) {
+	s.record("Save", payload, ttl)
+	if s.SaveFunc != nil {
+		return s.SaveFunc(ctx, payload, ttl)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.payloads = append(s.payloads, payload)
+	return strconv.Itoa(len(s.payloads) - 1), nil
+}
+
+// Load returns the payload stored under the given key.
+func (s *StateService) Load(ctx context.Context, key string) (string, error) {
+	s.record("Load", key)
+	if s.LoadFunc != nil {
+		return s.LoadFunc(ctx, key)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i, err := strconv.Atoi(key)
+	if err != nil || i < 0 || i >= len(s.payloads) {
+		return "", svcerr.New(svcerr.ErrNotFound, "")
+	}
+	return s.payloads[i], nil
+}
+
+// Purge deletes all expired payloads.
+func (s *StateService) Purge(ctx context.Context) (int64, error) {
+	s.record("Purge")
+	if s.PurgeFunc == nil {
+		return 0, nil
+	}
+	return s.PurgeFunc(ctx)
+}
@@ -0,0 +1,286 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/gofiber/fiber/v3"
+	"github.com/lvlcn-t/go-kit/apimanager/fiberutils"
+	"github.com/lvlcn-t/loggerhead/logger"
+	"github.com/lvlcn-t/raid-mate/app/bot/colors"
+	"github.com/lvlcn-t/raid-mate/app/bot/i18n"
+	"github.com/lvlcn-t/raid-mate/app/services/guild"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+)
+
+var (
+	_ Command[*events.ApplicationCommandInteractionCreate] = (*Compare)(nil)
+	_ AutocompleteCommand                                  = (*Compare)(nil)
+)
+
+// compareOptions are the names of the options of the characters to compare, the first is the baseline.
+var compareOptions = [2]string{"first", "second"}
+
+// Compare is a command to compare the profiles of two characters side by side.
+type Compare struct {
+	// Base is the common base for all commands.
+	*Base[*events.ApplicationCommandInteractionCreate]
+	// service is the guild service.
+	service guild.Service
+}
+
+// newCompare creates a new compare command.
+func newCompare(svc guild.Service) *Compare {
+	return &Compare{
+		Base:    NewBase[*events.ApplicationCommandInteractionCreate]("compare"),
+		service: svc,
+	}
+}
+
+// Handle is the handler for the command that is called when the event is triggered.
+// It shows the score, the best runs, the item level and the raid progression of both characters with the differences.
+func (c *Compare) Handle(ctx context.Context, event *events.ApplicationCommandInteractionCreate) {
+	log := logger.FromContext(ctx).With("command", c.Name())
+	data := event.SlashCommandInteractionData()
+	ctx = withLinkedGuild(ctx, data)
+
+	profiles, err := c.fetchProfiles(ctx, *event.GuildID(), [2]string{data.String(compareOptions[0]), data.String(compareOptions[1])})
+	if err != nil {
+		replyError(ctx, log, event, err)
+		return
+	}
+
+	err = event.CreateMessage(discord.NewMessageCreateBuilder().
+		AddEmbeds(compareEmbed(i18n.FromContext(ctx, event.Locale()), profiles)).
+		Build(),
+	)
+	if err != nil {
+		log.ErrorContext(ctx, "Error replying to interaction", "error", err)
+	}
+}
+
+// fetchProfiles fetches the profiles of both characters in parallel.
+// If a profile cannot be fetched, the error of the first character that failed is returned.
+func (c *Compare) fetchProfiles(ctx context.Context, guildID snowflake.ID, characters [2]string) ([2]*guild.UserProfile, error) {
+	var (
+		profiles [2]*guild.UserProfile
+		errs     [2]error
+		wg       sync.WaitGroup
+	)
+	for i, character := range characters {
+		wg.Go(func() {
+			profile, err := c.service.GetProfile(ctx, &guild.RequestProfile{Type: "user", GuildID: guildID, User: character})
+			switch {
+			case err != nil:
+				errs[i] = err
+			case !profile.IsUser():
+				errs[i] = svcerr.New(svcerr.ErrNotFound, character)
+			default:
+				profiles[i] = profile.UserProfile
+			}
+		})
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return profiles, err
+		}
+	}
+	return profiles, nil
+}
+
+// compareEmbed creates the embed comparing the characters side by side.
+// The differences are the values of the second character relative to the first.
+func compareEmbed(locale discord.Locale, profiles [2]*guild.UserProfile) discord.Embed {
+	first, second := profiles[0], profiles[1]
+	embed := discord.NewEmbedBuilder().
+		SetTitle(i18n.T(locale, "compare.title", first.Name, second.Name)).
+		SetDescription(i18n.T(locale, "compare.description", second.Name, first.Name)).
+		SetColor(colors.Blue.Int())
+
+	var firstScore, secondScore float64
+	if season, ok := first.CurrentSeason(); ok {
+		firstScore = season.Scores.All
+	}
+	if season, ok := second.CurrentSeason(); ok {
+		secondScore = season.Scores.All
+	}
+	embed.AddField(i18n.T(locale, "compare.overview_title"), compareTable(
+		compareRow("", first.Name, second.Name, i18n.T(locale, "compare.column_diff")),
+		compareRow(i18n.T(locale, "compare.score"),
+			fmt.Sprintf("%.1f", firstScore), fmt.Sprintf("%.1f", secondScore), fmt.Sprintf("%+.1f", secondScore-firstScore)),
+		compareRow(i18n.T(locale, "compare.item_level"),
+			fmt.Sprintf("%d", first.Gear.ItemLevelEquipped), fmt.Sprintf("%d", second.Gear.ItemLevelEquipped),
+			fmt.Sprintf("%+d", second.Gear.ItemLevelEquipped-first.Gear.ItemLevelEquipped)),
+	), false)
+
+	if dungeons := compareDungeons(locale, first, second); dungeons != "" {
+		embed.AddField(i18n.T(locale, "compare.mythic_plus_title"), dungeons, false)
+	}
+	if raids := compareRaids(locale, first, second); raids != "" {
+		embed.AddField(i18n.T(locale, "compare.raids_title"), raids, false)
+	}
+	return embed.Build()
+}
+
+// compareDungeons returns the table of the highest key level each character completed per dungeon this season.
+// It returns an empty string if neither character completed a key.
+func compareDungeons(locale discord.Locale, first, second *guild.UserProfile) string {
+	firstLevels, secondLevels := bestKeyLevels(first), bestKeyLevels(second)
+	dungeons := sortedKeys(firstLevels, secondLevels)
+	if len(dungeons) == 0 {
+		return ""
+	}
+
+	rows := []string{compareRow(i18n.T(locale, "compare.column_dungeon"), first.Name, second.Name, i18n.T(locale, "compare.column_diff"))}
+	for _, dungeon := range dungeons {
+		a, okA := firstLevels[dungeon]
+		b, okB := secondLevels[dungeon]
+		diff := "-"
+		if okA && okB {
+			diff = fmt.Sprintf("%+d", b-a)
+		}
+		rows = append(rows, compareRow(dungeon, keyLevel(a, okA), keyLevel(b, okB), diff))
+	}
+	return compareTable(rows...)
+}
+
+// bestKeyLevels returns the highest key level the character completed per dungeon, keyed by the short name of the dungeon.
+func bestKeyLevels(p *guild.UserProfile) map[string]int {
+	levels := map[string]int{}
+	for _, r := range slices.Concat(p.MythicPlusBestRuns, p.MythicPlusAlternateRuns) {
+		levels[r.ShortName] = max(levels[r.ShortName], r.MythicLevel)
+	}
+	return levels
+}
+
+// keyLevel returns the key level like "+12" or a dash if the dungeon has not been completed.
+func keyLevel(level int, ok bool) string {
+	if !ok {
+		return "-"
+	}
+	return fmt.Sprintf("+%d", level)
+}
+
+// compareRaids returns the table of the progression of the characters in every raid, the raids sorted by name.
+// The difference is the number of bosses killed on the highest difficulty either character killed a boss on.
+// It returns an empty string if neither character has progression.
+func compareRaids(locale discord.Locale, first, second *guild.UserProfile) string {
+	slugs := sortedKeys(first.RaidProgression, second.RaidProgression)
+	if len(slugs) == 0 {
+		return ""
+	}
+
+	rows := []string{compareRow(i18n.T(locale, "compare.column_raid"), first.Name, second.Name, i18n.T(locale, "compare.column_diff"))}
+	for _, slug := range slugs {
+		a, b := first.RaidProgression[slug], second.RaidProgression[slug]
+		diff := "-"
+		for _, d := range []struct {
+			name  string
+			kills func(p guild.RaidProgression) int
+		}{
+			{name: "mythic", kills: func(p guild.RaidProgression) int { return p.MythicBossesKilled }},
+			{name: "heroic", kills: func(p guild.RaidProgression) int { return p.HeroicBossesKilled }},
+			{name: "normal", kills: func(p guild.RaidProgression) int { return p.NormalBossesKilled }},
+		} {
+			if d.kills(a) > 0 || d.kills(b) > 0 {
+				diff = fmt.Sprintf("%+d %s", d.kills(b)-d.kills(a), i18n.T(locale, "vault.difficulties."+d.name))
+				break
+			}
+		}
+		rows = append(rows, compareRow(raidName(slug), orDash(a.Summary), orDash(b.Summary), diff))
+	}
+	return compareTable(rows...)
+}
+
+// orDash returns the value or a dash if it is empty.
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// sortedKeys returns the keys of both maps in lexical order without duplicates.
+func sortedKeys[V any](a, b map[string]V) []string {
+	keys := slices.Concat(slices.Collect(maps.Keys(a)), slices.Collect(maps.Keys(b)))
+	slices.Sort(keys)
+	return slices.Compact(keys)
+}
+
+// compareRow returns a row of a comparison table with the values of both characters and their difference.
+func compareRow(label, first, second, diff string) string {
+	return fmt.Sprintf("%-16s %12s %12s %8s", label, first, second, diff)
+}
+
+// compareTable returns the rows as code block, so the columns are aligned.
+func compareTable(rows ...string) string {
+	return "```\n" + strings.Join(rows, "\n") + "\n```"
+}
+
+// HandleAutocomplete suggests the linked guilds and the characters in the roster of the guild.
+func (c *Compare) HandleAutocomplete(ctx context.Context, event *events.AutocompleteInteractionCreate) {
+	log := logger.FromContext(ctx).With("command", c.Name())
+	if event.Data.Focused().Name == linkedGuildOption {
+		respondSuggestions(ctx, log, event, suggestLinkedGuilds(ctx, log, c.service, event))
+		return
+	}
+	focused := event.Data.Focused().Name
+	if event.GuildID() == nil || !slices.Contains(compareOptions[:], focused) {
+		respondSuggestions(ctx, log, event, nil)
+		return
+	}
+
+	roster, err := c.service.GetRoster(withLinkedGuild(ctx, event.Data), *event.GuildID())
+	if err != nil {
+		logError(ctx, log, err)
+	}
+	respondSuggestions(ctx, log, event, suggest(event.Data.String(focused), roster))
+}
+
+// HandleHTTP is the handler for the command that is called when the HTTP request is triggered.
+func (c *Compare) HandleHTTP(ctx fiber.Ctx) error {
+	log := logger.FromContext(ctx.Context()).With("command", c.Name())
+	gid, err := fiberutils.Params(ctx, "guildID", snowflake.Parse)
+	if err != nil {
+		return errorResponse(ctx, log, errors.Join(errInvalidGuildID, err))
+	}
+
+	profiles, err := c.fetchProfiles(ctx.Context(), gid, [2]string{ctx.Params(compareOptions[0]), ctx.Params(compareOptions[1])})
+	if err != nil {
+		return errorResponse(ctx, log, err)
+	}
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{"profiles": profiles})
+}
+
+// Route returns the route for the command.
+func (c *Compare) Route() (methods []string, path string) {
+	return []string{http.MethodGet}, "/guilds/:guildID/compare/:first/:second"
+}
+
+// Info returns the interaction command information.
+func (c *Compare) Info() (discord.ApplicationCommandCreate, error) {
+	info := NewInfoBuilder().
+		Name(c.Name(), i18n.Localizations("commands.compare.name")).
+		Description(i18n.Text("commands.compare.description"))
+	for _, option := range compareOptions {
+		info = info.Option(NewStringOptionBuilder().
+			Name(option, i18n.Localizations("commands.compare.options."+option+".name")).
+			Description(i18n.Text("commands.compare.options." + option + ".description")).
+			Required(true).
+			MaxLength(maxCharacterNameLength).
+			Autocomplete(true),
+		)
+	}
+	return info.Option(newLinkedGuildOption()).Build()
+}
@@ -0,0 +1,106 @@
+package commands_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands/commandstest"
+	"github.com/lvlcn-t/raid-mate/app/services/guild"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+)
+
+func TestCompare(t *testing.T) {
+	userProfile := raiderProfile()
+	otherProfile := &guild.UserProfile{
+		Class:          "Warrior",
+		ActiveSpecName: "Protection",
+		ActiveSpecRole: "TANK",
+		Gear:           guild.Gear{ItemLevelEquipped: 617, ItemLevelTotal: 618},
+		MythicPlusScoresBySeason: []guild.MythicPlusScoresBySeason{
+			{Season: "season-tww-1", Scores: guild.Scores{All: 3012.6}},
+		},
+		MythicPlusBestRuns:      []guild.MythicPlusRun{{ShortName: "SV", MythicLevel: 11}, {ShortName: "GB", MythicLevel: 9}},
+		MythicPlusAlternateRuns: []guild.MythicPlusRun{{ShortName: "SV", MythicLevel: 12}},
+	}
+	otherProfile.Name, otherProfile.Region, otherProfile.Realm, otherProfile.Faction = "Bjorn", "eu", "Draenor", "horde"
+	otherProfile.RaidProgression = map[string]guild.RaidProgression{
+		"nerubar-palace": {Summary: "8/8 H", TotalBosses: 8, NormalBossesKilled: 8, HeroicBossesKilled: 8, MythicBossesKilled: 1},
+	}
+
+	tests := []commandTest{
+		{
+			name: "compare - side by side with differences",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetProfileFunc: func(_ context.Context, req *guild.RequestProfile) (*guild.Profiles, error) {
+					if req.User == "bjorn" {
+						return &guild.Profiles{UserProfile: otherProfile}, nil
+					}
+					return &guild.Profiles{UserProfile: userProfile}, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "compare", commandstest.Options{"first": "aerith", "second": "bjorn"})
+			},
+			want: want{responded: true, embeds: []string{"Aerith vs. Bjorn"}},
+			check: func(t *testing.T, h *commandstest.Harness, rec *commandstest.Recorder) {
+				want := []discord.EmbedField{
+					{Name: "Overview", Value: "```\n" +
+						"                       Aerith        Bjorn     Diff\n" +
+						"Mythic+ score          2874.0       3012.6   +138.6\n" +
+						"Item level                619          617       -2\n```"},
+					{Name: "Best Mythic+ keys", Value: "```\n" +
+						"Dungeon                Aerith        Bjorn     Diff\n" +
+						"ARAK                      +10            -        -\n" +
+						"GB                          -           +9        -\n" +
+						"SV                        +11          +12       +1\n```"},
+					{Name: "Raid progression", Value: "```\n" +
+						"Raid                   Aerith        Bjorn     Diff\n" +
+						"Nerubar Palace              -        8/8 H     +1 M\n```"},
+				}
+				fields := rec.Embeds()[0].Fields
+				if len(fields) != len(want) {
+					t.Fatalf("got %d fields, want %d", len(fields), len(want))
+				}
+				for i := range want {
+					if fields[i].Name != want[i].Name || fields[i].Value != want[i].Value {
+						t.Errorf("field %d = %q: %q, want %q: %q", i, fields[i].Name, fields[i].Value, want[i].Name, want[i].Value)
+					}
+				}
+				if calls := h.Services.Guild.Called("GetProfile"); len(calls) != 2 {
+					t.Errorf("GetProfile calls = %v, want one per character", calls)
+				}
+			},
+		},
+		{
+			name: "compare - unknown character",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetProfileFunc: func(_ context.Context, req *guild.RequestProfile) (*guild.Profiles, error) {
+					if req.User == "tifa" {
+						return nil, svcerr.New(svcerr.ErrNotFound, req.User)
+					}
+					return &guild.Profiles{UserProfile: userProfile}, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "compare", commandstest.Options{"first": "aerith", "second": "tifa"})
+			},
+			want: want{responded: true, ephemeral: true, content: `Nothing was found for "tifa". Please check the spelling and try again.`},
+		},
+		{
+			name: "compare - autocomplete suggests the roster for the second character",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetRosterFunc: func(_ context.Context, _ snowflake.ID) ([]string, error) {
+					return []string{"Aerith", "Bjorn", "Tifa"}, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Autocomplete(ctx, "compare", commandstest.Options{"first": "aerith", "second": "b"}, "second")
+			},
+			want: want{responded: true, suggestions: []string{"Bjorn"}},
+		},
+	}
+
+	runCommandTests(t, tests)
+}
@@ -0,0 +1,227 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/lvlcn-t/loggerhead/logger"
+	"github.com/lvlcn-t/raid-mate/app/bot/colors"
+	"github.com/lvlcn-t/raid-mate/app/bot/i18n"
+	"github.com/lvlcn-t/raid-mate/app/database/repo"
+	"github.com/lvlcn-t/raid-mate/app/services/character"
+	"github.com/lvlcn-t/raid-mate/app/services/guild"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+)
+
+var (
+	_ ContextMenuCommand = (*RaiderIOProfile)(nil)
+	_ ContextMenuCommand = (*Attendance)(nil)
+	_ ContextMenuCommand = (*SendFeedback)(nil)
+)
+
+// attendancePeriod is the period the attendance of a member is tracked in.
+const attendancePeriod = 30 * 24 * time.Hour
+
+// resolveMain returns the main character of the member a user context menu command was used on.
+// If the member has not registered a main character or the lookup fails, the interaction is answered and false is returned.
+func resolveMain(ctx context.Context, log logger.Logger, svc character.Service, event *events.ApplicationCommandInteractionCreate) (repo.Character, bool) {
+	if event.GuildID() == nil {
+		log.ErrorContext(ctx, "No guild found in interaction")
+		return repo.Character{}, false
+	}
+
+	target := event.UserCommandInteractionData().TargetUser()
+	main, err := svc.GetMain(ctx, *event.GuildID(), target.ID)
+	if errors.Is(err, svcerr.ErrNotFound) {
+		cErr := event.CreateMessage(discord.NewMessageCreateBuilder().
+			SetContent(tr(ctx, event, "main.missing", target.EffectiveName())).
+			SetEphemeral(true).
+			Build(),
+		)
+		if cErr != nil {
+			log.ErrorContext(ctx, "Error replying to interaction", "error", cErr)
+		}
+		return repo.Character{}, false
+	}
+	if err != nil {
+		replyError(ctx, log, event, err)
+		return repo.Character{}, false
+	}
+	return main, true
+}
+
+// RaiderIOProfile is a user context menu command to show the Raider.IO profile of the member's main character.
+type RaiderIOProfile struct {
+	// Base is the common base for all commands.
+	*Base[*events.ApplicationCommandInteractionCreate]
+	// characters is the character service.
+	characters character.Service
+	// guilds is the guild service.
+	guilds guild.Service
+}
+
+// newRaiderIOProfile creates a new Raider.IO profile context menu command.
+func newRaiderIOProfile(characters character.Service, guilds guild.Service) *RaiderIOProfile {
+	return &RaiderIOProfile{
+		Base:       NewBase[*events.ApplicationCommandInteractionCreate]("Raider.IO profile"),
+		characters: characters,
+		guilds:     guilds,
+	}
+}
+
+// Type returns the type of the context menu the command is shown in.
+func (c *RaiderIOProfile) Type() discord.ApplicationCommandType {
+	return discord.ApplicationCommandTypeUser
+}
+
+// Handle is the handler for the command that is called when the event is triggered.
+func (c *RaiderIOProfile) Handle(ctx context.Context, event *events.ApplicationCommandInteractionCreate) {
+	log := logger.FromContext(ctx).With("command", c.Name())
+	main, ok := resolveMain(ctx, log, c.characters, event)
+	if !ok {
+		return
+	}
+
+	profile, err := c.guilds.GetProfile(ctx, &guild.RequestProfile{
+		Type:    "user",
+		GuildID: *event.GuildID(),
+		User:    main.Name,
+		Realm:   main.Realm,
+	})
+	if err != nil {
+		replyError(ctx, log, event, err)
+		return
+	}
+
+	err = event.CreateMessage(profileMessage(profile, i18n.FromContext(ctx, event.Locale())))
+	if err != nil {
+		log.ErrorContext(ctx, "Error replying to interaction", "error", err)
+	}
+}
+
+// Info returns the interaction command information.
+func (c *RaiderIOProfile) Info() (discord.ApplicationCommandCreate, error) {
+	return NewUserCommandBuilder().
+		Name(c.Name(), i18n.Localizations("commands.raiderio_profile.name")).
+		Contexts(discord.InteractionContextTypeGuild).
+		Build()
+}
+
+// Attendance is a user context menu command to show the raid attendance of the member's main character.
+type Attendance struct {
+	// Base is the common base for all commands.
+	*Base[*events.ApplicationCommandInteractionCreate]
+	// characters is the character service.
+	characters character.Service
+	// guilds is the guild service.
+	guilds guild.Service
+}
+
+// newAttendance creates a new attendance context menu command.
+func newAttendance(characters character.Service, guilds guild.Service) *Attendance {
+	return &Attendance{
+		Base:       NewBase[*events.ApplicationCommandInteractionCreate]("Attendance"),
+		characters: characters,
+		guilds:     guilds,
+	}
+}
+
+// Type returns the type of the context menu the command is shown in.
+func (c *Attendance) Type() discord.ApplicationCommandType {
+	return discord.ApplicationCommandTypeUser
+}
+
+// Handle is the handler for the command that is called when the event is triggered.
+func (c *Attendance) Handle(ctx context.Context, event *events.ApplicationCommandInteractionCreate) {
+	log := logger.FromContext(ctx).With("command", c.Name())
+	main, ok := resolveMain(ctx, log, c.characters, event)
+	if !ok {
+		return
+	}
+
+	since := time.Now().Add(-attendancePeriod).Truncate(24 * time.Hour)
+	attendance, err := c.guilds.GetAttendance(ctx, *event.GuildID(), main.Name, since)
+	if err != nil {
+		replyError(ctx, log, event, err)
+		return
+	}
+
+	locale := i18n.FromContext(ctx, event.Locale())
+	embed := discord.NewEmbedBuilder().
+		SetTitle(i18n.T(locale, "attendance.title", main.Name)).
+		SetDescription(i18n.T(locale, "attendance.description",
+			attendance.Attended, attendance.Raids, attendance.Rate()*100, since.Format(time.DateOnly))).
+		SetColor(colors.Red.Int()).
+		Build()
+	err = event.CreateMessage(discord.NewMessageCreateBuilder().
+		AddEmbeds(embed).
+		Build(),
+	)
+	if err != nil {
+		log.ErrorContext(ctx, "Error replying to interaction", "error", err)
+	}
+}
+
+// Info returns the interaction command information.
+func (c *Attendance) Info() (discord.ApplicationCommandCreate, error) {
+	return NewUserCommandBuilder().
+		Name(c.Name(), i18n.Localizations("commands.attendance.name")).
+		Contexts(discord.InteractionContextTypeGuild).
+		Build()
+}
+
+// SendFeedback is a message context menu command to submit the selected message as feedback.
+// Only the own messages of the invoking user can be submitted.
+type SendFeedback struct {
+	// Base is the common base for all commands.
+	*Base[*events.ApplicationCommandInteractionCreate]
+	// feedback is the feedback command the message is submitted with.
+	feedback *Feedback
+}
+
+// newSendFeedback creates a new send as feedback context menu command.
+func newSendFeedback(fb *Feedback) *SendFeedback {
+	return &SendFeedback{
+		Base:     NewBase[*events.ApplicationCommandInteractionCreate]("Send as feedback"),
+		feedback: fb,
+	}
+}
+
+// Type returns the type of the context menu the command is shown in.
+func (c *SendFeedback) Type() discord.ApplicationCommandType {
+	return discord.ApplicationCommandTypeMessage
+}
+
+// Ephemeral reports whether the responses of the command are only visible to the invoking user.
+func (c *SendFeedback) Ephemeral() bool {
+	return true
+}
+
+// Handle is the handler for the command that is called when the event is triggered.
+func (c *SendFeedback) Handle(ctx context.Context, event *events.ApplicationCommandInteractionCreate) {
+	log := logger.FromContext(ctx).With("command", c.Name())
+	msg := event.MessageCommandInteractionData().TargetMessage()
+	// The feedback is published on behalf of the invoking user, so they must not submit what others wrote.
+	if msg.Author.ID != event.User().ID {
+		replyError(ctx, log, event, svcerr.Invalid(svcerr.Msg("errors.invalid.feedback_foreign_message")))
+		return
+	}
+	content := strings.TrimSpace(msg.Content)
+	if content == "" {
+		replyError(ctx, log, event, svcerr.Invalid(svcerr.Msg("errors.invalid.feedback_no_text")))
+		return
+	}
+
+	c.feedback.submit(ctx, log, event, content)
+}
+
+// Info returns the interaction command information.
+func (c *SendFeedback) Info() (discord.ApplicationCommandCreate, error) {
+	return NewMessageCommandBuilder().
+		Name(c.Name(), i18n.Localizations("commands.send_feedback.name")).
+		Build()
+}
@@ -0,0 +1,149 @@
+package commands_test
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands/commandstest"
+	"github.com/lvlcn-t/raid-mate/app/database/repo"
+	"github.com/lvlcn-t/raid-mate/app/services/feedback"
+	"github.com/lvlcn-t/raid-mate/app/services/guild"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+)
+
+func TestContextMenus(t *testing.T) {
+	tests := []commandTest{
+		{
+			name: "context menu - Raider.IO profile of the main character",
+			services: commandstest.Services{
+				Guild: &commandstest.GuildService{
+					GetProfileFunc: func(_ context.Context, req *guild.RequestProfile) (*guild.Profiles, error) {
+						p := &guild.UserProfile{}
+						p.Name = req.User
+						p.Realm = req.Realm
+						return &guild.Profiles{UserProfile: p}, nil
+					},
+				},
+				Character: &commandstest.CharacterService{
+					GetMainFunc: func(_ context.Context, _, _ snowflake.ID) (repo.Character, error) {
+						return repo.Character{Name: "Aerith", Realm: "Silvermoon", Main: true}, nil
+					},
+				},
+			},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.UserCommand(ctx, "Raider.IO profile", discord.User{ID: 42, Username: "aerith"})
+			},
+			want: want{responded: true, embeds: []string{"Aerith"}},
+			check: func(t *testing.T, h *commandstest.Harness, _ *commandstest.Recorder) {
+				if calls := h.Services.Character.Called("GetMain"); len(calls) != 1 || calls[0].Args[1] != snowflake.ID(42) {
+					t.Errorf("GetMain calls = %v, want a single call for the target user", calls)
+				}
+				calls := h.Services.Guild.Called("GetProfile")
+				if len(calls) != 1 {
+					t.Fatalf("GetProfile called %d times, want 1", len(calls))
+				}
+				if req := calls[0].Args[0].(*guild.RequestProfile); req.User != "Aerith" || req.Realm != "Silvermoon" {
+					t.Errorf("GetProfile request = %+v, want Aerith-Silvermoon", req)
+				}
+			},
+		},
+		{
+			name: "context menu - member without main character",
+			services: commandstest.Services{Character: &commandstest.CharacterService{
+				GetMainFunc: func(_ context.Context, _, _ snowflake.ID) (repo.Character, error) {
+					return repo.Character{}, svcerr.FromDB(sql.ErrNoRows, svcerr.ErrNotFound, "")
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.UserCommand(ctx, "Attendance", discord.User{ID: 42, Username: "tifa"}, commandstest.WithLocale(discord.LocaleGerman))
+			},
+			want: want{responded: true, ephemeral: true, content: "tifa hat noch keinen Hauptcharakter registriert. Das geht mit `/main`."},
+		},
+		{
+			name: "context menu - attendance of the main character",
+			services: commandstest.Services{
+				Guild: &commandstest.GuildService{
+					GetAttendanceFunc: func(_ context.Context, _ snowflake.ID, character string, since time.Time) (*guild.Attendance, error) {
+						return &guild.Attendance{Character: character, Since: since, Raids: 4, Attended: 3}, nil
+					},
+				},
+				Character: &commandstest.CharacterService{
+					GetMainFunc: func(_ context.Context, _, _ snowflake.ID) (repo.Character, error) {
+						return repo.Character{Name: "Aerith", Realm: "Draenor", Main: true}, nil
+					},
+				},
+			},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.UserCommand(ctx, "Attendance", discord.User{ID: 42, Username: "aerith"})
+			},
+			want: want{responded: true, embeds: []string{"Attendance of Aerith"}},
+			check: func(t *testing.T, _ *commandstest.Harness, rec *commandstest.Recorder) {
+				if desc := rec.Embeds()[0].Description; !strings.HasPrefix(desc, "Attended 3 of 4 raids (75%)") {
+					t.Errorf("description = %q, want the attended raids", desc)
+				}
+			},
+		},
+		{
+			name: "context menu - send own message as feedback",
+			services: commandstest.Services{Feedback: &commandstest.FeedbackService{
+				SubmitFunc: func(_ context.Context, _ feedback.Request, _ bot.Client) error {
+					return nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.MessageCommand(ctx, "Send as feedback", discord.Message{
+					ID:        7,
+					ChannelID: commandstest.ChannelID,
+					Content:   " The signup button is broken ",
+					Author:    discord.User{ID: commandstest.UserID, Username: "aerith"},
+				})
+			},
+			want: want{responded: true, ephemeral: true, content: `Feedback submitted: "The signup button is broken"`},
+			check: func(t *testing.T, h *commandstest.Harness, _ *commandstest.Recorder) {
+				calls := h.Services.Feedback.Called("Submit")
+				if len(calls) != 1 {
+					t.Fatalf("Submit called %d times, want 1", len(calls))
+				}
+				if req := calls[0].Args[0].(feedback.Request); req.UserID != commandstest.UserID {
+					t.Errorf("Submit request = %+v, want feedback of the invoking user", req)
+				}
+			},
+		},
+		{
+			name: "context menu - send message of another user as feedback",
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.MessageCommand(ctx, "Send as feedback", discord.Message{
+					ID:        7,
+					ChannelID: commandstest.ChannelID,
+					Content:   "The signup button is broken",
+					Author:    discord.User{ID: 42, Username: "bjorn"},
+				})
+			},
+			want: want{responded: true, ephemeral: true, content: "Your input is invalid: you can only send your own messages as feedback."},
+			check: func(t *testing.T, h *commandstest.Harness, _ *commandstest.Recorder) {
+				if calls := h.Services.Feedback.Called("Submit"); len(calls) != 0 {
+					t.Errorf("Submit called %d times, want 0", len(calls))
+				}
+			},
+		},
+		{
+			name: "context menu - send message without text as feedback",
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.MessageCommand(ctx, "Send as feedback", discord.Message{
+					ID:        7,
+					ChannelID: commandstest.ChannelID,
+					Author:    discord.User{ID: commandstest.UserID, Username: "aerith"},
+				})
+			},
+			want: want{responded: true, ephemeral: true, content: "Your input is invalid: the message has no text to send as feedback."},
+		},
+	}
+
+	runCommandTests(t, tests)
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"net/http"
-	"strings"
 
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/events"
@@ -12,13 +11,16 @@ import (
 	"github.com/gofiber/fiber/v3"
 	"github.com/lvlcn-t/go-kit/apimanager/fiberutils"
 	"github.com/lvlcn-t/loggerhead/logger"
+	"github.com/lvlcn-t/raid-mate/app/bot/i18n"
 	"github.com/lvlcn-t/raid-mate/app/database/repo"
 	"github.com/lvlcn-t/raid-mate/app/services/guild"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
 )
 
 var (
 	_ Command[*events.ApplicationCommandInteractionCreate] = (*Credentials)(nil)
 	_ ApplicationInteractionCommand                        = (*Credentials)(nil)
+	_ AutocompleteCommand                                  = (*Credentials)(nil)
 )
 
 // Credentials is a command to get the login credentials for an account.
@@ -37,6 +39,11 @@ func newCredentials(svc guild.Service) *Credentials {
 	}
 }
 
+// Ephemeral reports whether the responses of the command are only visible to the invoking user.
+func (c *Credentials) Ephemeral() bool {
+	return true
+}
+
 // Handle is the handler for the command that is called when the event is triggered.
 func (c *Credentials) Handle(ctx context.Context, event *events.ApplicationCommandInteractionCreate) {
 	log := logger.FromContext(ctx).With("command", c.Name())
@@ -45,20 +52,7 @@ func (c *Credentials) Handle(ctx context.Context, event *events.ApplicationComma
 
 	err := c.validateRequest(account)
 	if err != nil {
-		err = event.CreateMessage(discord.NewMessageCreateBuilder().
-			SetContent(err.Error()).
-			SetEphemeral(true).
-			Build(),
-		)
-		if err != nil {
-			log.ErrorContext(ctx, "Error replying to interaction", "error", err)
-		}
-		return
-	}
-
-	err = event.DeferCreateMessage(true)
-	if err != nil {
-		log.ErrorContext(ctx, "Error deferring interaction", "error", err)
+		replyError(ctx, log, event, err)
 		return
 	}
 
@@ -67,19 +61,12 @@ func (c *Credentials) Handle(ctx context.Context, event *events.ApplicationComma
 		Name:    account,
 	})
 	if err != nil {
-		cErr := event.CreateMessage(discord.NewMessageCreateBuilder().
-			SetContent("Error while getting credentials").
-			SetEphemeral(true).
-			Build(),
-		)
-		if cErr != nil {
-			log.ErrorContext(ctx, "Error replying to interaction", "error", cErr, "getCredentialsError", err)
-		}
+		replyError(ctx, log, event, err)
 		return
 	}
 
 	err = event.CreateMessage(discord.NewMessageCreateBuilder().
-		SetContentf("The login credentials for %q are:\nUsername: %s\nPassword: %s", account, credentials.Username, credentials.Password).
+		SetContent(tr(ctx, event, "credentials.reply", account, credentials.Username, credentials.Password)).
 		SetEphemeral(true).
 		Build(),
 	)
@@ -88,6 +75,21 @@ func (c *Credentials) Handle(ctx context.Context, event *events.ApplicationComma
 	}
 }
 
+// HandleAutocomplete suggests the accounts the guild stored credentials for.
+func (c *Credentials) HandleAutocomplete(ctx context.Context, event *events.AutocompleteInteractionCreate) {
+	log := logger.FromContext(ctx).With("command", c.Name())
+	if event.GuildID() == nil {
+		respondSuggestions(ctx, log, event, nil)
+		return
+	}
+
+	accounts, err := c.service.ListCredentials(ctx, *event.GuildID())
+	if err != nil {
+		logError(ctx, log, err)
+	}
+	respondSuggestions(ctx, log, event, suggest(event.Data.String("account"), accounts))
+}
+
 // HandleHTTP is the handler for the command that is called when the HTTP request is triggered.
 func (c *Credentials) HandleHTTP(ctx fiber.Ctx) error {
 	log := logger.FromContext(ctx.Context()).With("command", c.Name())
@@ -97,20 +99,17 @@ func (c *Credentials) HandleHTTP(ctx fiber.Ctx) error {
 
 	req, err := fiberutils.Body[request](ctx)
 	if err != nil {
-		log.DebugContext(ctx.Context(), "Error unmarshalling request", "error", err)
-		return fiberutils.BadRequestResponse(ctx, "malformed request")
+		return errorResponse(ctx, log, errors.Join(errMalformedRequest, err))
 	}
 
 	gid, err := fiberutils.Params(ctx, "guildID", snowflake.Parse)
 	if err != nil {
-		log.DebugContext(ctx.Context(), "Error parsing guild ID", "error", err)
-		return fiberutils.BadRequestResponse(ctx, "missing or invalid guild ID")
+		return errorResponse(ctx, log, errors.Join(errInvalidGuildID, err))
 	}
 
 	err = c.validateRequest(req.Account)
 	if err != nil {
-		log.DebugContext(ctx.Context(), "Error validating request", "error", err)
-		return fiberutils.BadRequestResponse(ctx, err.Error())
+		return errorResponse(ctx, log, err)
 	}
 
 	credentials, err := c.service.GetCredentials(ctx.Context(), repo.GetCredentialsParams{
@@ -118,8 +117,7 @@ func (c *Credentials) HandleHTTP(ctx fiber.Ctx) error {
 		Name:    req.Account,
 	})
 	if err != nil {
-		log.ErrorContext(ctx.Context(), "Error getting credentials", "error", err)
-		return fiberutils.InternalServerErrorResponse(ctx, "error getting credentials")
+		return errorResponse(ctx, log, err)
 	}
 
 	return ctx.Status(http.StatusOK).JSON(fiber.Map{
@@ -134,34 +132,22 @@ func (c *Credentials) Route() (methods []string, route string) {
 }
 
 // Info returns the interaction command information.
-func (c *Credentials) Info() discord.ApplicationCommandCreate {
+func (c *Credentials) Info() (discord.ApplicationCommandCreate, error) {
 	return NewInfoBuilder().
-		Name(c.Name(), map[discord.Locale]string{
-			discord.LocaleGerman: "logindaten",
-		}).
-		Description("Get the login credentials for an account", map[discord.Locale]string{
-			discord.LocaleGerman: "Erhalte die Login-Daten für einen Account",
-		}).
+		Name(c.Name(), i18n.Localizations("commands.credentials.name")).
+		Description(i18n.Text("commands.credentials.description")).
 		Option(NewStringOptionBuilder().
 			Name("account", nil).
-			Description("The account to get the login credentials for", map[discord.Locale]string{
-				discord.LocaleGerman: "Der Account, für den die Login-Daten abgerufen werden sollen",
-			}).
+			Description(i18n.Text("commands.credentials.options.account.description")).
 			Required(true).
-			Choices(NewStringOptionChoice("raidbots", "raidbots", nil)).
-			Build(),
+			Autocomplete(true),
 		).Build()
 }
 
 // validateRequest validates the credentials request.
 func (c *Credentials) validateRequest(account string) error {
 	if account == "" {
-		return errors.New("invalid account")
-	}
-
-	if !strings.EqualFold(account, "raidbots") {
-		return errors.New("unknown account")
+		return svcerr.Invalid(svcerr.Msg("errors.invalid.account_required"))
 	}
-
 	return nil
 }
@@ -0,0 +1,101 @@
+package commands_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands/commandstest"
+	"github.com/lvlcn-t/raid-mate/app/database/repo"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+)
+
+func TestCredentials(t *testing.T) {
+	tests := []commandTest{
+		{
+			name: "credentials - slow lookup is deferred ephemerally",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetCredentialsFunc: func(_ context.Context, _ repo.GetCredentialsParams) (repo.Credential, error) {
+					time.Sleep(100 * time.Millisecond)
+					return repo.Credential{Username: "raider", Password: "hunter2"}, nil
+				},
+			}},
+			opts: []commandstest.Option{commandstest.WithDeferAfter(10 * time.Millisecond)},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "credentials", commandstest.Options{"account": "raidbots"})
+			},
+			want: want{
+				responded: true,
+				deferred:  true,
+				ephemeral: true,
+				content:   "The login credentials for \"raidbots\" are:\nUsername: raider\nPassword: hunter2",
+			},
+		},
+		{
+			name: "credentials - unknown account",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetCredentialsFunc: func(_ context.Context, gcp repo.GetCredentialsParams) (repo.Credential, error) {
+					return repo.Credential{}, svcerr.FromDB(sql.ErrNoRows, svcerr.ErrNotFound, gcp.Name)
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "credentials", commandstest.Options{"account": "warcraftlogs"})
+			},
+			want: want{responded: true, ephemeral: true, content: `Nothing was found for "warcraftlogs". Please check the spelling and try again.`},
+		},
+		{
+			name: "credentials - known account",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetCredentialsFunc: func(_ context.Context, _ repo.GetCredentialsParams) (repo.Credential, error) {
+					return repo.Credential{Username: "raider", Password: "hunter2"}, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "credentials", commandstest.Options{"account": "raidbots"})
+			},
+			want: want{
+				responded: true,
+				ephemeral: true,
+				content:   "The login credentials for \"raidbots\" are:\nUsername: raider\nPassword: hunter2",
+			},
+			check: func(t *testing.T, h *commandstest.Harness, _ *commandstest.Recorder) {
+				calls := h.Services.Guild.Called("GetCredentials")
+				if len(calls) != 1 {
+					t.Fatalf("GetCredentials called %d times, want 1", len(calls))
+				}
+				want := repo.GetCredentialsParams{GuildID: int64(commandstest.GuildID), Name: "raidbots"}
+				if got := calls[0].Args[0].(repo.GetCredentialsParams); got != want {
+					t.Errorf("GetCredentials params = %+v, want %+v", got, want)
+				}
+			},
+		},
+		{
+			name: "credentials - autocomplete suggests stored accounts",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				ListCredentialsFunc: func(_ context.Context, _ snowflake.ID) ([]string, error) {
+					return []string{"warcraftlogs", "raidbots", "Raider.IO", "wowaudit"}, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Autocomplete(ctx, "credentials", commandstest.Options{"account": "Ra"}, "account")
+			},
+			want: want{responded: true, suggestions: []string{"raidbots", "Raider.IO", "warcraftlogs"}},
+		},
+		{
+			name: "credentials - autocomplete without accounts",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				ListCredentialsFunc: func(_ context.Context, _ snowflake.ID) ([]string, error) {
+					return nil, errBoom
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Autocomplete(ctx, "credentials", commandstest.Options{"account": ""}, "account")
+			},
+			want: want{responded: true, suggestions: []string{}},
+		},
+	}
+
+	runCommandTests(t, tests)
+}
@@ -0,0 +1,221 @@
+// Package customid encodes state into the custom IDs of message components and routes them back to their handlers.
+//
+// A custom ID consists of a route and typed arguments separated by colons,
+// e.g. "raid:signup:<eventID>:tank", followed by a signature that prevents users
+// from tampering with the arguments. Custom IDs longer than the 100 characters
+// Discord allows are kept in a [Store] and replaced with a reference to it.
+//
+// Example:
+//
+//	codec := customid.NewCodec(&customid.Config{Secret: "secret"}, store)
+//	id, err := codec.Encode(ctx, "raid:signup", customid.Snowflake(eventID), customid.String("tank"))
+//	// ...
+//	router := customid.NewRouter[Handler]()
+//	err = router.Add("raid:signup:{event}:{role}", signup)
+//	// ...
+//	segments, err := codec.Decode(ctx, id)
+//	handler, params, ok := router.Match(segments)
+//	eventID, err := params.Snowflake("event")
+package customid
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+const (
+	// MaxLength is the maximum length of a custom ID allowed by Discord.
+	MaxLength = 100
+	// Separator separates the route segments and arguments of a custom ID.
+	Separator = ":"
+	// signatureSeparator separates the payload of a custom ID from its signature.
+	signatureSeparator = "~"
+	// overflowPrefix marks a custom ID whose payload is kept in the store.
+	overflowPrefix = "$"
+	// signatureLength is the number of bytes of the HMAC kept in the signature.
+	signatureLength = 6
+	// defaultStateTTL is the default duration overflowing state is kept in the store.
+	defaultStateTTL = 7 * 24 * time.Hour
+	// base is the base integers are encoded in to keep custom IDs short.
+	base = 36
+)
+
+var (
+	// ErrInvalid is returned if a custom ID is malformed.
+	ErrInvalid = errors.New("invalid custom ID")
+	// ErrTampered is returned if the signature of a custom ID does not match its payload.
+	ErrTampered = errors.New("custom ID has been tampered with")
+	// ErrExpired is returned if the overflowing state of a custom ID is no longer in the store.
+	ErrExpired = errors.New("custom ID has expired")
+)
+
+// escaper escapes the characters of string arguments that have a special meaning in custom IDs.
+var escaper = strings.NewReplacer("%", "%25", Separator, "%3A", signatureSeparator, "%7E")
+
+// unescaper reverts the [escaper].
+var unescaper = strings.NewReplacer("%3A", Separator, "%7E", signatureSeparator, "%25", "%")
+
+// Store keeps the payload of custom IDs that are too long for Discord.
+type Store interface {
+	// Save stores the payload until the ttl elapsed and returns the key to load it.
+	Save(ctx context.Context, payload string, ttl time.Duration) (string, error)
+	// Load returns the payload stored under the given key.
+	Load(ctx context.Context, key string) (string, error)
+}
+
+// Config is the configuration for custom IDs.
+type Config struct {
+	// Secret is the key custom IDs are signed with.
+	// If empty, a random key is generated on startup, which invalidates all components sent before a restart.
+	Secret string `yaml:"secret" mapstructure:"secret"`
+	// StateTTL is the duration state too large for a custom ID is kept in the database.
+	// Defaults to 7 days.
+	StateTTL time.Duration `yaml:"stateTTL" mapstructure:"stateTTL" validate:"gte=0"`
+}
+
+// Arg is a typed argument of a custom ID.
+type Arg string
+
+// String returns a string argument.
+func String(s string) Arg {
+	return Arg(escaper.Replace(s))
+}
+
+// Int returns an integer argument.
+func Int(i int) Arg {
+	return Arg(strconv.FormatInt(int64(i), base))
+}
+
+// Snowflake returns a snowflake argument.
+func Snowflake(id snowflake.ID) Arg {
+	return Arg(strconv.FormatUint(uint64(id), base))
+}
+
+// Bool returns a boolean argument.
+func Bool(b bool) Arg {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// Codec encodes and decodes signed custom IDs.
+type Codec struct {
+	// key is the key custom IDs are signed with.
+	key []byte
+	// store keeps the payload of custom IDs that are too long.
+	store Store
+	// ttl is the duration payloads are kept in the store.
+	ttl time.Duration
+}
+
+// NewCodec creates a new codec that keeps overflowing state in the given store.
+func NewCodec(cfg *Config, store Store) *Codec {
+	key := []byte(cfg.Secret)
+	if len(key) == 0 {
+		key = make([]byte, sha256.Size)
+		_, _ = rand.Read(key)
+	}
+
+	ttl := cfg.StateTTL
+	if ttl <= 0 {
+		ttl = defaultStateTTL
+	}
+
+	return &Codec{key: key, store: store, ttl: ttl}
+}
+
+// Encode returns the signed custom ID for the route and arguments.
+// The route consists of one or more segments separated by colons.
+// If the custom ID exceeds [MaxLength], its payload is kept in the store.
+func (c *Codec) Encode(ctx context.Context, route string, args ...Arg) (string, error) {
+	if route == "" || strings.HasPrefix(route, overflowPrefix) || strings.Contains(route, signatureSeparator) {
+		return "", fmt.Errorf("%w: route %q", ErrInvalid, route)
+	}
+
+	segments := make([]string, 0, len(args)+1)
+	segments = append(segments, route)
+	for _, arg := range args {
+		segments = append(segments, string(arg))
+	}
+	payload := strings.Join(segments, Separator)
+
+	id := c.sign(payload)
+	if len(id) <= MaxLength {
+		return id, nil
+	}
+
+	if c.store == nil {
+		return "", fmt.Errorf("%w: %d characters exceed the limit of %d", ErrInvalid, len(id), MaxLength)
+	}
+	key, err := c.store.Save(ctx, payload, c.ttl)
+	if err != nil {
+		return "", fmt.Errorf("error storing custom ID state: %w", err)
+	}
+	return c.sign(overflowPrefix + key), nil
+}
+
+// Decode verifies the custom ID and returns its route segments and arguments.
+//
+// Custom IDs without arguments need no signature, because there is nothing to tamper with.
+// This keeps static custom IDs like the ones of buttons sent before signing was introduced valid.
+func (c *Codec) Decode(ctx context.Context, customID string) ([]string, error) {
+	payload, sig, signed := strings.Cut(customID, signatureSeparator)
+	if !signed {
+		if payload == "" || strings.Contains(payload, Separator) || strings.HasPrefix(payload, overflowPrefix) {
+			return nil, ErrTampered
+		}
+		return []string{payload}, nil
+	}
+	if !hmac.Equal([]byte(sig), []byte(c.signature(payload))) {
+		return nil, ErrTampered
+	}
+
+	if key, ok := strings.CutPrefix(payload, overflowPrefix); ok {
+		var err error
+		payload, err = c.load(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	segments := strings.Split(payload, Separator)
+	for i, seg := range segments {
+		segments[i] = unescaper.Replace(seg)
+	}
+	return segments, nil
+}
+
+// load returns the payload kept in the store under the given key.
+func (c *Codec) load(ctx context.Context, key string) (string, error) {
+	if c.store == nil {
+		return "", ErrExpired
+	}
+	payload, err := c.store.Load(ctx, key)
+	if err != nil {
+		return "", errors.Join(ErrExpired, err)
+	}
+	return payload, nil
+}
+
+// sign returns the payload with its signature appended.
+func (c *Codec) sign(payload string) string {
+	return payload + signatureSeparator + c.signature(payload)
+}
+
+// signature returns the signature of the payload.
+func (c *Codec) signature(payload string) string {
+	mac := hmac.New(sha256.New, c.key)
+	_, _ = mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:signatureLength])
+}
@@ -0,0 +1,173 @@
+package customid_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands/customid"
+)
+
+// memoryStore is a [customid.Store] that keeps the payloads in memory.
+type memoryStore struct {
+	// payloads are the stored payloads.
+	payloads []string
+}
+
+func (s *memoryStore) Save(_ context.Context, payload string, _ time.Duration) (string, error) {
+	s.payloads = append(s.payloads, payload)
+	return fmt.Sprint(len(s.payloads) - 1), nil
+}
+
+func (s *memoryStore) Load(_ context.Context, key string) (string, error) {
+	for i, p := range s.payloads {
+		if fmt.Sprint(i) == key {
+			return p, nil
+		}
+	}
+	return "", errors.New("not found")
+}
+
+func TestCodec(t *testing.T) {
+	tests := []struct {
+		name     string
+		route    string
+		args     []customid.Arg
+		tamper   func(id string) string
+		want     []string
+		overflow bool
+		wantErr  error
+	}{
+		{
+			name:  "typed arguments",
+			route: "raid:signup",
+			args:  []customid.Arg{customid.Snowflake(1234567890123456789), customid.String("tank"), customid.Int(42), customid.Bool(true)},
+			want:  []string{"raid", "signup", "9do1sj396nf9", "tank", "16", "1"},
+		},
+		{
+			name:  "string arguments with separators",
+			route: "note",
+			args:  []customid.Arg{customid.String("a:b~c%d")},
+			want:  []string{"note", "a:b~c%d"},
+		},
+		{
+			name:     "overflowing state is kept in the store",
+			route:    "raid:note",
+			args:     []customid.Arg{customid.String(strings.Repeat("x", 120))},
+			want:     []string{"raid", "note", strings.Repeat("x", 120)},
+			overflow: true,
+		},
+		{
+			name:  "tampered argument",
+			route: "raid:signup",
+			args:  []customid.Arg{customid.Snowflake(1), customid.String("tank")},
+			tamper: func(id string) string {
+				return strings.Replace(id, "tank", "heal", 1)
+			},
+			wantErr: customid.ErrTampered,
+		},
+		{
+			name:  "removed signature",
+			route: "raid:signup",
+			args:  []customid.Arg{customid.Snowflake(1)},
+			tamper: func(id string) string {
+				payload, _, _ := strings.Cut(id, "~")
+				return payload
+			},
+			wantErr: customid.ErrTampered,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &memoryStore{}
+			codec := customid.NewCodec(&customid.Config{Secret: "secret"}, store)
+
+			id, err := codec.Encode(t.Context(), tt.route, tt.args...)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			if len(id) > customid.MaxLength {
+				t.Errorf("Encode() = %q has %d characters, want at most %d", id, len(id), customid.MaxLength)
+			}
+			if got := len(store.payloads) > 0; got != tt.overflow {
+				t.Errorf("payload stored = %v, want %v", got, tt.overflow)
+			}
+			if tt.tamper != nil {
+				id = tt.tamper(id)
+			}
+
+			got, err := codec.Decode(t.Context(), id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Decode() error = %v, want %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Decode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCodec_ExpiredState(t *testing.T) {
+	cfg := &customid.Config{Secret: "secret"}
+	id, err := customid.NewCodec(cfg, &memoryStore{}).Encode(t.Context(), "raid:note", customid.String(strings.Repeat("x", 120)))
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	_, err = customid.NewCodec(cfg, &memoryStore{}).Decode(t.Context(), id)
+	if !errors.Is(err, customid.ErrExpired) {
+		t.Errorf("Decode() error = %v, want %v", err, customid.ErrExpired)
+	}
+}
+
+func TestCodec_Static(t *testing.T) {
+	codec := customid.NewCodec(&customid.Config{}, nil)
+	got, err := codec.Decode(t.Context(), "guild")
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if !slices.Equal(got, []string{"guild"}) {
+		t.Errorf("Decode() = %q, want [guild]", got)
+	}
+}
+
+func TestRouter(t *testing.T) {
+	r := customid.NewRouter[string]()
+	for pattern, value := range map[string]string{
+		"guild":                      "guild",
+		"raid:signup:{event}:{role}": "signup",
+		"raid:cancel:{event}":        "cancel",
+	} {
+		if err := r.Add(pattern, value); err != nil {
+			t.Fatalf("Add(%q) error = %v", pattern, err)
+		}
+	}
+
+	for _, pattern := range []string{"", "{event}", "raid:{event}:{role}", "raid:signup:{event}:{event}", "raid::x"} {
+		if err := r.Add(pattern, "invalid"); !errors.Is(err, customid.ErrInvalid) {
+			t.Errorf("Add(%q) error = %v, want %v", pattern, err, customid.ErrInvalid)
+		}
+	}
+
+	value, params, ok := r.Match([]string{"raid", "signup", string(customid.Snowflake(42)), "tank"})
+	if !ok || value != "signup" {
+		t.Fatalf("Match() = %q, %v, want signup", value, ok)
+	}
+	event, err := params.Snowflake("event")
+	if err != nil || event != snowflake.ID(42) {
+		t.Errorf("event = %v, %v, want 42", event, err)
+	}
+	if role := params.String("role"); role != "tank" {
+		t.Errorf("role = %q, want tank", role)
+	}
+
+	if _, _, ok = r.Match([]string{"raid", "signup", "1"}); ok {
+		t.Error("Match() matched a custom ID with too few segments")
+	}
+}
@@ -0,0 +1,170 @@
+package customid
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/disgoorg/snowflake/v2"
+)
+
+// Router routes decoded custom IDs to the values registered for their patterns.
+//
+// A pattern consists of literal segments and named parameters in braces separated by colons,
+// e.g. "raid:signup:{event}:{role}". A custom ID matches a pattern if it has the same number
+// of segments and all literal segments are equal.
+type Router[T any] struct {
+	// routes are the registered routes in the order they were added.
+	routes []route[T]
+}
+
+// route is a pattern and the value registered for it.
+type route[T any] struct {
+	// pattern is the pattern as it was registered.
+	pattern string
+	// segments are the segments of the pattern.
+	// Parameters are stored by their name in braces.
+	segments []string
+	// value is the value registered for the pattern.
+	value T
+}
+
+// NewRouter creates a new empty router.
+func NewRouter[T any]() *Router[T] {
+	return &Router[T]{}
+}
+
+// Add registers the value for the pattern.
+// It returns an error if the pattern is malformed or conflicts with an already registered one.
+func (r *Router[T]) Add(pattern string, value T) error {
+	segments := strings.Split(pattern, Separator)
+	if segments[0] == "" || isParam(segments[0]) || strings.HasPrefix(segments[0], overflowPrefix) {
+		return fmt.Errorf("%w: pattern %q must start with a literal segment", ErrInvalid, pattern)
+	}
+
+	names := map[string]struct{}{}
+	for _, seg := range segments {
+		if seg == "" || strings.Contains(seg, signatureSeparator) {
+			return fmt.Errorf("%w: pattern %q contains an invalid segment %q", ErrInvalid, pattern, seg)
+		}
+		if !isParam(seg) {
+			continue
+		}
+		if _, ok := names[seg]; ok {
+			return fmt.Errorf("%w: pattern %q contains the parameter %s twice", ErrInvalid, pattern, seg)
+		}
+		names[seg] = struct{}{}
+	}
+
+	for _, rt := range r.routes {
+		if conflicts(rt.segments, segments) {
+			return fmt.Errorf("%w: pattern %q conflicts with %q", ErrInvalid, pattern, rt.pattern)
+		}
+	}
+
+	r.routes = append(r.routes, route[T]{pattern: pattern, segments: segments, value: value})
+	return nil
+}
+
+// Match returns the value registered for the pattern the segments match and the parameters of the match.
+func (r *Router[T]) Match(segments []string) (value T, params Params, ok bool) {
+	for _, rt := range r.routes {
+		if params, ok = rt.match(segments); ok {
+			return rt.value, params, true
+		}
+	}
+	return value, nil, false
+}
+
+// match returns the parameters if the segments match the route.
+func (rt *route[T]) match(segments []string) (Params, bool) {
+	if len(segments) != len(rt.segments) {
+		return nil, false
+	}
+
+	params := Params{}
+	for i, seg := range rt.segments {
+		if isParam(seg) {
+			params[strings.Trim(seg, "{}")] = segments[i]
+			continue
+		}
+		if seg != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// conflicts reports whether a custom ID could match both patterns.
+func conflicts(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !isParam(a[i]) && !isParam(b[i]) && a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// isParam reports whether the pattern segment is a named parameter.
+func isParam(seg string) bool {
+	return len(seg) > 2 && strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}")
+}
+
+// Params are the named parameters of a matched custom ID.
+type Params map[string]string
+
+// String returns the string parameter with the given name.
+func (p Params) String(name string) string {
+	return p[name]
+}
+
+// Int returns the integer parameter with the given name.
+func (p Params) Int(name string) (int, error) {
+	i, err := strconv.ParseInt(p[name], base, 0)
+	if err != nil {
+		return 0, fmt.Errorf("%w: parameter %q is not an integer: %w", ErrInvalid, name, err)
+	}
+	return int(i), nil
+}
+
+// Snowflake returns the snowflake parameter with the given name.
+func (p Params) Snowflake(name string) (snowflake.ID, error) {
+	id, err := strconv.ParseUint(p[name], base, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: parameter %q is not a snowflake: %w", ErrInvalid, name, err)
+	}
+	return snowflake.ID(id), nil
+}
+
+// Bool returns the boolean parameter with the given name.
+func (p Params) Bool(name string) (bool, error) {
+	switch p[name] {
+	case "1":
+		return true, nil
+	case "0":
+		return false, nil
+	default:
+		return false, fmt.Errorf("%w: parameter %q is not a boolean", ErrInvalid, name)
+	}
+}
+
+// paramsKey is the context key for the parameters of a custom ID.
+type paramsKey struct{}
+
+// NewContext returns a copy of the context carrying the parameters.
+func NewContext(ctx context.Context, params Params) context.Context {
+	return context.WithValue(ctx, paramsKey{}, params)
+}
+
+// FromContext returns the parameters carried by the context.
+// If the context carries no parameters, an empty set is returned.
+func FromContext(ctx context.Context) Params {
+	if params, ok := ctx.Value(paramsKey{}).(Params); ok {
+		return params
+	}
+	return Params{}
+}
@@ -0,0 +1,137 @@
+package commands
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/gofiber/fiber/v3"
+	"github.com/lvlcn-t/go-kit/apimanager/fiberutils"
+	"github.com/lvlcn-t/loggerhead/logger"
+	"github.com/lvlcn-t/raid-mate/app/bot/i18n"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+)
+
+var (
+	// errMalformedRequest is returned when the body of an HTTP request cannot be decoded.
+	errMalformedRequest = svcerr.Invalid(svcerr.Msg("errors.invalid.malformed_request"))
+	// errInvalidGuildID is returned when the guild ID of an HTTP request is missing or invalid.
+	errInvalidGuildID = svcerr.Invalid(svcerr.Msg("errors.invalid.guild_id"))
+)
+
+// localized is an interaction event that knows the locale of the user who triggered it.
+type localized interface {
+	// Locale returns the locale of the user who triggered the interaction.
+	Locale() discord.Locale
+}
+
+// tr returns the message with the given key in the locale the interaction is answered in.
+// The locale is the one set for the server or else the one of the user, see [Collection.locale].
+func tr(ctx context.Context, event localized, key string, args ...any) string {
+	return i18n.T(i18n.FromContext(ctx, event.Locale()), key, args...)
+}
+
+// errorMessage returns the localized user facing message for the error.
+func errorMessage(err error, locale discord.Locale) string {
+	switch svcerr.Kind(err) {
+	case svcerr.ErrNotConfigured:
+		return i18n.T(locale, "errors.not_configured")
+	case svcerr.ErrNotFound:
+		if detail := svcerr.Detail(err); detail != "" {
+			return i18n.T(locale, "errors.not_found_detail", detail)
+		}
+		return i18n.T(locale, "errors.not_found")
+	case svcerr.ErrInvalidInput:
+		if msgs := svcerr.Messages(err); len(msgs) > 0 {
+			details := make([]string, len(msgs))
+			for i, m := range msgs {
+				details[i] = i18n.T(locale, m.Key, m.Args...)
+			}
+			return i18n.T(locale, "errors.invalid_input_detail", strings.Join(details, "; "))
+		}
+		return i18n.T(locale, "errors.invalid_input")
+	case svcerr.ErrForbidden:
+		return i18n.T(locale, "errors.forbidden")
+	case svcerr.ErrUnavailable:
+		return i18n.T(locale, "errors.unavailable")
+	case svcerr.ErrRateLimited:
+		if wait := svcerr.RetryAfter(err); wait > 0 {
+			return i18n.T(locale, "errors.rate_limited_detail", max(wait.Round(time.Second), time.Second))
+		}
+		return i18n.T(locale, "errors.rate_limited")
+	default:
+		return i18n.T(locale, "errors.internal")
+	}
+}
+
+// errorStatus returns the HTTP status code for the error.
+func errorStatus(err error) int {
+	switch svcerr.Kind(err) {
+	case svcerr.ErrNotConfigured:
+		return http.StatusConflict
+	case svcerr.ErrNotFound:
+		return http.StatusNotFound
+	case svcerr.ErrInvalidInput:
+		return http.StatusBadRequest
+	case svcerr.ErrForbidden:
+		return http.StatusForbidden
+	case svcerr.ErrUnavailable:
+		return http.StatusServiceUnavailable
+	case svcerr.ErrRateLimited:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// logError logs the error that made the command fail.
+// Errors the user can act on are expected and only logged for debugging.
+func logError(ctx context.Context, log logger.Logger, err error) {
+	if svcerr.Kind(err) == nil {
+		log.ErrorContext(ctx, "Error handling command", "error", err)
+		return
+	}
+	log.DebugContext(ctx, "Command failed", "error", err)
+}
+
+// messageResponder is an interaction event that can be answered with a message.
+type messageResponder interface {
+	localized
+	// CreateMessage responds to the interaction with a new message.
+	CreateMessage(messageCreate discord.MessageCreate, opts ...rest.RequestOpt) error
+}
+
+// replyError logs the error and answers the interaction with the localized message for it.
+// After calling this you should return from the command handler.
+func replyError(ctx context.Context, log logger.Logger, event messageResponder, err error) {
+	logError(ctx, log, err)
+	cErr := event.CreateMessage(discord.NewMessageCreateBuilder().
+		SetContent(errorMessage(err, i18n.FromContext(ctx, event.Locale()))).
+		SetEphemeral(true).
+		Build(),
+	)
+	if cErr != nil {
+		log.ErrorContext(ctx, "Error replying to interaction", "error", cErr, "cause", err)
+	}
+}
+
+// errorResponse logs the error and sends the JSON error response matching it.
+// The message is localized according to the Accept-Language header of the request.
+func errorResponse(ctx fiber.Ctx, log logger.Logger, err error) error {
+	logError(ctx.Context(), log, err)
+	status := errorStatus(err)
+	if wait := svcerr.RetryAfter(err); wait > 0 {
+		ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	}
+
+	locale := i18n.Fallback
+	if accepted := ctx.AcceptsLanguages(string(discord.LocaleEnglishUS), string(discord.LocaleGerman)); accepted != "" {
+		locale = discord.Locale(accepted)
+	}
+	return ctx.Status(status).JSON(fiberutils.NewErrorResponse(errorMessage(err, locale), status))
+}
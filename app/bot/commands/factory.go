@@ -2,39 +2,131 @@ package commands
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/snowflake/v2"
 	"github.com/gofiber/fiber/v3"
+	"github.com/lvlcn-t/loggerhead/logger"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands/customid"
+	"github.com/lvlcn-t/raid-mate/app/bot/i18n"
 	"github.com/lvlcn-t/raid-mate/app/services"
+	"github.com/lvlcn-t/raid-mate/app/services/guild"
 )
 
+// Config is the configuration for the commands.
+type Config struct {
+	// DeferAfter is the duration after which a command that has not responded yet is deferred automatically.
+	// Discord fails interactions that are not answered within 3 seconds, so longer durations are replaced with the default of 2 seconds.
+	DeferAfter time.Duration `yaml:"deferAfter" mapstructure:"deferAfter" validate:"gte=0"`
+	// PageTimeout is the duration after which the navigation buttons of paginated messages expire.
+	// Defaults to 5 minutes.
+	PageTimeout time.Duration `yaml:"pageTimeout" mapstructure:"pageTimeout" validate:"gte=0"`
+	// CustomIDs is the configuration for the custom IDs of message components.
+	CustomIDs customid.Config `yaml:"customIDs" mapstructure:"customIDs"`
+}
+
 // Collection is a collection of commands.
 type Collection struct {
+	// deferAfter is the duration after which a command that has not responded yet is deferred.
+	deferAfter time.Duration
+	// customIDs encodes and decodes the custom IDs of message components.
+	customIDs *customid.Codec
+	// components routes custom IDs to the component commands.
+	components *customid.Router[ComponentInteractionCommand]
+	// guilds is the guild service used to look up the language set for a server.
+	guilds guild.Service
 	// logs is the logs command.
 	logs *Logs
+	// parses is the command to show the parses of the raiders.
+	parses *Parses
+	// wipes is the command to analyze the deaths of the boss pulls of a report.
+	wipes *Wipes
+	// audit is the command to audit the preparation of the raiders.
+	audit *Audit
+	// gearAudit is the command to audit the equipment of the raiders.
+	gearAudit *GearAudit
+	// leaderboard is the command to rank the characters of the roster.
+	leaderboard *Leaderboard
+	// vault is the command to show the progress of the raiders towards the Great Vault.
+	vault *Vault
 	// credentials is the credentials command.
 	credentials *Credentials
 	// feedback is the feedback command.
 	feedback *Feedback
 	// profile is the profile command.
 	profile *Profile
+	// compare is the command to compare the profiles of two characters.
+	compare *Compare
 	// help is the help command.
 	help *Help
+	// main is the command to register the main character of a member.
+	main *Main
+	// settings is the command to manage the guilds linked to the server.
+	settings *Settings
+	// raiderIOProfile is the user context menu command to show the profile of a member.
+	raiderIOProfile *RaiderIOProfile
+	// attendance is the user context menu command to show the attendance of a member.
+	attendance *Attendance
+	// sendFeedback is the message context menu command to submit a message as feedback.
+	sendFeedback *SendFeedback
 	// guild is the guild component command.
 	guild *Guild
+	// paginator is the component command to turn the pages of paginated messages.
+	paginator *Paginator
 }
 
 // NewCollection creates a new collection of commands.
-func NewCollection(svcs *services.Collection) *Collection {
+func NewCollection(cfg *Config, svcs *services.Collection) *Collection {
+	deferAfter := cfg.DeferAfter
+	if deferAfter <= 0 || deferAfter >= 3*time.Second {
+		deferAfter = defaultDeferAfter
+	}
+
+	var store customid.Store
+	if svcs.State != nil {
+		store = svcs.State
+	}
+	codec := customid.NewCodec(&cfg.CustomIDs, store)
+
+	paginator := newPaginator(cfg.PageTimeout, codec)
 	c := &Collection{
-		logs:        newLogs(svcs.Guild),
-		credentials: newCredentials(svcs.Guild),
-		feedback:    newFeedback(svcs.Feedback),
-		profile:     newProfile(svcs.Guild),
-		guild:       newGuild(svcs.Guild),
+		deferAfter:      deferAfter,
+		customIDs:       codec,
+		components:      customid.NewRouter[ComponentInteractionCommand](),
+		guilds:          svcs.Guild,
+		logs:            newLogs(svcs.Guild, paginator),
+		parses:          newParses(svcs.Guild, paginator),
+		wipes:           newWipes(svcs.Guild, paginator),
+		audit:           newAudit(svcs.Guild, paginator),
+		gearAudit:       newGearAudit(svcs.Guild, paginator),
+		leaderboard:     newLeaderboard(svcs.Guild, paginator),
+		vault:           newVault(svcs.Guild, paginator),
+		credentials:     newCredentials(svcs.Guild),
+		feedback:        newFeedback(svcs.Feedback),
+		profile:         newProfile(svcs.Guild),
+		compare:         newCompare(svcs.Guild),
+		main:            newMain(svcs.Character, svcs.Guild),
+		raiderIOProfile: newRaiderIOProfile(svcs.Character, svcs.Guild),
+		attendance:      newAttendance(svcs.Character, svcs.Guild),
+		guild:           newGuild(svcs.Guild, codec),
+		paginator:       paginator,
+	}
+	c.sendFeedback = newSendFeedback(c.feedback)
+	c.settings = newSettings(svcs.Guild, codec, c.guild)
+	c.help = newHelp(c.ApplicationInteractionCommands(), paginator)
+
+	for _, cmd := range []ComponentInteractionCommand{c.guild, c.paginator} {
+		for _, pattern := range cmd.Patterns() {
+			if err := c.components.Add(pattern, cmd); err != nil {
+				panic(fmt.Sprintf("command %q: %v", cmd.Name(), err))
+			}
+		}
 	}
-	c.help = newHelp(c.ApplicationInteractionCommands())
 	return c
 }
 
@@ -43,12 +135,30 @@ func (c *Collection) GetAppCommand(name string) ApplicationInteractionCommand {
 	switch name {
 	case c.logs.Name():
 		return c.logs
+	case c.parses.Name():
+		return c.parses
+	case c.wipes.Name():
+		return c.wipes
+	case c.audit.Name():
+		return c.audit
+	case c.gearAudit.Name():
+		return c.gearAudit
+	case c.leaderboard.Name():
+		return c.leaderboard
+	case c.vault.Name():
+		return c.vault
 	case c.credentials.Name():
 		return c.credentials
 	case c.feedback.Name():
 		return c.feedback
 	case c.profile.Name():
 		return c.profile
+	case c.compare.Name():
+		return c.compare
+	case c.main.Name():
+		return c.main
+	case c.settings.Name():
+		return c.settings
 	case c.help.Name():
 		return c.help
 	default:
@@ -56,20 +166,59 @@ func (c *Collection) GetAppCommand(name string) ApplicationInteractionCommand {
 	}
 }
 
-func (c *Collection) GetComponentCommand(name string) ComponentInteractionCommand {
-	if name == c.guild.Name() {
-		return c.guild
+// GetContextMenuCommand returns the context menu command of the given type with the given name.
+func (c *Collection) GetContextMenuCommand(typ discord.ApplicationCommandType, name string) ContextMenuCommand {
+	for _, cmd := range c.ContextMenuCommands() {
+		if cmd.Type() == typ && cmd.Name() == name {
+			return cmd
+		}
 	}
 	return nil
 }
 
+// GetComponentCommand returns the component command responsible for the given custom ID
+// and the parameters encoded in it.
+// It returns an error if the custom ID has been tampered with, has expired or matches no command.
+func (c *Collection) GetComponentCommand(ctx context.Context, customID string) (ComponentInteractionCommand, customid.Params, error) {
+	segments, err := c.customIDs.Decode(ctx, customID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cmd, params, ok := c.components.Match(segments)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: no component command for %q", customid.ErrInvalid, strings.Join(segments, customid.Separator))
+	}
+	return cmd, params, nil
+}
+
+// CustomIDs returns the codec for the custom IDs of message components.
+func (c *Collection) CustomIDs() *customid.Codec {
+	return c.customIDs
+}
+
+// SetupCustomID returns the custom ID of the setup button for the given guild.
+// It is used in direct messages to the owner of a guild, where the interaction does not tell the guild.
+func (c *Collection) SetupCustomID(ctx context.Context, guildID snowflake.ID) (string, error) {
+	return c.guild.SetupCustomID(ctx, guildID)
+}
+
 // InteractionCommands returns the interaction commands in the collection.
 func (c *Collection) ApplicationInteractionCommands() []ApplicationInteractionCommand {
 	ic := []ApplicationInteractionCommand{
 		c.logs,
+		c.parses,
+		c.wipes,
+		c.audit,
+		c.gearAudit,
+		c.leaderboard,
+		c.vault,
 		c.credentials,
 		c.feedback,
 		c.profile,
+		c.compare,
+		c.main,
+		c.settings,
 	}
 	if c.help != nil {
 		ic = append(ic, c.help)
@@ -77,12 +226,158 @@ func (c *Collection) ApplicationInteractionCommands() []ApplicationInteractionCo
 	return ic
 }
 
-func (c *Collection) Infos() []discord.ApplicationCommandCreate {
-	infos := make([]discord.ApplicationCommandCreate, len(c.ApplicationInteractionCommands()))
-	for i, cmd := range c.ApplicationInteractionCommands() {
-		infos[i] = cmd.Info()
+// ContextMenuCommands returns the context menu commands in the collection.
+func (c *Collection) ContextMenuCommands() []ContextMenuCommand {
+	return []ContextMenuCommand{
+		c.raiderIOProfile,
+		c.attendance,
+		c.sendFeedback,
+	}
+}
+
+// Infos returns the information of all slash and context menu commands to register them with Discord.
+// It returns the errors of all commands whose information violates the constraints of Discord.
+func (c *Collection) Infos() ([]discord.ApplicationCommandCreate, error) {
+	cmds := c.ApplicationInteractionCommands()
+	for _, cmd := range c.ContextMenuCommands() {
+		cmds = append(cmds, cmd)
+	}
+
+	var errs []error
+	infos := make([]discord.ApplicationCommandCreate, len(cmds))
+	for i, cmd := range cmds {
+		info, err := cmd.Info()
+		errs = append(errs, err)
+		infos[i] = info
+	}
+	return infos, errors.Join(errs...)
+}
+
+// HandleApplicationCommand dispatches the event to the matching slash or context menu command.
+// The command is deferred automatically if it does not respond in time.
+func (c *Collection) HandleApplicationCommand(ctx context.Context, event *events.ApplicationCommandInteractionCreate) {
+	received := time.Now()
+	var cmd ApplicationInteractionCommand
+	if typ := event.Data.Type(); typ == discord.ApplicationCommandTypeSlash {
+		cmd = c.GetAppCommand(event.Data.CommandName())
+	} else if menu := c.GetContextMenuCommand(typ, event.Data.CommandName()); menu != nil {
+		cmd = menu
+	}
+	if cmd == nil {
+		logger.FromContext(ctx).WarnContext(ctx, "Unknown application command", "command", event.Data.CommandName())
+		return
+	}
+	ctx = i18n.NewContext(ctx, c.locale(ctx, event.GuildID(), event.Locale()))
+
+	r := newResponder(ctx, event, event.Client().Rest(), event.Respond, cmd.Ephemeral(), discord.InteractionResponseTypeDeferredCreateMessage, c.deferIn(received))
+	e := *event
+	e.Respond = r.Respond
+	cmd.Handle(ctx, &e)
+	c.finish(ctx, cmd.Name(), r)
+}
+
+// HandleComponent dispatches the event to the matching component command.
+// The parameters of the custom ID are passed to the command via the context, see [customid.FromContext].
+// The command is deferred automatically as update of the message with the component if it does not respond in time.
+func (c *Collection) HandleComponent(ctx context.Context, event *events.ComponentInteractionCreate) {
+	received := time.Now()
+	ctx = i18n.NewContext(ctx, c.locale(ctx, event.GuildID(), event.Locale()))
+	cmd, params, err := c.GetComponentCommand(ctx, event.Data.CustomID())
+	if err != nil {
+		c.rejectComponent(ctx, event, event.Data.CustomID(), err)
+		return
+	}
+	ctx = customid.NewContext(ctx, params)
+
+	r := newResponder(ctx, event, event.Client().Rest(), event.Respond, cmd.Ephemeral(), discord.InteractionResponseTypeDeferredUpdateMessage, c.deferIn(received))
+	e := *event
+	e.Respond = r.Respond
+	cmd.Handle(ctx, &e)
+	c.finish(ctx, cmd.Name(), r)
+}
+
+// HandleModalSubmit dispatches the event to the component command that opened the modal.
+// The parameters of the custom ID are passed to the command via the context, see [customid.FromContext].
+// The command is deferred automatically if it does not respond in time.
+func (c *Collection) HandleModalSubmit(ctx context.Context, event *events.ModalSubmitInteractionCreate) {
+	received := time.Now()
+	ctx = i18n.NewContext(ctx, c.locale(ctx, event.GuildID(), event.Locale()))
+	cmd, params, err := c.GetComponentCommand(ctx, event.Data.CustomID)
+	if err != nil {
+		c.rejectComponent(ctx, event, event.Data.CustomID, err)
+		return
+	}
+	ctx = customid.NewContext(ctx, params)
+
+	// Modals opened by a message component can update the message, modals opened by a command cannot.
+	deferType := discord.InteractionResponseTypeDeferredCreateMessage
+	if event.Message != nil {
+		deferType = discord.InteractionResponseTypeDeferredUpdateMessage
+	}
+	r := newResponder(ctx, event, event.Client().Rest(), event.Respond, cmd.Ephemeral(), deferType, c.deferIn(received))
+	e := *event
+	e.Respond = r.Respond
+	cmd.HandleSubmission(ctx, &e)
+	c.finish(ctx, cmd.Name(), r)
+}
+
+// HandleAutocomplete dispatches the event to the application command whose option is being typed.
+// Discord does not allow deferring suggestions, so the command is cancelled if it does not respond in time.
+func (c *Collection) HandleAutocomplete(ctx context.Context, event *events.AutocompleteInteractionCreate) {
+	cmd, ok := c.GetAppCommand(event.Data.CommandName).(AutocompleteCommand)
+	if !ok {
+		logger.FromContext(ctx).WarnContext(ctx, "Unknown autocomplete command", "command", event.Data.CommandName)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, autocompleteTimeout)
+	defer cancel()
+	cmd.HandleAutocomplete(ctx, event)
+}
+
+// rejectComponent tells the user that the component they used is no longer valid.
+func (c *Collection) rejectComponent(ctx context.Context, event messageResponder, customID string, err error) {
+	log := logger.FromContext(ctx)
+	log.WarnContext(ctx, "Rejected custom ID", "custom_id", customID, "error", err)
+	cErr := event.CreateMessage(discord.NewMessageCreateBuilder().
+		SetContent(tr(ctx, event, "errors.component_invalid")).
+		SetEphemeral(true).
+		Build(),
+	)
+	if cErr != nil {
+		log.ErrorContext(ctx, "Error replying to interaction", "error", cErr)
+	}
+}
+
+// locale returns the locale to answer an interaction in.
+// The language set for the server takes precedence over the one of the user.
+func (c *Collection) locale(ctx context.Context, guildID *snowflake.ID, user discord.Locale) discord.Locale {
+	if guildID == nil || c.guilds == nil {
+		return user
+	}
+
+	locale, err := c.guilds.GetLocale(ctx, *guildID)
+	if err != nil {
+		logger.FromContext(ctx).DebugContext(ctx, "Using the locale of the user", "guild_id", guildID.String(), "error", err)
+		return user
+	}
+	if l := discord.Locale(locale); i18n.Default().Supports(l) {
+		return l
+	}
+	return user
+}
+
+// deferIn returns the time left until an interaction received at the given time is deferred.
+// Lookups before the command is handled, e.g. of the language of the server, count against the deadline of Discord.
+func (c *Collection) deferIn(received time.Time) time.Duration {
+	return c.deferAfter - time.Since(received)
+}
+
+// finish stops the automatic deferral once the command returned.
+func (c *Collection) finish(ctx context.Context, name string, r *responder) {
+	if !r.stop() {
+		logger.FromContext(ctx).WarnContext(ctx, "Command returned without responding to the interaction", "command", name)
 	}
-	return infos
 }
 
 // Router returns a router for the collection.
@@ -103,10 +398,31 @@ func (c *Collection) Router() fiber.Router {
 type ApplicationInteractionCommand interface {
 	Command[*events.ApplicationCommandInteractionCreate]
 	// Info returns the interaction command information.
-	Info() discord.ApplicationCommandCreate
+	// It returns an error if the information violates the constraints of Discord.
+	Info() (discord.ApplicationCommandCreate, error)
+}
+
+// AutocompleteCommand is an application command that suggests values for its options while the user types.
+type AutocompleteCommand interface {
+	ApplicationInteractionCommand
+	// HandleAutocomplete is the handler for the command that is called when the user types into an option with autocomplete.
+	// It must respond with the suggestions for the focused option, see [suggest].
+	HandleAutocomplete(ctx context.Context, event *events.AutocompleteInteractionCreate)
+}
+
+// ContextMenuCommand is an application command that is triggered from the context menu of a user or a message.
+type ContextMenuCommand interface {
+	ApplicationInteractionCommand
+	// Type returns the type of the context menu the command is shown in,
+	// either [discord.ApplicationCommandTypeUser] or [discord.ApplicationCommandTypeMessage].
+	Type() discord.ApplicationCommandType
 }
 
+// ComponentInteractionCommand is a command that is triggered by a message component or a modal.
 type ComponentInteractionCommand interface {
 	Command[*events.ComponentInteractionCreate]
+	// HandleSubmission is the handler for the command that is called when a modal of the command is submitted.
 	HandleSubmission(ctx context.Context, event *events.ModalSubmitInteractionCreate)
+	// Patterns returns the custom ID patterns the command handles, see [customid.Router].
+	Patterns() []string
 }
@@ -0,0 +1,47 @@
+package commands_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/lvlcn-t/raid-mate/app/bot/commands/commandstest"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands/customid"
+)
+
+func TestCollection_HandleComponent(t *testing.T) {
+	tests := []commandTest{
+		{
+			name: "component - unknown route",
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Component(ctx, h.CustomID("raid:signup", customid.Snowflake(1), customid.String("tank")))
+			},
+			want: want{responded: true, ephemeral: true, content: "This component is no longer valid. Please run the command again."},
+		},
+	}
+
+	runCommandTests(t, tests)
+}
+
+func TestCollection_HandleApplicationCommand(t *testing.T) {
+	tests := []commandTest{
+		{
+			name: "command - slow locale lookup counts towards the deferral",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetLocaleFunc: func(_ context.Context, _ snowflake.ID) (string, error) {
+					time.Sleep(50 * time.Millisecond)
+					return "de", nil
+				},
+			}},
+			opts: []commandstest.Option{commandstest.WithDeferAfter(10 * time.Millisecond)},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "help", nil)
+			},
+			want: want{responded: true, deferred: true, ephemeral: true, embeds: []string{"Hilfe"}},
+		},
+	}
+
+	runCommandTests(t, tests)
+}
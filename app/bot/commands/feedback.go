@@ -10,7 +10,9 @@ import (
 	"github.com/gofiber/fiber/v3"
 	"github.com/lvlcn-t/go-kit/apimanager/fiberutils"
 	"github.com/lvlcn-t/loggerhead/logger"
+	"github.com/lvlcn-t/raid-mate/app/bot/i18n"
 	"github.com/lvlcn-t/raid-mate/app/services/feedback"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
 )
 
 var (
@@ -35,6 +37,11 @@ func newFeedback(svc feedback.Service) *Feedback {
 	}
 }
 
+// Ephemeral reports whether the responses of the command are only visible to the invoking user.
+func (c *Feedback) Ephemeral() bool {
+	return true
+}
+
 // Handle is the handler for the command that is called when the event is triggered.
 func (c *Feedback) Handle(ctx context.Context, event *events.ApplicationCommandInteractionCreate) {
 	log := logger.FromContext(ctx).With("command", c.Name())
@@ -43,42 +50,33 @@ func (c *Feedback) Handle(ctx context.Context, event *events.ApplicationCommandI
 
 	err := c.validateRequest(fb)
 	if err != nil {
-		err = event.CreateMessage(discord.NewMessageCreateBuilder().
-			SetContent(err.Error()).
-			SetEphemeral(true).
-			Build(),
-		)
-		if err != nil {
-			log.ErrorContext(ctx, "Error replying to interaction", "error", err)
-		}
+		replyError(ctx, log, event, err)
 		return
 	}
 
+	c.submit(ctx, log, event, fb)
+}
+
+// submit submits the feedback on behalf of the user who triggered the event and confirms it.
+func (c *Feedback) submit(ctx context.Context, log logger.Logger, event *events.ApplicationCommandInteractionCreate, fb string) {
 	guild, ok := event.Guild()
 	if !ok {
 		guild.Name = "DM"
 	}
 
-	err = c.service.Submit(ctx, feedback.Request{
+	err := c.service.Submit(ctx, feedback.Request{
 		Feedback: fb,
 		Server:   guild.Name,
 		Username: event.User().Username,
 		UserID:   event.User().ID,
 	}, event.Client())
 	if err != nil {
-		cErr := event.CreateMessage(discord.NewMessageCreateBuilder().
-			SetContent("Error while submitting feedback").
-			SetEphemeral(true).
-			Build(),
-		)
-		if cErr != nil {
-			log.ErrorContext(ctx, "Error replying to interaction", "error", cErr, "createIssueError", err)
-		}
+		replyError(ctx, log, event, err)
 		return
 	}
 
 	err = event.CreateMessage(discord.NewMessageCreateBuilder().
-		SetContentf("Feedback submitted: %q", fb).
+		SetContent(tr(ctx, event, "feedback.submitted", fb)).
 		SetEphemeral(true).
 		Build(),
 	)
@@ -93,46 +91,38 @@ func (c *Feedback) HandleHTTP(ctx fiber.Ctx) error {
 
 	req, err := fiberutils.BodyWithValidation[feedback.Request](ctx)
 	if err != nil {
-		log.DebugContext(ctx.Context(), "Error unmarshalling request", "error", err)
-		return fiberutils.BadRequestResponse(ctx, "invalid request")
+		return errorResponse(ctx, log, errors.Join(errMalformedRequest, err))
 	}
 
 	err = c.validateRequest(req.Feedback)
 	if err != nil {
-		log.DebugContext(ctx.Context(), "Error validating request", "error", err)
-		return fiberutils.BadRequestResponse(ctx, "invalid feedback")
+		return errorResponse(ctx, log, err)
 	}
 
 	err = c.service.Submit(ctx.Context(), req, nil)
 	if err != nil {
-		log.ErrorContext(ctx.Context(), "Error submitting feedback", "error", err)
-		return fiberutils.InternalServerErrorResponse(ctx, "error submitting feedback")
+		return errorResponse(ctx, log, err)
 	}
 
 	return ctx.Status(http.StatusOK).JSON(fiber.Map{"status": http.StatusText(http.StatusOK)})
 }
 
 // Info returns the interaction command information.
-func (c *Feedback) Info() discord.ApplicationCommandCreate {
+func (c *Feedback) Info() (discord.ApplicationCommandCreate, error) {
 	return NewInfoBuilder().
 		Name(c.Name(), nil).
-		Description("Submit feedback", map[discord.Locale]string{
-			discord.LocaleGerman: "Feedback einreichen",
-		}).
+		Description(i18n.Text("commands.feedback.description")).
 		Option(NewStringOptionBuilder().
 			Name("feedback", nil).
-			Description("The feedback to submit", map[discord.Locale]string{
-				discord.LocaleGerman: "Das Feedback, das eingereicht werden soll",
-			}).
-			Required(true).
-			Build(),
+			Description(i18n.Text("commands.feedback.options.feedback.description")).
+			Required(true),
 		).Build()
 }
 
 // validateRequest validates the feedback request.
 func (c *Feedback) validateRequest(fb string) error {
 	if fb == "" {
-		return errors.New("invalid feedback")
+		return svcerr.Invalid(svcerr.Msg("errors.invalid.feedback_empty"))
 	}
 
 	return nil
@@ -0,0 +1,82 @@
+package commands_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/disgoorg/disgo/bot"
+	"github.com/disgoorg/disgo/discord"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands/commandstest"
+	"github.com/lvlcn-t/raid-mate/app/services/feedback"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+	"github.com/lvlcn-t/raid-mate/app/services/upstream"
+)
+
+func TestFeedback(t *testing.T) {
+	tests := []commandTest{
+		{
+			name: "feedback - submitted",
+			services: commandstest.Services{Feedback: &commandstest.FeedbackService{
+				SubmitFunc: func(_ context.Context, _ feedback.Request, _ bot.Client) error {
+					return nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				h.AddGuild(discord.Guild{ID: commandstest.GuildID, Name: "Raid Mate"})
+				return h.Slash(ctx, "feedback", commandstest.Options{"feedback": "great bot"})
+			},
+			want: want{responded: true, ephemeral: true, content: `Feedback submitted: "great bot"`},
+			check: func(t *testing.T, h *commandstest.Harness, _ *commandstest.Recorder) {
+				calls := h.Services.Feedback.Called("Submit")
+				if len(calls) != 1 {
+					t.Fatalf("Submit called %d times, want 1", len(calls))
+				}
+				want := feedback.Request{
+					Feedback: "great bot",
+					Server:   "Raid Mate",
+					Username: commandstest.Username,
+					UserID:   commandstest.UserID,
+				}
+				if got := calls[0].Args[0].(feedback.Request); got != want {
+					t.Errorf("Submit request = %+v, want %+v", got, want)
+				}
+			},
+		},
+		{
+			name: "feedback - submitted from DM",
+			services: commandstest.Services{Feedback: &commandstest.FeedbackService{
+				SubmitFunc: func(_ context.Context, req feedback.Request, _ bot.Client) error {
+					if req.Server != "DM" {
+						return errBoom
+					}
+					return nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "feedback", commandstest.Options{"feedback": "great bot"}, commandstest.InDM())
+			},
+			want: want{responded: true, ephemeral: true, content: `Feedback submitted: "great bot"`},
+		},
+		{
+			name: "feedback - empty",
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "feedback", commandstest.Options{"feedback": ""})
+			},
+			want: want{responded: true, ephemeral: true, content: "Your input is invalid: the feedback must not be empty."},
+		},
+		{
+			name: "feedback - upstream unavailable",
+			services: commandstest.Services{Feedback: &commandstest.FeedbackService{
+				SubmitFunc: func(_ context.Context, _ feedback.Request, _ bot.Client) error {
+					return svcerr.FromUpstream(upstream.ErrUpstreamDown, "")
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "feedback", commandstest.Options{"feedback": "great bot"})
+			},
+			want: want{responded: true, ephemeral: true, content: "The external service is currently unavailable. Please try again later."},
+		},
+	}
+
+	runCommandTests(t, tests)
+}
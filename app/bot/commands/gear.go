@@ -0,0 +1,236 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/gofiber/fiber/v3"
+	"github.com/lvlcn-t/go-kit/apimanager/fiberutils"
+	"github.com/lvlcn-t/loggerhead/logger"
+	"github.com/lvlcn-t/raid-mate/app/bot/colors"
+	"github.com/lvlcn-t/raid-mate/app/bot/i18n"
+	"github.com/lvlcn-t/raid-mate/app/services/guild"
+)
+
+var (
+	_ Command[*events.ApplicationCommandInteractionCreate] = (*GearAudit)(nil)
+	_ AutocompleteCommand                                  = (*GearAudit)(nil)
+)
+
+// gearPerPage is the number of raiders shown on a single page of the table.
+const gearPerPage = 20
+
+// GearAudit is a command to audit the equipment of the raiders.
+type GearAudit struct {
+	// Base is the common base for all commands.
+	*Base[*events.ApplicationCommandInteractionCreate]
+	// service is the guild service.
+	service guild.Service
+	// paginator sends the audit on multiple pages.
+	paginator *Paginator
+}
+
+// newGearAudit creates a new gear audit command.
+func newGearAudit(svc guild.Service, paginator *Paginator) *GearAudit {
+	return &GearAudit{
+		Base:      NewBase[*events.ApplicationCommandInteractionCreate]("gear-audit"),
+		service:   svc,
+		paginator: paginator,
+	}
+}
+
+// Handle is the handler for the command that is called when the event is triggered.
+// It shows a table of the raiders followed by the problems found in the equipment of each raider.
+func (c *GearAudit) Handle(ctx context.Context, event *events.ApplicationCommandInteractionCreate) {
+	log := logger.FromContext(ctx).With("command", c.Name())
+	data := event.SlashCommandInteractionData()
+	ctx = withLinkedGuild(ctx, data)
+	audit, err := c.service.AuditGear(ctx, *event.GuildID(), data.String("character"))
+	if err != nil {
+		replyError(ctx, log, event, err)
+		return
+	}
+
+	err = c.paginator.Send(ctx, event, gearAuditPages(ctx, event, audit), false)
+	if err != nil {
+		log.ErrorContext(ctx, "Error replying to interaction", "error", err)
+	}
+}
+
+// gearAuditPages returns the pages of the table of the raiders followed by the pages with the problems of each raider.
+func gearAuditPages(ctx context.Context, event localized, audit *guild.GearAudit) []discord.Embed {
+	description := gearRulesText(ctx, event, audit.Rules)
+	if len(audit.Unavailable) > 0 {
+		description += "\n" + tr(ctx, event, "gear.unavailable", strings.Join(audit.Unavailable, ", "))
+	}
+	if len(audit.Players) == 0 {
+		return []discord.Embed{discord.NewEmbedBuilder().
+			SetTitle(tr(ctx, event, "gear.title")).
+			SetDescription(description + "\n\n" + tr(ctx, event, "gear.none")).
+			SetColor(colors.Purple.Int()).
+			Build(),
+		}
+	}
+
+	row := func(character, itemLevel, tier, enchants, gems, low string) string {
+		return fmt.Sprintf("%-12s %6s %4s %5s %4s %4s", character, itemLevel, tier, enchants, gems, low)
+	}
+	columns := row(
+		tr(ctx, event, "gear.column_character"),
+		tr(ctx, event, "gear.column_item_level"),
+		tr(ctx, event, "gear.column_tier"),
+		tr(ctx, event, "gear.column_enchants"),
+		tr(ctx, event, "gear.column_gems"),
+		tr(ctx, event, "gear.column_low"),
+	)
+
+	var pages []discord.Embed
+	for start := 0; start < len(audit.Players); start += gearPerPage {
+		lines := []string{columns}
+		for _, p := range audit.Players[start:min(start+gearPerPage, len(audit.Players))] {
+			lines = append(lines, row(
+				p.Character,
+				fmt.Sprintf("%.1f", p.ItemLevel),
+				fmt.Sprintf("%d", p.TierPieces),
+				fmt.Sprintf("%d", len(p.MissingEnchants)),
+				fmt.Sprintf("%d", len(p.EmptySockets)),
+				fmt.Sprintf("%d", len(p.LowItemLevel)),
+			))
+		}
+		pages = append(pages, discord.NewEmbedBuilder().
+			SetTitle(tr(ctx, event, "gear.title")).
+			SetDescription(description+"\n```\n"+strings.Join(lines, "\n")+"\n```").
+			SetColor(colors.Purple.Int()).
+			Build(),
+		)
+	}
+
+	var fields []discord.EmbedField
+	for _, p := range audit.Players {
+		if field, ok := playerGearField(ctx, event, audit.Rules, &p); ok {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) == 0 {
+		return pages
+	}
+	template := discord.NewEmbedBuilder().
+		SetTitle(tr(ctx, event, "gear.details_title")).
+		SetColor(colors.Purple.Int()).
+		Build()
+	return append(pages, fieldPages(template, fields, maxEmbedFields)...)
+}
+
+// playerGearField returns the field listing the problems found in the equipment of the raider.
+// It returns false if the equipment of the raider is fine.
+func playerGearField(ctx context.Context, event localized, rules guild.GearRules, p *guild.PlayerGear) (discord.EmbedField, bool) {
+	var lines []string
+	if len(p.MissingEnchants) > 0 {
+		lines = append(lines, tr(ctx, event, "gear.missing_enchants", gearSlotNames(ctx, event, p.MissingEnchants)))
+	}
+	if len(p.EmptySockets) > 0 {
+		lines = append(lines, tr(ctx, event, "gear.empty_sockets", gearSlotNames(ctx, event, p.EmptySockets)))
+	}
+	if len(p.LowItemLevel) > 0 {
+		low := make([]string, 0, len(p.LowItemLevel))
+		for _, s := range p.LowItemLevel {
+			low = append(low, fmt.Sprintf("%s (%d)", gearSlotName(ctx, event, s.Slot), s.ItemLevel))
+		}
+		lines = append(lines, tr(ctx, event, "gear.low_item_level", rules.MinItemLevel, strings.Join(low, ", ")))
+	}
+	if !p.TierBonus() {
+		lines = append(lines, tr(ctx, event, "gear.tier_pieces", p.TierPieces))
+	}
+	if len(lines) == 0 {
+		return discord.EmbedField{}, false
+	}
+	return discord.EmbedField{Name: fmt.Sprintf("%s (%s)", p.Character, p.Class), Value: strings.Join(lines, "\n")}, true
+}
+
+// gearRulesText returns the localized description of the gear rules.
+func gearRulesText(ctx context.Context, event localized, rules guild.GearRules) string {
+	itemLevel := tr(ctx, event, "gear.rules_no_item_level")
+	if rules.MinItemLevel > 0 {
+		itemLevel = tr(ctx, event, "gear.rules_item_level", rules.MinItemLevel)
+	}
+	enchants := tr(ctx, event, "gear.rules_no_enchants")
+	if len(rules.EnchantSlots) > 0 {
+		enchants = tr(ctx, event, "gear.rules_enchants", gearSlotNames(ctx, event, rules.EnchantSlots))
+	}
+	return itemLevel + " " + enchants
+}
+
+// gearSlotNames returns the comma separated localized names of the slots.
+func gearSlotNames(ctx context.Context, event localized, slots []string) string {
+	names := make([]string, 0, len(slots))
+	for _, slot := range slots {
+		names = append(names, gearSlotName(ctx, event, slot))
+	}
+	return strings.Join(names, ", ")
+}
+
+// gearSlotName returns the localized name of the slot.
+func gearSlotName(ctx context.Context, event localized, slot string) string {
+	return tr(ctx, event, "gear.slots."+slot)
+}
+
+// HandleAutocomplete suggests the linked guilds and the characters in the roster of the guild.
+func (c *GearAudit) HandleAutocomplete(ctx context.Context, event *events.AutocompleteInteractionCreate) {
+	log := logger.FromContext(ctx).With("command", c.Name())
+	if event.Data.Focused().Name == linkedGuildOption {
+		respondSuggestions(ctx, log, event, suggestLinkedGuilds(ctx, log, c.service, event))
+		return
+	}
+	if event.GuildID() == nil || event.Data.Focused().Name != "character" {
+		respondSuggestions(ctx, log, event, nil)
+		return
+	}
+
+	roster, err := c.service.GetRoster(withLinkedGuild(ctx, event.Data), *event.GuildID())
+	if err != nil {
+		logError(ctx, log, err)
+	}
+	respondSuggestions(ctx, log, event, suggest(event.Data.String("character"), roster))
+}
+
+// HandleHTTP is the handler for the command that is called when the HTTP request is triggered.
+func (c *GearAudit) HandleHTTP(ctx fiber.Ctx) error {
+	log := logger.FromContext(ctx.Context()).With("command", c.Name())
+	gid, err := fiberutils.Params(ctx, "guildID", snowflake.Parse)
+	if err != nil {
+		return errorResponse(ctx, log, errors.Join(errInvalidGuildID, err))
+	}
+
+	audit, err := c.service.AuditGear(ctx.Context(), gid, ctx.Query("character"))
+	if err != nil {
+		return errorResponse(ctx, log, err)
+	}
+	return ctx.Status(http.StatusOK).JSON(audit)
+}
+
+// Route returns the route for the command.
+func (c *GearAudit) Route() (methods []string, path string) {
+	return []string{http.MethodGet}, "/guilds/:guildID/gear-audit"
+}
+
+// Info returns the interaction command information.
+func (c *GearAudit) Info() (discord.ApplicationCommandCreate, error) {
+	return NewInfoBuilder().
+		Name(c.Name(), nil).
+		Description(i18n.Text("commands.gear-audit.description")).
+		Option(NewStringOptionBuilder().
+			Name("character", i18n.Localizations("commands.gear-audit.options.character.name")).
+			Description(i18n.Text("commands.gear-audit.options.character.description")).
+			Required(false).
+			MaxLength(maxCharacterNameLength).
+			Autocomplete(true),
+		).
+		Option(newLinkedGuildOption()).
+		Build()
+}
@@ -0,0 +1,104 @@
+package commands_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands/commandstest"
+	"github.com/lvlcn-t/raid-mate/app/services/guild"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+)
+
+func TestGearAudit(t *testing.T) {
+	tests := []commandTest{
+		{
+			name: "gear-audit - table of the roster",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				AuditGearFunc: func(_ context.Context, _ snowflake.ID, _ string) (*guild.GearAudit, error) {
+					return nightGear(), nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "gear-audit", nil)
+			},
+			want: want{responded: true, embeds: []string{"Gear audit"}},
+			check: func(t *testing.T, _ *commandstest.Harness, rec *commandstest.Recorder) {
+				want := "Items must be at least item level 615. Required enchants: Chest, Legs, Main hand.\n" +
+					"The equipment of Tifa is unknown to Battle.net.\n```\n" +
+					"Character      iLvl Tier  Ench Gems  Low\n" +
+					"Bjorn         617.4    4     3    0    2\n" +
+					"Aerith        626.5    2     0    1    0\n```"
+				if got := rec.Embeds()[0].Description; got != want {
+					t.Errorf("description = %q, want %q", got, want)
+				}
+				checkPage(t, rec, "Page 1 of 2", "Gear audit", []bool{true, true, false, false})
+			},
+		},
+		{
+			name: "gear-audit - problems of each raider",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				AuditGearFunc: func(_ context.Context, _ snowflake.ID, _ string) (*guild.GearAudit, error) {
+					return nightGear(), nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				rec := h.Slash(ctx, "gear-audit", commandstest.Options{"character": "bjorn"})
+				return h.Component(ctx, rec.Buttons()[2].CustomID)
+			},
+			want: want{responded: true, embeds: []string{"Gear problems"}},
+			check: func(t *testing.T, h *commandstest.Harness, rec *commandstest.Recorder) {
+				want := []discord.EmbedField{
+					{Name: "Bjorn (Warrior)", Value: "Missing enchants: Chest, Legs, Main hand\nBelow item level 615: Wrist (610), Legs (606)"},
+					{Name: "Aerith (Priest)", Value: "Empty sockets: Ring 1\nOnly 2 tier pieces, no full set bonus"},
+				}
+				fields := rec.Embeds()[0].Fields
+				if len(fields) != len(want) {
+					t.Fatalf("got %d fields, want %d", len(fields), len(want))
+				}
+				for i := range want {
+					if fields[i].Name != want[i].Name || fields[i].Value != want[i].Value {
+						t.Errorf("field %d = %q: %q, want %q: %q", i, fields[i].Name, fields[i].Value, want[i].Name, want[i].Value)
+					}
+				}
+				if calls := h.Services.Guild.Called("AuditGear"); len(calls) != 1 || calls[0].Args[1] != "bjorn" {
+					t.Errorf("AuditGear calls = %v, want a single call for bjorn", calls)
+				}
+			},
+		},
+		{
+			name: "gear-audit - Battle.net not configured",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				AuditGearFunc: func(_ context.Context, _ snowflake.ID, _ string) (*guild.GearAudit, error) {
+					return nil, svcerr.New(svcerr.ErrUnavailable, "Battle.net")
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "gear-audit", nil)
+			},
+			want: want{responded: true, ephemeral: true, content: "The external service is currently unavailable. Please try again later."},
+		},
+	}
+
+	runCommandTests(t, tests)
+}
+
+// nightGear returns the gear audit of the raiders with a minimum item level of 615.
+func nightGear() *guild.GearAudit {
+	return &guild.GearAudit{
+		Rules: guild.GearRules{MinItemLevel: 615, EnchantSlots: []string{"chest", "legs", "main_hand"}},
+		Players: []guild.PlayerGear{
+			{
+				Character:       "Bjorn",
+				Class:           "Warrior",
+				ItemLevel:       617.44,
+				TierPieces:      4,
+				MissingEnchants: []string{"chest", "legs", "main_hand"},
+				LowItemLevel:    []guild.GearSlot{{Slot: "wrist", ItemLevel: 610}, {Slot: "legs", ItemLevel: 606}},
+			},
+			{Character: "Aerith", Class: "Priest", ItemLevel: 626.5, TierPieces: 2, EmptySockets: []string{"finger_1"}},
+		},
+		Unavailable: []string{"Tifa"},
+	}
+}
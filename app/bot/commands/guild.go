@@ -2,35 +2,49 @@ package commands
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/events"
 	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/snowflake/v2"
 	"github.com/lvlcn-t/loggerhead/logger"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands/customid"
+	"github.com/lvlcn-t/raid-mate/app/bot/i18n"
 	"github.com/lvlcn-t/raid-mate/app/database/repo"
 	"github.com/lvlcn-t/raid-mate/app/services/guild"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
 )
 
+// Guild is a component command to set up the server and to edit the settings opened with the settings command.
+//
+// The setup walks through several steps: the setup modal links the default guild,
+// then the announcement channel and the raider role are chosen with select menus.
+// Only members with the Manage Server permission can change the settings.
 type Guild struct {
 	// Base is the common base for all commands.
 	*Base[*events.ComponentInteractionCreate]
 	// service is the guild service.
 	service guild.Service
+	// customIDs encodes the custom IDs of the setup steps.
+	customIDs *customid.Codec
 }
 
 // newGuild creates a new guild command.
-func newGuild(svc guild.Service) *Guild {
+func newGuild(svc guild.Service, codec *customid.Codec) *Guild {
 	return &Guild{
-		Base:    NewBase[*events.ComponentInteractionCreate]("guild"),
-		service: svc,
+		Base:      NewBase[*events.ComponentInteractionCreate]("guild"),
+		service:   svc,
+		customIDs: codec,
 	}
 }
 
 const (
 	// minRealmLength is the minimum length of the realm name.
-	minRealmLength = 2
+	minRealmLength = guild.MinRealmLength
 	// maxRealmLength is the maximum length of the realm name.
-	maxRealmLength = 30
+	maxRealmLength = guild.MaxRealmLength
 	// minRegionLength is the minimum length of the region name.
 	minRegionLength = 2
 	// maxRegionLength is the maximum length of the region name.
@@ -41,68 +55,332 @@ const (
 	maxFactionLength = 8
 )
 
+// The actions of the guild command that are part of its custom IDs.
+const (
+	// guildSetup is the action of the setup button sent to the owner of a server via direct message.
+	guildSetup = "setup"
+	// guildAdd is the action of the modal to link another guild.
+	guildAdd = "add"
+	// guildEdit is the action of the modal to edit a linked guild.
+	guildEdit = "edit"
+	// guildRemove is the action of the button to confirm the removal of a linked guild.
+	guildRemove = "remove"
+	// guildReset is the action of the button to confirm the reset of all settings.
+	guildReset = "reset"
+	// guildPurge is the action of the button to confirm the deletion of all data of the server.
+	guildPurge = "purge"
+	// guildCancel is the action of the button to cancel a removal or reset.
+	guildCancel = "cancel"
+	// guildChannel is the action of the select menu to choose the announcement channel.
+	guildChannel = "channel"
+	// guildRole is the action of the select menu to choose the raider role.
+	guildRole = "role"
+	// guildStep is the action of the button to skip to another setup step.
+	guildStep = "step"
+)
+
+// The steps of the setup that follow the setup modal.
+const (
+	// stepChannel is the step to choose the announcement channel.
+	stepChannel = 2
+	// stepRole is the step to choose the raider role.
+	stepRole = 3
+	// stepDone is shown once the setup is complete.
+	stepDone = 4
+)
+
+// Patterns returns the custom ID patterns the command handles.
+// The custom ID of the setup button is static, so buttons of old welcome messages keep working.
+func (c *Guild) Patterns() []string {
+	return []string{c.Name(), c.Name() + ":{action}", c.Name() + ":{action}:{arg}"}
+}
+
+// Ephemeral reports whether the responses of the command are only visible to the invoking user.
+func (c *Guild) Ephemeral() bool {
+	return true
+}
+
 // Handle is the handler for the command that is called when the event is triggered.
+// The setup buttons open the setup modal, the select menus and buttons of the setup steps
+// and the settings command update the message they are attached to.
 func (c *Guild) Handle(ctx context.Context, event *events.ComponentInteractionCreate) {
 	log := logger.FromContext(ctx).With("command", c.Name())
+	params := customid.FromContext(ctx)
+	gid, err := authorize(params, event.GuildID(), event.Member())
+	if err != nil {
+		replyError(ctx, log, event, err)
+		return
+	}
 
-	err := event.Modal(discord.NewModalCreateBuilder().SetTitle("Setup your Guild").
-		AddContainerComponents(
-			discord.NewActionRow(
-				discord.NewTextInput("guild_name", discord.TextInputStyleShort, "Name of the Guild").
-					WithRequired(true).WithPlaceholder("My Guild"),
-			),
-			discord.NewActionRow(
-				discord.NewTextInput("guild_realm", discord.TextInputStyleShort, "Realm of the Guild").
-					WithRequired(true).WithPlaceholder("Draenor").WithMinLength(minRealmLength).WithMaxLength(maxRealmLength),
-			),
-			discord.NewActionRow(
-				discord.NewTextInput("guild_region", discord.TextInputStyleShort, "Region of the Guild (EU, US, etc.)").
-					WithRequired(true).WithPlaceholder("EU").WithMinLength(minRegionLength).WithMaxLength(maxRegionLength),
-			),
-			discord.NewActionRow(
-				discord.NewTextInput("guild_faction", discord.TextInputStyleShort, "Faction of the Guild (Alliance / Horde)").
-					WithRequired(true).WithPlaceholder("Horde").WithMinLength(minFactionLength).WithMaxLength(maxFactionLength),
-			),
-		).
-		SetCustomID("guild").
-		Build(), rest.WithCtx(ctx))
+	var (
+		locale  = i18n.FromContext(ctx, event.Locale())
+		content string
+		next    int
+	)
+	switch action := params.String("action"); action {
+	case "", guildSetup:
+		err = c.openSetup(ctx, event, gid, locale)
+		if err != nil {
+			replyError(ctx, log, event, err)
+		}
+		return
+	case guildRemove:
+		var id int
+		id, err = params.Int("arg")
+		if err == nil {
+			err = c.service.RemoveWowGuild(ctx, gid, int64(id))
+		}
+		content = i18n.T(locale, "settings.removed")
+	case guildReset:
+		err = c.service.Reset(ctx, gid)
+		content = i18n.T(locale, "settings.reset_done")
+	case guildPurge:
+		g, ok := event.Guild()
+		err = authorizeOwner(g, ok, event.User().ID)
+		if err != nil {
+			break
+		}
+		var purged guild.Purged
+		purged, err = c.service.Purge(ctx, gid)
+		content = i18n.T(locale, "settings.delete_all_done", purged.WowGuilds, purged.Characters, purged.Credentials)
+	case guildCancel:
+		content = i18n.T(locale, "settings.unchanged")
+	case guildChannel:
+		data, ok := event.Data.(discord.ChannelSelectMenuInteractionData)
+		if !ok || len(data.Values) == 0 {
+			err = svcerr.Invalid(svcerr.Msg("errors.invalid.no_channel"))
+			break
+		}
+		err = c.service.SetAnnouncementChannel(ctx, gid, data.Values[0])
+		next = stepRole
+	case guildRole:
+		data, ok := event.Data.(discord.RoleSelectMenuInteractionData)
+		if !ok || len(data.Values) == 0 {
+			err = svcerr.Invalid(svcerr.Msg("errors.invalid.no_role"))
+			break
+		}
+		err = c.service.SetRaiderRole(ctx, gid, data.Values[0])
+		next = stepDone
+	case guildStep:
+		next, err = params.Int("arg")
+	default:
+		err = fmt.Errorf("%w: unknown action %q", customid.ErrInvalid, action)
+	}
+	if err != nil {
+		replyError(ctx, log, event, err)
+		return
+	}
+
+	update := discord.NewMessageUpdateBuilder().SetContent(content).ClearContainerComponents()
+	if next != 0 {
+		var rows []discord.ContainerComponent
+		content, rows, err = c.step(ctx, locale, next)
+		if err != nil {
+			replyError(ctx, log, event, err)
+			return
+		}
+		update.SetContent(content)
+		if len(rows) > 0 {
+			update.SetContainerComponents(rows...)
+		}
+	}
+
+	err = event.UpdateMessage(update.Build())
 	if err != nil {
 		log.ErrorContext(ctx, "Error replying to interaction", "error", err)
 	}
 }
 
+// HandleSubmission is the handler for the setup modal and the modals of the settings command.
+// After the setup modal the setup continues with the next step, unless it was started from a direct message.
 func (c *Guild) HandleSubmission(ctx context.Context, event *events.ModalSubmitInteractionCreate) {
 	log := logger.FromContext(ctx).With("command", c.Name())
+	params := customid.FromContext(ctx)
+	gid, err := authorize(params, event.GuildID(), event.Member())
+	if err != nil {
+		replyError(ctx, log, event, err)
+		return
+	}
+
 	name := event.Data.Text("guild_name")
 	realm := event.Data.Text("guild_realm")
 	region := event.Data.Text("guild_region")
-	_ = event.Data.Text("guild_faction")
-
-	err := c.service.Create(ctx, repo.NewGuildParams{
-		ID:           int64(*event.GuildID()), //nolint:gosec // Snowflake cannot overflow AFAIK
-		Name:         name,
-		ServerName:   realm,
-		ServerRegion: region,
-	})
-	if err != nil {
-		log.ErrorContext(ctx, "Error creating guild", "error", err)
-		err = event.CreateMessage(discord.NewMessageCreateBuilder().
-			SetContent("Error while creating guild").
-			SetEphemeral(true).
-			Build(),
+	faction := event.Data.Text("guild_faction")
+
+	locale := i18n.FromContext(ctx, event.Locale())
+	msg := discord.NewMessageCreateBuilder().SetEphemeral(true)
+	switch action := params.String("action"); action {
+	case "", guildSetup:
+		err = c.service.Create(ctx, repo.NewGuildParams{
+			ID:           int64(gid), //nolint:gosec // Snowflake cannot overflow AFAIK
+			Name:         name,
+			ServerName:   realm,
+			ServerRegion: region,
+			ServerRealm:  realm,
+			Faction:      faction,
+		})
+		if err != nil {
+			break
+		}
+		if event.GuildID() == nil {
+			msg.SetContent(i18n.T(locale, "setup.created_dm", name))
+			break
+		}
+
+		var (
+			content string
+			rows    []discord.ContainerComponent
 		)
+		content, rows, err = c.step(ctx, locale, stepChannel)
+		msg.SetContent(i18n.T(locale, "setup.created", name) + "\n\n" + content).AddContainerComponents(rows...)
+	case guildAdd:
+		err = c.service.AddWowGuild(ctx, repo.AddWowGuildParams{
+			GuildID: int64(gid), //nolint:gosec // Snowflake cannot overflow AFAIK
+			Name:    name,
+			Realm:   realm,
+			Region:  region,
+			Faction: faction,
+		})
+		msg.SetContent(i18n.T(locale, "settings.linked", name))
+	case guildEdit:
+		var id int
+		id, err = params.Int("arg")
 		if err != nil {
-			log.ErrorContext(ctx, "Error replying to interaction", "error", err)
+			break
 		}
+		err = c.service.UpdateWowGuild(ctx, repo.UpdateWowGuildParams{
+			Name:    name,
+			Realm:   realm,
+			Region:  region,
+			Faction: faction,
+			ID:      int64(id),
+			GuildID: int64(gid), //nolint:gosec // Snowflake cannot overflow AFAIK
+		})
+		msg.SetContent(i18n.T(locale, "settings.updated", name))
+	default:
+		err = fmt.Errorf("%w: unknown action %q", customid.ErrInvalid, action)
+	}
+	if err != nil {
+		replyError(ctx, log, event, err)
 		return
 	}
 
-	err = event.CreateMessage(discord.NewMessageCreateBuilder().
-		SetContent("Guild created").
-		SetEphemeral(true).
-		Build(),
-	)
+	err = event.CreateMessage(msg.Build())
 	if err != nil {
 		log.ErrorContext(ctx, "Error replying to interaction", "error", err)
 	}
 }
+
+// openSetup opens the setup modal for the server in the given locale.
+// The modal of a setup started from a direct message carries the ID of the server in its custom ID.
+func (c *Guild) openSetup(ctx context.Context, event *events.ComponentInteractionCreate, gid snowflake.ID, locale discord.Locale) error {
+	customID := c.Name()
+	if event.GuildID() == nil {
+		var err error
+		customID, err = c.SetupCustomID(ctx, gid)
+		if err != nil {
+			return err
+		}
+	}
+	return event.Modal(guildModal(locale, i18n.T(locale, "setup.modal_title"), customID, repo.WowGuild{}), rest.WithCtx(ctx))
+}
+
+// SetupCustomID returns the custom ID of the setup button for the given server.
+// It is used outside of the server, e.g. in a direct message to its owner, where the interaction does not tell the server.
+func (c *Guild) SetupCustomID(ctx context.Context, guildID snowflake.ID) (string, error) {
+	return c.customIDs.Encode(ctx, c.Name(), customid.String(guildSetup), customid.Snowflake(guildID))
+}
+
+// step returns the content and components of the given setup step in the given locale.
+func (c *Guild) step(ctx context.Context, locale discord.Locale, step int) (string, []discord.ContainerComponent, error) {
+	if step >= stepDone {
+		return i18n.T(locale, "setup.done"), nil, nil
+	}
+
+	skipID, err := c.customIDs.Encode(ctx, c.Name(), customid.String(guildStep), customid.Int(step+1))
+	if err != nil {
+		return "", nil, err
+	}
+	skip := discord.NewActionRow(discord.NewSecondaryButton(i18n.T(locale, "setup.skip"), skipID))
+
+	switch step {
+	case stepChannel:
+		selectID, err := c.customIDs.Encode(ctx, c.Name(), customid.String(guildChannel))
+		if err != nil {
+			return "", nil, err
+		}
+		menu := discord.NewChannelSelectMenu(selectID, i18n.T(locale, "setup.channel_placeholder"))
+		menu.ChannelTypes = []discord.ChannelType{discord.ChannelTypeGuildText, discord.ChannelTypeGuildNews}
+		return i18n.T(locale, "setup.step_channel"), []discord.ContainerComponent{discord.NewActionRow(menu), skip}, nil
+	default:
+		selectID, err := c.customIDs.Encode(ctx, c.Name(), customid.String(guildRole))
+		if err != nil {
+			return "", nil, err
+		}
+		menu := discord.NewRoleSelectMenu(selectID, i18n.T(locale, "setup.role_placeholder"))
+		return i18n.T(locale, "setup.step_role"), []discord.ContainerComponent{discord.NewActionRow(menu), skip}, nil
+	}
+}
+
+// authorize returns the ID of the server whose settings the interaction changes.
+// In servers only members with the Manage Server permission may change the settings.
+// In direct messages the server is taken from the custom ID, which only the setup button sent to the owner carries.
+func authorize(params customid.Params, guildID *snowflake.ID, member *discord.ResolvedMember) (snowflake.ID, error) {
+	if guildID == nil {
+		if params.String("action") != guildSetup {
+			return 0, svcerr.New(svcerr.ErrForbidden, "")
+		}
+		return params.Snowflake("arg")
+	}
+	if member == nil || !member.Permissions.Has(discord.PermissionManageGuild) {
+		return 0, svcerr.New(svcerr.ErrForbidden, "")
+	}
+	return *guildID, nil
+}
+
+// authorizeOwner returns an [svcerr.ErrForbidden] error if the user does not own the given server.
+// The server is taken from the cache, ok reports whether it was found there.
+func authorizeOwner(g discord.Guild, ok bool, userID snowflake.ID) error {
+	if !ok || g.OwnerID != userID {
+		return svcerr.New(svcerr.ErrForbidden, "")
+	}
+	return nil
+}
+
+// guildModal returns the modal to enter the settings of a guild with its labels in the given locale.
+// The inputs are prefilled with the settings of the given guild.
+func guildModal(locale discord.Locale, title, customID string, g repo.WowGuild) discord.ModalCreate {
+	return discord.NewModalCreateBuilder().SetTitle(title).
+		AddContainerComponents(
+			discord.NewActionRow(
+				discord.NewTextInput("guild_name", discord.TextInputStyleShort, i18n.T(locale, "setup.name_label")).
+					WithRequired(true).WithPlaceholder("My Guild").WithValue(g.Name).
+					WithMinLength(guild.MinNameLength).WithMaxLength(guild.MaxNameLength),
+			),
+			discord.NewActionRow(
+				discord.NewTextInput("guild_realm", discord.TextInputStyleShort, i18n.T(locale, "setup.realm_label")).
+					WithRequired(true).WithPlaceholder("Draenor").WithValue(g.Realm).
+					WithMinLength(minRealmLength).WithMaxLength(maxRealmLength),
+			),
+			discord.NewActionRow(
+				discord.NewTextInput("guild_region", discord.TextInputStyleShort, i18n.T(locale, "setup.region_label")).
+					WithRequired(true).WithPlaceholder("EU").WithValue(strings.ToUpper(g.Region)).
+					WithMinLength(minRegionLength).WithMaxLength(maxRegionLength),
+			),
+			discord.NewActionRow(
+				discord.NewTextInput("guild_faction", discord.TextInputStyleShort, i18n.T(locale, "setup.faction_label")).
+					WithRequired(true).WithPlaceholder("Horde").WithValue(titleCase(g.Faction)).
+					WithMinLength(minFactionLength).WithMaxLength(maxFactionLength),
+			),
+		).
+		SetCustomID(customID).
+		Build()
+}
+
+// titleCase returns the word with its first letter in upper case.
+func titleCase(word string) string {
+	if word == "" {
+		return ""
+	}
+	return strings.ToUpper(word[:1]) + word[1:]
+}
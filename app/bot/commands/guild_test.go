@@ -0,0 +1,197 @@
+package commands_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands/commandstest"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands/customid"
+	"github.com/lvlcn-t/raid-mate/app/database/repo"
+)
+
+func TestGuild(t *testing.T) {
+	tests := []commandTest{
+		{
+			name: "guild - setup button opens modal",
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Component(ctx, "guild", manager)
+			},
+			want: want{responded: true, modal: "guild"},
+		},
+		{
+			name: "guild - setup button without permission",
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Component(ctx, "guild")
+			},
+			want: want{responded: true, ephemeral: true, content: "You are not allowed to do this."},
+		},
+		{
+			name: "guild - setup button in direct message opens modal for the server",
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Component(ctx, h.CustomID("guild", customid.String("setup"), customid.Snowflake(commandstest.GuildID)), commandstest.InDM())
+			},
+			want: want{responded: true, modal: "guild:setup:" + strconv.FormatUint(uint64(commandstest.GuildID), 36)},
+		},
+		{
+			name: "guild - buttons in direct messages are forbidden",
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Component(ctx, h.CustomID("guild", customid.String("reset")), commandstest.InDM())
+			},
+			want: want{responded: true, ephemeral: true, content: "You are not allowed to do this."},
+			check: func(t *testing.T, h *commandstest.Harness, _ *commandstest.Recorder) {
+				if calls := h.Services.Guild.Called("Reset"); len(calls) != 0 {
+					t.Errorf("Reset called from a direct message: %v", calls)
+				}
+			},
+		},
+		{
+			name: "guild - setup modal creates guild",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				CreateFunc: func(_ context.Context, _ repo.NewGuildParams) error {
+					return nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Modal(ctx, "guild", map[string]string{
+					"guild_name":    "Raid Mate",
+					"guild_realm":   "Draenor",
+					"guild_region":  "EU",
+					"guild_faction": "Horde",
+				}, manager)
+			},
+			want: want{
+				responded: true,
+				ephemeral: true,
+				content:   "The guild Raid Mate has been set up.\n\nStep 2 of 3: Choose the channel I should post announcements in.",
+			},
+			check: func(t *testing.T, h *commandstest.Harness, rec *commandstest.Recorder) {
+				menus := rec.SelectMenus()
+				if len(menus) != 1 || menus[0].Type() != discord.ComponentTypeChannelSelectMenu {
+					t.Errorf("SelectMenus() = %v, want a channel select menu", menus)
+				}
+				if buttons := rec.Buttons(); len(buttons) != 1 || buttons[0].Label != "Skip" {
+					t.Errorf("Buttons() = %v, want a skip button", buttons)
+				}
+				calls := h.Services.Guild.Called("Create")
+				if len(calls) != 1 {
+					t.Fatalf("Create called %d times, want 1", len(calls))
+				}
+				want := repo.NewGuildParams{
+					ID:           int64(commandstest.GuildID),
+					Name:         "Raid Mate",
+					ServerName:   "Draenor",
+					ServerRegion: "EU",
+					ServerRealm:  "Draenor",
+					Faction:      "Horde",
+				}
+				if got := calls[0].Args[0].(repo.NewGuildParams); got != want {
+					t.Errorf("Create params = %+v, want %+v", got, want)
+				}
+			},
+		},
+		{
+			name: "guild - setup modal fails",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				CreateFunc: func(_ context.Context, _ repo.NewGuildParams) error {
+					return errBoom
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Modal(ctx, "guild", map[string]string{"guild_name": "Raid Mate"}, manager)
+			},
+			want: want{responded: true, ephemeral: true, content: "Something went wrong on our side. Please try again later."},
+		},
+		{
+			name: "guild - setup modal in direct message",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				CreateFunc: func(_ context.Context, _ repo.NewGuildParams) error {
+					return nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Modal(ctx, h.CustomID("guild", customid.String("setup"), customid.Snowflake(commandstest.GuildID)), map[string]string{
+					"guild_name":    "Raid Mate",
+					"guild_realm":   "Draenor",
+					"guild_region":  "EU",
+					"guild_faction": "Horde",
+				}, commandstest.InDM())
+			},
+			want: want{
+				responded: true,
+				ephemeral: true,
+				content:   "The guild Raid Mate has been set up. Run `/settings configure` in your server to choose the announcement channel and the raider role.",
+			},
+			check: func(t *testing.T, h *commandstest.Harness, _ *commandstest.Recorder) {
+				calls := h.Services.Guild.Called("Create")
+				if len(calls) != 1 || calls[0].Args[0].(repo.NewGuildParams).ID != int64(commandstest.GuildID) {
+					t.Errorf("Create calls = %v, want a single call for the server", calls)
+				}
+			},
+		},
+		{
+			name: "guild - announcement channel selected",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				SetAnnouncementChannelFunc: func(_ context.Context, _, _ snowflake.ID) error {
+					return nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Select(ctx, h.CustomID("guild", customid.String("channel")), discord.ComponentTypeChannelSelectMenu, []string{"42"}, manager)
+			},
+			want: want{responded: true, content: "Step 3 of 3: Choose the role of your raiders."},
+			check: func(t *testing.T, h *commandstest.Harness, rec *commandstest.Recorder) {
+				calls := h.Services.Guild.Called("SetAnnouncementChannel")
+				if len(calls) != 1 || calls[0].Args[0] != commandstest.GuildID || calls[0].Args[1] != snowflake.ID(42) {
+					t.Errorf("SetAnnouncementChannel calls = %v, want a single call for channel 42", calls)
+				}
+				if menus := rec.SelectMenus(); len(menus) != 1 || menus[0].Type() != discord.ComponentTypeRoleSelectMenu {
+					t.Errorf("SelectMenus() = %v, want a role select menu", menus)
+				}
+			},
+		},
+		{
+			name: "guild - raider role selected",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				SetRaiderRoleFunc: func(_ context.Context, _, _ snowflake.ID) error {
+					return nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Select(ctx, h.CustomID("guild", customid.String("role")), discord.ComponentTypeRoleSelectMenu, []string{"7"}, manager)
+			},
+			want: want{responded: true, content: "The setup is complete. Use `/settings view` to review the settings."},
+			check: func(t *testing.T, h *commandstest.Harness, rec *commandstest.Recorder) {
+				if calls := h.Services.Guild.Called("SetRaiderRole"); len(calls) != 1 || calls[0].Args[1] != snowflake.ID(7) {
+					t.Errorf("SetRaiderRole calls = %v, want a single call for role 7", calls)
+				}
+				if len(rec.SelectMenus())+len(rec.Buttons()) != 0 {
+					t.Errorf("components of the setup were not removed")
+				}
+			},
+		},
+		{
+			name: "guild - skip announcement channel",
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Component(ctx, h.CustomID("guild", customid.String("step"), customid.Int(3)), manager)
+			},
+			want: want{responded: true, content: "Step 3 of 3: Choose the role of your raiders."},
+			check: func(t *testing.T, h *commandstest.Harness, _ *commandstest.Recorder) {
+				if calls := h.Services.Guild.Called("SetAnnouncementChannel"); len(calls) != 0 {
+					t.Errorf("SetAnnouncementChannel called when skipped: %v", calls)
+				}
+			},
+		},
+		{
+			name: "guild - select without permission",
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Select(ctx, h.CustomID("guild", customid.String("channel")), discord.ComponentTypeChannelSelectMenu, []string{"42"})
+			},
+			want: want{responded: true, ephemeral: true, content: "You are not allowed to do this."},
+		},
+	}
+
+	runCommandTests(t, tests)
+}
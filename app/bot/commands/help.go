@@ -2,17 +2,18 @@ package commands
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/events"
 	"github.com/lvlcn-t/loggerhead/logger"
 	"github.com/lvlcn-t/raid-mate/app/bot/colors"
+	"github.com/lvlcn-t/raid-mate/app/bot/i18n"
 )
 
 var (
 	_ Command[*events.ApplicationCommandInteractionCreate] = (*Help)(nil)
 	_ ApplicationInteractionCommand                        = (*Help)(nil)
+	_ AutocompleteCommand                                  = (*Help)(nil)
 )
 
 // Help is a command to get help on how to use the bot.
@@ -21,18 +22,29 @@ type Help struct {
 	*Base[*events.ApplicationCommandInteractionCreate]
 	// commands is the list of commands to get help for.
 	commands []ApplicationInteractionCommand
+	// paginator sends the list of commands on multiple pages.
+	paginator *Paginator
 }
 
+// commandsPerPage is the number of commands listed on a single help page.
+const commandsPerPage = 5
+
 // newHelp creates a new help command.
-func newHelp(cmds []ApplicationInteractionCommand) *Help {
+func newHelp(cmds []ApplicationInteractionCommand, paginator *Paginator) *Help {
 	cmd := &Help{
-		Base:     NewBase[*events.ApplicationCommandInteractionCreate]("help"),
-		commands: cmds,
+		Base:      NewBase[*events.ApplicationCommandInteractionCreate]("help"),
+		commands:  cmds,
+		paginator: paginator,
 	}
 	cmd.commands = append(cmd.commands, cmd)
 	return cmd
 }
 
+// Ephemeral reports whether the responses of the command are only visible to the invoking user.
+func (c *Help) Ephemeral() bool {
+	return true
+}
+
 // Handle is the handler for the command that is called when the event is triggered.
 func (c *Help) Handle(ctx context.Context, event *events.ApplicationCommandInteractionCreate) {
 	log := logger.FromContext(ctx).With("command", c.Name())
@@ -49,7 +61,7 @@ func (c *Help) Handle(ctx context.Context, event *events.ApplicationCommandInter
 		return
 	}
 
-	info := c.getInfo(cmd)
+	info := c.getInfo(cmd, i18n.FromContext(ctx, event.Locale()))
 	err := event.CreateMessage(discord.NewMessageCreateBuilder().
 		AddEmbeds(info).
 		SetEphemeral(true).
@@ -60,28 +72,28 @@ func (c *Help) Handle(ctx context.Context, event *events.ApplicationCommandInter
 	}
 }
 
-func (c *Help) Info() discord.ApplicationCommandCreate {
-	var choices []discord.ApplicationCommandOptionChoiceString
-	for _, command := range c.commands {
-		choices = append(choices, NewStringOptionChoice(command.Name(), command.Name(), nil))
-	}
-
+func (c *Help) Info() (discord.ApplicationCommandCreate, error) {
 	return NewInfoBuilder().
 		Name(c.Name(), nil).
-		Description("Get help on how to use the bot.", map[discord.Locale]string{
-			discord.LocaleGerman: "Erhalte Hilfe, wie du den Bot benutzen kannst.",
-		}).
+		Description(i18n.Text("commands.help.description")).
 		Option(NewStringOptionBuilder().
 			Name("name", nil).
-			Description("The name of the command to get help for.", map[discord.Locale]string{
-				discord.LocaleGerman: "Der Name des Befehls, für den du Hilfe benötigst.",
-			}).
+			Description(i18n.Text("commands.help.options.name.description")).
 			Required(false).
-			Choices(choices...).
-			Build(),
+			Autocomplete(true),
 		).Build()
 }
 
+// HandleAutocomplete suggests the names of the commands.
+func (c *Help) HandleAutocomplete(ctx context.Context, event *events.AutocompleteInteractionCreate) {
+	log := logger.FromContext(ctx).With("command", c.Name())
+	names := make([]string, 0, len(c.commands))
+	for _, command := range c.commands {
+		names = append(names, command.Name())
+	}
+	respondSuggestions(ctx, log, event, suggest(event.Data.String("name"), names))
+}
+
 // lookup finds the interaction command with the given name.
 func (c *Help) lookup(name string) ApplicationInteractionCommand {
 	for _, command := range c.commands {
@@ -92,41 +104,50 @@ func (c *Help) lookup(name string) ApplicationInteractionCommand {
 	return nil
 }
 
-// getInfo returns the information for the given command.
-func (c *Help) getInfo(command ApplicationInteractionCommand) discord.Embed {
-	info := command.Info().(discord.SlashCommandCreate)
+// getInfo returns the information for the given command in the given locale.
+func (c *Help) getInfo(command ApplicationInteractionCommand, locale discord.Locale) discord.Embed {
 	return discord.NewEmbedBuilder().
 		SetTitle(command.Name()).
-		SetDescription(info.Description).
+		SetDescription(describe(command, locale)).
 		SetColor(colors.Red.Int()).
 		Build()
 }
 
+// describe returns the description of the given command in the given locale.
+// Invalid command infos are reported on startup, so they are not handled here.
+func describe(command ApplicationInteractionCommand, locale discord.Locale) string {
+	info, _ := command.Info()
+	slash, ok := info.(discord.SlashCommandCreate)
+	if !ok {
+		return ""
+	}
+	if desc, ok := slash.DescriptionLocalizations[locale]; ok {
+		return desc
+	}
+	return slash.Description
+}
+
 // sendDefaultHelp sends the default help message.
 // After calling this you should return from the command handler.
 func (c *Help) sendDefaultHelp(ctx context.Context, event *events.ApplicationCommandInteractionCreate) {
 	log := logger.FromContext(ctx).With("command", c.Name())
+	locale := i18n.FromContext(ctx, event.Locale())
 	var fields []discord.EmbedField
 	for _, cmd := range c.commands {
 		fields = append(fields, discord.EmbedField{
-			Name:   fmt.Sprintf("Command: `/%s`", cmd.Name()),
-			Value:  cmd.Info().(discord.SlashCommandCreate).Description,
+			Name:   i18n.T(locale, "help.command", cmd.Name()),
+			Value:  describe(cmd, locale),
 			Inline: toPtr(false),
 		})
 	}
 
-	embed := discord.NewEmbedBuilder().
-		SetTitle("Help").
-		SetDescription("Here are the available commands:").
+	template := discord.NewEmbedBuilder().
+		SetTitle(i18n.T(locale, "help.title")).
+		SetDescription(i18n.T(locale, "help.description")).
 		SetColor(colors.Red.Int()).
-		AddFields(fields...).
 		Build()
 
-	err := event.CreateMessage(discord.NewMessageCreateBuilder().
-		AddEmbeds(embed).
-		SetEphemeral(true).
-		Build(),
-	)
+	err := c.paginator.Send(ctx, event, fieldPages(template, fields, commandsPerPage), true)
 	if err != nil {
 		log.ErrorContext(ctx, "Error replying to interaction", "error", err)
 	}
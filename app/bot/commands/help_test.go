@@ -0,0 +1,53 @@
+package commands_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lvlcn-t/raid-mate/app/bot/commands/commandstest"
+)
+
+func TestHelp(t *testing.T) {
+	tests := []commandTest{
+		{
+			name: "help - autocomplete suggests commands",
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Autocomplete(ctx, "help", commandstest.Options{"name": "P"}, "name")
+			},
+			want: want{responded: true, suggestions: []string{"parses", "profile", "wipes", "compare", "help"}},
+		},
+		{
+			name: "help - all commands",
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "help", nil)
+			},
+			want: want{responded: true, ephemeral: true, embeds: []string{"Help"}},
+			check: func(t *testing.T, h *commandstest.Harness, rec *commandstest.Recorder) {
+				// The help lists five commands per page.
+				total := len(h.Commands.ApplicationInteractionCommands())
+				if got, want := len(rec.Embeds()[0].Fields), min(total, 5); got != want {
+					t.Errorf("help lists %d commands on the first page, want %d", got, want)
+				}
+				if got, want := len(rec.Buttons()) > 0, total > 5; got != want {
+					t.Errorf("help has navigation buttons = %v, want %v", got, want)
+				}
+			},
+		},
+		{
+			name: "help - single command",
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "help", commandstest.Options{"name": "logs"})
+			},
+			want: want{responded: true, ephemeral: true, embeds: []string{"logs"}},
+		},
+		{
+			name: "help - unknown command",
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "help", commandstest.Options{"name": "dance"})
+			},
+			want: want{responded: true, ephemeral: true, embeds: []string{"Help"}},
+		},
+	}
+
+	runCommandTests(t, tests)
+}
@@ -0,0 +1,236 @@
+package commands
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/gofiber/fiber/v3"
+	"github.com/lvlcn-t/go-kit/apimanager/fiberutils"
+	"github.com/lvlcn-t/loggerhead/logger"
+	"github.com/lvlcn-t/raid-mate/app/bot/colors"
+	"github.com/lvlcn-t/raid-mate/app/bot/i18n"
+	"github.com/lvlcn-t/raid-mate/app/services/guild"
+)
+
+var (
+	_ Command[*events.ApplicationCommandInteractionCreate] = (*Leaderboard)(nil)
+	_ AutocompleteCommand                                  = (*Leaderboard)(nil)
+)
+
+const (
+	// leaderboardMythicPlus is the subcommand to show the Mythic+ leaderboard.
+	leaderboardMythicPlus = "mplus"
+	// leaderboardPerPage is the number of characters shown on a single page of the leaderboard.
+	leaderboardPerPage = 20
+	// weeklyLeaderboardSize is the number of characters shown in the weekly post.
+	weeklyLeaderboardSize = 20
+)
+
+// Leaderboard is a command to rank the characters of the roster.
+type Leaderboard struct {
+	// Base is the common base for all commands.
+	*Base[*events.ApplicationCommandInteractionCreate]
+	// service is the guild service.
+	service guild.Service
+	// paginator sends the leaderboard on multiple pages.
+	paginator *Paginator
+}
+
+// newLeaderboard creates a new leaderboard command.
+func newLeaderboard(svc guild.Service, paginator *Paginator) *Leaderboard {
+	return &Leaderboard{
+		Base:      NewBase[*events.ApplicationCommandInteractionCreate]("leaderboard"),
+		service:   svc,
+		paginator: paginator,
+	}
+}
+
+// Handle is the handler for the command that is called when the event is triggered.
+// It ranks the characters of the roster by their Mythic+ score in the current season.
+func (c *Leaderboard) Handle(ctx context.Context, event *events.ApplicationCommandInteractionCreate) {
+	log := logger.FromContext(ctx).With("command", c.Name())
+	data := event.SlashCommandInteractionData()
+	if data.SubCommandName == nil || *data.SubCommandName != leaderboardMythicPlus {
+		replyError(ctx, log, event, fmt.Errorf("unknown subcommand %v", data.SubCommandName))
+		return
+	}
+
+	ctx = withLinkedGuild(ctx, data)
+	lb, err := c.service.GetLeaderboard(ctx, *event.GuildID(), data.String("role"))
+	if err != nil {
+		replyError(ctx, log, event, err)
+		return
+	}
+
+	err = c.paginator.Send(ctx, event, leaderboardPages(i18n.FromContext(ctx, event.Locale()), lb), false)
+	if err != nil {
+		log.ErrorContext(ctx, "Error replying to interaction", "error", err)
+	}
+}
+
+// leaderboardPages returns the pages of the table of the characters ranked by their score.
+func leaderboardPages(locale discord.Locale, lb *guild.Leaderboard) []discord.Embed {
+	description := leaderboardDescription(locale, lb)
+	if len(lb.Players) == 0 {
+		return []discord.Embed{discord.NewEmbedBuilder().
+			SetTitle(i18n.T(locale, "leaderboard.title")).
+			SetDescription(description + "\n\n" + i18n.T(locale, "leaderboard.none")).
+			SetColor(colors.Orange.Int()).
+			Build(),
+		}
+	}
+
+	var pages []discord.Embed
+	for start := 0; start < len(lb.Players); start += leaderboardPerPage {
+		end := min(start+leaderboardPerPage, len(lb.Players))
+		pages = append(pages, discord.NewEmbedBuilder().
+			SetTitle(i18n.T(locale, "leaderboard.title")).
+			SetDescription(description+"\n"+leaderboardTable(locale, lb, start, lb.Players[start:end])).
+			SetColor(colors.Orange.Int()).
+			Build(),
+		)
+	}
+	return pages
+}
+
+// WeeklyLeaderboardMessage creates the weekly post of the leaderboard in the given locale.
+// It shows the characters who gained the most score since the previous snapshot.
+func WeeklyLeaderboardMessage(locale discord.Locale, lb *guild.Leaderboard) discord.MessageCreate {
+	gainers := slices.DeleteFunc(slices.Clone(lb.Players), func(e guild.LeaderboardEntry) bool { return e.Gain == nil || *e.Gain <= 0 })
+	slices.SortStableFunc(gainers, func(a, b guild.LeaderboardEntry) int {
+		return cmp.Or(cmp.Compare(*b.Gain, *a.Gain), strings.Compare(a.Character, b.Character))
+	})
+
+	description := leaderboardDescription(locale, lb) + "\n"
+	if len(gainers) == 0 {
+		description += "\n" + i18n.T(locale, "leaderboard.no_gains")
+	} else {
+		description += leaderboardTable(locale, lb, 0, gainers[:min(weeklyLeaderboardSize, len(gainers))])
+	}
+	if len(lb.Players) > 0 {
+		best := lb.Players[0]
+		description += "\n" + i18n.T(locale, "leaderboard.best", best.Character, best.Score)
+	}
+
+	return discord.NewMessageCreateBuilder().
+		AddEmbeds(discord.NewEmbedBuilder().
+			SetTitle(i18n.T(locale, "leaderboard.weekly_title")).
+			SetDescription(description).
+			SetColor(colors.Orange.Int()).
+			Build(),
+		).
+		Build()
+}
+
+// leaderboardDescription returns the season and the role the leaderboard ranks and the time the gains are relative to.
+func leaderboardDescription(locale discord.Locale, lb *guild.Leaderboard) string {
+	description := i18n.T(locale, "leaderboard.season", cmp.Or(lb.Season, "-"))
+	if lb.Role != "" {
+		description += "\n" + i18n.T(locale, "leaderboard.role", i18n.T(locale, "leaderboard.roles."+lb.Role))
+	}
+	if !lb.Since.IsZero() {
+		description += "\n" + i18n.T(locale, "leaderboard.since", lb.Since.Unix())
+	}
+	return description
+}
+
+// leaderboardTable returns the table of the given characters, ranked from the given offset on.
+func leaderboardTable(locale discord.Locale, lb *guild.Leaderboard, offset int, players []guild.LeaderboardEntry) string {
+	row := func(rank, character, spec, score, gain string) string {
+		return fmt.Sprintf("%3s %-12s %-18s %6s %6s", rank, character, spec, score, gain)
+	}
+	lines := []string{row("#",
+		i18n.T(locale, "leaderboard.column_character"),
+		i18n.T(locale, "leaderboard.column_spec"),
+		i18n.T(locale, "leaderboard.column_score"),
+		i18n.T(locale, "leaderboard.column_gain"),
+	)}
+	for i, p := range players {
+		// Without a snapshot there is nothing the gains could be relative to.
+		gain := "-"
+		switch {
+		case p.Gain != nil:
+			gain = fmt.Sprintf("%+.1f", *p.Gain)
+		case !lb.Since.IsZero():
+			gain = i18n.T(locale, "leaderboard.new")
+		}
+		lines = append(lines, row(
+			fmt.Sprintf("%d", offset+i+1),
+			p.Character,
+			fmt.Sprintf("%s %s", p.Spec, p.Class),
+			fmt.Sprintf("%.1f", p.Score),
+			gain,
+		))
+	}
+	return "```\n" + strings.Join(lines, "\n") + "\n```"
+}
+
+// HandleAutocomplete suggests the linked guilds.
+func (c *Leaderboard) HandleAutocomplete(ctx context.Context, event *events.AutocompleteInteractionCreate) {
+	log := logger.FromContext(ctx).With("command", c.Name())
+	if event.Data.Focused().Name != linkedGuildOption {
+		respondSuggestions(ctx, log, event, nil)
+		return
+	}
+	respondSuggestions(ctx, log, event, suggestLinkedGuilds(ctx, log, c.service, event))
+}
+
+// HandleHTTP is the handler for the command that is called when the HTTP request is triggered.
+// With a character it returns the scores of the character in all weekly snapshots, otherwise the leaderboard.
+func (c *Leaderboard) HandleHTTP(ctx fiber.Ctx) error {
+	log := logger.FromContext(ctx.Context()).With("command", c.Name())
+	gid, err := fiberutils.Params(ctx, "guildID", snowflake.Parse)
+	if err != nil {
+		return errorResponse(ctx, log, errors.Join(errInvalidGuildID, err))
+	}
+
+	if character := ctx.Params("character"); character != "" {
+		history, err := c.service.GetScoreHistory(ctx.Context(), gid, character)
+		if err != nil {
+			return errorResponse(ctx, log, err)
+		}
+		return ctx.Status(http.StatusOK).JSON(history)
+	}
+
+	lb, err := c.service.GetLeaderboard(ctx.Context(), gid, ctx.Query("role"))
+	if err != nil {
+		return errorResponse(ctx, log, err)
+	}
+	return ctx.Status(http.StatusOK).JSON(lb)
+}
+
+// Route returns the route for the command.
+func (c *Leaderboard) Route() (methods []string, path string) {
+	return []string{http.MethodGet}, "/guilds/:guildID/leaderboard/mplus/:character?"
+}
+
+// Info returns the interaction command information.
+func (c *Leaderboard) Info() (discord.ApplicationCommandCreate, error) {
+	choices := make([]discord.ApplicationCommandOptionChoice, 0, len(guild.Roles))
+	for _, role := range guild.Roles {
+		choices = append(choices, NewStringOptionChoice(role, role, i18n.Localizations("commands.leaderboard.mplus.options.role.choices."+role)))
+	}
+
+	return NewInfoBuilder().
+		Name(c.Name(), nil).
+		Description(i18n.Text("commands.leaderboard.description")).
+		SubCommand(NewSubCommandBuilder().
+			Name(leaderboardMythicPlus, nil).
+			Description(i18n.Text("commands.leaderboard.mplus.description")).
+			Option(NewStringOptionBuilder().
+				Name("role", i18n.Localizations("commands.leaderboard.mplus.options.role.name")).
+				Description(i18n.Text("commands.leaderboard.mplus.options.role.description")).
+				Required(false).
+				Choices(choices...),
+			).
+			Option(newLinkedGuildOption()),
+		).Build()
+}
@@ -0,0 +1,119 @@
+package commands_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands/commandstest"
+	"github.com/lvlcn-t/raid-mate/app/services/guild"
+)
+
+func TestLeaderboard(t *testing.T) {
+	tests := []commandTest{
+		{
+			name: "leaderboard - ranked table",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetLeaderboardFunc: func(_ context.Context, _ snowflake.ID, _ string) (*guild.Leaderboard, error) {
+					return seasonLeaderboard(), nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "leaderboard mplus", nil)
+			},
+			want: want{responded: true, embeds: []string{"Mythic+ leaderboard"}},
+			check: func(t *testing.T, _ *commandstest.Harness, rec *commandstest.Recorder) {
+				want := "Season: season-tww-1\nGains since <t:1726632000:f>\n```\n" +
+					"  # Character    Spec                Score   Gain\n" +
+					"  1 Bjorn        Protection Warrior 3012.5 +112.5\n" +
+					"  2 Aerith       Holy Paladin       2874.0   +0.0\n" +
+					"  3 Tifa         Windwalker Monk    1500.0    new\n```"
+				if got := rec.Embeds()[0].Description; got != want {
+					t.Errorf("description = %q, want %q", got, want)
+				}
+			},
+		},
+		{
+			name: "leaderboard - role is passed",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetLeaderboardFunc: func(_ context.Context, _ snowflake.ID, role string) (*guild.Leaderboard, error) {
+					return &guild.Leaderboard{Season: "season-tww-1", Role: role, Players: []guild.LeaderboardEntry{}}, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "leaderboard mplus", commandstest.Options{"role": "tank"}, commandstest.WithLocale(discord.LocaleGerman))
+			},
+			want: want{responded: true, embeds: []string{"Mythisch+-Bestenliste"}},
+			check: func(t *testing.T, h *commandstest.Harness, rec *commandstest.Recorder) {
+				want := "Saison: season-tww-1\nSortiert nach der Wertung als Tank.\n\n" +
+					"Kein Charakter des Rosters hat in dieser Saison eine Wertung."
+				if got := rec.Embeds()[0].Description; got != want {
+					t.Errorf("description = %q, want %q", got, want)
+				}
+				if calls := h.Services.Guild.Called("GetLeaderboard"); len(calls) != 1 || calls[0].Args[1] != "tank" {
+					t.Errorf("GetLeaderboard calls = %v, want a single call for tank", calls)
+				}
+			},
+		},
+	}
+
+	runCommandTests(t, tests)
+}
+
+func TestWeeklyLeaderboardMessage(t *testing.T) {
+	tests := []struct {
+		name   string
+		locale discord.Locale
+		lb     *guild.Leaderboard
+		want   string
+	}{
+		{
+			name:   "gainers only",
+			locale: discord.LocaleEnglishUS,
+			lb:     seasonLeaderboard(),
+			want: "Season: season-tww-1\nGains since <t:1726632000:f>\n```\n" +
+				"  # Character    Spec                Score   Gain\n" +
+				"  1 Bjorn        Protection Warrior 3012.5 +112.5\n```\n" +
+				"The highest score has Bjorn with **3012.5**.",
+		},
+		{
+			name:   "no gains",
+			locale: discord.LocaleGerman,
+			lb: &guild.Leaderboard{Season: "season-tww-1", Since: time.Unix(1726632000, 0), Players: []guild.LeaderboardEntry{
+				{Character: "Aerith", Realm: "Draenor", Class: "Paladin", Spec: "Holy", Score: 2874, Gain: new(float64)},
+			}},
+			want: "Saison: season-tww-1\nZuwachs seit <t:1726632000:f>\n\n" +
+				"Diese Woche hat niemand Wertung dazugewonnen.\n" +
+				"Die höchste Wertung hat Aerith mit **2874.0**.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := commands.WeeklyLeaderboardMessage(tt.locale, tt.lb)
+			if len(msg.Embeds) != 1 {
+				t.Fatalf("got %d embeds, want 1", len(msg.Embeds))
+			}
+			if got := msg.Embeds[0].Description; got != tt.want {
+				t.Errorf("description = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// seasonLeaderboard returns the Mythic+ leaderboard with the gains since the snapshot after the reset of the first week.
+func seasonLeaderboard() *guild.Leaderboard {
+	gain := 112.5
+	return &guild.Leaderboard{
+		Season: "season-tww-1",
+		Since:  time.Date(2024, 9, 18, 4, 0, 0, 0, time.UTC),
+		Players: []guild.LeaderboardEntry{
+			{Character: "Bjorn", Realm: "Draenor", Class: "Warrior", Spec: "Protection", Score: 3012.5, Gain: &gain},
+			{Character: "Aerith", Realm: "Draenor", Class: "Paladin", Spec: "Holy", Score: 2874, Gain: new(float64)},
+			{Character: "Tifa", Realm: "Draenor", Class: "Monk", Spec: "Windwalker", Score: 1500},
+		},
+	}
+}
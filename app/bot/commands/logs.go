@@ -3,8 +3,8 @@ package commands
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
-	"strings"
 	"time"
 
 	"github.com/disgoorg/disgo/discord"
@@ -13,12 +13,15 @@ import (
 	"github.com/gofiber/fiber/v3"
 	"github.com/lvlcn-t/go-kit/apimanager/fiberutils"
 	"github.com/lvlcn-t/loggerhead/logger"
+	"github.com/lvlcn-t/raid-mate/app/bot/colors"
+	"github.com/lvlcn-t/raid-mate/app/bot/i18n"
 	"github.com/lvlcn-t/raid-mate/app/services/guild"
 )
 
 var (
 	_ Command[*events.ApplicationCommandInteractionCreate] = (*Logs)(nil)
 	_ ApplicationInteractionCommand                        = (*Logs)(nil)
+	_ AutocompleteCommand                                  = (*Logs)(nil)
 )
 
 // Logs is a command to get the logs for a guild.
@@ -27,13 +30,16 @@ type Logs struct {
 	*Base[*events.ApplicationCommandInteractionCreate]
 	// service is the guild service.
 	service guild.Service
+	// paginator sends the reports on multiple pages.
+	paginator *Paginator
 }
 
 // newLogs creates a new logs command.
-func newLogs(svc guild.Service) *Logs {
+func newLogs(svc guild.Service, paginator *Paginator) *Logs {
 	return &Logs{
-		Base:    NewBase[*events.ApplicationCommandInteractionCreate]("logs"),
-		service: svc,
+		Base:      NewBase[*events.ApplicationCommandInteractionCreate]("logs"),
+		service:   svc,
+		paginator: paginator,
 	}
 }
 
@@ -41,106 +47,146 @@ func newLogs(svc guild.Service) *Logs {
 func (c *Logs) Handle(ctx context.Context, event *events.ApplicationCommandInteractionCreate) {
 	log := logger.FromContext(ctx).With("command", c.Name())
 	data := event.SlashCommandInteractionData()
-	date := data.String("date")
-	if date == "" {
-		date = time.Now().Format(time.DateOnly)
+	ctx = withLinkedGuild(ctx, data)
+	if code, ok := data.OptString("report"); ok {
+		report, err := c.service.GetReport(ctx, *event.GuildID(), code)
+		if err != nil {
+			replyError(ctx, log, event, err)
+			return
+		}
+		err = c.paginator.Send(ctx, event, reportPages(ctx, event, "", report), false)
+		if err != nil {
+			log.ErrorContext(ctx, "Error replying to interaction", "error", err)
+		}
+		return
 	}
 
-	d, err := c.parseDate(date)
+	reports, err := c.service.GetReports(ctx, *event.GuildID(), data.String("date"))
 	if err != nil {
-		cErr := event.CreateMessage(discord.NewMessageCreateBuilder().
-			SetContent(err.Error()).
-			SetEphemeral(true).
-			Build(),
-		)
-		if cErr != nil {
-			log.ErrorContext(ctx, "Error replying to interaction", "error", cErr, "parseDateError", err)
-		}
+		replyError(ctx, log, event, err)
 		return
 	}
 
-	logs, err := c.service.GetReports(ctx, *event.GuildID(), d)
-	if err != nil {
-		cErr := event.CreateMessage(discord.NewMessageCreateBuilder().
-			SetContent("Error while getting logs").
-			SetEphemeral(true).
+	from, to := reports.Period.From.Format(time.DateOnly), reports.Period.To.Format(time.DateOnly)
+	header := tr(ctx, event, "logs.title", from)
+	if from != to {
+		header = tr(ctx, event, "logs.title_range", from, to)
+	}
+
+	var pages []discord.Embed
+	for i := range reports.Reports {
+		pages = append(pages, reportPages(ctx, event, header, &reports.Reports[i])...)
+	}
+	if len(pages) == 0 {
+		pages = append(pages, discord.NewEmbedBuilder().
+			SetTitle(header).
+			SetDescription(tr(ctx, event, "logs.none")).
+			SetColor(colors.Red.Int()).
 			Build(),
 		)
-		if cErr != nil {
-			log.ErrorContext(ctx, "Error replying to interaction", "error", cErr, "getLogsError", err)
-		}
-		return
 	}
-
-	err = event.CreateMessage(discord.NewMessageCreateBuilder().
-		SetContent(strings.Join(logs, "\n")).
-		Build(),
-	)
+	err = c.paginator.Send(ctx, event, pages, false)
 	if err != nil {
 		log.ErrorContext(ctx, "Error replying to interaction", "error", err)
 	}
 }
 
+// reportPages returns the pages showing the boss encounters of the report.
+// The header is shown above the title of the report if it is not empty.
+// The report is split into multiple pages if it has more encounters than an embed can hold.
+func reportPages(ctx context.Context, event localized, header string, report *guild.Report) []discord.Embed {
+	description := tr(ctx, event, "logs.report", report.Owner, report.Start.Unix(), report.End.Unix())
+	if report.Zone != "" {
+		description = report.Zone + "\n" + description
+	}
+	if len(report.Encounters) == 0 {
+		description += "\n\n" + tr(ctx, event, "logs.no_encounters")
+	}
+
+	builder := discord.NewEmbedBuilder().
+		SetTitle(report.Title).
+		SetURL(report.URL).
+		SetDescription(description).
+		SetColor(colors.Red.Int())
+	if header != "" {
+		builder.SetAuthorName(header)
+	}
+
+	fields := make([]discord.EmbedField, 0, len(report.Encounters))
+	for _, e := range report.Encounters {
+		name := e.Boss
+		if e.Difficulty != "" {
+			name = fmt.Sprintf("%s (%s)", e.Boss, e.Difficulty)
+		}
+
+		duration := e.Duration.Round(time.Second).String()
+		value := tr(ctx, event, "logs.kill", e.Pulls, duration)
+		if !e.Kill {
+			value = tr(ctx, event, "logs.wipe", e.BestPercent, e.Pulls, duration)
+		}
+		fields = append(fields, discord.EmbedField{Name: name, Value: value})
+	}
+	return fieldPages(builder.Build(), fields, maxEmbedFields)
+}
+
+// HandleAutocomplete suggests the linked guilds.
+func (c *Logs) HandleAutocomplete(ctx context.Context, event *events.AutocompleteInteractionCreate) {
+	log := logger.FromContext(ctx).With("command", c.Name())
+	if event.Data.Focused().Name != linkedGuildOption {
+		respondSuggestions(ctx, log, event, nil)
+		return
+	}
+	respondSuggestions(ctx, log, event, suggestLinkedGuilds(ctx, log, c.service, event))
+}
+
 // HandleHTTP is the handler for the command that is called when the HTTP request is triggered.
+// It returns the report with the given ID or the reports of the given period if no report ID is given.
 func (c *Logs) HandleHTTP(ctx fiber.Ctx) error {
 	log := logger.FromContext(ctx.Context()).With("command", c.Name())
 	gid, err := fiberutils.Params(ctx, "guildID", snowflake.Parse)
 	if err != nil {
-		log.DebugContext(ctx.Context(), "Error parsing guild ID", "error", err)
-		return fiberutils.BadRequestResponse(ctx, "missing or invalid guild ID")
+		return errorResponse(ctx, log, errors.Join(errInvalidGuildID, err))
 	}
 
-	date, err := c.parseDate(ctx.Query("date", time.Now().Format(time.DateOnly)))
-	if err != nil {
-		log.DebugContext(ctx.Context(), "Error parsing date", "error", err)
-		return fiberutils.BadRequestResponse(ctx, "invalid date")
+	if code := ctx.Params("reportID"); code != "" {
+		report, err := c.service.GetReport(ctx.Context(), gid, code)
+		if err != nil {
+			return errorResponse(ctx, log, err)
+		}
+		return ctx.Status(http.StatusOK).JSON(report)
 	}
 
-	logs, err := c.service.GetReports(ctx.Context(), gid, date)
+	reports, err := c.service.GetReports(ctx.Context(), gid, ctx.Query("date"))
 	if err != nil {
-		return fiberutils.InternalServerErrorResponse(ctx, "Error while getting logs")
+		return errorResponse(ctx, log, err)
 	}
 
-	return ctx.Status(http.StatusOK).JSON(fiber.Map{"logs": logs})
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"logs": reports.Reports,
+		"from": reports.Period.From.Format(time.DateOnly),
+		"to":   reports.Period.To.Format(time.DateOnly),
+	})
 }
 
 // Route returns the route for the command.
 func (c *Logs) Route() (methods []string, path string) {
-	return []string{http.MethodGet}, "/guilds/:guildID/logs"
+	return []string{http.MethodGet}, "/guilds/:guildID/logs/:reportID?"
 }
 
-func (c *Logs) Info() discord.ApplicationCommandCreate {
+func (c *Logs) Info() (discord.ApplicationCommandCreate, error) {
 	return NewInfoBuilder().
 		Name(c.Name(), nil).
-		Description("Fetch guild logs.", map[discord.Locale]string{
-			discord.LocaleGerman: "Hole Gilde-Logs.",
-		}).
+		Description(i18n.Text("commands.logs.description")).
 		Option(NewStringOptionBuilder().
-			Name("date", map[discord.Locale]string{
-				discord.LocaleGerman: "datum",
-			}).
-			Description("Date of logs (YYYY-MM-DD or YYYY.MM.DD). Defaults to today.", map[discord.Locale]string{
-				discord.LocaleGerman: "Datum der Logs (JJJJ-MM-TT oder JJJJ.MM.TT). Standard ist heute.",
-			}).
-			Required(false).
-			Build(),
-		).Build()
-}
-
-func (c *Logs) parseDate(date string) (time.Time, error) {
-	layouts := []string{
-		time.DateOnly,
-		strings.ReplaceAll(time.DateOnly, "-", "."),
-		"02.01.2006",
-		"02-01-2006",
-	}
-
-	for _, layout := range layouts {
-		d, err := time.Parse(layout, date)
-		if err == nil {
-			return d, nil
-		}
-	}
-
-	return time.Time{}, errors.New("Invalid date format")
+			Name("date", i18n.Localizations("commands.logs.options.date.name")).
+			Description(i18n.Text("commands.logs.options.date.description")).
+			Required(false),
+		).
+		Option(NewStringOptionBuilder().
+			Name("report", i18n.Localizations("commands.logs.options.report.name")).
+			Description(i18n.Text("commands.logs.options.report.description")).
+			Required(false),
+		).
+		Option(newLinkedGuildOption()).
+		Build()
 }
@@ -0,0 +1,331 @@
+package commands_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands/commandstest"
+	"github.com/lvlcn-t/raid-mate/app/database/repo"
+	"github.com/lvlcn-t/raid-mate/app/services/guild"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+	"github.com/lvlcn-t/raid-mate/app/services/upstream"
+)
+
+func TestLogs(t *testing.T) {
+	linked := linkedGuilds()
+
+	tests := []commandTest{
+		{
+			name: "logs - reports of the given date",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetReportsFunc: func(_ context.Context, _ snowflake.ID, _ string) (*guild.Reports, error) {
+					return dayReports(raidReport()), nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "logs", commandstest.Options{"date": "2024.05.07"})
+			},
+			want: want{responded: true, embeds: []string{"Nerub-ar Palace Heroic"}},
+			check: func(t *testing.T, h *commandstest.Harness, rec *commandstest.Recorder) {
+				embed := rec.Embeds()[0]
+				if embed.Author == nil || embed.Author.Name != "Logs from 2024-05-07" {
+					t.Errorf("author = %+v, want %q", embed.Author, "Logs from 2024-05-07")
+				}
+				if want := "https://www.warcraftlogs.com/reports/a1B2c3D4e5F6g7H8"; embed.URL != want {
+					t.Errorf("url = %q, want %q", embed.URL, want)
+				}
+				if want := "Nerub-ar Palace\nUploaded by Aerith\n<t:1715022000:f> - <t:1715032800:t>"; embed.Description != want {
+					t.Errorf("description = %q, want %q", embed.Description, want)
+				}
+				wantFields := []discord.EmbedField{
+					{Name: "Ulgrax the Devourer (Heroic)", Value: "**Kill** after 3 pulls, duration: 12m30s"},
+					{Name: "The Bloodbound Horror (Heroic)", Value: "**Wipe**, best pull: 12.3%, pulls: 5, duration: 20m0s"},
+				}
+				if len(embed.Fields) != len(wantFields) {
+					t.Fatalf("got %d fields, want %d", len(embed.Fields), len(wantFields))
+				}
+				for i, f := range embed.Fields {
+					if f.Name != wantFields[i].Name || f.Value != wantFields[i].Value {
+						t.Errorf("field %d = %q: %q, want %q: %q", i, f.Name, f.Value, wantFields[i].Name, wantFields[i].Value)
+					}
+				}
+				if n := len(rec.Buttons()); n != 0 {
+					t.Errorf("got %d buttons for a single page, want 0", n)
+				}
+
+				calls := h.Services.Guild.Called("GetReports")
+				if len(calls) != 1 {
+					t.Fatalf("GetReports called %d times, want 1", len(calls))
+				}
+				if id := calls[0].Args[0].(snowflake.ID); id != commandstest.GuildID {
+					t.Errorf("GetReports guild = %s, want %s", id, commandstest.GuildID)
+				}
+				if period := calls[0].Args[1].(string); period != "2024.05.07" {
+					t.Errorf("GetReports period = %q, want %q", period, "2024.05.07")
+				}
+			},
+		},
+		{
+			name: "logs - fights of a single report",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetReportFunc: func(_ context.Context, _ snowflake.ID, _ string) (*guild.Report, error) {
+					r := raidReport()
+					return &r, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "logs", commandstest.Options{"report": "a1B2c3D4e5F6g7H8"}, commandstest.WithLocale(discord.LocaleGerman))
+			},
+			want: want{responded: true, embeds: []string{"Nerub-ar Palace Heroic"}},
+			check: func(t *testing.T, h *commandstest.Harness, rec *commandstest.Recorder) {
+				embed := rec.Embeds()[0]
+				if embed.Author != nil {
+					t.Errorf("author = %+v, want none", embed.Author)
+				}
+				if got, want := embed.Fields[1].Value, "**Wipe**, bester Pull: 12.3%, Pulls: 5, Dauer: 20m0s"; got != want {
+					t.Errorf("field value = %q, want %q", got, want)
+				}
+				if calls := h.Services.Guild.Called("GetReports"); len(calls) != 0 {
+					t.Errorf("GetReports called for a single report: %v", calls)
+				}
+				calls := h.Services.Guild.Called("GetReport")
+				if len(calls) != 1 || calls[0].Args[1].(string) != "a1B2c3D4e5F6g7H8" {
+					t.Errorf("GetReport calls = %v, want one for %q", calls, "a1B2c3D4e5F6g7H8")
+				}
+			},
+		},
+		{
+			name: "logs - report without boss encounters",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetReportFunc: func(_ context.Context, _ snowflake.ID, _ string) (*guild.Report, error) {
+					return &reports(1)[0], nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "logs", commandstest.Options{"report": "0"})
+			},
+			want: want{responded: true, embeds: []string{"Report 0"}},
+			check: func(t *testing.T, _ *commandstest.Harness, rec *commandstest.Recorder) {
+				want := "Uploaded by Aerith\n<t:1715022000:f> - <t:1715032800:t>\n\nNo boss encounters were logged."
+				if got := rec.Embeds()[0].Description; got != want {
+					t.Errorf("description = %q, want %q", got, want)
+				}
+			},
+		},
+		{
+			name: "logs - unknown report",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetReportFunc: func(_ context.Context, _ snowflake.ID, code string) (*guild.Report, error) {
+					return nil, svcerr.FromUpstream(&upstream.StatusError{Host: "www.warcraftlogs.com", StatusCode: 404}, code)
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "logs", commandstest.Options{"report": "nope"})
+			},
+			want: want{responded: true, ephemeral: true, content: `Nothing was found for "nope". Please check the spelling and try again.`},
+		},
+		{
+			name: "logs - reports of a range of raid days",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetReportsFunc: func(_ context.Context, _ snowflake.ID, _ string) (*guild.Reports, error) {
+					return &guild.Reports{
+						Period: guild.Period{From: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2024, 5, 7, 0, 0, 0, 0, time.UTC)},
+					}, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "logs", commandstest.Options{"date": "2024-05-01..2024-05-07"})
+			},
+			want: want{responded: true, embeds: []string{"Logs from 2024-05-01 to 2024-05-07"}},
+			check: func(t *testing.T, _ *commandstest.Harness, rec *commandstest.Recorder) {
+				if got, want := rec.Embeds()[0].Description, "No reports were uploaded in this period."; got != want {
+					t.Errorf("description = %q, want %q", got, want)
+				}
+			},
+		},
+		{
+			name: "logs - invalid date",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetReportsFunc: func(_ context.Context, _ snowflake.ID, period string) (*guild.Reports, error) {
+					_, err := guild.ParsePeriod(period, time.Now())
+					return nil, err
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "logs", commandstest.Options{"date": "someday"})
+			},
+			want: want{
+				responded: true,
+				ephemeral: true,
+				content:   `Your input is invalid: "someday" is not a date like 2024-05-07, today, yesterday, last wednesday or a range like 2024-05-01..2024-05-07.`,
+			},
+		},
+		{
+			name: "logs - no reports found upstream",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetReportsFunc: func(_ context.Context, _ snowflake.ID, _ string) (*guild.Reports, error) {
+					return nil, svcerr.FromUpstream(&upstream.StatusError{Host: "www.warcraftlogs.com", StatusCode: 404}, "Raid Mate")
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "logs", nil)
+			},
+			want: want{responded: true, ephemeral: true, content: `Nothing was found for "Raid Mate". Please check the spelling and try again.`},
+		},
+		{
+			name: "logs - guild not set up",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetReportsFunc: func(_ context.Context, _ snowflake.ID, _ string) (*guild.Reports, error) {
+					return nil, svcerr.FromDB(sql.ErrNoRows, svcerr.ErrNotConfigured, "")
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "logs", nil)
+			},
+			want: want{responded: true, ephemeral: true, content: `This server has not been set up yet. Ask an administrator to click the "Set me up!" button in the welcome message.`},
+		},
+		{
+			name: "logs - guild not set up in german",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetReportsFunc: func(_ context.Context, _ snowflake.ID, _ string) (*guild.Reports, error) {
+					return nil, svcerr.FromDB(sql.ErrNoRows, svcerr.ErrNotConfigured, "")
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "logs", nil, commandstest.WithLocale(discord.LocaleGerman))
+			},
+			want: want{responded: true, ephemeral: true, content: `Dieser Server wurde noch nicht eingerichtet. Bitte einen Administrator, auf den "Set me up!"-Button in der Willkommensnachricht zu klicken.`},
+		},
+		{
+			name: "logs - rate limited",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetReportsFunc: func(_ context.Context, _ snowflake.ID, _ string) (*guild.Reports, error) {
+					return nil, svcerr.FromUpstream(&upstream.StatusError{Host: "www.warcraftlogs.com", StatusCode: 429, RetryAfter: 42 * time.Second}, "")
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "logs", nil)
+			},
+			want: want{responded: true, ephemeral: true, content: "We are sending too many requests right now. Please try again in 42s."},
+		},
+		{
+			name: "logs - service error",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetReportsFunc: func(_ context.Context, _ snowflake.ID, _ string) (*guild.Reports, error) {
+					return nil, errBoom
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "logs", nil)
+			},
+			want: want{responded: true, ephemeral: true, content: "Something went wrong on our side. Please try again later."},
+		},
+		{
+			name: "logs - slow reports are deferred",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetReportsFunc: func(_ context.Context, _ snowflake.ID, _ string) (*guild.Reports, error) {
+					time.Sleep(100 * time.Millisecond)
+					return dayReports(reports(1)...), nil
+				},
+			}},
+			opts: []commandstest.Option{commandstest.WithDeferAfter(10 * time.Millisecond)},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "logs", commandstest.Options{"date": "2024-05-07"})
+			},
+			want: want{responded: true, deferred: true, embeds: []string{"Report 0"}},
+			check: func(t *testing.T, _ *commandstest.Harness, rec *commandstest.Recorder) {
+				if n := len(rec.Edits()); n != 1 {
+					t.Errorf("got %d edits, want 1", n)
+				}
+			},
+		},
+		{
+			name: "logs - slow error replaces public loading state",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetReportsFunc: func(_ context.Context, _ snowflake.ID, _ string) (*guild.Reports, error) {
+					time.Sleep(100 * time.Millisecond)
+					return nil, errBoom
+				},
+			}},
+			opts: []commandstest.Option{commandstest.WithDeferAfter(10 * time.Millisecond)},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "logs", nil)
+			},
+			want: want{responded: true, deferred: true},
+			check: func(t *testing.T, _ *commandstest.Harness, rec *commandstest.Recorder) {
+				if !rec.Deleted() {
+					t.Error("the public loading state was not deleted")
+				}
+				followups := rec.Followups()
+				if len(followups) != 1 {
+					t.Fatalf("got %d follow-ups, want 1", len(followups))
+				}
+				if !followups[0].Flags.Has(discord.MessageFlagEphemeral) {
+					t.Error("the follow-up is not ephemeral")
+				}
+				if want := "Something went wrong on our side. Please try again later."; followups[0].Content != want {
+					t.Errorf("follow-up content = %q, want %q", followups[0].Content, want)
+				}
+			},
+		},
+		{
+			name: "logs - autocomplete suggests linked guilds",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				ListWowGuildsFunc: func(_ context.Context, _ snowflake.ID) ([]repo.WowGuild, error) {
+					return linked, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Autocomplete(ctx, "logs", commandstest.Options{"guild": "raid"}, "guild")
+			},
+			want: want{responded: true, suggestions: []string{"1"}},
+		},
+	}
+
+	runCommandTests(t, tests)
+}
+
+// reports returns n distinct reports without boss encounters.
+func reports(n int) []guild.Report {
+	res := make([]guild.Report, n)
+	for i := range res {
+		res[i] = guild.Report{
+			Code:  strconv.Itoa(i),
+			Title: fmt.Sprintf("Report %d", i),
+			Owner: "Aerith",
+			Start: time.UnixMilli(1715022000000),
+			End:   time.UnixMilli(1715032800000),
+			URL:   fmt.Sprintf("https://www.warcraftlogs.com/reports/%d", i),
+		}
+	}
+	return res
+}
+
+// raidReport returns a report with a killed and a wiped boss.
+func raidReport() guild.Report {
+	return guild.Report{
+		Code:  "a1B2c3D4e5F6g7H8",
+		Title: "Nerub-ar Palace Heroic",
+		Owner: "Aerith",
+		Zone:  "Nerub-ar Palace",
+		Start: time.UnixMilli(1715022000000),
+		End:   time.UnixMilli(1715032800000),
+		URL:   "https://www.warcraftlogs.com/reports/a1B2c3D4e5F6g7H8",
+		Encounters: []guild.Encounter{
+			{Boss: "Ulgrax the Devourer", Difficulty: "Heroic", Kill: true, Duration: 12*time.Minute + 30*time.Second, Pulls: 3},
+			{Boss: "The Bloodbound Horror", Difficulty: "Heroic", BestPercent: 12.34, Duration: 20 * time.Minute, Pulls: 5},
+		},
+	}
+}
+
+// dayReports returns the given reports uploaded on 2024-05-07.
+func dayReports(reports ...guild.Report) *guild.Reports {
+	day := time.Date(2024, 5, 7, 0, 0, 0, 0, time.UTC)
+	return &guild.Reports{Period: guild.Period{From: day, To: day}, Reports: reports}
+}
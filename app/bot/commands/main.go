@@ -0,0 +1,132 @@
+package commands
+
+import (
+	"context"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/lvlcn-t/loggerhead/logger"
+	"github.com/lvlcn-t/raid-mate/app/bot/i18n"
+	"github.com/lvlcn-t/raid-mate/app/database/repo"
+	"github.com/lvlcn-t/raid-mate/app/services/character"
+	"github.com/lvlcn-t/raid-mate/app/services/guild"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+)
+
+var (
+	_ Command[*events.ApplicationCommandInteractionCreate] = (*Main)(nil)
+	_ ApplicationInteractionCommand                        = (*Main)(nil)
+	_ AutocompleteCommand                                  = (*Main)(nil)
+)
+
+// Main is a command to register the main character of a member.
+// The main character is used by the context menu commands on the member.
+type Main struct {
+	// Base is the common base for all commands.
+	*Base[*events.ApplicationCommandInteractionCreate]
+	// characters is the character service.
+	characters character.Service
+	// guilds is the guild service.
+	guilds guild.Service
+}
+
+// newMain creates a new main command.
+func newMain(characters character.Service, guilds guild.Service) *Main {
+	return &Main{
+		Base:       NewBase[*events.ApplicationCommandInteractionCreate]("main"),
+		characters: characters,
+		guilds:     guilds,
+	}
+}
+
+// Ephemeral reports whether the responses of the command are only visible to the invoking user.
+func (c *Main) Ephemeral() bool {
+	return true
+}
+
+// Handle is the handler for the command that is called when the event is triggered.
+func (c *Main) Handle(ctx context.Context, event *events.ApplicationCommandInteractionCreate) {
+	log := logger.FromContext(ctx).With("command", c.Name())
+	data := event.SlashCommandInteractionData()
+	name := strings.TrimSpace(data.String("name"))
+	realm := strings.TrimSpace(data.String("realm"))
+
+	if event.GuildID() == nil {
+		log.ErrorContext(ctx, "No guild found in interaction")
+		return
+	}
+	if name == "" {
+		replyError(ctx, log, event, svcerr.Invalid(svcerr.Msg("errors.invalid.character_required")))
+		return
+	}
+
+	g, err := c.guilds.Get(ctx, *event.GuildID())
+	if err != nil {
+		replyError(ctx, log, event, err)
+		return
+	}
+	if realm == "" {
+		realm = g.ServerRealm
+	}
+
+	err = c.characters.SetMain(ctx, repo.SetMainCharacterParams{
+		GuildID: int64(*event.GuildID()), //nolint:gosec // Snowflake cannot overflow AFAIK
+		UserID:  int64(event.User().ID),  //nolint:gosec // Snowflake cannot overflow AFAIK
+		Name:    name,
+		Realm:   realm,
+		Region:  g.ServerRegion,
+	})
+	if err != nil {
+		replyError(ctx, log, event, err)
+		return
+	}
+
+	err = event.CreateMessage(discord.NewMessageCreateBuilder().
+		SetContent(tr(ctx, event, "main.registered", name, realm)).
+		SetEphemeral(true).
+		Build(),
+	)
+	if err != nil {
+		log.ErrorContext(ctx, "Error replying to interaction", "error", err)
+	}
+}
+
+// HandleAutocomplete suggests the characters in the roster of the guild.
+func (c *Main) HandleAutocomplete(ctx context.Context, event *events.AutocompleteInteractionCreate) {
+	log := logger.FromContext(ctx).With("command", c.Name())
+	if event.GuildID() == nil || event.Data.Focused().Name != "name" {
+		respondSuggestions(ctx, log, event, nil)
+		return
+	}
+
+	roster, err := c.guilds.GetRoster(ctx, *event.GuildID())
+	if err != nil {
+		logError(ctx, log, err)
+	}
+	respondSuggestions(ctx, log, event, suggest(event.Data.String("name"), roster))
+}
+
+// Info returns the interaction command information.
+func (c *Main) Info() (discord.ApplicationCommandCreate, error) {
+	return NewInfoBuilder().
+		Name(c.Name(), nil).
+		Description(i18n.Text("commands.main.description")).
+		Option(NewStringOptionBuilder().
+			Name("name", nil).
+			Description(i18n.Text("commands.main.options.name.description")).
+			Required(true).
+			MaxLength(maxCharacterNameLength).
+			Autocomplete(true),
+		).
+		Option(NewStringOptionBuilder().
+			Name("realm", nil).
+			Description(i18n.Text("commands.main.options.realm.description")).
+			Required(false).
+			MinLength(minRealmLength).
+			MaxLength(maxRealmLength),
+		).Build()
+}
+
+// maxCharacterNameLength is the maximum length of a character name in World of Warcraft.
+const maxCharacterNameLength = 12
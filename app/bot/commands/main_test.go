@@ -0,0 +1,52 @@
+package commands_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands/commandstest"
+	"github.com/lvlcn-t/raid-mate/app/database/repo"
+)
+
+func TestMainCharacter(t *testing.T) {
+	tests := []commandTest{
+		{
+			name: "main - registers main on the realm of the guild",
+			services: commandstest.Services{
+				Guild: &commandstest.GuildService{
+					GetFunc: func(_ context.Context, _ snowflake.ID) (repo.Guild, error) {
+						return repo.Guild{Name: "Raid Mate", ServerRealm: "Draenor", ServerRegion: "eu"}, nil
+					},
+				},
+				Character: &commandstest.CharacterService{
+					SetMainFunc: func(_ context.Context, _ repo.SetMainCharacterParams) error {
+						return nil
+					},
+				},
+			},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "main", commandstest.Options{"name": "Aerith"})
+			},
+			want: want{responded: true, ephemeral: true, content: "Your main character is now Aerith-Draenor."},
+			check: func(t *testing.T, h *commandstest.Harness, _ *commandstest.Recorder) {
+				calls := h.Services.Character.Called("SetMain")
+				if len(calls) != 1 {
+					t.Fatalf("SetMain called %d times, want 1", len(calls))
+				}
+				want := repo.SetMainCharacterParams{
+					GuildID: int64(commandstest.GuildID),
+					UserID:  int64(commandstest.UserID),
+					Name:    "Aerith",
+					Realm:   "Draenor",
+					Region:  "eu",
+				}
+				if got := calls[0].Args[0].(repo.SetMainCharacterParams); got != want {
+					t.Errorf("SetMain params = %+v, want %+v", got, want)
+				}
+			},
+		},
+	}
+
+	runCommandTests(t, tests)
+}
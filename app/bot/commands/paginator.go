@@ -0,0 +1,269 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lvlcn-t/loggerhead/logger"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands/customid"
+	"github.com/lvlcn-t/raid-mate/app/bot/i18n"
+)
+
+var (
+	_ Command[*events.ComponentInteractionCreate] = (*Paginator)(nil)
+	_ ComponentInteractionCommand                 = (*Paginator)(nil)
+)
+
+// errNoPages is returned if a paginated message without pages is sent.
+var errNoPages = errors.New("cannot send a paginated message without pages")
+
+const (
+	// defaultPageTimeout is the default duration after which the navigation buttons of a paginated message expire.
+	defaultPageTimeout = 5 * time.Minute
+	// maxEmbedFields is the maximum number of fields of an embed allowed by Discord.
+	maxEmbedFields = 25
+	// pagePattern is the custom ID pattern of the navigation buttons.
+	pagePattern = "page:{session}:{action}:{page}"
+)
+
+// The navigation actions of a paginated message.
+// They are part of the custom IDs of the buttons, so every button is unique even if two of them lead to the same page.
+const (
+	pageFirst = "first"
+	pagePrev  = "prev"
+	pageNext  = "next"
+	pageLast  = "last"
+)
+
+// Paginator splits long results into embed pages that can be browsed with navigation buttons.
+//
+// The current page is kept in the custom IDs of the buttons, while the pages themselves
+// are kept in memory until they expire. Only the user who ran the command can turn the pages.
+type Paginator struct {
+	// Base is the common base for all commands.
+	*Base[*events.ComponentInteractionCreate]
+	// timeout is the duration after which the navigation buttons expire.
+	timeout time.Duration
+	// customIDs encodes the custom IDs of the navigation buttons.
+	customIDs *customid.Codec
+
+	// mu guards the sessions.
+	mu sync.Mutex
+	// sessions are the paginated messages mapped by the ID of the interaction that created them.
+	sessions map[snowflake.ID]*pageSession
+}
+
+// pageSession is a paginated message.
+type pageSession struct {
+	// owner is the ID of the user who is allowed to turn the pages.
+	owner snowflake.ID
+	// pages are the pages of the message.
+	pages []discord.Embed
+	// expiresAt is the time the navigation buttons expire.
+	expiresAt time.Time
+}
+
+// pageResponder is an interaction event that can be answered with a paginated message.
+type pageResponder interface {
+	messageResponder
+	// ID returns the ID of the interaction.
+	ID() snowflake.ID
+	// User returns the user who triggered the interaction.
+	User() discord.User
+}
+
+// newPaginator creates a new paginator whose navigation buttons expire after the given duration.
+func newPaginator(timeout time.Duration, codec *customid.Codec) *Paginator {
+	if timeout <= 0 {
+		timeout = defaultPageTimeout
+	}
+
+	return &Paginator{
+		Base:      NewBase[*events.ComponentInteractionCreate]("page"),
+		timeout:   timeout,
+		customIDs: codec,
+		sessions:  map[snowflake.ID]*pageSession{},
+	}
+}
+
+// Patterns returns the custom ID patterns the command handles.
+func (p *Paginator) Patterns() []string {
+	return []string{pagePattern}
+}
+
+// Send answers the interaction with the first of the given pages.
+// If there is more than one page, navigation buttons are added that only the invoking user can use.
+func (p *Paginator) Send(ctx context.Context, event pageResponder, pages []discord.Embed, ephemeral bool) error {
+	if len(pages) == 0 {
+		return errNoPages
+	}
+
+	builder := discord.NewMessageCreateBuilder().SetEphemeral(ephemeral)
+	if len(pages) == 1 {
+		return event.CreateMessage(builder.AddEmbeds(pages[0]).Build())
+	}
+
+	nav, err := p.navigation(ctx, event.ID(), 0, len(pages))
+	if err != nil {
+		return err
+	}
+
+	pages = withPageFooters(pages, i18n.FromContext(ctx, event.Locale()))
+	p.store(event.ID(), &pageSession{
+		owner:     event.User().ID,
+		pages:     pages,
+		expiresAt: time.Now().Add(p.timeout),
+	})
+
+	return event.CreateMessage(builder.
+		AddEmbeds(pages[0]).
+		AddContainerComponents(nav).
+		Build(),
+	)
+}
+
+// Handle turns the page of a paginated message.
+func (p *Paginator) Handle(ctx context.Context, event *events.ComponentInteractionCreate) {
+	log := logger.FromContext(ctx).With("command", p.Name())
+
+	params := customid.FromContext(ctx)
+	id, err := params.Snowflake("session")
+	if err != nil {
+		replyError(ctx, log, event, err)
+		return
+	}
+	page, err := params.Int("page")
+	if err != nil {
+		replyError(ctx, log, event, err)
+		return
+	}
+
+	session, ok := p.load(id)
+	if !ok {
+		p.reply(ctx, log, event, "page.expired")
+		return
+	}
+	if event.User().ID != session.owner {
+		p.reply(ctx, log, event, "page.forbidden")
+		return
+	}
+
+	page = max(0, min(page, len(session.pages)-1))
+	nav, err := p.navigation(ctx, id, page, len(session.pages))
+	if err != nil {
+		replyError(ctx, log, event, err)
+		return
+	}
+
+	err = event.UpdateMessage(discord.NewMessageUpdateBuilder().
+		SetEmbeds(session.pages[page]).
+		SetContainerComponents(nav).
+		Build(),
+	)
+	if err != nil {
+		log.ErrorContext(ctx, "Error replying to interaction", "error", err)
+	}
+}
+
+// HandleSubmission is not supported, because the paginator never opens a modal.
+func (p *Paginator) HandleSubmission(ctx context.Context, _ *events.ModalSubmitInteractionCreate) {
+	logger.FromContext(ctx).With("command", p.Name()).WarnContext(ctx, "Paginator received a modal submission")
+}
+
+// reply answers the interaction with the message of the given key only visible to the invoking user.
+// After calling this you should return from the command handler.
+func (p *Paginator) reply(ctx context.Context, log logger.Logger, event *events.ComponentInteractionCreate, key string) {
+	err := event.CreateMessage(discord.NewMessageCreateBuilder().
+		SetContent(tr(ctx, event, key)).
+		SetEphemeral(true).
+		Build(),
+	)
+	if err != nil {
+		log.ErrorContext(ctx, "Error replying to interaction", "error", err)
+	}
+}
+
+// navigation returns the navigation buttons for the given page.
+func (p *Paginator) navigation(ctx context.Context, id snowflake.ID, page, total int) (discord.ActionRowComponent, error) {
+	last := total - 1
+	buttons := []struct {
+		label  string
+		action string
+		target int
+		off    bool
+	}{
+		{label: "⏮", action: pageFirst, target: 0, off: page == 0},
+		{label: "◀", action: pagePrev, target: max(page-1, 0), off: page == 0},
+		{label: "▶", action: pageNext, target: min(page+1, last), off: page == last},
+		{label: "⏭", action: pageLast, target: last, off: page == last},
+	}
+
+	row := discord.NewActionRow()
+	for _, b := range buttons {
+		customID, err := p.customIDs.Encode(ctx, p.Name(), customid.Snowflake(id), customid.String(b.action), customid.Int(b.target))
+		if err != nil {
+			return nil, err
+		}
+		row = row.AddComponents(discord.NewSecondaryButton(b.label, customID).WithDisabled(b.off))
+	}
+	return row, nil
+}
+
+// store stores the session and drops all expired ones.
+func (p *Paginator) store(id snowflake.ID, session *pageSession) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	for sid, s := range p.sessions {
+		if now.After(s.expiresAt) {
+			delete(p.sessions, sid)
+		}
+	}
+	p.sessions[id] = session
+}
+
+// load returns the session with the given ID if it has not expired yet.
+func (p *Paginator) load(id snowflake.ID) (*pageSession, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	session, ok := p.sessions[id]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(session.expiresAt) {
+		delete(p.sessions, id)
+		return nil, false
+	}
+	return session, true
+}
+
+// withPageFooters returns copies of the pages with the page number as footer.
+func withPageFooters(pages []discord.Embed, locale discord.Locale) []discord.Embed {
+	res := make([]discord.Embed, len(pages))
+	for i, page := range pages {
+		page.Footer = &discord.EmbedFooter{Text: i18n.T(locale, "page.footer", i+1, len(pages))}
+		res[i] = page
+	}
+	return res
+}
+
+// fieldPages splits the fields into pages of at most perPage fields.
+// Each page is a copy of the template with the fields.
+func fieldPages(template discord.Embed, fields []discord.EmbedField, perPage int) []discord.Embed {
+	perPage = max(1, min(perPage, maxEmbedFields))
+
+	var pages []discord.Embed
+	for start := 0; start < len(fields) || len(pages) == 0; start += perPage {
+		embed := template
+		embed.Fields = fields[start:min(start+perPage, len(fields))]
+		pages = append(pages, embed)
+	}
+	return pages
+}
@@ -0,0 +1,148 @@
+package commands_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands/commandstest"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands/customid"
+	"github.com/lvlcn-t/raid-mate/app/services/guild"
+)
+
+func TestPaginator(t *testing.T) {
+	tests := []commandTest{
+		{
+			name: "logs - many reports are paginated",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetReportsFunc: func(_ context.Context, _ snowflake.ID, _ string) (*guild.Reports, error) {
+					return dayReports(reports(3)...), nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "logs", commandstest.Options{"date": "2024-05-07"})
+			},
+			want: want{responded: true, embeds: []string{"Report 0"}},
+			check: func(t *testing.T, _ *commandstest.Harness, rec *commandstest.Recorder) {
+				checkPage(t, rec, "Page 1 of 3", "Report 0", []bool{true, true, false, false})
+			},
+		},
+		{
+			name: "logs - turn to the next page",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetReportsFunc: func(_ context.Context, _ snowflake.ID, _ string) (*guild.Reports, error) {
+					return dayReports(reports(3)...), nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				rec := h.Slash(ctx, "logs", commandstest.Options{"date": "2024-05-07"})
+				return h.Component(ctx, rec.Buttons()[2].CustomID)
+			},
+			want: want{responded: true, embeds: []string{"Report 1"}},
+			check: func(t *testing.T, _ *commandstest.Harness, rec *commandstest.Recorder) {
+				checkPage(t, rec, "Page 2 of 3", "Report 1", []bool{false, false, false, false})
+			},
+		},
+		{
+			name: "logs - turn to the last page",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetReportsFunc: func(_ context.Context, _ snowflake.ID, _ string) (*guild.Reports, error) {
+					return dayReports(reports(3)...), nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				rec := h.Slash(ctx, "logs", commandstest.Options{"date": "2024-05-07"})
+				return h.Component(ctx, rec.Buttons()[3].CustomID)
+			},
+			want: want{responded: true, embeds: []string{"Report 2"}},
+			check: func(t *testing.T, _ *commandstest.Harness, rec *commandstest.Recorder) {
+				checkPage(t, rec, "Page 3 of 3", "Report 2", []bool{false, false, true, true})
+			},
+		},
+		{
+			name: "logs - pages can only be turned by the invoking user",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetReportsFunc: func(_ context.Context, _ snowflake.ID, _ string) (*guild.Reports, error) {
+					return dayReports(reports(3)...), nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				rec := h.Slash(ctx, "logs", nil)
+				return h.Component(ctx, rec.Buttons()[2].CustomID, commandstest.AsUser(commandstest.UserID+1, "intruder"))
+			},
+			want: want{responded: true, ephemeral: true, content: "Only the user who ran the command can turn the pages."},
+		},
+		{
+			name: "logs - pages expire",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetReportsFunc: func(_ context.Context, _ snowflake.ID, _ string) (*guild.Reports, error) {
+					return dayReports(reports(3)...), nil
+				},
+			}},
+			opts: []commandstest.Option{commandstest.WithPageTimeout(time.Millisecond)},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				rec := h.Slash(ctx, "logs", nil)
+				time.Sleep(10 * time.Millisecond)
+				return h.Component(ctx, rec.Buttons()[2].CustomID)
+			},
+			want: want{responded: true, ephemeral: true, content: "These buttons have expired. Please run the command again."},
+		},
+		{
+			name: "page - unknown paginated message",
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				id := h.CustomID("page", customid.Snowflake(1), customid.String("next"), customid.Int(1))
+				return h.Component(ctx, id, commandstest.WithLocale(discord.LocaleGerman))
+			},
+			want: want{responded: true, ephemeral: true, content: "Diese Buttons sind abgelaufen. Bitte führe den Befehl erneut aus."},
+		},
+		{
+			name: "page - tampered custom ID",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetReportsFunc: func(_ context.Context, _ snowflake.ID, _ string) (*guild.Reports, error) {
+					return dayReports(reports(3)...), nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				rec := h.Slash(ctx, "logs", nil)
+				id := strings.Replace(rec.Buttons()[3].CustomID, ":last:2~", ":last:9~", 1)
+				return h.Component(ctx, id)
+			},
+			want: want{responded: true, ephemeral: true, content: "This component is no longer valid. Please run the command again."},
+		},
+		{
+			name: "page - custom ID signed with another secret",
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				other := commandstest.New(t, commandstest.Services{}, commandstest.WithCustomIDSecret("other"))
+				return h.Component(ctx, other.CustomID("page", customid.Snowflake(1), customid.String("next"), customid.Int(1)))
+			},
+			want: want{responded: true, ephemeral: true, content: "This component is no longer valid. Please run the command again."},
+		},
+	}
+
+	runCommandTests(t, tests)
+}
+
+// checkPage checks the page of a paginated message and whether its first, previous, next and last buttons are disabled.
+func checkPage(t *testing.T, rec *commandstest.Recorder, footer, title string, disabled []bool) {
+	t.Helper()
+	embed := rec.Embeds()[0]
+	if embed.Footer == nil || embed.Footer.Text != footer {
+		t.Errorf("footer = %+v, want %q", embed.Footer, footer)
+	}
+	if embed.Title != title {
+		t.Errorf("title = %q, want %q", embed.Title, title)
+	}
+
+	buttons := rec.Buttons()
+	if len(buttons) != len(disabled) {
+		t.Fatalf("got %d buttons, want %d", len(buttons), len(disabled))
+	}
+	for i, b := range buttons {
+		if b.Disabled != disabled[i] {
+			t.Errorf("button %d disabled = %v, want %v", i, b.Disabled, disabled[i])
+		}
+	}
+}
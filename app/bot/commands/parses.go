@@ -0,0 +1,222 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/gofiber/fiber/v3"
+	"github.com/lvlcn-t/go-kit/apimanager/fiberutils"
+	"github.com/lvlcn-t/loggerhead/logger"
+	"github.com/lvlcn-t/raid-mate/app/bot/colors"
+	"github.com/lvlcn-t/raid-mate/app/bot/i18n"
+	"github.com/lvlcn-t/raid-mate/app/services/guild"
+)
+
+var (
+	_ Command[*events.ApplicationCommandInteractionCreate] = (*Parses)(nil)
+	_ AutocompleteCommand                                  = (*Parses)(nil)
+)
+
+// parsesPerPage is the number of raiders shown on a single page of the table.
+const parsesPerPage = 20
+
+// Parses is a command to get the parses of the raiders of a guild.
+type Parses struct {
+	// Base is the common base for all commands.
+	*Base[*events.ApplicationCommandInteractionCreate]
+	// service is the guild service.
+	service guild.Service
+	// paginator sends the parses on multiple pages.
+	paginator *Paginator
+}
+
+// newParses creates a new parses command.
+func newParses(svc guild.Service, paginator *Paginator) *Parses {
+	return &Parses{
+		Base:      NewBase[*events.ApplicationCommandInteractionCreate]("parses"),
+		service:   svc,
+		paginator: paginator,
+	}
+}
+
+// Handle is the handler for the command that is called when the event is triggered.
+// Without a character it shows a table of all raiders sorted by their average parse,
+// with a character it shows its parse on every boss.
+func (c *Parses) Handle(ctx context.Context, event *events.ApplicationCommandInteractionCreate) {
+	log := logger.FromContext(ctx).With("command", c.Name())
+	data := event.SlashCommandInteractionData()
+	ctx = withLinkedGuild(ctx, data)
+	parses, err := c.service.GetParses(ctx, *event.GuildID(), data.String("character"), data.String("date"))
+	if err != nil {
+		replyError(ctx, log, event, err)
+		return
+	}
+
+	from, to := parses.Period.From.Format(time.DateOnly), parses.Period.To.Format(time.DateOnly)
+	header := tr(ctx, event, "parses.title", from)
+	if from != to {
+		header = tr(ctx, event, "parses.title_range", from, to)
+	}
+
+	var pages []discord.Embed
+	switch {
+	case len(parses.Players) == 0:
+		pages = append(pages, discord.NewEmbedBuilder().
+			SetTitle(header).
+			SetDescription(tr(ctx, event, "parses.none")).
+			SetColor(colors.Purple.Int()).
+			Build(),
+		)
+	case data.String("character") != "":
+		pages = playerParsesPages(ctx, event, header, &parses.Players[0])
+	default:
+		pages = parsesTablePages(ctx, event, header, parses.Players)
+	}
+
+	err = c.paginator.Send(ctx, event, pages, false)
+	if err != nil {
+		log.ErrorContext(ctx, "Error replying to interaction", "error", err)
+	}
+}
+
+// parsesTablePages returns the pages of the table of the raiders.
+func parsesTablePages(ctx context.Context, event localized, header string, players []guild.PlayerParses) []discord.Embed {
+	row := func(rank, character, spec, average, bosses string) string {
+		return fmt.Sprintf("%3s %-12s %-18s %5s %6s", rank, character, spec, average, bosses)
+	}
+	columns := row("#",
+		tr(ctx, event, "parses.column_character"),
+		tr(ctx, event, "parses.column_spec"),
+		tr(ctx, event, "parses.column_average"),
+		tr(ctx, event, "parses.column_bosses"),
+	)
+
+	var pages []discord.Embed
+	for start := 0; start < len(players); start += parsesPerPage {
+		lines := []string{columns}
+		for i, p := range players[start:min(start+parsesPerPage, len(players))] {
+			lines = append(lines, row(
+				fmt.Sprintf("%d", start+i+1),
+				p.Character,
+				fmt.Sprintf("%s (%s)", p.Spec, strings.ToUpper(p.Metric)),
+				fmt.Sprintf("%.1f", p.Average),
+				fmt.Sprintf("%d", len(p.Bosses)),
+			))
+		}
+		pages = append(pages, discord.NewEmbedBuilder().
+			SetTitle(header).
+			SetDescription("```\n"+strings.Join(lines, "\n")+"\n```").
+			SetColor(colors.Purple.Int()).
+			Build(),
+		)
+	}
+	return pages
+}
+
+// playerParsesPages returns the pages showing the parse of the raider on every boss.
+func playerParsesPages(ctx context.Context, event localized, header string, p *guild.PlayerParses) []discord.Embed {
+	metric := strings.ToUpper(p.Metric)
+	template := discord.NewEmbedBuilder().
+		SetAuthorName(header).
+		SetTitle(tr(ctx, event, "parses.player_title", p.Character, p.Spec, p.Class)).
+		SetDescription(tr(ctx, event, "parses.average", p.Average, metric)).
+		SetColor(colors.Purple.Int()).
+		Build()
+
+	fields := make([]discord.EmbedField, 0, len(p.Bosses))
+	for _, b := range p.Bosses {
+		name := b.Boss
+		if b.Difficulty != "" {
+			name = fmt.Sprintf("%s (%s)", b.Boss, b.Difficulty)
+		}
+		fields = append(fields, discord.EmbedField{
+			Name:  name,
+			Value: tr(ctx, event, "parses.boss", b.Percentile, compactNumber(b.Amount), metric),
+		})
+	}
+	return fieldPages(template, fields, maxEmbedFields)
+}
+
+// compactNumber returns the number rounded to thousands or millions, e.g. 1.23M.
+func compactNumber(n float64) string {
+	switch {
+	case n >= 1e6:
+		return fmt.Sprintf("%.2fM", n/1e6)
+	case n >= 1e3:
+		return fmt.Sprintf("%.1fk", n/1e3)
+	default:
+		return fmt.Sprintf("%.0f", n)
+	}
+}
+
+// HandleAutocomplete suggests the linked guilds and the characters in the roster of the guild.
+func (c *Parses) HandleAutocomplete(ctx context.Context, event *events.AutocompleteInteractionCreate) {
+	log := logger.FromContext(ctx).With("command", c.Name())
+	if event.Data.Focused().Name == linkedGuildOption {
+		respondSuggestions(ctx, log, event, suggestLinkedGuilds(ctx, log, c.service, event))
+		return
+	}
+	if event.GuildID() == nil || event.Data.Focused().Name != "character" {
+		respondSuggestions(ctx, log, event, nil)
+		return
+	}
+
+	roster, err := c.service.GetRoster(withLinkedGuild(ctx, event.Data), *event.GuildID())
+	if err != nil {
+		logError(ctx, log, err)
+	}
+	respondSuggestions(ctx, log, event, suggest(event.Data.String("character"), roster))
+}
+
+// HandleHTTP is the handler for the command that is called when the HTTP request is triggered.
+func (c *Parses) HandleHTTP(ctx fiber.Ctx) error {
+	log := logger.FromContext(ctx.Context()).With("command", c.Name())
+	gid, err := fiberutils.Params(ctx, "guildID", snowflake.Parse)
+	if err != nil {
+		return errorResponse(ctx, log, errors.Join(errInvalidGuildID, err))
+	}
+
+	parses, err := c.service.GetParses(ctx.Context(), gid, ctx.Query("character"), ctx.Query("date"))
+	if err != nil {
+		return errorResponse(ctx, log, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"players": parses.Players,
+		"from":    parses.Period.From.Format(time.DateOnly),
+		"to":      parses.Period.To.Format(time.DateOnly),
+	})
+}
+
+// Route returns the route for the command.
+func (c *Parses) Route() (methods []string, path string) {
+	return []string{http.MethodGet}, "/guilds/:guildID/parses"
+}
+
+// Info returns the interaction command information.
+func (c *Parses) Info() (discord.ApplicationCommandCreate, error) {
+	return NewInfoBuilder().
+		Name(c.Name(), nil).
+		Description(i18n.Text("commands.parses.description")).
+		Option(NewStringOptionBuilder().
+			Name("character", i18n.Localizations("commands.parses.options.character.name")).
+			Description(i18n.Text("commands.parses.options.character.description")).
+			Required(false).
+			MaxLength(maxCharacterNameLength).
+			Autocomplete(true),
+		).
+		Option(NewStringOptionBuilder().
+			Name("date", i18n.Localizations("commands.parses.options.date.name")).
+			Description(i18n.Text("commands.parses.options.date.description")).
+			Required(false),
+		).
+		Option(newLinkedGuildOption()).
+		Build()
+}
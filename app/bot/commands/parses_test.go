@@ -0,0 +1,189 @@
+package commands_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands/commandstest"
+	"github.com/lvlcn-t/raid-mate/app/database/repo"
+	"github.com/lvlcn-t/raid-mate/app/services/guild"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+)
+
+func TestParses(t *testing.T) {
+	linked := linkedGuilds()
+
+	tests := []commandTest{
+		{
+			name: "parses - table of the raiders",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetParsesFunc: func(_ context.Context, _ snowflake.ID, _, _ string) (*guild.Parses, error) {
+					return nightParses(), nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "parses", commandstest.Options{"date": "yesterday"})
+			},
+			want: want{responded: true, embeds: []string{"Parses from 2024-05-07"}},
+			check: func(t *testing.T, h *commandstest.Harness, rec *commandstest.Recorder) {
+				want := "```\n" +
+					"  # Character    Spec                 Avg Bosses\n" +
+					"  1 Aerith       Holy (HPS)          87.4      1\n" +
+					"  2 Bjorn        Fury (DPS)          80.0      2\n" +
+					"```"
+				if got := rec.Embeds()[0].Description; got != want {
+					t.Errorf("description = %q, want %q", got, want)
+				}
+
+				calls := h.Services.Guild.Called("GetParses")
+				if len(calls) != 1 {
+					t.Fatalf("GetParses called %d times, want 1", len(calls))
+				}
+				if character, period := calls[0].Args[1].(string), calls[0].Args[2].(string); character != "" || period != "yesterday" {
+					t.Errorf("GetParses character, period = %q, %q, want %q, %q", character, period, "", "yesterday")
+				}
+			},
+		},
+		{
+			name: "parses - bosses of a raider",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetParsesFunc: func(_ context.Context, _ snowflake.ID, _, _ string) (*guild.Parses, error) {
+					parses := nightParses()
+					parses.Players = parses.Players[1:]
+					return parses, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "parses", commandstest.Options{"character": "bjorn"})
+			},
+			want: want{responded: true, embeds: []string{"Parses of Bjorn (Fury Warrior)"}},
+			check: func(t *testing.T, _ *commandstest.Harness, rec *commandstest.Recorder) {
+				embed := rec.Embeds()[0]
+				if want := "Average parse: **80.0** (DPS)"; embed.Description != want {
+					t.Errorf("description = %q, want %q", embed.Description, want)
+				}
+				wantFields := []discord.EmbedField{
+					{Name: "Ulgrax the Devourer (Heroic)", Value: "Percentile **64** with 1.02M DPS"},
+					{Name: "Ulgrax the Devourer (Mythic)", Value: "Percentile **96** with 1.19M DPS"},
+				}
+				if len(embed.Fields) != len(wantFields) {
+					t.Fatalf("got %d fields, want %d", len(embed.Fields), len(wantFields))
+				}
+				for i, f := range embed.Fields {
+					if f.Name != wantFields[i].Name || f.Value != wantFields[i].Value {
+						t.Errorf("field %d = %q: %q, want %q: %q", i, f.Name, f.Value, wantFields[i].Name, wantFields[i].Value)
+					}
+				}
+			},
+		},
+		{
+			name: "parses - no ranked kills",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetParsesFunc: func(_ context.Context, _ snowflake.ID, _, _ string) (*guild.Parses, error) {
+					return &guild.Parses{Period: nightParses().Period}, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "parses", nil, commandstest.WithLocale(discord.LocaleGerman))
+			},
+			want: want{responded: true, embeds: []string{"Parses vom 2024-05-07"}},
+			check: func(t *testing.T, _ *commandstest.Harness, rec *commandstest.Recorder) {
+				if got, want := rec.Embeds()[0].Description, "In diesem Zeitraum wurden keine gewerteten Kills gefunden."; got != want {
+					t.Errorf("description = %q, want %q", got, want)
+				}
+			},
+		},
+		{
+			name: "parses - raider without ranked kills",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetParsesFunc: func(_ context.Context, _ snowflake.ID, character, _ string) (*guild.Parses, error) {
+					return nil, svcerr.New(svcerr.ErrNotFound, character)
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "parses", commandstest.Options{"character": "Tifa"})
+			},
+			want: want{responded: true, ephemeral: true, content: `Nothing was found for "Tifa". Please check the spelling and try again.`},
+		},
+		{
+			name: "parses - autocomplete suggests roster",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetRosterFunc: func(_ context.Context, _ snowflake.ID) ([]string, error) {
+					return []string{"Aerith", "Tifa", "Bjorn"}, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Autocomplete(ctx, "parses", commandstest.Options{"character": "b"}, "character")
+			},
+			want: want{responded: true, suggestions: []string{"Bjorn"}},
+		},
+		{
+			name: "parses - linked guild",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetParsesFunc: func(ctx context.Context, _ snowflake.ID, _, _ string) (*guild.Parses, error) {
+					if id, ok := guild.WowGuildFromContext(ctx); !ok || id != 2 {
+						return nil, errBoom
+					}
+					return nightParses(), nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "parses", commandstest.Options{"guild": 2})
+			},
+			want: want{responded: true, embeds: []string{"Parses from 2024-05-07"}},
+		},
+		{
+			name: "parses - autocomplete suggests linked guilds",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				ListWowGuildsFunc: func(_ context.Context, _ snowflake.ID) ([]repo.WowGuild, error) {
+					return linked, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Autocomplete(ctx, "parses", commandstest.Options{"guild": "alt"}, "guild")
+			},
+			want: want{responded: true, suggestions: []string{"2"}},
+		},
+		{
+			name: "parses - autocomplete suggests roster of the linked guild",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetRosterFunc: func(ctx context.Context, _ snowflake.ID) ([]string, error) {
+					if id, ok := guild.WowGuildFromContext(ctx); ok && id == 2 {
+						return []string{"Barret"}, nil
+					}
+					return []string{"Aerith", "Tifa", "Bjorn"}, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Autocomplete(ctx, "parses", commandstest.Options{"guild": 2, "character": "b"}, "character")
+			},
+			want: want{responded: true, suggestions: []string{"Barret"}},
+		},
+	}
+
+	runCommandTests(t, tests)
+}
+
+// nightParses returns the parses of two raiders on 2024-05-07.
+func nightParses() *guild.Parses {
+	day := time.Date(2024, 5, 7, 0, 0, 0, 0, time.UTC)
+	return &guild.Parses{
+		Period: guild.Period{From: day, To: day},
+		Players: []guild.PlayerParses{
+			{
+				Character: "Aerith", Class: "Priest", Spec: "Holy", Metric: "hps", Average: 87.4,
+				Bosses: []guild.BossParse{{Boss: "Ulgrax the Devourer", Difficulty: "Heroic", Percentile: 87.4, Amount: 412345.6}},
+			},
+			{
+				Character: "Bjorn", Class: "Warrior", Spec: "Fury", Metric: "dps", Average: 80,
+				Bosses: []guild.BossParse{
+					{Boss: "Ulgrax the Devourer", Difficulty: "Heroic", Percentile: 64.2, Amount: 1023456.7},
+					{Boss: "Ulgrax the Devourer", Difficulty: "Mythic", Percentile: 95.8, Amount: 1187654.3},
+				},
+			},
+		},
+	}
+}
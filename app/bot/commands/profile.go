@@ -1,9 +1,15 @@
 package commands
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"net/http"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/events"
@@ -12,14 +18,20 @@ import (
 	"github.com/lvlcn-t/go-kit/apimanager/fiberutils"
 	"github.com/lvlcn-t/loggerhead/logger"
 	"github.com/lvlcn-t/raid-mate/app/bot/colors"
+	"github.com/lvlcn-t/raid-mate/app/bot/i18n"
 	"github.com/lvlcn-t/raid-mate/app/services/guild"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
 )
 
 var (
 	_ Command[*events.ApplicationCommandInteractionCreate] = (*Profile)(nil)
 	_ ApplicationInteractionCommand                        = (*Profile)(nil)
+	_ AutocompleteCommand                                  = (*Profile)(nil)
 )
 
+// maxBestRuns is the number of the best Mythic+ runs shown in a profile.
+const maxBestRuns = 8
+
 // Profile is a command to get profiles.
 type Profile struct {
 	// Base is the common base for all commands.
@@ -42,6 +54,7 @@ func (c *Profile) Handle(ctx context.Context, event *events.ApplicationCommandIn
 	data := event.SlashCommandInteractionData()
 	typ := data.String("name")
 	username := data.String("username")
+	ctx = withLinkedGuild(ctx, data)
 
 	member := event.Member()
 	if member == nil {
@@ -55,36 +68,42 @@ func (c *Profile) Handle(ctx context.Context, event *events.ApplicationCommandIn
 		User:    username,
 	})
 	if err != nil {
-		log.ErrorContext(ctx, "Error getting profile", "error", err)
-		err = event.CreateMessage(discord.NewMessageCreateBuilder().
-			SetContent("Error while getting profile").
-			SetEphemeral(true).
-			Build(),
-		)
-		if err != nil {
-			log.ErrorContext(ctx, "Error replying to interaction", "error", err)
-		}
+		replyError(ctx, log, event, err)
 		return
 	}
 
-	embed := c.createEmbed(profile)
-	err = event.CreateMessage(discord.NewMessageCreateBuilder().
-		AddEmbeds(embed).
-		Build(),
-	)
+	err = event.CreateMessage(profileMessage(profile, i18n.FromContext(ctx, event.Locale())))
 	if err != nil {
 		log.ErrorContext(ctx, "Error replying to interaction", "error", err)
 	}
 }
 
+// HandleAutocomplete suggests the linked guilds and the characters in the roster of the guild for user profiles.
+func (c *Profile) HandleAutocomplete(ctx context.Context, event *events.AutocompleteInteractionCreate) {
+	log := logger.FromContext(ctx).With("command", c.Name())
+	if event.Data.Focused().Name == linkedGuildOption {
+		respondSuggestions(ctx, log, event, suggestLinkedGuilds(ctx, log, c.service, event))
+		return
+	}
+	if event.GuildID() == nil || event.Data.String("name") == "guild" {
+		respondSuggestions(ctx, log, event, nil)
+		return
+	}
+
+	roster, err := c.service.GetRoster(withLinkedGuild(ctx, event.Data), *event.GuildID())
+	if err != nil {
+		logError(ctx, log, err)
+	}
+	respondSuggestions(ctx, log, event, suggest(event.Data.String("username"), roster))
+}
+
 // HandleHTTP is the handler for the command that is called when the HTTP request is triggered.
 func (c *Profile) HandleHTTP(ctx fiber.Ctx) error {
 	log := logger.FromContext(ctx.Context()).With("command", c.Name())
 
 	gid, err := fiberutils.Params(ctx, "guildID", snowflake.Parse)
 	if err != nil {
-		log.DebugContext(ctx.Context(), "Error parsing guild ID", "error", err)
-		return fiberutils.BadRequestResponse(ctx, "missing or invalid guild ID")
+		return errorResponse(ctx, log, errors.Join(errInvalidGuildID, err))
 	}
 
 	typ, err := fiberutils.Params(ctx, "name", func(s string) (string, error) {
@@ -92,27 +111,20 @@ func (c *Profile) HandleHTTP(ctx fiber.Ctx) error {
 		case "user", "guild":
 			return s, nil
 		default:
-			return "", fmt.Errorf("invalid profile type. Options: %q, %q", "user", "guild")
+			return "", fmt.Errorf("invalid profile type %q", s)
 		}
 	})
 	if err != nil {
-		log.DebugContext(ctx.Context(), "Error getting name", "error", err)
-		return fiberutils.BadRequestResponse(ctx, err.Error())
-	}
-
-	username := ctx.Query("username")
-	if typ == "user" && username == "" {
-		return fiberutils.BadRequestResponse(ctx, "missing username")
+		return errorResponse(ctx, log, svcerr.WrapInvalid(err, svcerr.Msg("errors.invalid.profile_type", "user", "guild")))
 	}
 
 	profile, err := c.service.GetProfile(ctx.Context(), &guild.RequestProfile{
 		Type:    typ,
 		GuildID: gid,
-		User:    username,
+		User:    ctx.Query("username"),
 	})
 	if err != nil {
-		log.ErrorContext(ctx.Context(), "Error getting profile", "error", err)
-		return fiberutils.InternalServerErrorResponse(ctx, "Error while getting profile")
+		return errorResponse(ctx, log, err)
 	}
 
 	return ctx.Status(http.StatusOK).JSON(fiber.Map{"profile": profile})
@@ -124,71 +136,189 @@ func (c *Profile) Route() (methods []string, path string) {
 }
 
 // Info returns the interaction command information.
-func (c *Profile) Info() discord.ApplicationCommandCreate {
+func (c *Profile) Info() (discord.ApplicationCommandCreate, error) {
 	return NewInfoBuilder().
-		Name(c.Name(), map[discord.Locale]string{
-			discord.LocaleGerman: "profil",
-		}).
-		Description("Gets the profile of the given chosen option.", map[discord.Locale]string{
-			discord.LocaleGerman: "Gibt das Profil der gewählten Option zurück.",
-		}).
+		Name(c.Name(), i18n.Localizations("commands.profile.name")).
+		Description(i18n.Text("commands.profile.description")).
 		Option(NewStringOptionBuilder().
 			Name("name", nil).
-			Description("The name of the profile. Note that on user profile requests, you need to provide the user's name.", map[discord.Locale]string{
-				discord.LocaleGerman: "Der Name des Profils. Bei User-Anfragen muss der Name des Benutzers angegeben werden.",
-			}).
+			Description(i18n.Text("commands.profile.options.name.description")).
 			Required(true).
-			Choices([]discord.ApplicationCommandOptionChoiceString{
-				{
-					Name:              "user",
-					NameLocalizations: nil,
-					Value:             "user",
-				},
-				{
-					Name: "guild",
-					NameLocalizations: map[discord.Locale]string{
-						discord.LocaleGerman: "Gilde",
-					},
-					Value: "guild",
-				},
-			}...).
-			Build(),
+			Choices(
+				NewStringOptionChoice("user", "user", i18n.Localizations("commands.profile.options.name.choices.user")),
+				NewStringOptionChoice("guild", "guild", i18n.Localizations("commands.profile.options.name.choices.guild")),
+			),
 		).
 		Option(NewStringOptionBuilder().
 			Name("username", nil).
-			Description("The username to get the profile from.", map[discord.Locale]string{
-				discord.LocaleGerman: "Der Benutzername, von dem das Profil abgerufen werden soll.",
-			}).
+			Description(i18n.Text("commands.profile.options.username.description")).
 			Required(false).
-			Build(),
-		).Build()
+			Autocomplete(true),
+		).
+		Option(newLinkedGuildOption()).
+		Build()
 }
 
-func (c *Profile) createEmbed(profile *guild.Profiles) discord.Embed {
-	embed := discord.NewEmbedBuilder().
-		SetTitle("Profile").
-		SetDescription("Here is the profile you requested.").
-		SetColor(colors.Red.Int())
-
+// profileMessage creates the message showing the given profile in the given locale.
+// It has a button linking to the profile on Raider.IO if the profile has a link.
+func profileMessage(profile *guild.Profiles, locale discord.Locale) discord.MessageCreate {
+	msg := discord.NewMessageCreateBuilder()
+	var link string
 	if profile.IsGuild() {
-		embed.AddFields(
-			discord.EmbedField{
-				Name:   profile.GuildProfile.Name,
-				Value:  fmt.Sprintf("Region: %s\nRealm: %s\nFaction: %s", profile.GuildProfile.Region, profile.GuildProfile.Realm, profile.GuildProfile.Faction),
-				Inline: toPtr(true),
-			},
-		)
+		msg.AddEmbeds(guildProfileEmbed(profile.GuildProfile, locale))
+		link = profile.GuildProfile.ProfileURL
 	}
-
 	if profile.IsUser() {
-		embed.AddFields(
-			discord.EmbedField{
-				Name:   profile.UserProfile.Name,
-				Value:  fmt.Sprintf("Region: %s\nRealm: %s\nFaction: %s", profile.UserProfile.Region, profile.UserProfile.Realm, profile.UserProfile.Faction),
-				Inline: toPtr(true),
-			},
-		)
+		msg.AddEmbeds(userProfileEmbeds(profile.UserProfile, locale)...)
+		link = profile.UserProfile.ProfileURL
 	}
+	if link != "" {
+		msg.AddActionRow(discord.NewLinkButton(i18n.T(locale, "profile.link"), link))
+	}
+	return msg.Build()
+}
 
+// guildProfileEmbed creates the embed showing the guild with its raid progression in the color of its faction.
+func guildProfileEmbed(p *guild.GuildProfile, locale discord.Locale) discord.Embed {
+	embed := discord.NewEmbedBuilder().
+		SetAuthorName(i18n.T(locale, "profile.title")).
+		SetTitle(p.Name).
+		SetURL(p.ProfileURL).
+		SetColor(factionColor(p.Faction).Int()).
+		AddField(i18n.T(locale, "profile.details_title"), i18n.T(locale, "profile.details", strings.ToUpper(p.Region), p.Realm, p.Faction), true)
+	if raids := raidProgressionText(locale, p.RaidProgression, p.RaidRankings); raids != "" {
+		embed.AddField(i18n.T(locale, "profile.raids_title"), raids, false)
+	}
 	return embed.Build()
 }
+
+// userProfileEmbeds creates the embed showing the character in the color of its class.
+// If the character played Mythic+ this season, a second embed in the color of its score shows the score and the best runs.
+func userProfileEmbeds(p *guild.UserProfile, locale discord.Locale) []discord.Embed {
+	color, ok := colors.Class(p.Class)
+	if !ok {
+		color = factionColor(p.Faction)
+	}
+
+	embed := discord.NewEmbedBuilder().
+		SetAuthorName(i18n.T(locale, "profile.title")).
+		SetTitle(p.Name).
+		SetURL(p.ProfileURL).
+		SetColor(color.Int()).
+		AddField(i18n.T(locale, "profile.details_title"), i18n.T(locale, "profile.details", strings.ToUpper(p.Region), p.Realm, p.Faction), true)
+	// The portrait from the Battle.net API is preferred, because the thumbnail of Raider.IO is only updated occasionally.
+	if thumbnail := cmp.Or(p.Portrait, p.ThumbnailURL); thumbnail != "" {
+		embed.SetThumbnail(thumbnail)
+	}
+	if p.Class != "" {
+		embed.SetDescription(i18n.T(locale, "profile.character", p.ActiveSpecName, p.Class, p.Race, roleName(locale, p.ActiveSpecRole)))
+	}
+	if p.Gear.ItemLevelEquipped > 0 {
+		embed.AddField(i18n.T(locale, "profile.item_level_title"), i18n.T(locale, "profile.item_level", p.Gear.ItemLevelEquipped, p.Gear.ItemLevelTotal), true)
+	}
+	if raids := raidProgressionText(locale, p.RaidProgression, nil); raids != "" {
+		embed.AddField(i18n.T(locale, "profile.raids_title"), raids, false)
+	}
+	embeds := []discord.Embed{embed.Build()}
+
+	season, ok := p.CurrentSeason()
+	if !ok && len(p.MythicPlusBestRuns) == 0 {
+		return embeds
+	}
+	// The score is shown in the color Raider.IO uses for it, so it looks familiar to the players.
+	if c, err := colors.Parse(season.Segments.All.Color); err == nil {
+		color = c
+	}
+	mplus := discord.NewEmbedBuilder().
+		SetTitle(i18n.T(locale, "profile.mythic_plus_title")).
+		SetDescription(i18n.T(locale, "profile.score", season.Scores.All, season.Season)).
+		SetColor(color.Int())
+	if r := p.MythicPlusRanks.Overall; r.World > 0 {
+		mplus.AddField(i18n.T(locale, "profile.ranks_title"), i18n.T(locale, "profile.ranks", r.Realm, r.Region, r.World), false)
+	}
+	if len(p.MythicPlusBestRuns) > 0 {
+		mplus.AddField(i18n.T(locale, "profile.best_runs_title"), bestRunsTable(locale, p.MythicPlusBestRuns), false)
+	}
+	return append(embeds, mplus.Build())
+}
+
+// bestRunsTable returns a table of the best Mythic+ runs with the keystone upgrades as stars.
+func bestRunsTable(locale discord.Locale, runs []guild.MythicPlusRun) string {
+	row := func(dungeon, level, clear, score string) string {
+		return fmt.Sprintf("%-7s %-6s %8s %6s", dungeon, level, clear, score)
+	}
+	lines := []string{row(
+		i18n.T(locale, "profile.column_dungeon"),
+		i18n.T(locale, "profile.column_level"),
+		i18n.T(locale, "profile.column_time"),
+		i18n.T(locale, "profile.column_score"),
+	)}
+	for _, r := range runs[:min(maxBestRuns, len(runs))] {
+		lines = append(lines, row(
+			r.ShortName,
+			fmt.Sprintf("+%d%s", r.MythicLevel, strings.Repeat("*", r.NumKeystoneUpgrades)),
+			(time.Duration(r.ClearTimeMS)*time.Millisecond).Round(time.Second).String(),
+			fmt.Sprintf("%.1f", r.Score),
+		))
+	}
+	return "```\n" + strings.Join(lines, "\n") + "\n```"
+}
+
+// raidProgressionText returns the progression in every raid, the raids sorted by name.
+// If rankings are given, the best ranking of the guild in a raid is shown below its progression.
+func raidProgressionText(locale discord.Locale, progression map[string]guild.RaidProgression, rankings map[string]guild.RaidRanking) string {
+	lines := make([]string, 0, len(progression))
+	for _, slug := range slices.Sorted(maps.Keys(progression)) {
+		p := progression[slug]
+		lines = append(lines, i18n.T(locale, "profile.raid", raidName(slug), p.Summary,
+			p.NormalBossesKilled, p.TotalBosses, p.HeroicBossesKilled, p.TotalBosses, p.MythicBossesKilled, p.TotalBosses))
+		if rank, ok := raidRank(locale, rankings[slug]); ok {
+			lines = append(lines, rank)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// raidRank returns the ranking in the highest difficulty the guild is ranked in.
+// It returns false if the guild is not ranked in the raid.
+func raidRank(locale discord.Locale, ranking guild.RaidRanking) (string, bool) {
+	for _, d := range []struct {
+		name  string
+		stats guild.Stats
+	}{
+		{name: "mythic", stats: ranking.Mythic},
+		{name: "heroic", stats: ranking.Heroic},
+		{name: "normal", stats: ranking.Normal},
+	} {
+		if d.stats.World > 0 {
+			difficulty := i18n.T(locale, "profile.difficulties."+d.name)
+			return i18n.T(locale, "profile.raid_rank", difficulty, d.stats.Realm, d.stats.Region, d.stats.World), true
+		}
+	}
+	return "", false
+}
+
+// raidName returns the name of the raid with the given Raider.IO slug, e.g. "Nerubar Palace" for "nerubar-palace".
+func raidName(slug string) string {
+	words := strings.Split(slug, "-")
+	for i, w := range words {
+		if w == "" || (i > 0 && (w == "of" || w == "the")) {
+			continue
+		}
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// roleName returns the localized name of the Raider.IO role, e.g. "HEALING".
+func roleName(locale discord.Locale, role string) string {
+	return i18n.T(locale, "profile.roles."+strings.ToLower(role))
+}
+
+// factionColor returns the color of the faction, blue for the Alliance and red for the Horde.
+func factionColor(faction string) colors.Color {
+	if strings.EqualFold(faction, "alliance") {
+		return colors.Blue
+	}
+	return colors.Red
+}
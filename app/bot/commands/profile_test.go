@@ -0,0 +1,187 @@
+package commands_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lvlcn-t/raid-mate/app/bot/colors"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands/commandstest"
+	"github.com/lvlcn-t/raid-mate/app/services/guild"
+)
+
+func TestProfile(t *testing.T) {
+	guildProfile := &guild.GuildProfile{}
+	guildProfile.Name = "Raid Mate"
+	guildProfile.Region = "eu"
+	guildProfile.Realm = "Draenor"
+	guildProfile.Faction = "horde"
+	guildProfile.ProfileURL = "https://raider.io/guilds/eu/draenor/Raid%20Mate"
+	guildProfile.RaidProgression = map[string]guild.RaidProgression{
+		"nerubar-palace": {Summary: "8/8 H", TotalBosses: 8, NormalBossesKilled: 8, HeroicBossesKilled: 8, MythicBossesKilled: 2},
+	}
+	guildProfile.RaidRankings = map[string]guild.RaidRanking{
+		"nerubar-palace": {Heroic: guild.Stats{World: 4210, Region: 2130, Realm: 41}, Mythic: guild.Stats{World: 1890, Region: 977, Realm: 18}},
+	}
+	userProfile := raiderProfile()
+
+	tests := []commandTest{
+		{
+			name: "profile - guild",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetProfileFunc: func(_ context.Context, _ *guild.RequestProfile) (*guild.Profiles, error) {
+					return &guild.Profiles{GuildProfile: guildProfile}, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "profile", commandstest.Options{"name": "guild"})
+			},
+			want: want{responded: true, embeds: []string{"Raid Mate"}},
+			check: func(t *testing.T, _ *commandstest.Harness, rec *commandstest.Recorder) {
+				embed := rec.Embeds()[0]
+				if embed.Color != colors.Red.Int() {
+					t.Errorf("profile color = %#x, want the color of the Horde", embed.Color)
+				}
+				if len(embed.Fields) != 2 || embed.Fields[1].Name != "Raid progression" {
+					t.Fatalf("profile fields = %+v, want the details and the raid progression", embed.Fields)
+				}
+				if want := "**Nerubar Palace**: 8/8 H\nNormal 8/8 · Heroic 8/8 · Mythic 2/8\nMythic rank: #18 realm, #977 region, #1890 world"; embed.Fields[1].Value != want {
+					t.Errorf("raid progression = %q, want %q", embed.Fields[1].Value, want)
+				}
+				buttons := rec.Buttons()
+				if len(buttons) != 1 || buttons[0].URL != "https://raider.io/guilds/eu/draenor/Raid%20Mate" || buttons[0].Style != discord.ButtonStyleLink {
+					t.Errorf("profile buttons = %+v, want a link to the profile on Raider.IO", buttons)
+				}
+			},
+		},
+		{
+			name: "profile - user with portrait",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetProfileFunc: func(_ context.Context, _ *guild.RequestProfile) (*guild.Profiles, error) {
+					p := &guild.UserProfile{Portrait: "https://render.worldofwarcraft.com/eu/character/draenor/103/171234567-avatar.jpg"}
+					p.Name, p.Region, p.Realm, p.Faction = "Aerith", "eu", "Draenor", "horde"
+					return &guild.Profiles{UserProfile: p}, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "profile", commandstest.Options{"name": "user", "username": "aerith"})
+			},
+			want: want{responded: true, embeds: []string{"Aerith"}},
+			check: func(t *testing.T, _ *commandstest.Harness, rec *commandstest.Recorder) {
+				embed := rec.Embeds()[0]
+				if embed.Thumbnail == nil || embed.Thumbnail.URL != "https://render.worldofwarcraft.com/eu/character/draenor/103/171234567-avatar.jpg" {
+					t.Errorf("profile thumbnail = %+v, want the portrait of the character", embed.Thumbnail)
+				}
+			},
+		},
+		{
+			name: "profile - user with Mythic+ score",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetProfileFunc: func(_ context.Context, _ *guild.RequestProfile) (*guild.Profiles, error) {
+					return &guild.Profiles{UserProfile: userProfile}, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "profile", commandstest.Options{"name": "user", "username": "aerith"})
+			},
+			want: want{responded: true, embeds: []string{"Aerith", "Mythic+"}},
+			check: func(t *testing.T, _ *commandstest.Harness, rec *commandstest.Recorder) {
+				embeds := rec.Embeds()
+				if embeds[0].Color != 0xf48cba || embeds[0].Description != "Holy Paladin, Blood Elf (Healer)" {
+					t.Errorf("profile = %+v, want a description of the paladin in the color of the class", embeds[0])
+				}
+				if embeds[0].Thumbnail == nil || embeds[0].Thumbnail.URL != userProfile.ThumbnailURL {
+					t.Errorf("profile thumbnail = %+v, want the thumbnail of Raider.IO", embeds[0].Thumbnail)
+				}
+				if embeds[1].Color != 0xff8000 || embeds[1].Description != "Score **2874** in season-tww-1" {
+					t.Errorf("Mythic+ = %+v, want the score in the color of its segment", embeds[1])
+				}
+				table := "```\nDungeon Level      Time  Score\nSV      +11*     29m49s  335.2\nARAK    +10**    28m10s  327.9\n```"
+				if len(embeds[1].Fields) != 2 || embeds[1].Fields[1].Value != table {
+					t.Errorf("Mythic+ fields = %+v, want the ranks and the best runs\n%s", embeds[1].Fields, table)
+				}
+				if buttons := rec.Buttons(); len(buttons) != 1 || buttons[0].URL != userProfile.ProfileURL {
+					t.Errorf("profile buttons = %+v, want a link to the profile on Raider.IO", buttons)
+				}
+			},
+		},
+		{
+			name: "profile - service error",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetProfileFunc: func(_ context.Context, _ *guild.RequestProfile) (*guild.Profiles, error) {
+					return nil, errBoom
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "profile", commandstest.Options{"name": "user", "username": "aerith"})
+			},
+			want: want{responded: true, ephemeral: true, content: "Something went wrong on our side. Please try again later."},
+			check: func(t *testing.T, h *commandstest.Harness, _ *commandstest.Recorder) {
+				calls := h.Services.Guild.Called("GetProfile")
+				if len(calls) != 1 {
+					t.Fatalf("GetProfile called %d times, want 1", len(calls))
+				}
+				if req := calls[0].Args[0].(*guild.RequestProfile); req.Type != "user" || req.User != "aerith" {
+					t.Errorf("GetProfile request = %+v, want user profile of aerith", req)
+				}
+			},
+		},
+		{
+			name: "profile - DM is ignored",
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "profile", commandstest.Options{"name": "guild"}, commandstest.InDM())
+			},
+			want: want{},
+		},
+		{
+			name: "profile - autocomplete suggests roster",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetRosterFunc: func(_ context.Context, _ snowflake.ID) ([]string, error) {
+					return []string{"Aerith", "Tifa", "Barret", "aerith"}, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Autocomplete(ctx, "profile", commandstest.Options{"name": "user", "username": "a"}, "username")
+			},
+			want: want{responded: true, suggestions: []string{"Aerith", "Tifa", "Barret"}},
+		},
+		{
+			name: "profile - autocomplete for guild profiles",
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Autocomplete(ctx, "profile", commandstest.Options{"name": "guild", "username": "a"}, "username")
+			},
+			want: want{responded: true, suggestions: []string{}},
+			check: func(t *testing.T, h *commandstest.Harness, _ *commandstest.Recorder) {
+				if calls := h.Services.Guild.Called("GetRoster"); len(calls) != 0 {
+					t.Errorf("GetRoster called %d times, want 0", len(calls))
+				}
+			},
+		},
+	}
+
+	runCommandTests(t, tests)
+}
+
+// raiderProfile returns the Raider.IO profile of the raider Aerith.
+func raiderProfile() *guild.UserProfile {
+	p := &guild.UserProfile{
+		Race:           "Blood Elf",
+		Class:          "Paladin",
+		ActiveSpecName: "Holy",
+		ActiveSpecRole: "HEALING",
+		Gear:           guild.Gear{ItemLevelEquipped: 619, ItemLevelTotal: 621},
+		MythicPlusScoresBySeason: []guild.MythicPlusScoresBySeason{
+			{Season: "season-tww-1", Scores: guild.Scores{All: 2874}, Segments: guild.Segments{All: guild.All{Score: 2874, Color: "#ff8000"}}},
+		},
+		MythicPlusRanks: guild.MythicPlusRanks{Overall: guild.Class{World: 15234, Region: 6120, Realm: 88}},
+		MythicPlusBestRuns: []guild.MythicPlusRun{
+			{Dungeon: "The Stonevault", ShortName: "SV", MythicLevel: 11, ClearTimeMS: 1789001, NumKeystoneUpgrades: 1, Score: 335.2},
+			{Dungeon: "Ara-Kara, City of Echoes", ShortName: "ARAK", MythicLevel: 10, ClearTimeMS: 1690442, NumKeystoneUpgrades: 2, Score: 327.9},
+		},
+		ThumbnailURL: "https://render.worldofwarcraft.com/eu/character/draenor/103/171234567-avatar.jpg?alt=/wow/static/images/2d/avatar/10-1.jpg",
+	}
+	p.Name, p.Region, p.Realm, p.Faction = "Aerith", "eu", "Draenor", "horde"
+	p.ProfileURL = "https://raider.io/characters/eu/draenor/Aerith"
+	return p
+}
@@ -0,0 +1,256 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lvlcn-t/loggerhead/logger"
+)
+
+// defaultDeferAfter is the default duration after which a command that has not responded yet is deferred.
+// Discord fails interactions that are not answered within 3 seconds.
+const defaultDeferAfter = 2 * time.Second
+
+// errUnsupportedResponse is returned if a command sends a response that is not allowed after the interaction was answered.
+var errUnsupportedResponse = errors.New("response type is not supported after the interaction was answered")
+
+// responseState is the state of the response to an interaction.
+type responseState int
+
+const (
+	// stateNone means the interaction has not been answered yet.
+	stateNone responseState = iota
+	// stateDeferred means the interaction has been deferred and the original response is a loading state.
+	stateDeferred
+	// stateResponded means the original response has been sent.
+	stateResponded
+)
+
+// responder answers a single interaction on behalf of a command.
+//
+// It defers the interaction if the command has not answered it within the threshold.
+// Messages the command creates afterwards are transparently sent as edit of the
+// deferred response or, once the original response exists, as follow-up messages.
+// Commands can therefore always call CreateMessage, no matter how long they took.
+//
+// Interactions with a message component are deferred as update of the message the component belongs to,
+// so a late UpdateMessage edits that message and a late CreateMessage is sent as follow-up.
+type responder struct {
+	// respond is the original responder of the event.
+	respond events.InteractionResponderFunc
+	// rest is the REST client used for edits and follow-ups.
+	rest rest.Interactions
+	// applicationID is the ID of the application the interaction is for.
+	applicationID snowflake.ID
+	// token is the token of the interaction.
+	token string
+	// ephemeral is whether the command answers only visible to the invoking user.
+	ephemeral bool
+	// deferType is the type of the response the interaction is deferred with,
+	// either [discord.InteractionResponseTypeDeferredCreateMessage] or [discord.InteractionResponseTypeDeferredUpdateMessage].
+	deferType discord.InteractionResponseType
+	// timer defers the interaction when it fires.
+	timer *time.Timer
+
+	// mu guards the fields below and serializes all responses.
+	mu sync.Mutex
+	// state is the state of the response.
+	state responseState
+	// deferredEphemeral is whether the deferred response is only visible to the invoking user.
+	deferredEphemeral bool
+	// deferredUpdate is whether the interaction was deferred as update of the message with the component.
+	deferredUpdate bool
+}
+
+// interaction is the interaction of an event that can be answered by a [responder].
+type interaction interface {
+	// ApplicationID returns the ID of the application the interaction is for.
+	ApplicationID() snowflake.ID
+	// Token returns the token of the interaction.
+	Token() string
+}
+
+// newResponder creates a responder for the interaction that defers it with the given response type after the given duration.
+// A duration that is not positive defers the interaction right away.
+// The returned responder must be stopped once the command returned.
+func newResponder(ctx context.Context, i interaction, client rest.Interactions, respond events.InteractionResponderFunc, ephemeral bool, deferType discord.InteractionResponseType, after time.Duration) *responder {
+	r := &responder{
+		respond:       respond,
+		rest:          client,
+		applicationID: i.ApplicationID(),
+		token:         i.Token(),
+		ephemeral:     ephemeral,
+		deferType:     deferType,
+	}
+	deferResponse := func() {
+		if err := r.deferResponse(ctx); err != nil {
+			logger.FromContext(ctx).ErrorContext(ctx, "Error deferring interaction", "error", err)
+		}
+	}
+	if after <= 0 {
+		// The time is already up, so the interaction is deferred before the command can answer it.
+		deferResponse()
+	}
+	r.timer = time.AfterFunc(after, deferResponse)
+	return r
+}
+
+// stop stops the automatic deferral.
+// It reports whether the interaction has been answered.
+func (r *responder) stop() bool {
+	r.timer.Stop()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.state != stateNone
+}
+
+// deferResponse defers the interaction if it has not been answered yet.
+func (r *responder) deferResponse(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.state != stateNone {
+		return nil
+	}
+
+	// Updates of a message keep its visibility, only new messages can be hidden.
+	update := r.deferType == discord.InteractionResponseTypeDeferredUpdateMessage
+	var data discord.InteractionResponseData
+	if r.ephemeral && !update {
+		data = discord.MessageCreate{Flags: discord.MessageFlagEphemeral}
+	}
+	err := r.respond(r.deferType, data, rest.WithCtx(ctx))
+	if err != nil {
+		return err
+	}
+	r.state = stateDeferred
+	r.deferredEphemeral = r.ephemeral && !update
+	r.deferredUpdate = update
+	return nil
+}
+
+// Respond answers the interaction. It is used as responder of the event passed to the command.
+func (r *responder) Respond(typ discord.InteractionResponseType, data discord.InteractionResponseData, opts ...rest.RequestOpt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch r.state {
+	case stateNone:
+		r.timer.Stop()
+		err := r.respond(typ, data, opts...)
+		if err != nil {
+			return err
+		}
+		r.state = stateResponded
+		if typ == discord.InteractionResponseTypeDeferredCreateMessage || typ == discord.InteractionResponseTypeDeferredUpdateMessage {
+			r.state = stateDeferred
+			mc, ok := data.(discord.MessageCreate)
+			r.deferredEphemeral = ok && mc.Flags.Has(discord.MessageFlagEphemeral)
+			r.deferredUpdate = typ == discord.InteractionResponseTypeDeferredUpdateMessage
+		}
+		return nil
+	case stateDeferred:
+		return r.respondDeferred(typ, data, opts...)
+	default:
+		return r.respondFollowup(typ, data, opts...)
+	}
+}
+
+// respondDeferred turns a response to a deferred interaction into an edit of the original response.
+// If the interaction was deferred as update, the original response is the message with the component,
+// so only updates edit it and new messages are sent as follow-ups.
+func (r *responder) respondDeferred(typ discord.InteractionResponseType, data discord.InteractionResponseData, opts ...rest.RequestOpt) error {
+	switch typ {
+	case discord.InteractionResponseTypeDeferredCreateMessage, discord.InteractionResponseTypeDeferredUpdateMessage:
+		return nil
+	case discord.InteractionResponseTypeCreateMessage:
+		if r.deferredUpdate {
+			return r.respondFollowup(typ, data, opts...)
+		}
+	case discord.InteractionResponseTypeUpdateMessage:
+	default:
+		return fmt.Errorf("%w: %d", errUnsupportedResponse, typ)
+	}
+
+	mc, err := messageCreate(data)
+	if err != nil {
+		return err
+	}
+
+	// The visibility of a deferred response cannot be changed by editing it.
+	// If the message must be hidden but the loading state is public, we replace it with a hidden follow-up.
+	if mc.Flags.Has(discord.MessageFlagEphemeral) && !r.deferredEphemeral {
+		err = r.rest.DeleteInteractionResponse(r.applicationID, r.token, opts...)
+		if err != nil {
+			return fmt.Errorf("error deleting deferred response: %w", err)
+		}
+		_, err = r.rest.CreateFollowupMessage(r.applicationID, r.token, mc, opts...)
+		if err != nil {
+			return fmt.Errorf("error creating follow-up message: %w", err)
+		}
+		r.state = stateResponded
+		return nil
+	}
+
+	_, err = r.rest.UpdateInteractionResponse(r.applicationID, r.token, discord.MessageUpdate{
+		Content:         &mc.Content,
+		Embeds:          &mc.Embeds,
+		Components:      &mc.Components,
+		Files:           mc.Files,
+		AllowedMentions: mc.AllowedMentions,
+	}, opts...)
+	if err != nil {
+		return fmt.Errorf("error editing deferred response: %w", err)
+	}
+	r.state = stateResponded
+	return nil
+}
+
+// respondFollowup turns a response to an answered interaction into a follow-up message.
+func (r *responder) respondFollowup(typ discord.InteractionResponseType, data discord.InteractionResponseData, opts ...rest.RequestOpt) error {
+	switch typ {
+	case discord.InteractionResponseTypeDeferredCreateMessage, discord.InteractionResponseTypeDeferredUpdateMessage:
+		return nil
+	case discord.InteractionResponseTypeCreateMessage:
+	default:
+		return fmt.Errorf("%w: %d", errUnsupportedResponse, typ)
+	}
+
+	mc, err := messageCreate(data)
+	if err != nil {
+		return err
+	}
+	_, err = r.rest.CreateFollowupMessage(r.applicationID, r.token, mc, opts...)
+	if err != nil {
+		return fmt.Errorf("error creating follow-up message: %w", err)
+	}
+	return nil
+}
+
+// messageCreate returns the message of the response data.
+func messageCreate(data discord.InteractionResponseData) (discord.MessageCreate, error) {
+	switch d := data.(type) {
+	case discord.MessageCreate:
+		return d, nil
+	case discord.MessageUpdate:
+		mc := discord.MessageCreate{Files: d.Files, AllowedMentions: d.AllowedMentions}
+		if d.Content != nil {
+			mc.Content = *d.Content
+		}
+		if d.Embeds != nil {
+			mc.Embeds = *d.Embeds
+		}
+		if d.Components != nil {
+			mc.Components = *d.Components
+		}
+		return mc, nil
+	default:
+		return discord.MessageCreate{}, fmt.Errorf("%w: %T", errUnsupportedResponse, data)
+	}
+}
@@ -0,0 +1,449 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/lvlcn-t/loggerhead/logger"
+	"github.com/lvlcn-t/raid-mate/app/bot/colors"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands/customid"
+	"github.com/lvlcn-t/raid-mate/app/bot/i18n"
+	"github.com/lvlcn-t/raid-mate/app/database/repo"
+	"github.com/lvlcn-t/raid-mate/app/services/guild"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+)
+
+var (
+	_ Command[*events.ApplicationCommandInteractionCreate] = (*Settings)(nil)
+	_ ApplicationInteractionCommand                        = (*Settings)(nil)
+	_ AutocompleteCommand                                  = (*Settings)(nil)
+)
+
+// Settings is a command to view and manage the WoW guilds linked to the server.
+// The modals and confirmation buttons it sends are handled by the [Guild] component command.
+type Settings struct {
+	// Base is the common base for all commands.
+	*Base[*events.ApplicationCommandInteractionCreate]
+	// service is the guild service.
+	service guild.Service
+	// customIDs encodes the custom IDs of the modals and buttons.
+	customIDs *customid.Codec
+	// component is the component command handling the modals and buttons.
+	component *Guild
+}
+
+// newSettings creates a new settings command whose modals and buttons are handled by the given component command.
+func newSettings(svc guild.Service, codec *customid.Codec, component *Guild) *Settings {
+	return &Settings{
+		Base:      NewBase[*events.ApplicationCommandInteractionCreate]("settings"),
+		service:   svc,
+		customIDs: codec,
+		component: component,
+	}
+}
+
+// The subcommands of the settings command.
+const (
+	settingsView      = "view"
+	settingsAdd       = "add"
+	settingsEdit      = "edit"
+	settingsDefault   = "default"
+	settingsRemove    = "remove"
+	settingsReset     = "reset"
+	settingsConfigure = "configure"
+	settingsDeleteAll = "delete-all-data"
+	settingsLanguage  = "language"
+	settingsSchedule  = "schedule"
+	settingsGear      = "gear"
+	settingsRaiders   = "raiders"
+)
+
+// languageUser is the choice of the language subcommand that answers every user in their own language.
+const languageUser = "user"
+
+// Ephemeral reports whether the responses of the command are only visible to the invoking user.
+func (c *Settings) Ephemeral() bool {
+	return true
+}
+
+// Handle is the handler for the command that is called when the event is triggered.
+func (c *Settings) Handle(ctx context.Context, event *events.ApplicationCommandInteractionCreate) {
+	log := logger.FromContext(ctx).With("command", c.Name())
+	if event.GuildID() == nil {
+		log.ErrorContext(ctx, "No guild found in interaction")
+		return
+	}
+
+	data := event.SlashCommandInteractionData()
+	var sub string
+	if data.SubCommandName != nil {
+		sub = *data.SubCommandName
+	}
+
+	var err error
+	switch sub {
+	case settingsAdd:
+		err = c.openModal(ctx, event, "settings.add_title", repo.WowGuild{}, guildAdd)
+	case settingsReset:
+		err = c.confirm(ctx, event, tr(ctx, event, "settings.reset_question"),
+			"settings.reset_button", customid.String(guildReset))
+	case settingsConfigure:
+		err = c.configure(ctx, event)
+	case settingsLanguage:
+		err = c.setLanguage(ctx, event, data.String("language"))
+	case settingsSchedule:
+		err = c.setSchedule(ctx, event, data.String("timezone"), data.String("raid-start"), data.String("raid-end"))
+	case settingsGear:
+		err = c.setGearRules(ctx, event, data.Int("min-item-level"), data.String("enchant-slots"))
+	case settingsRaiders:
+		rank, ok := data.OptInt("rank")
+		if !ok {
+			rank = -1
+		}
+		err = c.setRaiderRank(ctx, event, rank)
+	case settingsDeleteAll:
+		g, ok := event.Guild()
+		err = authorizeOwner(g, ok, event.User().ID)
+		if err == nil {
+			err = c.confirm(ctx, event, tr(ctx, event, "settings.delete_all_question"),
+				"settings.delete_all_button", customid.String(guildPurge))
+		}
+	case settingsView, settingsEdit, settingsDefault, settingsRemove:
+		err = c.handleLinked(ctx, event, sub, data.Int(linkedGuildOption))
+	default:
+		err = fmt.Errorf("unknown subcommand %q", sub)
+	}
+	if err != nil {
+		replyError(ctx, log, event, err)
+	}
+}
+
+// handleLinked handles the subcommands that need the linked guilds.
+func (c *Settings) handleLinked(ctx context.Context, event *events.ApplicationCommandInteractionCreate, sub string, id int) error {
+	linked, err := c.service.ListWowGuilds(ctx, *event.GuildID())
+	if err != nil {
+		return err
+	}
+	if sub == settingsView {
+		return event.CreateMessage(discord.NewMessageCreateBuilder().
+			AddEmbeds(settingsEmbed(linked, i18n.FromContext(ctx, event.Locale()))).
+			SetEphemeral(true).
+			Build(),
+		)
+	}
+
+	var g *repo.WowGuild
+	for i := range linked {
+		if linked[i].ID == int64(id) {
+			g = &linked[i]
+		}
+	}
+	if g == nil {
+		return svcerr.Invalid(svcerr.Msg("errors.invalid.guild_not_linked"))
+	}
+
+	switch sub {
+	case settingsEdit:
+		return c.openModal(ctx, event, "settings.edit_title", *g, guildEdit, customid.Int(id))
+	case settingsDefault:
+		err = c.service.SetDefaultWowGuild(ctx, *event.GuildID(), g.ID)
+		if err != nil {
+			return err
+		}
+		return event.CreateMessage(discord.NewMessageCreateBuilder().
+			SetContent(tr(ctx, event, "settings.default_set", g.Name)).
+			SetEphemeral(true).
+			Build(),
+		)
+	default:
+		if g.IsDefault {
+			return svcerr.Invalid(svcerr.Msg("errors.invalid.default_guild_removal"))
+		}
+		return c.confirm(ctx, event, tr(ctx, event, "settings.remove_question", describeWowGuild(*g)),
+			"settings.remove_button", customid.String(guildRemove), customid.Int(id))
+	}
+}
+
+// configure continues the setup with the choice of the announcement channel and the raider role.
+func (c *Settings) configure(ctx context.Context, event *events.ApplicationCommandInteractionCreate) error {
+	content, rows, err := c.component.step(ctx, i18n.FromContext(ctx, event.Locale()), stepChannel)
+	if err != nil {
+		return err
+	}
+	return event.CreateMessage(discord.NewMessageCreateBuilder().
+		SetContent(content).
+		AddContainerComponents(rows...).
+		SetEphemeral(true).
+		Build(),
+	)
+}
+
+// setLanguage sets the language of the responses on the server and confirms it in the new language.
+// The choice [languageUser] clears it, so that every user is answered in their own language.
+func (c *Settings) setLanguage(ctx context.Context, event *events.ApplicationCommandInteractionCreate, language string) error {
+	locale := discord.Locale(language)
+	switch {
+	case language == languageUser:
+		locale = ""
+	case !i18n.Default().Supports(locale):
+		return svcerr.Invalid(svcerr.Msg("errors.invalid.language", language))
+	}
+
+	err := c.service.SetLocale(ctx, *event.GuildID(), string(locale))
+	if err != nil {
+		return err
+	}
+
+	content := i18n.T(event.Locale(), "settings.language_user")
+	if locale != "" {
+		content = i18n.T(locale, "settings.language_set", i18n.T(locale, "language.name"))
+	}
+	return event.CreateMessage(discord.NewMessageCreateBuilder().
+		SetContent(content).
+		SetEphemeral(true).
+		Build(),
+	)
+}
+
+// setSchedule sets the timezone and the raid hours of the server, which define its raid days.
+func (c *Settings) setSchedule(ctx context.Context, event *events.ApplicationCommandInteractionCreate, timezone, raidStart, raidEnd string) error {
+	schedule, err := guild.NewSchedule(timezone, raidStart, raidEnd)
+	if err != nil {
+		return err
+	}
+
+	err = c.service.SetSchedule(ctx, *event.GuildID(), schedule)
+	if err != nil {
+		return err
+	}
+	return event.CreateMessage(discord.NewMessageCreateBuilder().
+		SetContent(tr(ctx, event, "settings.schedule_set", schedule.String())).
+		SetEphemeral(true).
+		Build(),
+	)
+}
+
+// setGearRules sets the rules the equipment of the raiders is audited against by /gear-audit.
+func (c *Settings) setGearRules(ctx context.Context, event *events.ApplicationCommandInteractionCreate, minItemLevel int, enchantSlots string) error {
+	rules, err := guild.NewGearRules(minItemLevel, enchantSlots)
+	if err != nil {
+		return err
+	}
+
+	err = c.service.SetGearRules(ctx, *event.GuildID(), rules)
+	if err != nil {
+		return err
+	}
+	return event.CreateMessage(discord.NewMessageCreateBuilder().
+		SetContent(tr(ctx, event, "settings.gear_set", gearRulesText(ctx, event, rules))).
+		SetEphemeral(true).
+		Build(),
+	)
+}
+
+// setRaiderRank sets the lowest guild rank of the raiders, whose data commands like /gear-audit fetch.
+// A negative rank makes all members of the guild count as raiders.
+func (c *Settings) setRaiderRank(ctx context.Context, event *events.ApplicationCommandInteractionCreate, rank int) error {
+	err := c.service.SetRaiderRank(ctx, *event.GuildID(), rank)
+	if err != nil {
+		return err
+	}
+
+	content := tr(ctx, event, "settings.raiders_all")
+	if rank >= 0 {
+		content = tr(ctx, event, "settings.raiders_set", rank)
+	}
+	return event.CreateMessage(discord.NewMessageCreateBuilder().
+		SetContent(content).
+		SetEphemeral(true).
+		Build(),
+	)
+}
+
+// openModal opens the modal to enter the settings of a guild with the title of the given key.
+func (c *Settings) openModal(ctx context.Context, event *events.ApplicationCommandInteractionCreate, title string, g repo.WowGuild, action string, args ...customid.Arg) error {
+	customID, err := c.customIDs.Encode(ctx, c.component.Name(), append([]customid.Arg{customid.String(action)}, args...)...)
+	if err != nil {
+		return err
+	}
+	locale := i18n.FromContext(ctx, event.Locale())
+	return event.Modal(guildModal(locale, i18n.T(locale, title), customID, g), rest.WithCtx(ctx))
+}
+
+// confirm asks the user to confirm an action with a button that carries the given arguments.
+// The label of the button is the message with the given key.
+func (c *Settings) confirm(ctx context.Context, event *events.ApplicationCommandInteractionCreate, question, label string, args ...customid.Arg) error {
+	confirmID, err := c.customIDs.Encode(ctx, c.component.Name(), args...)
+	if err != nil {
+		return err
+	}
+	cancelID, err := c.customIDs.Encode(ctx, c.component.Name(), customid.String(guildCancel))
+	if err != nil {
+		return err
+	}
+
+	return event.CreateMessage(discord.NewMessageCreateBuilder().
+		SetContent(question).
+		AddActionRow(
+			discord.NewDangerButton(tr(ctx, event, label), confirmID),
+			discord.NewSecondaryButton(tr(ctx, event, "settings.cancel_button"), cancelID),
+		).
+		SetEphemeral(true).
+		Build(),
+	)
+}
+
+// linkedGuildOption is the name of the option choosing one of the WoW guilds linked to the server.
+// Its value is the ID of the WoW guild, the suggestions show the name, realm and region.
+const linkedGuildOption = "guild"
+
+// newLinkedGuildOption returns the option of the commands reading guild data
+// that chooses another linked WoW guild than the default guild of the server.
+func newLinkedGuildOption() OptionBuilder {
+	return NewIntOptionBuilder().
+		Name(linkedGuildOption, i18n.Localizations("commands.options.guild.name")).
+		Description(i18n.Text("commands.options.guild.description")).
+		Required(false).
+		Autocomplete(true)
+}
+
+// withLinkedGuild returns a copy of the context selecting the WoW guild chosen in the guild option, see [guild.WithWowGuild].
+// The context is returned unchanged if no guild was chosen, so the default guild of the server is used.
+func withLinkedGuild(ctx context.Context, options interface{ OptInt(name string) (int, bool) }) context.Context {
+	if id, ok := options.OptInt(linkedGuildOption); ok {
+		return guild.WithWowGuild(ctx, int64(id))
+	}
+	return ctx
+}
+
+// suggestLinkedGuilds returns the WoW guilds linked to the server matching what the user typed into the guild option.
+func suggestLinkedGuilds(ctx context.Context, log logger.Logger, svc guild.Service, event *events.AutocompleteInteractionCreate) []discord.AutocompleteChoice {
+	if event.GuildID() == nil {
+		return nil
+	}
+	linked, err := svc.ListWowGuilds(ctx, *event.GuildID())
+	if err != nil {
+		logError(ctx, log, err)
+	}
+
+	ids := make(map[string]int64, len(linked))
+	labels := make([]string, 0, len(linked))
+	for _, g := range linked {
+		label := describeWowGuild(g)
+		ids[label] = g.ID
+		labels = append(labels, label)
+	}
+
+	choices := suggest(event.Data.String(linkedGuildOption), labels)
+	for i, choice := range choices {
+		choices[i] = discord.AutocompleteChoiceInt{Name: choice.ChoiceName(), Value: int(ids[choice.ChoiceName()])}
+	}
+	return choices
+}
+
+// HandleAutocomplete suggests the guilds linked to the server and common timezones.
+func (c *Settings) HandleAutocomplete(ctx context.Context, event *events.AutocompleteInteractionCreate) {
+	log := logger.FromContext(ctx).With("command", c.Name())
+	if event.Data.Focused().Name == "timezone" {
+		respondSuggestions(ctx, log, event, suggest(event.Data.String("timezone"), guild.Timezones))
+		return
+	}
+	if event.Data.Focused().Name != linkedGuildOption {
+		respondSuggestions(ctx, log, event, nil)
+		return
+	}
+	respondSuggestions(ctx, log, event, suggestLinkedGuilds(ctx, log, c.service, event))
+}
+
+// Info returns the interaction command information.
+func (c *Settings) Info() (discord.ApplicationCommandCreate, error) {
+	guildOption := func(sub string) OptionBuilder {
+		return NewIntOptionBuilder().
+			Name(linkedGuildOption, i18n.Localizations("commands.settings.options.guild.name")).
+			Description(i18n.Text("commands.settings." + sub + ".options.guild.description")).
+			Required(true).
+			Autocomplete(true)
+	}
+	stringOption := func(sub, name string) OptionBuilder {
+		return NewStringOptionBuilder().
+			Name(name, i18n.Localizations("commands.settings."+sub+".options."+name+".name")).
+			Description(i18n.Text("commands.settings." + sub + ".options." + name + ".description"))
+	}
+	subCommand := func(sub string) SubCommandBuilder {
+		return NewSubCommandBuilder().
+			Name(sub, i18n.Localizations("commands.settings."+sub+".name")).
+			Description(i18n.Text("commands.settings." + sub + ".description"))
+	}
+
+	languages := []discord.ApplicationCommandOptionChoice{
+		NewStringOptionChoice(i18n.T(i18n.Fallback, "commands.settings.language.options.language.choices.user"), languageUser,
+			i18n.Localizations("commands.settings.language.options.language.choices.user")),
+	}
+	for _, locale := range i18n.Default().Locales() {
+		languages = append(languages, NewStringOptionChoice(i18n.T(locale, "language.name"), string(locale), nil))
+	}
+
+	return NewInfoBuilder().
+		Name(c.Name(), i18n.Localizations("commands.settings.name")).
+		Description(i18n.Text("commands.settings.description")).
+		DefaultMemberPermissions(discord.PermissionManageGuild).
+		SubCommand(subCommand(settingsView)).
+		SubCommand(subCommand(settingsAdd)).
+		SubCommand(subCommand(settingsEdit).Option(guildOption(settingsEdit))).
+		SubCommand(subCommand(settingsDefault).Option(guildOption(settingsDefault))).
+		SubCommand(subCommand(settingsRemove).Option(guildOption(settingsRemove))).
+		SubCommand(subCommand(settingsConfigure)).
+		SubCommand(subCommand(settingsLanguage).
+			Option(stringOption(settingsLanguage, "language").Required(true).Choices(languages...)),
+		).
+		SubCommand(subCommand(settingsSchedule).
+			Option(stringOption(settingsSchedule, "timezone").Required(true).Autocomplete(true)).
+			Option(stringOption(settingsSchedule, "raid-start").MinLength(len("0:00")).MaxLength(len("00:00"))).
+			Option(stringOption(settingsSchedule, "raid-end").MinLength(len("0:00")).MaxLength(len("00:00"))),
+		).
+		SubCommand(subCommand(settingsGear).
+			Option(NewIntOptionBuilder().
+				Name("min-item-level", i18n.Localizations("commands.settings.gear.options.min-item-level.name")).
+				Description(i18n.Text("commands.settings.gear.options.min-item-level.description")).
+				MinValue(0).
+				MaxValue(guild.MaxItemLevel),
+			).
+			Option(stringOption(settingsGear, "enchant-slots")),
+		).
+		SubCommand(subCommand(settingsRaiders).
+			Option(NewIntOptionBuilder().
+				Name("rank", i18n.Localizations("commands.settings.raiders.options.rank.name")).
+				Description(i18n.Text("commands.settings.raiders.options.rank.description")).
+				MinValue(0).
+				MaxValue(guild.MaxGuildRank),
+			),
+		).
+		SubCommand(subCommand(settingsReset)).
+		SubCommand(subCommand(settingsDeleteAll)).
+		Build()
+}
+
+// settingsEmbed returns the embed listing the linked guilds in the given locale.
+func settingsEmbed(linked []repo.WowGuild, locale discord.Locale) discord.Embed {
+	builder := discord.NewEmbedBuilder().
+		SetTitle(i18n.T(locale, "settings.title")).
+		SetDescription(i18n.T(locale, "settings.description")).
+		SetColor(colors.Red.Int())
+	for _, g := range linked {
+		name := g.Name
+		if g.IsDefault {
+			name = i18n.T(locale, "settings.default_guild", g.Name)
+		}
+		builder.AddField(name, i18n.T(locale, "settings.details", g.Realm, strings.ToUpper(g.Region), titleCase(g.Faction)), true)
+	}
+	return builder.Build()
+}
+
+// describeWowGuild returns the name of the guild with its realm and region.
+func describeWowGuild(g repo.WowGuild) string {
+	return fmt.Sprintf("%s (%s-%s)", g.Name, g.Realm, strings.ToUpper(g.Region))
+}
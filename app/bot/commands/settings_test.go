@@ -0,0 +1,585 @@
+package commands_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands/commandstest"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands/customid"
+	"github.com/lvlcn-t/raid-mate/app/database/repo"
+	"github.com/lvlcn-t/raid-mate/app/services/guild"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+)
+
+func TestSettings(t *testing.T) {
+	linked := linkedGuilds()
+
+	tests := []commandTest{
+		{
+			name: "settings - view lists linked guilds",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				ListWowGuildsFunc: func(_ context.Context, _ snowflake.ID) ([]repo.WowGuild, error) {
+					return linked, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "settings view", nil)
+			},
+			want: want{responded: true, ephemeral: true, embeds: []string{"Guild settings"}},
+			check: func(t *testing.T, _ *commandstest.Harness, rec *commandstest.Recorder) {
+				fields := rec.Embeds()[0].Fields
+				if len(fields) != 2 || fields[0].Name != "Raid Mate (default)" || fields[1].Name != "Alt Mate" {
+					t.Errorf("settings fields = %+v, want the default and the alt guild", fields)
+				}
+			},
+		},
+		{
+			name: "settings - not set up",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				ListWowGuildsFunc: func(_ context.Context, _ snowflake.ID) ([]repo.WowGuild, error) {
+					return nil, svcerr.New(svcerr.ErrNotConfigured, "")
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "settings view", nil)
+			},
+			want: want{
+				responded: true,
+				ephemeral: true,
+				content:   "This server has not been set up yet. Ask an administrator to click the \"Set me up!\" button in the welcome message.",
+			},
+		},
+		{
+			name: "settings - add opens modal",
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "settings add", nil)
+			},
+			want: want{responded: true, modal: "guild:add"},
+		},
+		{
+			name: "settings - edit opens prefilled modal",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				ListWowGuildsFunc: func(_ context.Context, _ snowflake.ID) ([]repo.WowGuild, error) {
+					return linked, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "settings edit", commandstest.Options{"guild": 2})
+			},
+			want: want{responded: true, modal: "guild:edit:2"},
+			check: func(t *testing.T, _ *commandstest.Harness, rec *commandstest.Recorder) {
+				modal, _ := rec.Modal()
+				row := modal.Components[1].(discord.ActionRowComponent)
+				if input := row[0].(discord.TextInputComponent); input.Value != "Silvermoon" {
+					t.Errorf("realm input = %q, want the realm of the guild", input.Value)
+				}
+			},
+		},
+		{
+			name: "settings - edit guild of another server",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				ListWowGuildsFunc: func(_ context.Context, _ snowflake.ID) ([]repo.WowGuild, error) {
+					return linked, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "settings edit", commandstest.Options{"guild": 99})
+			},
+			want: want{
+				responded: true,
+				ephemeral: true,
+				content:   "Your input is invalid: the guild is not linked to this server, choose one of the suggestions.",
+			},
+		},
+		{
+			name: "settings - edit modal updates guild",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				UpdateWowGuildFunc: func(_ context.Context, _ repo.UpdateWowGuildParams) error {
+					return nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Modal(ctx, h.CustomID("guild", customid.String("edit"), customid.Int(2)), map[string]string{
+					"guild_name":    "Alt Mate",
+					"guild_realm":   "Blackrock",
+					"guild_region":  "EU",
+					"guild_faction": "Horde",
+				}, manager)
+			},
+			want: want{responded: true, ephemeral: true, content: "The guild Alt Mate has been updated."},
+			check: func(t *testing.T, h *commandstest.Harness, _ *commandstest.Recorder) {
+				calls := h.Services.Guild.Called("UpdateWowGuild")
+				if len(calls) != 1 {
+					t.Fatalf("UpdateWowGuild called %d times, want 1", len(calls))
+				}
+				want := repo.UpdateWowGuildParams{
+					Name:    "Alt Mate",
+					Realm:   "Blackrock",
+					Region:  "EU",
+					Faction: "Horde",
+					ID:      2,
+					GuildID: int64(commandstest.GuildID),
+				}
+				if got := calls[0].Args[0].(repo.UpdateWowGuildParams); got != want {
+					t.Errorf("UpdateWowGuild params = %+v, want %+v", got, want)
+				}
+			},
+		},
+		{
+			name: "settings - add modal with invalid region",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				AddWowGuildFunc: func(_ context.Context, _ repo.AddWowGuildParams) error {
+					return svcerr.Invalid(svcerr.Msg("errors.invalid.region", "US, EU, KR, TW, CN"))
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Modal(ctx, h.CustomID("guild", customid.String("add")), map[string]string{
+					"guild_name":    "Alt Mate",
+					"guild_realm":   "Blackrock",
+					"guild_region":  "XX",
+					"guild_faction": "Horde",
+				}, manager)
+			},
+			want: want{responded: true, ephemeral: true, content: "Your input is invalid: the region must be one of US, EU, KR, TW, CN."},
+		},
+		{
+			name: "settings - default guild",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				ListWowGuildsFunc: func(_ context.Context, _ snowflake.ID) ([]repo.WowGuild, error) {
+					return linked, nil
+				},
+				SetDefaultWowGuildFunc: func(_ context.Context, _ snowflake.ID, _ int64) error {
+					return nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "settings default", commandstest.Options{"guild": 2})
+			},
+			want: want{responded: true, ephemeral: true, content: "Alt Mate is now the default guild."},
+			check: func(t *testing.T, h *commandstest.Harness, _ *commandstest.Recorder) {
+				if calls := h.Services.Guild.Called("SetDefaultWowGuild"); len(calls) != 1 || calls[0].Args[1] != int64(2) {
+					t.Errorf("SetDefaultWowGuild calls = %v, want a single call for the alt guild", calls)
+				}
+			},
+		},
+		{
+			name: "settings - remove asks for confirmation",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				ListWowGuildsFunc: func(_ context.Context, _ snowflake.ID) ([]repo.WowGuild, error) {
+					return linked, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "settings remove", commandstest.Options{"guild": 2})
+			},
+			want: want{responded: true, ephemeral: true, content: "Do you really want to remove Alt Mate (Silvermoon-EU)?"},
+			check: func(t *testing.T, h *commandstest.Harness, rec *commandstest.Recorder) {
+				buttons := rec.Buttons()
+				if len(buttons) != 2 {
+					t.Fatalf("got %d buttons, want confirm and cancel", len(buttons))
+				}
+				if want := h.CustomID("guild", customid.String("remove"), customid.Int(2)); buttons[0].CustomID != want {
+					t.Errorf("confirm button custom ID = %q, want %q", buttons[0].CustomID, want)
+				}
+				if calls := h.Services.Guild.Called("RemoveWowGuild"); len(calls) != 0 {
+					t.Errorf("RemoveWowGuild called before confirmation: %v", calls)
+				}
+			},
+		},
+		{
+			name: "settings - remove default guild",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				ListWowGuildsFunc: func(_ context.Context, _ snowflake.ID) ([]repo.WowGuild, error) {
+					return linked, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "settings remove", commandstest.Options{"guild": 1})
+			},
+			want: want{
+				responded: true,
+				ephemeral: true,
+				content:   "Your input is invalid: the default guild cannot be removed, make another guild the default first or reset the settings.",
+			},
+		},
+		{
+			name: "settings - confirmed removal",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				RemoveWowGuildFunc: func(_ context.Context, _ snowflake.ID, _ int64) error {
+					return nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Component(ctx, h.CustomID("guild", customid.String("remove"), customid.Int(2)), manager)
+			},
+			want: want{responded: true, content: "The guild has been removed."},
+			check: func(t *testing.T, h *commandstest.Harness, rec *commandstest.Recorder) {
+				if calls := h.Services.Guild.Called("RemoveWowGuild"); len(calls) != 1 || calls[0].Args[1] != int64(2) {
+					t.Errorf("RemoveWowGuild calls = %v, want a single call for the alt guild", calls)
+				}
+				if len(rec.Buttons()) != 0 {
+					t.Errorf("confirmation buttons were not removed")
+				}
+			},
+		},
+		{
+			name: "settings - slow removal updates the clicked message",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				RemoveWowGuildFunc: func(_ context.Context, _ snowflake.ID, _ int64) error {
+					time.Sleep(100 * time.Millisecond)
+					return nil
+				},
+			}},
+			opts: []commandstest.Option{commandstest.WithDeferAfter(10 * time.Millisecond)},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Component(ctx, h.CustomID("guild", customid.String("remove"), customid.Int(2)), manager)
+			},
+			want: want{responded: true, deferred: true, content: "The guild has been removed."},
+			check: func(t *testing.T, _ *commandstest.Harness, rec *commandstest.Recorder) {
+				if typ := rec.Responses()[0].Type; typ != discord.InteractionResponseTypeDeferredUpdateMessage {
+					t.Errorf("deferred with response type %d, want %d", typ, discord.InteractionResponseTypeDeferredUpdateMessage)
+				}
+				if n := len(rec.Edits()); n != 1 {
+					t.Errorf("got %d edits of the clicked message, want 1", n)
+				}
+				if n := len(rec.Followups()); n != 0 {
+					t.Errorf("got %d follow-ups, want none", n)
+				}
+			},
+		},
+		{
+			name: "settings - slow error of a button is sent as follow-up",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				RemoveWowGuildFunc: func(_ context.Context, _ snowflake.ID, _ int64) error {
+					time.Sleep(100 * time.Millisecond)
+					return errBoom
+				},
+			}},
+			opts: []commandstest.Option{commandstest.WithDeferAfter(10 * time.Millisecond)},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Component(ctx, h.CustomID("guild", customid.String("remove"), customid.Int(2)), manager)
+			},
+			want: want{responded: true, deferred: true},
+			check: func(t *testing.T, _ *commandstest.Harness, rec *commandstest.Recorder) {
+				if rec.Deleted() || len(rec.Edits()) != 0 {
+					t.Error("the clicked message was changed")
+				}
+				followups := rec.Followups()
+				if len(followups) != 1 || !followups[0].Flags.Has(discord.MessageFlagEphemeral) {
+					t.Fatalf("follow-ups = %+v, want a single ephemeral follow-up", followups)
+				}
+				if want := "Something went wrong on our side. Please try again later."; followups[0].Content != want {
+					t.Errorf("follow-up content = %q, want %q", followups[0].Content, want)
+				}
+			},
+		},
+		{
+			name: "settings - confirmed reset",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				ResetFunc: func(_ context.Context, _ snowflake.ID) error {
+					return nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Component(ctx, h.CustomID("guild", customid.String("reset")), manager)
+			},
+			want: want{responded: true, content: "All settings have been reset and only the default guild is still linked."},
+			check: func(t *testing.T, h *commandstest.Harness, _ *commandstest.Recorder) {
+				if calls := h.Services.Guild.Called("Reset"); len(calls) != 1 || calls[0].Args[0] != commandstest.GuildID {
+					t.Errorf("Reset calls = %v, want a single call for the server", calls)
+				}
+				if calls := h.Services.Guild.Called("Purge"); len(calls) != 0 {
+					t.Errorf("Purge called by a reset: %v", calls)
+				}
+			},
+		},
+		{
+			name: "settings - cancelled reset",
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Component(ctx, h.CustomID("guild", customid.String("cancel")), manager)
+			},
+			want: want{responded: true, content: "Nothing has been changed."},
+		},
+		{
+			name: "settings - delete all data asks the owner for confirmation",
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				h.AddGuild(discord.Guild{ID: commandstest.GuildID, OwnerID: commandstest.UserID})
+				return h.Slash(ctx, "settings delete-all-data", nil)
+			},
+			want: want{
+				responded: true,
+				ephemeral: true,
+				content:   "Do you really want to delete all data of this server? All linked guilds, credentials and main characters will be deleted immediately. This cannot be undone.",
+			},
+			check: func(t *testing.T, h *commandstest.Harness, rec *commandstest.Recorder) {
+				buttons := rec.Buttons()
+				if len(buttons) != 2 || buttons[0].CustomID != h.CustomID("guild", customid.String("purge")) {
+					t.Errorf("Buttons() = %v, want confirm and cancel", buttons)
+				}
+			},
+		},
+		{
+			name: "settings - delete all data is only allowed for the owner",
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				h.AddGuild(discord.Guild{ID: commandstest.GuildID, OwnerID: 1})
+				return h.Slash(ctx, "settings delete-all-data", nil)
+			},
+			want: want{responded: true, ephemeral: true, content: "You are not allowed to do this."},
+		},
+		{
+			name: "settings - confirmed deletion of all data",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				PurgeFunc: func(_ context.Context, id snowflake.ID) (guild.Purged, error) {
+					return guild.Purged{GuildID: id, WowGuilds: 2, Characters: 5, Credentials: 1}, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				h.AddGuild(discord.Guild{ID: commandstest.GuildID, OwnerID: commandstest.UserID})
+				return h.Component(ctx, h.CustomID("guild", customid.String("purge")), manager)
+			},
+			want: want{responded: true, content: "All data of this server has been deleted: 2 linked guilds, 5 main characters and 1 credentials."},
+			check: func(t *testing.T, h *commandstest.Harness, _ *commandstest.Recorder) {
+				if calls := h.Services.Guild.Called("Purge"); len(calls) != 1 || calls[0].Args[0] != commandstest.GuildID {
+					t.Errorf("Purge calls = %v, want a single call for the server", calls)
+				}
+			},
+		},
+		{
+			name: "settings - confirmed deletion of all data by another admin",
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				h.AddGuild(discord.Guild{ID: commandstest.GuildID, OwnerID: 1})
+				return h.Component(ctx, h.CustomID("guild", customid.String("purge")), manager)
+			},
+			want: want{responded: true, ephemeral: true, content: "You are not allowed to do this."},
+			check: func(t *testing.T, h *commandstest.Harness, _ *commandstest.Recorder) {
+				if calls := h.Services.Guild.Called("Purge"); len(calls) != 0 {
+					t.Errorf("Purge called by another admin: %v", calls)
+				}
+			},
+		},
+		{
+			name: "settings - view in the language of the server",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetLocaleFunc: func(_ context.Context, _ snowflake.ID) (string, error) {
+					return string(discord.LocaleGerman), nil
+				},
+				ListWowGuildsFunc: func(_ context.Context, _ snowflake.ID) ([]repo.WowGuild, error) {
+					return linked, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "settings view", nil)
+			},
+			want: want{responded: true, ephemeral: true, embeds: []string{"Gildeneinstellungen"}},
+			check: func(t *testing.T, _ *commandstest.Harness, rec *commandstest.Recorder) {
+				if fields := rec.Embeds()[0].Fields; len(fields) == 0 || fields[0].Name != "Raid Mate (Standard)" {
+					t.Errorf("settings fields = %+v, want the default guild in german", fields)
+				}
+			},
+		},
+		{
+			name: "settings - language of the server",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				SetLocaleFunc: func(_ context.Context, _ snowflake.ID, _ string) error {
+					return nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "settings language", commandstest.Options{"language": "de"})
+			},
+			want: want{responded: true, ephemeral: true, content: "Antworten auf diesem Server sind jetzt auf Deutsch."},
+			check: func(t *testing.T, h *commandstest.Harness, _ *commandstest.Recorder) {
+				if calls := h.Services.Guild.Called("SetLocale"); len(calls) != 1 || calls[0].Args[1] != "de" {
+					t.Errorf("SetLocale calls = %v, want a single call with german", calls)
+				}
+			},
+		},
+		{
+			name: "settings - language of each user",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetLocaleFunc: func(_ context.Context, _ snowflake.ID) (string, error) {
+					return string(discord.LocaleGerman), nil
+				},
+				SetLocaleFunc: func(_ context.Context, _ snowflake.ID, _ string) error {
+					return nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "settings language", commandstest.Options{"language": "user"})
+			},
+			want: want{responded: true, ephemeral: true, content: "Responses on this server now follow the language of each user."},
+			check: func(t *testing.T, h *commandstest.Harness, _ *commandstest.Recorder) {
+				if calls := h.Services.Guild.Called("SetLocale"); len(calls) != 1 || calls[0].Args[1] != "" {
+					t.Errorf("SetLocale calls = %v, want a single call clearing the language", calls)
+				}
+			},
+		},
+		{
+			name: "settings - schedule",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				SetScheduleFunc: func(_ context.Context, _ snowflake.ID, _ guild.Schedule) error {
+					return nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "settings schedule", commandstest.Options{"timezone": "Europe/Berlin", "raid-start": "20:00", "raid-end": "01:00"})
+			},
+			want: want{
+				responded: true,
+				ephemeral: true,
+				content:   "The schedule of this server is now Europe/Berlin, 20:00-01:00. Commands like `/logs` use it to determine the raid day.",
+			},
+			check: func(t *testing.T, h *commandstest.Harness, _ *commandstest.Recorder) {
+				calls := h.Services.Guild.Called("SetSchedule")
+				if len(calls) != 1 {
+					t.Fatalf("SetSchedule called %d times, want 1", len(calls))
+				}
+				if s := calls[0].Args[1].(guild.Schedule); s.RaidStart != 20*time.Hour || s.RaidEnd != time.Hour {
+					t.Errorf("SetSchedule raid hours = %s-%s, want 20h-1h", s.RaidStart, s.RaidEnd)
+				}
+			},
+		},
+		{
+			name: "settings - schedule with unknown timezone",
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "settings schedule", commandstest.Options{"timezone": "Mars/Olympus"})
+			},
+			want: want{responded: true, ephemeral: true, content: `Your input is invalid: "Mars/Olympus" is not a timezone like Europe/Berlin.`},
+			check: func(t *testing.T, h *commandstest.Harness, _ *commandstest.Recorder) {
+				if calls := h.Services.Guild.Called("SetSchedule"); len(calls) != 0 {
+					t.Errorf("SetSchedule called for an unknown timezone: %v", calls)
+				}
+			},
+		},
+		{
+			name: "settings - gear rules",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				SetGearRulesFunc: func(_ context.Context, _ snowflake.ID, _ guild.GearRules) error {
+					return nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "settings gear", commandstest.Options{"min-item-level": 610, "enchant-slots": "main-hand, back"})
+			},
+			want: want{
+				responded: true,
+				ephemeral: true,
+				content:   "The gear rules of this server are set. Items must be at least item level 610. Required enchants: Back, Main hand.",
+			},
+			check: func(t *testing.T, h *commandstest.Harness, _ *commandstest.Recorder) {
+				want := guild.GearRules{MinItemLevel: 610, EnchantSlots: []string{"back", "main_hand"}}
+				calls := h.Services.Guild.Called("SetGearRules")
+				if len(calls) != 1 || !slices.Equal(calls[0].Args[1].(guild.GearRules).EnchantSlots, want.EnchantSlots) ||
+					calls[0].Args[1].(guild.GearRules).MinItemLevel != want.MinItemLevel {
+					t.Errorf("SetGearRules calls = %v, want a single call with %+v", calls, want)
+				}
+			},
+		},
+		{
+			name: "settings - gear rules with unknown slot",
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "settings gear", commandstest.Options{"enchant-slots": "tabard"})
+			},
+			want: want{
+				responded: true,
+				ephemeral: true,
+				content:   `Your input is invalid: "tabard" is not one of the slots head, neck, shoulder, back, chest, wrist, hands, waist, legs, feet, finger_1, finger_2, trinket_1, trinket_2, main_hand, off_hand.`,
+			},
+			check: func(t *testing.T, h *commandstest.Harness, _ *commandstest.Recorder) {
+				if calls := h.Services.Guild.Called("SetGearRules"); len(calls) != 0 {
+					t.Errorf("SetGearRules called for an unknown slot: %v", calls)
+				}
+			},
+		},
+		{
+			name: "settings - raider rank",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				SetRaiderRankFunc: func(_ context.Context, _ snowflake.ID, _ int) error {
+					return nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "settings raiders", commandstest.Options{"rank": 3})
+			},
+			want: want{
+				responded: true,
+				ephemeral: true,
+				content:   "Members up to the guild rank 3 count as raiders now. Commands like `/gear-audit` only fetch their data.",
+			},
+			check: func(t *testing.T, h *commandstest.Harness, _ *commandstest.Recorder) {
+				if calls := h.Services.Guild.Called("SetRaiderRank"); len(calls) != 1 || calls[0].Args[1] != 3 {
+					t.Errorf("SetRaiderRank calls = %v, want a single call with rank 3", calls)
+				}
+			},
+		},
+		{
+			name: "settings - all members are raiders",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				SetRaiderRankFunc: func(_ context.Context, _ snowflake.ID, _ int) error {
+					return nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "settings raiders", nil)
+			},
+			want: want{responded: true, ephemeral: true, content: "All members of the guild count as raiders now."},
+			check: func(t *testing.T, h *commandstest.Harness, _ *commandstest.Recorder) {
+				if calls := h.Services.Guild.Called("SetRaiderRank"); len(calls) != 1 || calls[0].Args[1] != -1 {
+					t.Errorf("SetRaiderRank calls = %v, want a single call with a negative rank", calls)
+				}
+			},
+		},
+		{
+			name: "settings - timezone suggestions",
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Autocomplete(ctx, "settings schedule", commandstest.Options{"timezone": "berl"}, "timezone")
+			},
+			want: want{responded: true, suggestions: []string{"Europe/Berlin"}},
+		},
+		{
+			name: "settings - unsupported language",
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "settings language", commandstest.Options{"language": "fr"})
+			},
+			want: want{responded: true, ephemeral: true, content: "Your input is invalid: \"fr\" is not a supported language."},
+			check: func(t *testing.T, h *commandstest.Harness, _ *commandstest.Recorder) {
+				if calls := h.Services.Guild.Called("SetLocale"); len(calls) != 0 {
+					t.Errorf("SetLocale called for an unsupported language: %v", calls)
+				}
+			},
+		},
+		{
+			name: "settings - autocomplete for linked guilds",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				ListWowGuildsFunc: func(_ context.Context, _ snowflake.ID) ([]repo.WowGuild, error) {
+					return linked, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Autocomplete(ctx, "settings edit", commandstest.Options{"guild": "alt"}, "guild")
+			},
+			want: want{responded: true, suggestions: []string{"2"}},
+		},
+		{
+			name: "settings - configure starts with the announcement channel",
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "settings configure", nil)
+			},
+			want: want{responded: true, ephemeral: true, content: "Step 2 of 3: Choose the channel I should post announcements in."},
+		},
+	}
+
+	runCommandTests(t, tests)
+}
+
+// linkedGuilds returns the default and an extra WoW guild linked to the test server.
+func linkedGuilds() []repo.WowGuild {
+	return []repo.WowGuild{
+		{ID: 1, GuildID: int64(commandstest.GuildID), Name: "Raid Mate", Realm: "Draenor", Region: "eu", Faction: "horde", IsDefault: true},
+		{ID: 2, GuildID: int64(commandstest.GuildID), Name: "Alt Mate", Realm: "Silvermoon", Region: "eu", Faction: "alliance"},
+	}
+}
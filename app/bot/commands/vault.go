@@ -0,0 +1,204 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/gofiber/fiber/v3"
+	"github.com/lvlcn-t/go-kit/apimanager/fiberutils"
+	"github.com/lvlcn-t/loggerhead/logger"
+	"github.com/lvlcn-t/raid-mate/app/bot/colors"
+	"github.com/lvlcn-t/raid-mate/app/bot/i18n"
+	"github.com/lvlcn-t/raid-mate/app/services/guild"
+)
+
+var (
+	_ Command[*events.ApplicationCommandInteractionCreate] = (*Vault)(nil)
+	_ AutocompleteCommand                                  = (*Vault)(nil)
+)
+
+const (
+	// vaultPerPage is the number of raiders shown on a single page of the table.
+	vaultPerPage = 20
+	// vaultLockedSlot is shown for the slots of the Great Vault that are not unlocked yet.
+	vaultLockedSlot = "-"
+)
+
+// Vault is a command to show the progress of the raiders towards the slots of the Great Vault.
+type Vault struct {
+	// Base is the common base for all commands.
+	*Base[*events.ApplicationCommandInteractionCreate]
+	// service is the guild service.
+	service guild.Service
+	// paginator sends the progress on multiple pages.
+	paginator *Paginator
+}
+
+// newVault creates a new vault command.
+func newVault(svc guild.Service, paginator *Paginator) *Vault {
+	return &Vault{
+		Base:      NewBase[*events.ApplicationCommandInteractionCreate]("vault"),
+		service:   svc,
+		paginator: paginator,
+	}
+}
+
+// Handle is the handler for the command that is called when the event is triggered.
+// It shows a table of the raiders with their Mythic+ runs and raid bosses of the week and the slots they unlock.
+func (c *Vault) Handle(ctx context.Context, event *events.ApplicationCommandInteractionCreate) {
+	log := logger.FromContext(ctx).With("command", c.Name())
+	data := event.SlashCommandInteractionData()
+	ctx = withLinkedGuild(ctx, data)
+	vault, err := c.service.GetVault(ctx, *event.GuildID(), data.String("character"))
+	if err != nil {
+		replyError(ctx, log, event, err)
+		return
+	}
+
+	err = c.paginator.Send(ctx, event, vaultPages(ctx, event, vault), false)
+	if err != nil {
+		log.ErrorContext(ctx, "Error replying to interaction", "error", err)
+	}
+}
+
+// vaultPages returns the pages of the table of the raiders.
+func vaultPages(ctx context.Context, event localized, vault *guild.Vault) []discord.Embed {
+	description := tr(ctx, event, "vault.reset", vault.Reset.Unix()) + "\n" +
+		tr(ctx, event, "vault.rules", vaultThresholds(guild.VaultRuns), vaultThresholds(guild.VaultBosses))
+	if len(vault.Unavailable) > 0 {
+		description += "\n" + tr(ctx, event, "vault.unavailable", strings.Join(vault.Unavailable, ", "))
+	}
+	if len(vault.Players) == 0 {
+		return []discord.Embed{discord.NewEmbedBuilder().
+			SetTitle(tr(ctx, event, "vault.title")).
+			SetDescription(description + "\n\n" + tr(ctx, event, "vault.none")).
+			SetColor(colors.Yellow.Int()).
+			Build(),
+		}
+	}
+
+	row := func(character, runs, dungeons, bosses, raid string) string {
+		return fmt.Sprintf("%-12s %5s %-11s %6s %s", character, runs, dungeons, bosses, raid)
+	}
+	columns := row(
+		tr(ctx, event, "vault.column_character"),
+		tr(ctx, event, "vault.column_runs"),
+		tr(ctx, event, "vault.column_dungeons"),
+		tr(ctx, event, "vault.column_bosses"),
+		tr(ctx, event, "vault.column_raid"),
+	)
+
+	var pages []discord.Embed
+	for start := 0; start < len(vault.Players); start += vaultPerPage {
+		lines := []string{columns}
+		for _, p := range vault.Players[start:min(start+vaultPerPage, len(vault.Players))] {
+			dungeons := make([]string, 0, len(p.Dungeons))
+			for _, level := range p.Dungeons {
+				dungeons = append(dungeons, fmt.Sprintf("+%d", level))
+			}
+			raid := make([]string, 0, len(p.Raid))
+			for _, difficulty := range p.Raid {
+				raid = append(raid, tr(ctx, event, "vault.difficulties."+strings.ToLower(difficulty)))
+			}
+			lines = append(lines, row(
+				p.Character,
+				vaultProgress(len(p.Runs), guild.VaultRuns),
+				vaultSlots(dungeons, len(guild.VaultRuns)),
+				vaultProgress(len(p.Bosses), guild.VaultBosses),
+				vaultSlots(raid, len(guild.VaultBosses)),
+			))
+		}
+		pages = append(pages, discord.NewEmbedBuilder().
+			SetTitle(tr(ctx, event, "vault.title")).
+			SetDescription(description+"\n```\n"+strings.Join(lines, "\n")+"\n```").
+			SetColor(colors.Yellow.Int()).
+			Build(),
+		)
+	}
+	return pages
+}
+
+// vaultThresholds returns the numbers of runs or bosses that unlock the slots separated by slashes, e.g. "1/4/8".
+func vaultThresholds(thresholds []int) string {
+	parts := make([]string, 0, len(thresholds))
+	for _, n := range thresholds {
+		parts = append(parts, fmt.Sprintf("%d", n))
+	}
+	return strings.Join(parts, "/")
+}
+
+// vaultProgress returns the number of runs or bosses towards the number that unlocks the last slot, e.g. "4/8".
+// More than needed for the last slot do not matter, so they are not shown.
+func vaultProgress(done int, thresholds []int) string {
+	last := thresholds[len(thresholds)-1]
+	return fmt.Sprintf("%d/%d", min(done, last), last)
+}
+
+// vaultSlots returns the rewards of the unlocked slots followed by a placeholder for each locked slot.
+func vaultSlots(rewards []string, slots int) string {
+	for len(rewards) < slots {
+		rewards = append(rewards, vaultLockedSlot)
+	}
+	return strings.Join(rewards, " ")
+}
+
+// HandleAutocomplete suggests the linked guilds and the characters in the roster of the guild.
+func (c *Vault) HandleAutocomplete(ctx context.Context, event *events.AutocompleteInteractionCreate) {
+	log := logger.FromContext(ctx).With("command", c.Name())
+	if event.Data.Focused().Name == linkedGuildOption {
+		respondSuggestions(ctx, log, event, suggestLinkedGuilds(ctx, log, c.service, event))
+		return
+	}
+	if event.GuildID() == nil || event.Data.Focused().Name != "character" {
+		respondSuggestions(ctx, log, event, nil)
+		return
+	}
+
+	roster, err := c.service.GetRoster(withLinkedGuild(ctx, event.Data), *event.GuildID())
+	if err != nil {
+		logError(ctx, log, err)
+	}
+	respondSuggestions(ctx, log, event, suggest(event.Data.String("character"), roster))
+}
+
+// HandleHTTP is the handler for the command that is called when the HTTP request is triggered.
+func (c *Vault) HandleHTTP(ctx fiber.Ctx) error {
+	log := logger.FromContext(ctx.Context()).With("command", c.Name())
+	gid, err := fiberutils.Params(ctx, "guildID", snowflake.Parse)
+	if err != nil {
+		return errorResponse(ctx, log, errors.Join(errInvalidGuildID, err))
+	}
+
+	vault, err := c.service.GetVault(ctx.Context(), gid, ctx.Query("character"))
+	if err != nil {
+		return errorResponse(ctx, log, err)
+	}
+	return ctx.Status(http.StatusOK).JSON(vault)
+}
+
+// Route returns the route for the command.
+func (c *Vault) Route() (methods []string, path string) {
+	return []string{http.MethodGet}, "/guilds/:guildID/vault"
+}
+
+// Info returns the interaction command information.
+func (c *Vault) Info() (discord.ApplicationCommandCreate, error) {
+	return NewInfoBuilder().
+		Name(c.Name(), nil).
+		Description(i18n.Text("commands.vault.description")).
+		Option(NewStringOptionBuilder().
+			Name("character", i18n.Localizations("commands.vault.options.character.name")).
+			Description(i18n.Text("commands.vault.options.character.description")).
+			Required(false).
+			MaxLength(maxCharacterNameLength).
+			Autocomplete(true),
+		).
+		Option(newLinkedGuildOption()).
+		Build()
+}
@@ -0,0 +1,88 @@
+package commands_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands/commandstest"
+	"github.com/lvlcn-t/raid-mate/app/services/guild"
+)
+
+func TestVault(t *testing.T) {
+	tests := []commandTest{
+		{
+			name: "vault - table of the roster",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetVaultFunc: func(_ context.Context, _ snowflake.ID, _ string) (*guild.Vault, error) {
+					return weekVault(), nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "vault", nil)
+			},
+			want: want{responded: true, embeds: []string{"Great Vault"}},
+			check: func(t *testing.T, _ *commandstest.Harness, rec *commandstest.Recorder) {
+				want := "Progress since the weekly reset <t:1714536000:f>.\n" +
+					"Slots unlock at 1/4/8 Mythic+ runs and 2/4/6 raid bosses and reward the worst run or boss counted for them.\n" +
+					"The Mythic+ runs of Tifa are unknown to Raider.IO.\n```\n" +
+					"Character     Runs Mythic+     Bosses Raid\n" +
+					"Bjorn          4/8 +12 +8 -       4/6 H N -\n" +
+					"Aerith         1/8 +11 - -        1/6 - - -\n" +
+					"Tifa           0/8 - - -          0/6 - - -\n```"
+				if got := rec.Embeds()[0].Description; got != want {
+					t.Errorf("description = %q, want %q", got, want)
+				}
+			},
+		},
+		{
+			name: "vault - character is passed",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetVaultFunc: func(_ context.Context, _ snowflake.ID, _ string) (*guild.Vault, error) {
+					vault := weekVault()
+					vault.Players, vault.Unavailable = vault.Players[:1], []string{}
+					return vault, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "vault", commandstest.Options{"character": "bjorn"}, commandstest.WithLocale(discord.LocaleGerman))
+			},
+			want: want{responded: true, embeds: []string{"Große Schatzkammer"}},
+			check: func(t *testing.T, h *commandstest.Harness, rec *commandstest.Recorder) {
+				want := "```\nCharakter    Läufe Mythisch+    Bosse Raid\n" +
+					"Bjorn          4/8 +12 +8 -       4/6 H N -\n```"
+				if got := rec.Embeds()[0].Description; !strings.HasSuffix(got, want) {
+					t.Errorf("description = %q, want suffix %q", got, want)
+				}
+				if calls := h.Services.Guild.Called("GetVault"); len(calls) != 1 || calls[0].Args[1] != "bjorn" {
+					t.Errorf("GetVault calls = %v, want a single call for bjorn", calls)
+				}
+			},
+		},
+	}
+
+	runCommandTests(t, tests)
+}
+
+// weekVault returns the progress of the raiders towards the Great Vault in the week after the reset of the EU.
+func weekVault() *guild.Vault {
+	return &guild.Vault{
+		Reset: time.Date(2024, 5, 1, 4, 0, 0, 0, time.UTC),
+		Players: []guild.PlayerVault{
+			{
+				Character: "Bjorn",
+				Class:     "Warrior",
+				Runs:      []int{12, 10, 9, 8},
+				Bosses:    []string{"Mythic", "Heroic", "Heroic", "Normal"},
+				Dungeons:  []int{12, 8},
+				Raid:      []string{"Heroic", "Normal"},
+			},
+			{Character: "Aerith", Class: "Priest", Runs: []int{11}, Bosses: []string{"Heroic"}, Dungeons: []int{11}, Raid: []string{}},
+			{Character: "Tifa", Class: "Monk", Runs: []int{}, Bosses: []string{}, Dungeons: []int{}, Raid: []string{}},
+		},
+		Unavailable: []string{"Tifa"},
+	}
+}
@@ -0,0 +1,200 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/events"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/gofiber/fiber/v3"
+	"github.com/lvlcn-t/go-kit/apimanager/fiberutils"
+	"github.com/lvlcn-t/loggerhead/logger"
+	"github.com/lvlcn-t/raid-mate/app/bot/colors"
+	"github.com/lvlcn-t/raid-mate/app/bot/i18n"
+	"github.com/lvlcn-t/raid-mate/app/services/analysis"
+	"github.com/lvlcn-t/raid-mate/app/services/guild"
+)
+
+var (
+	_ Command[*events.ApplicationCommandInteractionCreate] = (*Wipes)(nil)
+	_ AutocompleteCommand                                  = (*Wipes)(nil)
+)
+
+// maxWipeCauses is the number of wipe causes shown above the pulls.
+const maxWipeCauses = 5
+
+// Wipes is a command to analyze the deaths of the boss pulls of a report.
+type Wipes struct {
+	// Base is the common base for all commands.
+	*Base[*events.ApplicationCommandInteractionCreate]
+	// service is the guild service.
+	service guild.Service
+	// paginator sends the pulls on multiple pages.
+	paginator *Paginator
+}
+
+// newWipes creates a new wipes command.
+func newWipes(svc guild.Service, paginator *Paginator) *Wipes {
+	return &Wipes{
+		Base:      NewBase[*events.ApplicationCommandInteractionCreate]("wipes"),
+		service:   svc,
+		paginator: paginator,
+	}
+}
+
+// Handle is the handler for the command that is called when the event is triggered.
+// It shows the most common causes of the wipes and who died first in each pull.
+func (c *Wipes) Handle(ctx context.Context, event *events.ApplicationCommandInteractionCreate) {
+	log := logger.FromContext(ctx).With("command", c.Name())
+	data := event.SlashCommandInteractionData()
+	ctx = withLinkedGuild(ctx, data)
+	wipes, err := c.service.GetWipes(ctx, *event.GuildID(), data.String("report"), data.String("boss"))
+	if err != nil {
+		replyError(ctx, log, event, err)
+		return
+	}
+
+	err = c.paginator.Send(ctx, event, wipesPages(ctx, event, wipes), false)
+	if err != nil {
+		log.ErrorContext(ctx, "Error replying to interaction", "error", err)
+	}
+}
+
+// wipesPages returns the pages showing the wipe causes in the description and a field per pull.
+func wipesPages(ctx context.Context, event localized, wipes *guild.Wipes) []discord.Embed {
+	title := tr(ctx, event, "wipes.title", wipes.Title)
+	if wipes.Boss != "" {
+		title = tr(ctx, event, "wipes.title_boss", wipes.Boss, wipes.Title)
+	}
+
+	var description string
+	switch {
+	case len(wipes.Pulls) == 0:
+		description = tr(ctx, event, "wipes.no_pulls")
+	case len(wipes.Causes) == 0:
+		description = tr(ctx, event, "wipes.no_wipes")
+	default:
+		lines := []string{tr(ctx, event, "wipes.causes")}
+		for i, cause := range wipes.Causes[:min(maxWipeCauses, len(wipes.Causes))] {
+			lines = append(lines, tr(ctx, event, "wipes.cause", i+1, ability(ctx, event, cause.Ability), cause.FirstDeaths, cause.Deaths))
+		}
+		description = strings.Join(lines, "\n")
+	}
+
+	template := discord.NewEmbedBuilder().
+		SetTitle(title).
+		SetURL(wipes.URL).
+		SetDescription(description).
+		SetColor(colors.Red.Int()).
+		Build()
+
+	fields := make([]discord.EmbedField, 0, len(wipes.Pulls))
+	for _, p := range wipes.Pulls {
+		fields = append(fields, pullField(ctx, event, &p))
+	}
+	return fieldPages(template, fields, maxEmbedFields)
+}
+
+// pullField returns the field showing the outcome of the pull and who died first.
+func pullField(ctx context.Context, event localized, p *analysis.PullDeaths) discord.EmbedField {
+	boss := p.Boss
+	if p.Difficulty != "" {
+		boss = fmt.Sprintf("%s (%s)", p.Boss, p.Difficulty)
+	}
+
+	duration := p.Duration.Round(time.Second).String()
+	outcome := tr(ctx, event, "wipes.kill", duration)
+	if !p.Kill {
+		outcome = tr(ctx, event, "wipes.wipe", duration)
+	}
+
+	deaths := tr(ctx, event, "wipes.no_deaths")
+	if p.First != nil {
+		deaths = tr(ctx, event, "wipes.first_death",
+			p.First.Player, ability(ctx, event, p.First.Ability), p.First.After.Round(time.Second).String(), p.Deaths)
+	}
+	return discord.EmbedField{Name: tr(ctx, event, "wipes.pull", boss, p.Number), Value: outcome + "\n" + deaths}
+}
+
+// ability returns the name of the ability or a placeholder if the killing blow is unknown.
+func ability(ctx context.Context, event localized, name string) string {
+	if name == "" {
+		return tr(ctx, event, "wipes.unknown_ability")
+	}
+	return name
+}
+
+// HandleAutocomplete suggests the linked guilds and the bosses pulled in the report given in the report option.
+func (c *Wipes) HandleAutocomplete(ctx context.Context, event *events.AutocompleteInteractionCreate) {
+	log := logger.FromContext(ctx).With("command", c.Name())
+	if event.Data.Focused().Name == linkedGuildOption {
+		respondSuggestions(ctx, log, event, suggestLinkedGuilds(ctx, log, c.service, event))
+		return
+	}
+	code := event.Data.String("report")
+	if event.GuildID() == nil || event.Data.Focused().Name != "boss" || code == "" {
+		respondSuggestions(ctx, log, event, nil)
+		return
+	}
+
+	report, err := c.service.GetReport(withLinkedGuild(ctx, event.Data), *event.GuildID(), code)
+	if err != nil {
+		logError(ctx, log, err)
+		respondSuggestions(ctx, log, event, nil)
+		return
+	}
+
+	var bosses []string
+	for _, e := range report.Encounters {
+		if !slices.Contains(bosses, e.Boss) {
+			bosses = append(bosses, e.Boss)
+		}
+	}
+	respondSuggestions(ctx, log, event, suggest(event.Data.String("boss"), bosses))
+}
+
+// HandleHTTP is the handler for the command that is called when the HTTP request is triggered.
+func (c *Wipes) HandleHTTP(ctx fiber.Ctx) error {
+	log := logger.FromContext(ctx.Context()).With("command", c.Name())
+	gid, err := fiberutils.Params(ctx, "guildID", snowflake.Parse)
+	if err != nil {
+		return errorResponse(ctx, log, errors.Join(errInvalidGuildID, err))
+	}
+
+	wipes, err := c.service.GetWipes(ctx.Context(), gid, ctx.Params("reportID"), ctx.Query("boss"))
+	if err != nil {
+		return errorResponse(ctx, log, err)
+	}
+	return ctx.Status(http.StatusOK).JSON(wipes)
+}
+
+// Route returns the route for the command.
+func (c *Wipes) Route() (methods []string, path string) {
+	return []string{http.MethodGet}, "/guilds/:guildID/wipes/:reportID"
+}
+
+// Info returns the interaction command information.
+func (c *Wipes) Info() (discord.ApplicationCommandCreate, error) {
+	return NewInfoBuilder().
+		Name(c.Name(), nil).
+		Description(i18n.Text("commands.wipes.description")).
+		Option(NewStringOptionBuilder().
+			Name("report", i18n.Localizations("commands.wipes.options.report.name")).
+			Description(i18n.Text("commands.wipes.options.report.description")).
+			Required(true),
+		).
+		Option(NewStringOptionBuilder().
+			Name("boss", i18n.Localizations("commands.wipes.options.boss.name")).
+			Description(i18n.Text("commands.wipes.options.boss.description")).
+			Required(false).
+			Autocomplete(true),
+		).
+		Option(newLinkedGuildOption()).
+		Build()
+}
@@ -0,0 +1,135 @@
+package commands_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands/commandstest"
+	"github.com/lvlcn-t/raid-mate/app/services/analysis"
+	"github.com/lvlcn-t/raid-mate/app/services/guild"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+)
+
+func TestWipes(t *testing.T) {
+	tests := []commandTest{
+		{
+			name: "wipes - deaths of a report",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetWipesFunc: func(_ context.Context, _ snowflake.ID, _, _ string) (*guild.Wipes, error) {
+					return nightWipes(), nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "wipes", commandstest.Options{"report": "https://www.warcraftlogs.com/reports/a1B2c3D4e5F6g7H8"})
+			},
+			want: want{responded: true, embeds: []string{"Wipes in Nerub-ar Palace Heroic"}},
+			check: func(t *testing.T, h *commandstest.Harness, rec *commandstest.Recorder) {
+				embed := rec.Embeds()[0]
+				wantDescription := "**Most common wipe causes**\n" +
+					"1. Gruesome Disgorge: first death in 1 wipes, 3 deaths\n" +
+					"2. Unknown: first death in 1 wipes, 1 deaths"
+				if embed.Description != wantDescription {
+					t.Errorf("description = %q, want %q", embed.Description, wantDescription)
+				}
+				wantFields := []discord.EmbedField{
+					{Name: "The Bloodbound Horror (Heroic), pull 1", Value: "**Wipe** after 4m10s\nAerith died first to Gruesome Disgorge after 1m1s, 2 deaths in total"},
+					{Name: "The Bloodbound Horror (Heroic), pull 2", Value: "**Wipe** after 5m12s\nBjorn died first to Unknown after 2m3s, 2 deaths in total"},
+					{Name: "The Bloodbound Horror (Heroic), pull 3", Value: "**Kill** after 4m49s\nNobody died."},
+				}
+				if len(embed.Fields) != len(wantFields) {
+					t.Fatalf("got %d fields, want %d", len(embed.Fields), len(wantFields))
+				}
+				for i, f := range embed.Fields {
+					if f.Name != wantFields[i].Name || f.Value != wantFields[i].Value {
+						t.Errorf("field %d = %q: %q, want %q: %q", i, f.Name, f.Value, wantFields[i].Name, wantFields[i].Value)
+					}
+				}
+
+				calls := h.Services.Guild.Called("GetWipes")
+				if len(calls) != 1 || calls[0].Args[2].(string) != "" {
+					t.Errorf("GetWipes calls = %v, want one for all bosses", calls)
+				}
+			},
+		},
+		{
+			name: "wipes - boss without wipes",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetWipesFunc: func(_ context.Context, _ snowflake.ID, _, boss string) (*guild.Wipes, error) {
+					wipes := nightWipes()
+					wipes.Boss = boss
+					wipes.Pulls, wipes.Causes = wipes.Pulls[2:], nil
+					return wipes, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "wipes", commandstest.Options{"report": "a1B2c3D4e5F6g7H8", "boss": "The Bloodbound Horror"},
+					commandstest.WithLocale(discord.LocaleGerman))
+			},
+			want: want{responded: true, embeds: []string{"Wipes bei The Bloodbound Horror in Nerub-ar Palace Heroic"}},
+			check: func(t *testing.T, _ *commandstest.Harness, rec *commandstest.Recorder) {
+				if got, want := rec.Embeds()[0].Description, "Kein Pull endete in einem Wipe."; got != want {
+					t.Errorf("description = %q, want %q", got, want)
+				}
+			},
+		},
+		{
+			name: "wipes - invalid report",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetWipesFunc: func(_ context.Context, _ snowflake.ID, code, _ string) (*guild.Wipes, error) {
+					return nil, svcerr.Invalid(svcerr.Msg("errors.invalid.report", code))
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Slash(ctx, "wipes", commandstest.Options{"report": "yesterday"})
+			},
+			want: want{responded: true, ephemeral: true, content: `Your input is invalid: "yesterday" is not the code or the URL of a Warcraft Logs report.`},
+		},
+		{
+			name: "wipes - autocomplete suggests bosses of the report",
+			services: commandstest.Services{Guild: &commandstest.GuildService{
+				GetReportFunc: func(_ context.Context, _ snowflake.ID, _ string) (*guild.Report, error) {
+					r := raidReport()
+					return &r, nil
+				},
+			}},
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Autocomplete(ctx, "wipes", commandstest.Options{"report": "a1B2c3D4e5F6g7H8", "boss": "horror"}, "boss")
+			},
+			want: want{responded: true, suggestions: []string{"The Bloodbound Horror"}},
+		},
+		{
+			name: "wipes - autocomplete without report",
+			run: func(ctx context.Context, h *commandstest.Harness) *commandstest.Recorder {
+				return h.Autocomplete(ctx, "wipes", commandstest.Options{"boss": "horror"}, "boss")
+			},
+			want: want{responded: true, suggestions: []string{}},
+		},
+	}
+
+	runCommandTests(t, tests)
+}
+
+// nightWipes returns the deaths of the pulls of The Bloodbound Horror in the raid report.
+func nightWipes() *guild.Wipes {
+	return &guild.Wipes{
+		Code:  "a1B2c3D4e5F6g7H8",
+		Title: "Nerub-ar Palace Heroic",
+		URL:   "https://www.warcraftlogs.com/reports/a1B2c3D4e5F6g7H8",
+		Wipes: analysis.Wipes{
+			Pulls: []analysis.PullDeaths{
+				{Boss: "The Bloodbound Horror", Difficulty: "Heroic", Number: 1, Duration: 250 * time.Second, Deaths: 2,
+					First: &analysis.FirstDeath{Player: "Aerith", Ability: "Gruesome Disgorge", After: 61 * time.Second}},
+				{Boss: "The Bloodbound Horror", Difficulty: "Heroic", Number: 2, Duration: 312 * time.Second, Deaths: 2,
+					First: &analysis.FirstDeath{Player: "Bjorn", After: 123 * time.Second}},
+				{Boss: "The Bloodbound Horror", Difficulty: "Heroic", Number: 3, Kill: true, Duration: 289 * time.Second},
+			},
+			Causes: []analysis.Cause{
+				{Ability: "Gruesome Disgorge", FirstDeaths: 1, Deaths: 3},
+				{Ability: "", FirstDeaths: 1, Deaths: 1},
+			},
+		},
+	}
+}
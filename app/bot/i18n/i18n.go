@@ -0,0 +1,172 @@
+// Package i18n provides the catalogs of the user facing messages of the bot.
+//
+// The messages of each locale are kept in a JSON file named after the locale, e.g. "de.json",
+// which maps the keys of the messages to their text. Texts can be format strings, see [Catalog.T].
+// The catalogs are embedded in the binary and used for both the metadata of the commands and their responses.
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+// Fallback is the locale of the messages that are not translated to the requested locale.
+// It is also the locale the commands are registered in.
+const Fallback = discord.LocaleEnglishUS
+
+// files are the embedded catalogs.
+//
+//go:embed locales/*.json
+var files embed.FS
+
+// catalog is the catalog of the embedded files.
+var catalog = mustLoad()
+
+// Catalog holds the messages of all supported locales.
+type Catalog struct {
+	// messages are the messages mapped by their locale and key.
+	messages map[discord.Locale]map[string]string
+}
+
+// Load parses the catalogs in the root of the file system, one JSON file per locale.
+// It returns an error if there is no catalog for the [Fallback] locale.
+func Load(fsys fs.FS) (*Catalog, error) {
+	names, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Catalog{messages: make(map[discord.Locale]map[string]string, len(names))}
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, fmt.Errorf("error reading catalog %q: %w", name, err)
+		}
+
+		var messages map[string]string
+		if err = json.Unmarshal(data, &messages); err != nil {
+			return nil, fmt.Errorf("error parsing catalog %q: %w", name, err)
+		}
+		c.messages[discord.Locale(strings.TrimSuffix(path.Base(name), ".json"))] = messages
+	}
+
+	if _, ok := c.messages[Fallback]; !ok {
+		return nil, fmt.Errorf("missing catalog for the fallback locale %q", Fallback)
+	}
+	return c, nil
+}
+
+// mustLoad loads the embedded catalogs and panics if they are invalid.
+func mustLoad() *Catalog {
+	sub, err := fs.Sub(files, "locales")
+	if err != nil {
+		panic(err)
+	}
+	c, err := Load(sub)
+	if err != nil {
+		panic(fmt.Sprintf("invalid embedded catalogs: %v", err))
+	}
+	return c
+}
+
+// Default returns the catalog embedded in the binary.
+func Default() *Catalog {
+	return catalog
+}
+
+// Locales returns the supported locales in lexical order.
+func (c *Catalog) Locales() []discord.Locale {
+	return slices.Sorted(maps.Keys(c.messages))
+}
+
+// Supports reports whether there is a catalog for the locale.
+func (c *Catalog) Supports(locale discord.Locale) bool {
+	_, ok := c.messages[locale]
+	return ok
+}
+
+// Keys returns the keys of the messages of the locale in lexical order.
+func (c *Catalog) Keys(locale discord.Locale) []string {
+	return slices.Sorted(maps.Keys(c.messages[locale]))
+}
+
+// T returns the message with the given key in the locale.
+// If the message is not translated to the locale, the message of the [Fallback] locale is returned.
+// If there are arguments, the message is used as format string for them.
+// Unknown keys are returned as they are, so missing messages are noticeable but do not break responses.
+func (c *Catalog) T(locale discord.Locale, key string, args ...any) string {
+	msg, ok := c.messages[locale][key]
+	if !ok {
+		msg, ok = c.messages[Fallback][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// Localizations returns the translations of the message with the given key for all locales except the [Fallback] locale.
+// It is meant for the localizations of the metadata of commands and returns nil if there are none.
+func (c *Catalog) Localizations(key string) map[discord.Locale]string {
+	var res map[discord.Locale]string
+	for locale, messages := range c.messages {
+		msg, ok := messages[key]
+		if locale == Fallback || !ok {
+			continue
+		}
+		if res == nil {
+			res = map[discord.Locale]string{}
+		}
+		res[locale] = msg
+	}
+	return res
+}
+
+// Text returns the message with the given key in the [Fallback] locale and its [Catalog.Localizations].
+// It matches the signature of the name and description methods of the command builders.
+func (c *Catalog) Text(key string) (string, map[discord.Locale]string) {
+	return c.T(Fallback, key), c.Localizations(key)
+}
+
+// T returns the message with the given key of the default catalog, see [Catalog.T].
+func T(locale discord.Locale, key string, args ...any) string {
+	return catalog.T(locale, key, args...)
+}
+
+// Localizations returns the translations of the message with the given key of the default catalog, see [Catalog.Localizations].
+func Localizations(key string) map[discord.Locale]string {
+	return catalog.Localizations(key)
+}
+
+// Text returns the message with the given key of the default catalog and its translations, see [Catalog.Text].
+func Text(key string) (string, map[discord.Locale]string) {
+	return catalog.Text(key)
+}
+
+// localeKey is the context key of the locale.
+type localeKey struct{}
+
+// NewContext returns a copy of the context that carries the locale responses are sent in.
+func NewContext(ctx context.Context, locale discord.Locale) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// FromContext returns the locale carried by the context or the given locale if there is none.
+func FromContext(ctx context.Context, locale discord.Locale) discord.Locale {
+	if l, ok := ctx.Value(localeKey{}).(discord.Locale); ok {
+		return l
+	}
+	return locale
+}
@@ -0,0 +1,170 @@
+package i18n
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/disgoorg/disgo/discord"
+)
+
+// verbPattern matches the verbs of format strings.
+var verbPattern = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+// TestCatalogs flags messages missing in any locale and translations whose format verbs differ from the fallback.
+func TestCatalogs(t *testing.T) {
+	c := Default()
+	if len(c.Locales()) < 2 {
+		t.Fatalf("Locales() = %v, want at least one locale besides %q", c.Locales(), Fallback)
+	}
+
+	want := c.Keys(Fallback)
+	for _, locale := range c.Locales() {
+		t.Run(string(locale), func(t *testing.T) {
+			got := c.Keys(locale)
+			for _, key := range want {
+				if !slices.Contains(got, key) {
+					t.Errorf("missing key %q", key)
+				}
+			}
+			for _, key := range got {
+				if !slices.Contains(want, key) {
+					t.Errorf("key %q does not exist in %q", key, Fallback)
+				}
+			}
+
+			for _, key := range got {
+				msg, fallback := c.messages[locale][key], c.messages[Fallback][key]
+				if msg == "" {
+					t.Errorf("key %q is empty", key)
+				}
+				if v, w := verbPattern.FindAllString(msg, -1), verbPattern.FindAllString(fallback, -1); !slices.Equal(v, w) {
+					t.Errorf("key %q has the format verbs %q, want %q", key, v, w)
+				}
+			}
+		})
+	}
+}
+
+// TestErrorMessages flags the messages of service errors whose keys are missing in the catalogs
+// or that are given another number of arguments than their format verbs.
+// The messages are found by scanning the sources of the app for calls of svcerr.Msg.
+func TestErrorMessages(t *testing.T) {
+	c := Default()
+	fset := token.NewFileSet()
+	found := 0
+	err := filepath.WalkDir(filepath.Join("..", ".."), func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") {
+			return err
+		}
+		f, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || !isMsgCall(call) || len(call.Args) == 0 {
+				return true
+			}
+			pos := fset.Position(call.Pos())
+			lit, ok := call.Args[0].(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				t.Errorf("%s: the key of the message is not a string literal", pos)
+				return true
+			}
+			key, _ := strconv.Unquote(lit.Value)
+			found++
+
+			msg, ok := c.messages[Fallback][key]
+			if !ok {
+				t.Errorf("%s: missing key %q", pos, key)
+				return true
+			}
+			if verbs, args := len(verbPattern.FindAllString(msg, -1)), len(call.Args)-1; call.Ellipsis == token.NoPos && verbs != args {
+				t.Errorf("%s: key %q has %d format verbs, but %d arguments are given", pos, key, verbs, args)
+			}
+			return true
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("error parsing the sources: %v", err)
+	}
+	if found == 0 {
+		t.Error("found no messages of service errors")
+	}
+}
+
+// isMsgCall reports whether the call creates a message of a service error.
+func isMsgCall(call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Msg" {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == "svcerr"
+}
+
+func TestCatalog_T(t *testing.T) {
+	c, err := Load(fstest.MapFS{
+		"en-US.json": {Data: []byte(`{"greeting": "Hello %s!", "bye": "Bye"}`)},
+		"de.json":    {Data: []byte(`{"greeting": "Hallo %s!"}`)},
+	})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		locale discord.Locale
+		key    string
+		args   []any
+		want   string
+	}{
+		{name: "translated", locale: discord.LocaleGerman, key: "greeting", args: []any{"Raid Mate"}, want: "Hallo Raid Mate!"},
+		{name: "not translated", locale: discord.LocaleGerman, key: "bye", want: "Bye"},
+		{name: "unsupported locale", locale: discord.LocaleFrench, key: "greeting", args: []any{"Raid Mate"}, want: "Hello Raid Mate!"},
+		{name: "unknown key", locale: discord.LocaleGerman, key: "unknown", want: "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.T(tt.locale, tt.key, tt.args...); got != tt.want {
+				t.Errorf("T() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := c.Localizations("bye"); got != nil {
+		t.Errorf("Localizations() = %v, want nil for an untranslated message", got)
+	}
+	if got := c.Localizations("greeting"); len(got) != 1 || got[discord.LocaleGerman] != "Hallo %s!" {
+		t.Errorf("Localizations() = %v, want the german message only", got)
+	}
+}
+
+func TestLoad_MissingFallback(t *testing.T) {
+	_, err := Load(fstest.MapFS{"de.json": {Data: []byte(`{}`)}})
+	if err == nil {
+		t.Error("Load() error = nil, want an error for a missing fallback catalog")
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if got := FromContext(context.Background(), discord.LocaleGerman); got != discord.LocaleGerman {
+		t.Errorf("FromContext() = %q, want the given locale without a locale in the context", got)
+	}
+	ctx := NewContext(context.Background(), discord.LocaleEnglishUS)
+	if got := FromContext(ctx, discord.LocaleGerman); got != discord.LocaleEnglishUS {
+		t.Errorf("FromContext() = %q, want the locale of the context", got)
+	}
+}
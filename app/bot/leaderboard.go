@@ -0,0 +1,70 @@
+package bot
+
+import (
+	"context"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lvlcn-t/loggerhead/logger"
+	"github.com/lvlcn-t/raid-mate/app/bot/commands"
+	"github.com/lvlcn-t/raid-mate/app/bot/i18n"
+	"github.com/lvlcn-t/raid-mate/app/database/repo"
+)
+
+// leaderboardInterval is the interval the weekly leaderboards are checked for being due in.
+const leaderboardInterval = time.Hour
+
+// runLeaderboards takes the weekly Mythic+ snapshots every [leaderboardInterval] until the context is canceled.
+func (b *bot) runLeaderboards(ctx context.Context) {
+	ticker := time.NewTicker(leaderboardInterval)
+	defer ticker.Stop()
+
+	for {
+		b.postLeaderboards(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// postLeaderboards takes the Mythic+ snapshots that are due after the weekly reset
+// and posts the gains since the previous snapshot in the announcement channel of the servers.
+// The first snapshot of a server is not posted, as there is nothing the gains could be relative to.
+func (b *bot) postLeaderboards(ctx context.Context) {
+	log := logger.FromContext(ctx).With("job", "leaderboards")
+
+	weekly, err := b.services.Guild.SnapshotLeaderboards(ctx, time.Now())
+	if err != nil {
+		log.ErrorContext(ctx, "Failed to snapshot leaderboards", "error", err)
+	}
+
+	for _, w := range weekly {
+		if w.Leaderboard.Since.IsZero() || !w.Guild.AnnouncementChannelID.Valid {
+			continue
+		}
+
+		//nolint:gosec // Snowflake cannot overflow AFAIK
+		channelID := snowflake.ID(w.Guild.AnnouncementChannelID.Int64)
+		_, err = b.conn.Rest().CreateMessage(channelID, commands.WeeklyLeaderboardMessage(b.guildLocale(w.Guild), w.Leaderboard), rest.WithCtx(ctx))
+		if err != nil {
+			log.ErrorContext(ctx, "Failed to post leaderboard", "guild", w.Guild.ID, "error", err)
+		}
+	}
+}
+
+// guildLocale returns the locale of the messages posted in a server without an interaction.
+// The language set for the server takes precedence over the preferred locale of the server.
+func (b *bot) guildLocale(g repo.Guild) discord.Locale {
+	if l := discord.Locale(g.Locale.String); g.Locale.Valid && i18n.Default().Supports(l) {
+		return l
+	}
+	//nolint:gosec // Snowflake cannot overflow AFAIK
+	if cached, ok := b.conn.Caches().Guild(snowflake.ID(g.ID)); ok {
+		return discord.Locale(cached.PreferredLocale)
+	}
+	return i18n.Fallback
+}
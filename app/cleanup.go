@@ -0,0 +1,56 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/lvlcn-t/loggerhead/logger"
+)
+
+// cleanupInterval is the interval the cleanup job runs in.
+const cleanupInterval = time.Hour
+
+// runCleanup runs the cleanup job every [cleanupInterval] until the context is canceled.
+func (r *RaidMate) runCleanup(ctx context.Context) {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		r.cleanup(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// cleanup purges the data of servers the bot left longer than the retention period ago
+// and the expired state of message components.
+func (r *RaidMate) cleanup(ctx context.Context) {
+	log := logger.FromContext(ctx).With("job", "cleanup")
+
+	purged, err := r.services.Guild.PurgeLeft(ctx)
+	for _, p := range purged {
+		log.InfoContext(ctx, "Purged data of left guild",
+			"guild", p.GuildID.String(),
+			"wow_guilds", p.WowGuilds,
+			"characters", p.Characters,
+			"credentials", p.Credentials,
+			"consumable_audits", p.ConsumableAudits,
+			"mplus_snapshots", p.MplusSnapshots,
+		)
+	}
+	if err != nil {
+		log.ErrorContext(ctx, "Failed to purge left guilds", "error", err)
+	}
+
+	n, err := r.services.State.Purge(ctx)
+	if err != nil {
+		log.ErrorContext(ctx, "Failed to purge expired component state", "error", err)
+		return
+	}
+	if n > 0 {
+		log.InfoContext(ctx, "Purged expired component state", "count", n)
+	}
+}
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS component_states;
\ No newline at end of file
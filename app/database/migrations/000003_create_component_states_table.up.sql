@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS component_states (
+    id BIGSERIAL PRIMARY KEY,
+    payload TEXT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS component_states_expires_at_idx ON component_states (expires_at);
\ No newline at end of file
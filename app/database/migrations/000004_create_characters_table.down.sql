@@ -0,0 +1 @@
+DROP TABLE IF EXISTS characters;
\ No newline at end of file
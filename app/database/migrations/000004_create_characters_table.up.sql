@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS characters (
+    id BIGSERIAL PRIMARY KEY,
+    guild_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    name TEXT NOT NULL,
+    realm TEXT NOT NULL,
+    region TEXT NOT NULL,
+    main BOOLEAN NOT NULL DEFAULT FALSE,
+    FOREIGN KEY (guild_id) REFERENCES guilds(id),
+    UNIQUE (guild_id, user_id, name, realm)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS characters_main_idx ON characters (guild_id, user_id)
+WHERE main;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS wow_guilds;
\ No newline at end of file
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS wow_guilds (
+    id BIGSERIAL PRIMARY KEY,
+    guild_id BIGINT NOT NULL,
+    name TEXT NOT NULL,
+    realm TEXT NOT NULL,
+    region TEXT NOT NULL,
+    faction TEXT NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    FOREIGN KEY (guild_id) REFERENCES guilds(id) ON DELETE CASCADE,
+    UNIQUE (guild_id, name, realm, region)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS wow_guilds_default_idx ON wow_guilds (guild_id)
+WHERE is_default;
+
+INSERT INTO wow_guilds (guild_id, name, realm, region, faction, is_default)
+SELECT id,
+    name,
+    server_name,
+    LOWER(server_region),
+    LOWER(faction),
+    TRUE
+FROM guilds ON CONFLICT DO NOTHING;
+
+UPDATE guilds
+SET server_realm = server_name
+WHERE server_realm = '';
\ No newline at end of file
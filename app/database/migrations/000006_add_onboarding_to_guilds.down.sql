@@ -0,0 +1,2 @@
+ALTER TABLE guilds DROP COLUMN IF EXISTS announcement_channel_id,
+    DROP COLUMN IF EXISTS raider_role_id;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE guilds
+ADD COLUMN IF NOT EXISTS announcement_channel_id BIGINT,
+    ADD COLUMN IF NOT EXISTS raider_role_id BIGINT;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE guilds DROP COLUMN IF EXISTS left_at;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE guilds
+ADD COLUMN IF NOT EXISTS left_at TIMESTAMPTZ;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE guilds DROP COLUMN IF EXISTS locale;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE guilds
+ADD COLUMN IF NOT EXISTS locale TEXT;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE guilds DROP COLUMN IF EXISTS timezone,
+    DROP COLUMN IF EXISTS raid_start,
+    DROP COLUMN IF EXISTS raid_end;
\ No newline at end of file
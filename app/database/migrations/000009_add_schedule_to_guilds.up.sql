@@ -0,0 +1,4 @@
+ALTER TABLE guilds
+ADD COLUMN IF NOT EXISTS timezone TEXT,
+    ADD COLUMN IF NOT EXISTS raid_start SMALLINT,
+    ADD COLUMN IF NOT EXISTS raid_end SMALLINT;
\ No newline at end of file
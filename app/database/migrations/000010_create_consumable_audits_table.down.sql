@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS consumable_findings;
+
+DROP TABLE IF EXISTS consumable_audits;
\ No newline at end of file
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS consumable_audits (
+    id BIGSERIAL PRIMARY KEY,
+    guild_id BIGINT NOT NULL,
+    report_code TEXT NOT NULL,
+    logged_at TIMESTAMPTZ NOT NULL,
+    FOREIGN KEY (guild_id) REFERENCES guilds(id) ON DELETE CASCADE,
+    UNIQUE (guild_id, report_code)
+);
+
+CREATE INDEX IF NOT EXISTS consumable_audits_logged_at_idx ON consumable_audits (guild_id, logged_at);
+
+CREATE TABLE IF NOT EXISTS consumable_findings (
+    id BIGSERIAL PRIMARY KEY,
+    audit_id BIGINT NOT NULL,
+    player TEXT NOT NULL,
+    consumable TEXT NOT NULL,
+    missing_pulls INT NOT NULL,
+    total_pulls INT NOT NULL,
+    FOREIGN KEY (audit_id) REFERENCES consumable_audits(id) ON DELETE CASCADE,
+    UNIQUE (audit_id, player, consumable)
+);
\ No newline at end of file
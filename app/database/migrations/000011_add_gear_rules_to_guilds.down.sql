@@ -0,0 +1,2 @@
+ALTER TABLE guilds DROP COLUMN IF EXISTS gear_min_item_level,
+    DROP COLUMN IF EXISTS gear_enchant_slots;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE guilds
+ADD COLUMN IF NOT EXISTS gear_min_item_level SMALLINT,
+    ADD COLUMN IF NOT EXISTS gear_enchant_slots TEXT [];
\ No newline at end of file
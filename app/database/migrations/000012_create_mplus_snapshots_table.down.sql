@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS mplus_scores;
+
+DROP TABLE IF EXISTS mplus_snapshots;
\ No newline at end of file
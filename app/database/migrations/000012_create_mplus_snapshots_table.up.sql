@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS mplus_snapshots (
+    id BIGSERIAL PRIMARY KEY,
+    guild_id BIGINT NOT NULL,
+    season TEXT NOT NULL,
+    taken_at TIMESTAMPTZ NOT NULL,
+    FOREIGN KEY (guild_id) REFERENCES guilds(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS mplus_snapshots_taken_at_idx ON mplus_snapshots (guild_id, taken_at);
+
+CREATE TABLE IF NOT EXISTS mplus_scores (
+    id BIGSERIAL PRIMARY KEY,
+    snapshot_id BIGINT NOT NULL,
+    name TEXT NOT NULL,
+    realm TEXT NOT NULL,
+    class TEXT NOT NULL,
+    spec TEXT NOT NULL,
+    score_all DOUBLE PRECISION NOT NULL,
+    score_tank DOUBLE PRECISION NOT NULL,
+    score_healer DOUBLE PRECISION NOT NULL,
+    score_dps DOUBLE PRECISION NOT NULL,
+    FOREIGN KEY (snapshot_id) REFERENCES mplus_snapshots(id) ON DELETE CASCADE,
+    UNIQUE (snapshot_id, name, realm)
+);
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE guilds DROP COLUMN IF EXISTS raider_max_rank;
\ No newline at end of file
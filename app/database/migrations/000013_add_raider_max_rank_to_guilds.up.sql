@@ -0,0 +1,2 @@
+ALTER TABLE guilds
+ADD COLUMN IF NOT EXISTS raider_max_rank SMALLINT;
\ No newline at end of file
@@ -0,0 +1,29 @@
+-- name: GetMainCharacter :one
+SELECT id,
+    guild_id,
+    user_id,
+    name,
+    realm,
+    region,
+    main
+FROM characters
+WHERE guild_id = $1
+    AND user_id = $2
+    AND main;
+
+-- name: ClearMainCharacter :exec
+UPDATE characters
+SET main = FALSE
+WHERE guild_id = $1
+    AND user_id = $2;
+
+-- name: SetMainCharacter :exec
+INSERT INTO characters (guild_id, user_id, name, realm, region, main)
+VALUES ($1, $2, $3, $4, $5, TRUE) ON CONFLICT (guild_id, user_id, name, realm) DO
+UPDATE
+SET region = EXCLUDED.region,
+    main = TRUE;
+
+-- name: DeleteGuildCharacters :execrows
+DELETE FROM characters
+WHERE guild_id = $1;
\ No newline at end of file
@@ -0,0 +1,14 @@
+-- name: CreateComponentState :one
+INSERT INTO component_states (payload, expires_at)
+VALUES ($1, $2)
+RETURNING id;
+
+-- name: GetComponentState :one
+SELECT payload
+FROM component_states
+WHERE id = $1
+    AND expires_at > NOW();
+
+-- name: DeleteExpiredComponentStates :execrows
+DELETE FROM component_states
+WHERE expires_at <= NOW();
\ No newline at end of file
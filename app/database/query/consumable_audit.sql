@@ -0,0 +1,44 @@
+-- name: UpsertConsumableAudit :one
+INSERT INTO consumable_audits (guild_id, report_code, logged_at)
+VALUES ($1, $2, $3) ON CONFLICT (guild_id, report_code) DO
+UPDATE
+SET logged_at = EXCLUDED.logged_at
+RETURNING id;
+
+-- name: DeleteConsumableFindings :exec
+DELETE FROM consumable_findings
+WHERE audit_id = $1;
+
+-- name: AddConsumableFinding :exec
+INSERT INTO consumable_findings (
+        audit_id,
+        player,
+        consumable,
+        missing_pulls,
+        total_pulls
+    )
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: CountConsumableAudits :one
+SELECT COUNT(*)
+FROM consumable_audits
+WHERE guild_id = $1
+    AND logged_at >= $2;
+
+-- name: ListConsumableFindings :many
+SELECT a.report_code,
+    f.player,
+    f.consumable,
+    f.missing_pulls,
+    f.total_pulls
+FROM consumable_findings f
+    JOIN consumable_audits a ON a.id = f.audit_id
+WHERE a.guild_id = $1
+    AND a.logged_at >= $2
+ORDER BY a.logged_at,
+    f.player,
+    f.consumable;
+
+-- name: DeleteGuildConsumableAudits :execrows
+DELETE FROM consumable_audits
+WHERE guild_id = $1;
\ No newline at end of file
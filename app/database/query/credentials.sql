@@ -16,4 +16,14 @@ UPDATE
 SET url = EXCLUDED.url,
     username = EXCLUDED.username,
     password = EXCLUDED.password
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: ListCredentialNames :many
+SELECT name
+FROM credentials
+WHERE guild_id = $1
+ORDER BY name;
+
+-- name: DeleteGuildCredentials :execrows
+DELETE FROM credentials
+WHERE guild_id = $1;
\ No newline at end of file
@@ -15,7 +15,17 @@ SELECT id,
     server_name,
     server_region,
     server_realm,
-    faction
+    faction,
+    announcement_channel_id,
+    raider_role_id,
+    left_at,
+    locale,
+    timezone,
+    raid_start,
+    raid_end,
+    gear_min_item_level,
+    gear_enchant_slots,
+    raider_max_rank
 FROM guilds;
 
 -- name: GetGuild :one
@@ -24,7 +34,17 @@ SELECT id,
     server_name,
     server_region,
     server_realm,
-    faction
+    faction,
+    announcement_channel_id,
+    raider_role_id,
+    left_at,
+    locale,
+    timezone,
+    raid_start,
+    raid_end,
+    gear_min_item_level,
+    gear_enchant_slots,
+    raider_max_rank
 FROM guilds
 WHERE id = $1;
 
@@ -38,7 +58,7 @@ SET name = $1,
 WHERE id = $6
 RETURNING *;
 
--- name: DeleteGuild :exec
+-- name: DeleteGuild :execrows
 DELETE FROM guilds
 WHERE id = $1;
 
@@ -49,4 +69,65 @@ FROM guilds;
 -- name: FuzzyGuildSearch :many
 SELECT *
 FROM guilds
-WHERE similarity(name, $1) > 0.15;
\ No newline at end of file
+WHERE similarity(name, $1) > 0.15;
+
+-- name: SetGuildAnnouncementChannel :execrows
+UPDATE guilds
+SET announcement_channel_id = $1
+WHERE id = $2;
+
+-- name: SetGuildRaiderRole :execrows
+UPDATE guilds
+SET raider_role_id = $1
+WHERE id = $2;
+
+-- name: SetGuildLeftAt :execrows
+UPDATE guilds
+SET left_at = $1
+WHERE id = $2;
+
+-- name: ListLeftGuilds :many
+SELECT id
+FROM guilds
+WHERE left_at < $1;
+
+-- name: GetGuildLocale :one
+SELECT locale
+FROM guilds
+WHERE id = $1;
+
+-- name: SetGuildLocale :execrows
+UPDATE guilds
+SET locale = $1
+WHERE id = $2;
+
+-- name: SetGuildSchedule :execrows
+UPDATE guilds
+SET timezone = $1,
+    raid_start = $2,
+    raid_end = $3
+WHERE id = $4;
+
+-- name: SetGuildGearRules :execrows
+UPDATE guilds
+SET gear_min_item_level = $1,
+    gear_enchant_slots = $2
+WHERE id = $3;
+
+-- name: SetGuildRaiderMaxRank :execrows
+UPDATE guilds
+SET raider_max_rank = $1
+WHERE id = $2;
+
+-- name: ResetGuildSettings :execrows
+UPDATE guilds
+SET announcement_channel_id = NULL,
+    raider_role_id = NULL,
+    locale = NULL,
+    timezone = NULL,
+    raid_start = NULL,
+    raid_end = NULL,
+    gear_min_item_level = NULL,
+    gear_enchant_slots = NULL,
+    raider_max_rank = NULL
+WHERE id = $1;
\ No newline at end of file
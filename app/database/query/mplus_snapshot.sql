@@ -0,0 +1,51 @@
+-- name: CreateMplusSnapshot :one
+INSERT INTO mplus_snapshots (guild_id, season, taken_at)
+VALUES ($1, $2, $3)
+RETURNING id;
+
+-- name: AddMplusScore :exec
+INSERT INTO mplus_scores (
+        snapshot_id,
+        name,
+        realm,
+        class,
+        spec,
+        score_all,
+        score_tank,
+        score_healer,
+        score_dps
+    )
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+
+-- name: GetLatestMplusSnapshot :one
+SELECT *
+FROM mplus_snapshots
+WHERE guild_id = $1
+ORDER BY taken_at DESC
+LIMIT 1;
+
+-- name: ListMplusScores :many
+SELECT *
+FROM mplus_scores
+WHERE snapshot_id = $1
+ORDER BY name,
+    realm;
+
+-- name: ListMplusScoreHistory :many
+SELECT s.season,
+    s.taken_at,
+    c.name,
+    c.realm,
+    c.score_all,
+    c.score_tank,
+    c.score_healer,
+    c.score_dps
+FROM mplus_scores c
+    JOIN mplus_snapshots s ON s.id = c.snapshot_id
+WHERE s.guild_id = $1
+    AND LOWER(c.name) = LOWER(sqlc.arg(name))
+ORDER BY s.taken_at;
+
+-- name: DeleteGuildMplusSnapshots :execrows
+DELETE FROM mplus_snapshots
+WHERE guild_id = $1;
\ No newline at end of file
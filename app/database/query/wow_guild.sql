@@ -0,0 +1,64 @@
+-- name: AddWowGuild :exec
+INSERT INTO wow_guilds (guild_id, name, realm, region, faction, is_default)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: ListWowGuilds :many
+SELECT id,
+    guild_id,
+    name,
+    realm,
+    region,
+    faction,
+    is_default
+FROM wow_guilds
+WHERE guild_id = $1
+ORDER BY is_default DESC,
+    name;
+
+-- name: UpdateWowGuild :execrows
+UPDATE wow_guilds
+SET name = $1,
+    realm = $2,
+    region = $3,
+    faction = $4
+WHERE id = $5
+    AND guild_id = $6;
+
+-- name: DeleteWowGuild :execrows
+DELETE FROM wow_guilds
+WHERE id = $1
+    AND guild_id = $2
+    AND NOT is_default;
+
+-- name: ClearDefaultWowGuild :exec
+UPDATE wow_guilds
+SET is_default = FALSE
+WHERE guild_id = $1
+    AND is_default;
+
+-- name: SetDefaultWowGuild :execrows
+UPDATE wow_guilds
+SET is_default = TRUE
+WHERE id = $1
+    AND guild_id = $2;
+
+-- name: SyncDefaultWowGuild :exec
+UPDATE guilds
+SET name = w.name,
+    server_name = w.realm,
+    server_region = w.region,
+    server_realm = w.realm,
+    faction = w.faction
+FROM wow_guilds w
+WHERE guilds.id = $1
+    AND w.guild_id = guilds.id
+    AND w.is_default;
+
+-- name: DeleteGuildWowGuilds :execrows
+DELETE FROM wow_guilds
+WHERE guild_id = $1;
+
+-- name: DeleteOtherWowGuilds :execrows
+DELETE FROM wow_guilds
+WHERE guild_id = $1
+    AND NOT is_default;
\ No newline at end of file
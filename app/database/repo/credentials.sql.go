@@ -9,6 +9,19 @@ import (
 	"context"
 )
 
+const deleteGuildCredentials = `-- name: DeleteGuildCredentials :execrows
+DELETE FROM credentials
+WHERE guild_id = $1
+`
+
+func (q *Queries) DeleteGuildCredentials(ctx context.Context, guildID int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteGuildCredentials, guildID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getCredentials = `-- name: GetCredentials :one
 SELECT id,
     guild_id,
@@ -40,6 +53,36 @@ func (q *Queries) GetCredentials(ctx context.Context, arg GetCredentialsParams)
 	return i, err
 }
 
+const listCredentialNames = `-- name: ListCredentialNames :many
+SELECT name
+FROM credentials
+WHERE guild_id = $1
+ORDER BY name
+`
+
+func (q *Queries) ListCredentialNames(ctx context.Context, guildID int64) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listCredentialNames, guildID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		items = append(items, name)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const setCredentials = `-- name: SetCredentials :exec
 INSERT INTO credentials (guild_id, name, url, username, password)
 VALUES ($1, $2, $3, $4, $5) ON CONFLICT (guild_id, name) DO
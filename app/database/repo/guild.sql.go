@@ -7,6 +7,9 @@ package repo
 
 import (
 	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
 )
 
 const countGuilds = `-- name: CountGuilds :one
@@ -21,18 +24,21 @@ func (q *Queries) CountGuilds(ctx context.Context) (int64, error) {
 	return count, err
 }
 
-const deleteGuild = `-- name: DeleteGuild :exec
+const deleteGuild = `-- name: DeleteGuild :execrows
 DELETE FROM guilds
 WHERE id = $1
 `
 
-func (q *Queries) DeleteGuild(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteGuild, id)
-	return err
+func (q *Queries) DeleteGuild(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteGuild, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const fuzzyGuildSearch = `-- name: FuzzyGuildSearch :many
-SELECT id, name, server_name, server_region, server_realm
+SELECT id, name, server_name, server_region, server_realm, faction, announcement_channel_id, raider_role_id, left_at, locale, timezone, raid_start, raid_end, gear_min_item_level, gear_enchant_slots, raider_max_rank
 FROM guilds
 WHERE similarity(name, $1) > 0.15
 `
@@ -52,6 +58,17 @@ func (q *Queries) FuzzyGuildSearch(ctx context.Context, similarity interface{})
 			&i.ServerName,
 			&i.ServerRegion,
 			&i.ServerRealm,
+			&i.Faction,
+			&i.AnnouncementChannelID,
+			&i.RaiderRoleID,
+			&i.LeftAt,
+			&i.Locale,
+			&i.Timezone,
+			&i.RaidStart,
+			&i.RaidEnd,
+			&i.GearMinItemLevel,
+			pq.Array(&i.GearEnchantSlots),
+			&i.RaiderMaxRank,
 		); err != nil {
 			return nil, err
 		}
@@ -71,7 +88,18 @@ SELECT id,
     name,
     server_name,
     server_region,
-    server_realm
+    server_realm,
+    faction,
+    announcement_channel_id,
+    raider_role_id,
+    left_at,
+    locale,
+    timezone,
+    raid_start,
+    raid_end,
+    gear_min_item_level,
+    gear_enchant_slots,
+    raider_max_rank
 FROM guilds
 WHERE id = $1
 `
@@ -85,16 +113,51 @@ func (q *Queries) GetGuild(ctx context.Context, id int64) (Guild, error) {
 		&i.ServerName,
 		&i.ServerRegion,
 		&i.ServerRealm,
+		&i.Faction,
+		&i.AnnouncementChannelID,
+		&i.RaiderRoleID,
+		&i.LeftAt,
+		&i.Locale,
+		&i.Timezone,
+		&i.RaidStart,
+		&i.RaidEnd,
+		&i.GearMinItemLevel,
+		pq.Array(&i.GearEnchantSlots),
+		&i.RaiderMaxRank,
 	)
 	return i, err
 }
 
+const getGuildLocale = `-- name: GetGuildLocale :one
+SELECT locale
+FROM guilds
+WHERE id = $1
+`
+
+func (q *Queries) GetGuildLocale(ctx context.Context, id int64) (sql.NullString, error) {
+	row := q.db.QueryRowContext(ctx, getGuildLocale, id)
+	var locale sql.NullString
+	err := row.Scan(&locale)
+	return locale, err
+}
+
 const listGuilds = `-- name: ListGuilds :many
 SELECT id,
     name,
     server_name,
     server_region,
-    server_realm
+    server_realm,
+    faction,
+    announcement_channel_id,
+    raider_role_id,
+    left_at,
+    locale,
+    timezone,
+    raid_start,
+    raid_end,
+    gear_min_item_level,
+    gear_enchant_slots,
+    raider_max_rank
 FROM guilds
 `
 
@@ -113,6 +176,17 @@ func (q *Queries) ListGuilds(ctx context.Context) ([]Guild, error) {
 			&i.ServerName,
 			&i.ServerRegion,
 			&i.ServerRealm,
+			&i.Faction,
+			&i.AnnouncementChannelID,
+			&i.RaiderRoleID,
+			&i.LeftAt,
+			&i.Locale,
+			&i.Timezone,
+			&i.RaidStart,
+			&i.RaidEnd,
+			&i.GearMinItemLevel,
+			pq.Array(&i.GearEnchantSlots),
+			&i.RaiderMaxRank,
 		); err != nil {
 			return nil, err
 		}
@@ -127,15 +201,45 @@ func (q *Queries) ListGuilds(ctx context.Context) ([]Guild, error) {
 	return items, nil
 }
 
+const listLeftGuilds = `-- name: ListLeftGuilds :many
+SELECT id
+FROM guilds
+WHERE left_at < $1
+`
+
+func (q *Queries) ListLeftGuilds(ctx context.Context, leftAt sql.NullTime) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, listLeftGuilds, leftAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const newGuild = `-- name: NewGuild :exec
 INSERT INTO guilds (
         id,
         name,
         server_name,
         server_region,
-        server_realm
+        server_realm,
+        faction
     )
-VALUES ($1, $2, $3, $4, $5)
+VALUES ($1, $2, $3, $4, $5, $6)
 `
 
 type NewGuildParams struct {
@@ -144,6 +248,7 @@ type NewGuildParams struct {
 	ServerName   string
 	ServerRegion string
 	ServerRealm  string
+	Faction      string
 }
 
 func (q *Queries) NewGuild(ctx context.Context, arg NewGuildParams) error {
@@ -153,18 +258,186 @@ func (q *Queries) NewGuild(ctx context.Context, arg NewGuildParams) error {
 		arg.ServerName,
 		arg.ServerRegion,
 		arg.ServerRealm,
+		arg.Faction,
 	)
 	return err
 }
 
+const resetGuildSettings = `-- name: ResetGuildSettings :execrows
+UPDATE guilds
+SET announcement_channel_id = NULL,
+    raider_role_id = NULL,
+    locale = NULL,
+    timezone = NULL,
+    raid_start = NULL,
+    raid_end = NULL,
+    gear_min_item_level = NULL,
+    gear_enchant_slots = NULL,
+    raider_max_rank = NULL
+WHERE id = $1
+`
+
+func (q *Queries) ResetGuildSettings(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, resetGuildSettings, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setGuildAnnouncementChannel = `-- name: SetGuildAnnouncementChannel :execrows
+UPDATE guilds
+SET announcement_channel_id = $1
+WHERE id = $2
+`
+
+type SetGuildAnnouncementChannelParams struct {
+	AnnouncementChannelID sql.NullInt64
+	ID                    int64
+}
+
+func (q *Queries) SetGuildAnnouncementChannel(ctx context.Context, arg SetGuildAnnouncementChannelParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setGuildAnnouncementChannel, arg.AnnouncementChannelID, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setGuildGearRules = `-- name: SetGuildGearRules :execrows
+UPDATE guilds
+SET gear_min_item_level = $1,
+    gear_enchant_slots = $2
+WHERE id = $3
+`
+
+type SetGuildGearRulesParams struct {
+	GearMinItemLevel sql.NullInt16
+	GearEnchantSlots []string
+	ID               int64
+}
+
+func (q *Queries) SetGuildGearRules(ctx context.Context, arg SetGuildGearRulesParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setGuildGearRules, arg.GearMinItemLevel, pq.Array(arg.GearEnchantSlots), arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setGuildLeftAt = `-- name: SetGuildLeftAt :execrows
+UPDATE guilds
+SET left_at = $1
+WHERE id = $2
+`
+
+type SetGuildLeftAtParams struct {
+	LeftAt sql.NullTime
+	ID     int64
+}
+
+func (q *Queries) SetGuildLeftAt(ctx context.Context, arg SetGuildLeftAtParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setGuildLeftAt, arg.LeftAt, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setGuildLocale = `-- name: SetGuildLocale :execrows
+UPDATE guilds
+SET locale = $1
+WHERE id = $2
+`
+
+type SetGuildLocaleParams struct {
+	Locale sql.NullString
+	ID     int64
+}
+
+func (q *Queries) SetGuildLocale(ctx context.Context, arg SetGuildLocaleParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setGuildLocale, arg.Locale, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setGuildRaiderMaxRank = `-- name: SetGuildRaiderMaxRank :execrows
+UPDATE guilds
+SET raider_max_rank = $1
+WHERE id = $2
+`
+
+type SetGuildRaiderMaxRankParams struct {
+	RaiderMaxRank sql.NullInt16
+	ID            int64
+}
+
+func (q *Queries) SetGuildRaiderMaxRank(ctx context.Context, arg SetGuildRaiderMaxRankParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setGuildRaiderMaxRank, arg.RaiderMaxRank, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setGuildRaiderRole = `-- name: SetGuildRaiderRole :execrows
+UPDATE guilds
+SET raider_role_id = $1
+WHERE id = $2
+`
+
+type SetGuildRaiderRoleParams struct {
+	RaiderRoleID sql.NullInt64
+	ID           int64
+}
+
+func (q *Queries) SetGuildRaiderRole(ctx context.Context, arg SetGuildRaiderRoleParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setGuildRaiderRole, arg.RaiderRoleID, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setGuildSchedule = `-- name: SetGuildSchedule :execrows
+UPDATE guilds
+SET timezone = $1,
+    raid_start = $2,
+    raid_end = $3
+WHERE id = $4
+`
+
+type SetGuildScheduleParams struct {
+	Timezone  sql.NullString
+	RaidStart sql.NullInt16
+	RaidEnd   sql.NullInt16
+	ID        int64
+}
+
+func (q *Queries) SetGuildSchedule(ctx context.Context, arg SetGuildScheduleParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setGuildSchedule,
+		arg.Timezone,
+		arg.RaidStart,
+		arg.RaidEnd,
+		arg.ID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateGuild = `-- name: UpdateGuild :exec
 UPDATE guilds
 SET name = $1,
     server_name = $2,
     server_region = $3,
-    server_realm = $4
-WHERE id = $5
-RETURNING id, name, server_name, server_region, server_realm
+    server_realm = $4,
+    faction = $5
+WHERE id = $6
+RETURNING id, name, server_name, server_region, server_realm, faction, announcement_channel_id, raider_role_id, left_at, locale, timezone, raid_start, raid_end, gear_min_item_level, gear_enchant_slots, raider_max_rank
 `
 
 type UpdateGuildParams struct {
@@ -172,6 +445,7 @@ type UpdateGuildParams struct {
 	ServerName   string
 	ServerRegion string
 	ServerRealm  string
+	Faction      string
 	ID           int64
 }
 
@@ -181,6 +455,7 @@ func (q *Queries) UpdateGuild(ctx context.Context, arg UpdateGuildParams) error
 		arg.ServerName,
 		arg.ServerRegion,
 		arg.ServerRealm,
+		arg.Faction,
 		arg.ID,
 	)
 	return err
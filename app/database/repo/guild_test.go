@@ -0,0 +1,168 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lvlcn-t/raid-mate/app/database/repo"
+)
+
+// rowConnector is a [driver.Connector] whose queries return a single row with the values of the selected columns.
+// The columns are taken from the SQL of the query, so the generated code is checked against its query.
+type rowConnector struct {
+	// row maps the names of the columns to their values.
+	row map[string]driver.Value
+}
+
+func (c rowConnector) Connect(context.Context) (driver.Conn, error) { return rowConn(c), nil }
+
+func (c rowConnector) Driver() driver.Driver { return nil }
+
+// rowConn is the connection of a [rowConnector].
+type rowConn rowConnector
+
+func (c rowConn) Prepare(query string) (driver.Stmt, error) { return rowStmt{row: c.row, query: query}, nil }
+
+func (rowConn) Close() error { return nil }
+
+func (rowConn) Begin() (driver.Tx, error) { return nil, errors.New("transactions are not supported") }
+
+// rowStmt is a statement of a [rowConn].
+type rowStmt struct {
+	// row maps the names of the columns to their values.
+	row map[string]driver.Value
+	// query is the SQL of the statement.
+	query string
+}
+
+func (rowStmt) Close() error { return nil }
+
+func (rowStmt) NumInput() int { return -1 }
+
+func (rowStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("exec is not supported")
+}
+
+func (s rowStmt) Query([]driver.Value) (driver.Rows, error) {
+	start, end := strings.Index(s.query, "SELECT "), strings.Index(s.query, "\nFROM ")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("no column list in query %q", s.query)
+	}
+
+	r := &rows{}
+	for _, column := range strings.Split(s.query[start+len("SELECT "):end], ",") {
+		column = strings.TrimSpace(column)
+		value, ok := s.row[column]
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q", column)
+		}
+		r.columns = append(r.columns, column)
+		r.values = append(r.values, value)
+	}
+	return r, nil
+}
+
+// rows are the rows of a [rowStmt], a single row with the values of the columns.
+type rows struct {
+	// columns are the names of the selected columns.
+	columns []string
+	// values are the values of the columns.
+	values []driver.Value
+	// done is true once the row has been read.
+	done bool
+}
+
+func (r *rows) Columns() []string { return r.columns }
+
+func (*rows) Close() error { return nil }
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	copy(dest, r.values)
+	return nil
+}
+
+func TestQueries_guilds(t *testing.T) {
+	db := sql.OpenDB(rowConnector{row: map[string]driver.Value{
+		"id":                      int64(1),
+		"name":                    "Raid Mate",
+		"server_name":             "Draenor",
+		"server_region":           "eu",
+		"server_realm":            "draenor",
+		"faction":                 "horde",
+		"announcement_channel_id": int64(2),
+		"raider_role_id":          nil,
+		"left_at":                 nil,
+		"locale":                  "de",
+		"timezone":                "Europe/Berlin",
+		"raid_start":              int64(1200),
+		"raid_end":                int64(1380),
+		"gear_min_item_level":     int64(610),
+		"gear_enchant_slots":      []byte("{back,chest}"),
+		"raider_max_rank":         int64(3),
+	}})
+	defer db.Close()
+	q := repo.New(db)
+
+	want := repo.Guild{
+		ID:                    1,
+		Name:                  "Raid Mate",
+		ServerName:            "Draenor",
+		ServerRegion:          "eu",
+		ServerRealm:           "draenor",
+		Faction:               "horde",
+		AnnouncementChannelID: sql.NullInt64{Int64: 2, Valid: true},
+		Locale:                sql.NullString{String: "de", Valid: true},
+		Timezone:              sql.NullString{String: "Europe/Berlin", Valid: true},
+		RaidStart:             sql.NullInt16{Int16: 1200, Valid: true},
+		RaidEnd:               sql.NullInt16{Int16: 1380, Valid: true},
+		GearMinItemLevel:      sql.NullInt16{Int16: 610, Valid: true},
+		GearEnchantSlots:      []string{"back", "chest"},
+		RaiderMaxRank:         sql.NullInt16{Int16: 3, Valid: true},
+	}
+
+	tests := []struct {
+		name  string
+		query func(ctx context.Context) ([]repo.Guild, error)
+	}{
+		{
+			name:  "ListGuilds",
+			query: q.ListGuilds,
+		},
+		{
+			name: "GetGuild",
+			query: func(ctx context.Context) ([]repo.Guild, error) {
+				g, err := q.GetGuild(ctx, 1)
+				return []repo.Guild{g}, err
+			},
+		},
+		{
+			name: "FuzzyGuildSearch",
+			query: func(ctx context.Context) ([]repo.Guild, error) {
+				return q.FuzzyGuildSearch(ctx, "Raid")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.query(context.Background())
+			if err != nil {
+				t.Fatalf("%s() error = %v", tt.name, err)
+			}
+			if !reflect.DeepEqual(got, []repo.Guild{want}) {
+				t.Errorf("%s() = %+v, want %+v", tt.name, got, []repo.Guild{want})
+			}
+		})
+	}
+}
@@ -4,7 +4,42 @@
 
 package repo
 
-import ()
+import (
+	"database/sql"
+	"time"
+)
+
+type Character struct {
+	ID      int64
+	GuildID int64
+	UserID  int64
+	Name    string
+	Realm   string
+	Region  string
+	Main    bool
+}
+
+type ComponentState struct {
+	ID        int64
+	Payload   string
+	ExpiresAt time.Time
+}
+
+type ConsumableAudit struct {
+	ID         int64
+	GuildID    int64
+	ReportCode string
+	LoggedAt   time.Time
+}
+
+type ConsumableFinding struct {
+	ID           int64
+	AuditID      int64
+	Player       string
+	Consumable   string
+	MissingPulls int32
+	TotalPulls   int32
+}
 
 type Credential struct {
 	ID       int32
@@ -16,9 +51,50 @@ type Credential struct {
 }
 
 type Guild struct {
-	ID           int64
-	Name         string
-	ServerName   string
-	ServerRegion string
-	ServerRealm  string
+	ID                    int64
+	Name                  string
+	ServerName            string
+	ServerRegion          string
+	ServerRealm           string
+	Faction               string
+	AnnouncementChannelID sql.NullInt64
+	RaiderRoleID          sql.NullInt64
+	LeftAt                sql.NullTime
+	Locale                sql.NullString
+	Timezone              sql.NullString
+	RaidStart             sql.NullInt16
+	RaidEnd               sql.NullInt16
+	GearMinItemLevel      sql.NullInt16
+	GearEnchantSlots      []string
+	RaiderMaxRank         sql.NullInt16
+}
+
+type MplusScore struct {
+	ID          int64
+	SnapshotID  int64
+	Name        string
+	Realm       string
+	Class       string
+	Spec        string
+	ScoreAll    float64
+	ScoreTank   float64
+	ScoreHealer float64
+	ScoreDps    float64
+}
+
+type MplusSnapshot struct {
+	ID      int64
+	GuildID int64
+	Season  string
+	TakenAt time.Time
+}
+
+type WowGuild struct {
+	ID        int64
+	GuildID   int64
+	Name      string
+	Realm     string
+	Region    string
+	Faction   string
+	IsDefault bool
 }
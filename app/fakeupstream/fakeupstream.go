@@ -0,0 +1,254 @@
+// Package fakeupstream provides a fake server for all upstream APIs used by the bot.
+//
+// It serves recorded Warcraft Logs, Raider.IO, Battle.net and GitHub responses from fixture files,
+// so the bot can be run end to end locally and in CI without network access.
+// Point the base URLs of the services to the address of the server to use it.
+//
+// Fixtures are looked up by the slugs of the requested resource, e.g. the Raider.IO
+// character "Aerith" on "Draenor" in "EU" is served from raiderio/characters/eu/draenor/aerith.json.
+// Missing fixtures are answered with a 404, like the real APIs do for unknown resources.
+package fakeupstream
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lvlcn-t/loggerhead/logger"
+)
+
+// readHeaderTimeout is the maximum duration for reading the request headers.
+const readHeaderTimeout = 5 * time.Second
+
+//go:embed fixtures
+var fixtures embed.FS
+
+// Fixtures returns the fixtures bundled with the binary.
+func Fixtures() fs.FS {
+	sub, err := fs.Sub(fixtures, "fixtures")
+	if err != nil {
+		panic(fmt.Sprintf("bundled fixtures are missing: %v", err))
+	}
+	return sub
+}
+
+// Issue is an issue created on the fake GitHub API.
+type Issue struct {
+	// Owner is the owner of the repository.
+	Owner string `json:"-"`
+	// Repo is the name of the repository.
+	Repo string `json:"-"`
+	// Number is the number of the issue.
+	Number int `json:"number"`
+	// Title is the title of the issue.
+	Title string `json:"title"`
+	// Body is the body of the issue.
+	Body string `json:"body"`
+	// Labels are the labels of the issue.
+	Labels []string `json:"labels"`
+}
+
+// Server is a fake server for all upstream APIs.
+type Server struct {
+	// fixtures is the file system the fixtures are served from.
+	fixtures fs.FS
+	// mux is the router of the server.
+	mux *http.ServeMux
+	// mu guards the issues.
+	mu sync.Mutex
+	// issues are the issues created on the fake GitHub API.
+	issues []Issue
+}
+
+// New creates a new fake upstream server serving the given fixtures.
+// If fixtures is nil, the fixtures bundled with the binary are used.
+func New(fixtures fs.FS) *Server {
+	if fixtures == nil {
+		fixtures = Fixtures()
+	}
+
+	s := &Server{
+		fixtures: fixtures,
+		mux:      http.NewServeMux(),
+	}
+	s.routes()
+	return s
+}
+
+// routes registers the routes of all fake APIs.
+func (s *Server) routes() {
+	// Warcraft Logs
+	s.mux.HandleFunc("GET /v1/reports/guild/{name}/{server}/{region}", func(w http.ResponseWriter, r *http.Request) {
+		s.serveFixture(w, "warcraftlogs", "reports", r.PathValue("region"), r.PathValue("server"), r.PathValue("name"))
+	})
+	s.mux.HandleFunc("GET /v1/report/fights/{code}", func(w http.ResponseWriter, r *http.Request) {
+		s.serveFixture(w, "warcraftlogs", "fights", r.PathValue("code"))
+	})
+	s.mux.HandleFunc("GET /v1/report/events/{view}/{code}", func(w http.ResponseWriter, r *http.Request) {
+		s.serveFixture(w, "warcraftlogs", "events", r.PathValue("view"), r.PathValue("code"))
+	})
+	s.mux.HandleFunc("GET /v1/rankings/character/{name}/{server}/{region}", func(w http.ResponseWriter, r *http.Request) {
+		s.serveFixture(w, "warcraftlogs", "rankings", r.PathValue("region"), r.PathValue("server"), r.PathValue("name"))
+	})
+
+	// Raider.IO
+	s.mux.HandleFunc("GET /api/v1/guilds/profile", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		s.serveFixture(w, "raiderio", "guilds", q.Get("region"), q.Get("realm"), q.Get("name"))
+	})
+	s.mux.HandleFunc("GET /api/v1/characters/profile", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		s.serveFixture(w, "raiderio", "characters", q.Get("region"), q.Get("realm"), q.Get("name"))
+	})
+
+	// Battle.net
+	s.mux.HandleFunc("POST /token", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{"access_token": "fake", "token_type": "bearer", "expires_in": 86399})
+	})
+	s.mux.HandleFunc("GET /profile/wow/character/{realm}/{name}/equipment", func(w http.ResponseWriter, r *http.Request) {
+		s.serveFixture(w, "battlenet", "equipment", namespaceRegion(r), r.PathValue("realm"), r.PathValue("name"))
+	})
+	s.mux.HandleFunc("GET /profile/wow/character/{realm}/{name}/character-media", func(w http.ResponseWriter, r *http.Request) {
+		s.serveFixture(w, "battlenet", "media", namespaceRegion(r), r.PathValue("realm"), r.PathValue("name"))
+	})
+	s.mux.HandleFunc("GET /data/wow/realm/index", func(w http.ResponseWriter, r *http.Request) {
+		s.serveFixture(w, "battlenet", "realms", namespaceRegion(r))
+	})
+
+	// GitHub
+	s.mux.HandleFunc("GET /repos/{owner}/{repo}", func(w http.ResponseWriter, r *http.Request) {
+		s.serveFixture(w, "github", "repos", r.PathValue("owner"), r.PathValue("repo"))
+	})
+	s.mux.HandleFunc("POST /repos/{owner}/{repo}/issues", s.createIssue)
+}
+
+// ServeHTTP serves the fake APIs.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Issues returns the issues created on the fake GitHub API.
+func (s *Server) Issues() []Issue {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Issue(nil), s.issues...)
+}
+
+// Run starts the server on the given address and blocks until the context is done.
+func (s *Server) Run(ctx context.Context, address string) error {
+	log := logger.FromContext(ctx)
+	srv := &http.Server{
+		Addr:              address,
+		Handler:           s,
+		ReadHeaderTimeout: readHeaderTimeout,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.InfoContext(ctx, "Fake upstream server listening", "address", address)
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		c, cancel := context.WithTimeout(context.WithoutCancel(ctx), readHeaderTimeout)
+		defer cancel()
+		err := srv.Shutdown(c)
+		if lErr := <-errCh; !errors.Is(lErr, http.ErrServerClosed) {
+			err = errors.Join(err, lErr)
+		}
+		return err
+	}
+}
+
+// serveFixture writes the fixture at the path built from the slugs of the given segments.
+func (s *Server) serveFixture(w http.ResponseWriter, segments ...string) {
+	for i, seg := range segments {
+		segments[i] = slug(seg)
+	}
+
+	b, err := fs.ReadFile(s.fixtures, path.Join(segments...)+".json")
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+			return
+		}
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"message": err.Error()})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(b)
+}
+
+// createIssue records an issue created on the fake GitHub API.
+func (s *Server) createIssue(w http.ResponseWriter, r *http.Request) {
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+		return
+	}
+
+	var issue Issue
+	if err = json.Unmarshal(b, &issue); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": "Problems parsing JSON"})
+		return
+	}
+	issue.Owner = r.PathValue("owner")
+	issue.Repo = r.PathValue("repo")
+
+	s.mu.Lock()
+	issue.Number = len(s.issues) + 1
+	s.issues = append(s.issues, issue)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusCreated, map[string]any{
+		"id":       issue.Number,
+		"number":   issue.Number,
+		"title":    issue.Title,
+		"body":     issue.Body,
+		"html_url": fmt.Sprintf("https://github.com/%s/%s/issues/%d", issue.Owner, issue.Repo, issue.Number),
+	})
+}
+
+// writeJSON writes v as JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// namespaceRegion returns the region of the Battle.net namespace of the request, e.g. "eu" for "profile-eu".
+func namespaceRegion(r *http.Request) string {
+	ns := r.URL.Query().Get("namespace")
+	return ns[strings.LastIndex(ns, "-")+1:]
+}
+
+// slug returns the lower case, dash separated form of the given name.
+// Characters other than letters, digits and dashes are dropped.
+func slug(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		switch {
+		case r == ' ' || r == '_':
+			b.WriteRune('-')
+		case r == '-' || (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r > 127:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
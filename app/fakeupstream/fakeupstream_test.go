@@ -0,0 +1,187 @@
+package fakeupstream
+
+import (
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+)
+
+// recordingFS is a [fs.FS] that records the names of the opened files.
+type recordingFS struct {
+	fs.FS
+	// mu guards opened.
+	mu sync.Mutex
+	// opened are the names of the opened files.
+	opened map[string]bool
+}
+
+func (r *recordingFS) Open(name string) (fs.File, error) {
+	r.mu.Lock()
+	r.opened[name] = true
+	r.mu.Unlock()
+	return r.FS.Open(name)
+}
+
+func TestServer_fixtures(t *testing.T) {
+	fixtures := &recordingFS{FS: Fixtures(), opened: map[string]bool{}}
+	s := New(fixtures)
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		wantStatus int
+	}{
+		{name: "warcraft logs reports", target: "/v1/reports/guild/Raid%20Mate/Draenor/EU", wantStatus: http.StatusOK},
+		{name: "warcraft logs fights", target: "/v1/report/fights/a1b2c3d4e5f6g7h8", wantStatus: http.StatusOK},
+		{name: "warcraft logs fights of another report", target: "/v1/report/fights/z9y8x7w6v5u4t3s2", wantStatus: http.StatusOK},
+		{name: "warcraft logs fights of a report of another guild", target: "/v1/report/fights/q1w2e3r4t5y6u7i8", wantStatus: http.StatusOK},
+		{name: "warcraft logs deaths", target: "/v1/report/events/deaths/a1b2c3d4e5f6g7h8", wantStatus: http.StatusOK},
+		{name: "warcraft logs summary", target: "/v1/report/events/summary/a1b2c3d4e5f6g7h8", wantStatus: http.StatusOK},
+		{name: "warcraft logs rankings", target: "/v1/rankings/character/Aerith/Draenor/EU", wantStatus: http.StatusOK},
+		{name: "warcraft logs rankings of another character", target: "/v1/rankings/character/Bjorn/Draenor/EU", wantStatus: http.StatusOK},
+		{name: "raider.io guild", target: "/api/v1/guilds/profile?region=eu&realm=Draenor&name=Raid+Mate", wantStatus: http.StatusOK},
+		{name: "raider.io character", target: "/api/v1/characters/profile?region=eu&realm=Draenor&name=Aerith", wantStatus: http.StatusOK},
+		{name: "raider.io another character", target: "/api/v1/characters/profile?region=eu&realm=Draenor&name=Bjorn", wantStatus: http.StatusOK},
+		{name: "battle.net equipment", target: "/profile/wow/character/draenor/aerith/equipment?namespace=profile-eu", wantStatus: http.StatusOK},
+		{name: "battle.net equipment of another character", target: "/profile/wow/character/draenor/bjorn/equipment?namespace=profile-eu", wantStatus: http.StatusOK},
+		{name: "battle.net media", target: "/profile/wow/character/draenor/aerith/character-media?namespace=profile-eu", wantStatus: http.StatusOK},
+		{name: "battle.net realms", target: "/data/wow/realm/index?namespace=dynamic-eu", wantStatus: http.StatusOK},
+		{name: "battle.net token", method: http.MethodPost, target: "/token", wantStatus: http.StatusOK},
+		{name: "github repository", target: "/repos/lvlcn-t/raid-mate", wantStatus: http.StatusOK},
+		{name: "unknown character", target: "/api/v1/characters/profile?region=eu&realm=Draenor&name=Tifa", wantStatus: http.StatusNotFound},
+		{name: "unknown report", target: "/v1/report/fights/unknown", wantStatus: http.StatusNotFound},
+		{name: "unknown route", target: "/v1/unknown", wantStatus: http.StatusNotFound},
+		{name: "wrong method", method: http.MethodPost, target: "/v1/report/fights/a1b2c3d4e5f6g7h8", wantStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, httptest.NewRequest(method, tt.target, http.NoBody))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("ServeHTTP() status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if rec.Code == http.StatusOK && !json.Valid(rec.Body.Bytes()) {
+				t.Errorf("ServeHTTP() body is not valid JSON: %s", rec.Body)
+			}
+		})
+	}
+
+	// Every bundled fixture must be reachable by a route, so renamed or misplaced fixtures fail here.
+	err := fs.WalkDir(Fixtures(), ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if !fixtures.opened[name] {
+			t.Errorf("fixture %q is not served by any of the requests above", name)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WalkDir() error = %v", err)
+	}
+}
+
+func TestServer_serveFixture(t *testing.T) {
+	s := New(fstest.MapFS{
+		"raiderio/characters/eu/draenor/aerith.json":       {Data: []byte(`{"name":"Aerith"}`)},
+		"raiderio/characters/eu/draenor/broken.json/child": {Data: []byte(`{}`)},
+	})
+
+	tests := []struct {
+		name       string
+		target     string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "fixture",
+			target:     "/api/v1/characters/profile?region=EU&realm=Draenor&name=Aerith",
+			wantStatus: http.StatusOK,
+			wantBody:   `{"name":"Aerith"}`,
+		},
+		{
+			name:       "missing fixture",
+			target:     "/api/v1/characters/profile?region=eu&realm=Draenor&name=Tifa",
+			wantStatus: http.StatusNotFound,
+			wantBody:   `{"message":"Not Found"}`,
+		},
+		{
+			name:       "unreadable fixture",
+			target:     "/api/v1/characters/profile?region=eu&realm=Draenor&name=Broken",
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, http.NoBody))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("ServeHTTP() status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := strings.TrimSpace(rec.Body.String()); tt.wantBody != "" && got != tt.wantBody {
+				t.Errorf("ServeHTTP() body = %s, want %s", got, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestServer_createIssue(t *testing.T) {
+	s := New(fstest.MapFS{})
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/repos/lvlcn-t/raid-mate/issues",
+		strings.NewReader(`{"title":"Bug","body":"It broke","labels":["bug"]}`)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("ServeHTTP() status = %d, want %d", rec.Code, http.StatusCreated)
+	}
+
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/repos/lvlcn-t/raid-mate/issues", strings.NewReader(`{`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("ServeHTTP() status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+
+	want := []Issue{{Owner: "lvlcn-t", Repo: "raid-mate", Number: 1, Title: "Bug", Body: "It broke", Labels: []string{"bug"}}}
+	if got := s.Issues(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Issues() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "Aerith", want: "aerith"},
+		{name: " Raid Mate ", want: "raid-mate"},
+		{name: "Aman'Thul", want: "amanthul"},
+		{name: "lvlcn_t", want: "lvlcn-t"},
+		{name: "Die-Aldor", want: "die-aldor"},
+		{name: "Blackrock 2!", want: "blackrock-2"},
+		{name: "Thrall", want: "thrall"},
+		{name: "Kaël", want: "kaël"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := slug(tt.name); got != tt.want {
+				t.Errorf("slug(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -80,6 +80,8 @@ func (r *RaidMate) Run(ctx context.Context) error {
 		}
 	}()
 
+	go r.runCleanup(ctx)
+
 	select {
 	case <-ctx.Done():
 		return r.Shutdown(ctx)
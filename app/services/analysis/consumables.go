@@ -0,0 +1,112 @@
+package analysis
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// Consumable is a consumable raiders are expected to use on every boss pull.
+type Consumable string
+
+const (
+	// Flask is a flask or a phial.
+	Flask Consumable = "flask"
+	// Food is a food buff.
+	Food Consumable = "food"
+	// AugmentRune is an augment rune.
+	AugmentRune Consumable = "augment_rune"
+	// WeaponOil is a temporary weapon enchant like an oil or a whetstone.
+	WeaponOil Consumable = "weapon_oil"
+)
+
+// Consumables are all audited consumables in the order they are shown.
+var Consumables = []Consumable{Flask, Food, AugmentRune, WeaponOil}
+
+// Combatant is the state of a player at the start of a pull.
+type Combatant struct {
+	// Pull is the ID of the pull.
+	Pull int
+	// Player is the name of the player.
+	Player string
+	// Auras are the names of the auras the player had.
+	Auras []string
+	// WeaponEnchanted is whether the main hand of the player had a temporary enchant.
+	WeaponEnchanted bool
+}
+
+// consumed returns the consumables the combatant used.
+func (c *Combatant) consumed() map[Consumable]bool {
+	used := map[Consumable]bool{WeaponOil: c.WeaponEnchanted}
+	for _, aura := range c.Auras {
+		switch {
+		// The names of flasks and phials change every expansion, but they always name themselves.
+		case strings.Contains(aura, "Flask") || strings.Contains(aura, "Phial"):
+			used[Flask] = true
+		case strings.Contains(aura, "Well Fed"):
+			used[Food] = true
+		case strings.Contains(aura, "Augment"):
+			used[AugmentRune] = true
+		}
+	}
+	return used
+}
+
+// ConsumableAudit are the consumables a player missed on the boss pulls of a report.
+type ConsumableAudit struct {
+	// Player is the name of the player.
+	Player string `json:"player"`
+	// Pulls is the number of boss pulls the player took part in.
+	Pulls int `json:"pulls"`
+	// Missing maps the consumables to the number of pulls the player missed them on.
+	// Consumables the player used on every pull are not included.
+	Missing map[Consumable]int `json:"missing"`
+}
+
+// AuditConsumables checks the consumables of every player on each of the pulls.
+// The audits are sorted by the number of missed consumables, the most first.
+// Combatants outside of the given pulls are ignored.
+func AuditConsumables(pulls []Pull, combatants []Combatant) []ConsumableAudit {
+	ids := map[int]struct{}{}
+	for _, p := range pulls {
+		ids[p.ID] = struct{}{}
+	}
+
+	// index maps the players to the position of their audit.
+	index := map[string]int{}
+	var audits []ConsumableAudit
+	for _, c := range combatants {
+		if _, ok := ids[c.Pull]; !ok {
+			continue
+		}
+		i, ok := index[c.Player]
+		if !ok {
+			i = len(audits)
+			index[c.Player] = i
+			audits = append(audits, ConsumableAudit{Player: c.Player, Missing: map[Consumable]int{}})
+		}
+
+		a := &audits[i]
+		a.Pulls++
+		used := c.consumed()
+		for _, consumable := range Consumables {
+			if !used[consumable] {
+				a.Missing[consumable]++
+			}
+		}
+	}
+
+	slices.SortStableFunc(audits, func(a, b ConsumableAudit) int {
+		return cmp.Or(cmp.Compare(b.missed(), a.missed()), strings.Compare(a.Player, b.Player))
+	})
+	return audits
+}
+
+// missed returns the number of consumables missed on all pulls combined.
+func (a *ConsumableAudit) missed() int {
+	var n int
+	for _, pulls := range a.Missing {
+		n += pulls
+	}
+	return n
+}
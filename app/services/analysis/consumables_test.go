@@ -0,0 +1,29 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAuditConsumables(t *testing.T) {
+	pulls := []Pull{{ID: 2, Boss: "Ulgrax the Devourer"}, {ID: 3, Boss: "Ulgrax the Devourer", Kill: true}}
+	prepared := []string{"Flask of Alchemical Chaos", "Well Fed", "Crystallized Augment Rune", "Power Word: Fortitude"}
+	combatants := []Combatant{
+		{Pull: 2, Player: "Aerith", Auras: prepared, WeaponEnchanted: true},
+		{Pull: 3, Player: "Aerith", Auras: prepared, WeaponEnchanted: true},
+		{Pull: 2, Player: "Bjorn", Auras: []string{"Phial of Tepid Versatility", "Well Fed"}},
+		{Pull: 3, Player: "Bjorn", Auras: []string{"Well Fed", "Battle Shout"}, WeaponEnchanted: true},
+		{Pull: 3, Player: "Tifa", Auras: []string{"Flask of Tempered Swiftness", "Hearty Well Fed"}, WeaponEnchanted: true},
+		// Trash pulls are not audited.
+		{Pull: 1, Player: "Tifa"},
+	}
+
+	want := []ConsumableAudit{
+		{Player: "Bjorn", Pulls: 2, Missing: map[Consumable]int{Flask: 1, AugmentRune: 2, WeaponOil: 1}},
+		{Player: "Tifa", Pulls: 1, Missing: map[Consumable]int{AugmentRune: 1}},
+		{Player: "Aerith", Pulls: 2, Missing: map[Consumable]int{}},
+	}
+	if got := AuditConsumables(pulls, combatants); !reflect.DeepEqual(got, want) {
+		t.Errorf("AuditConsumables() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,159 @@
+// Package analysis aggregates the events of Warcraft Logs reports into summaries for raid post-mortems.
+//
+// It is independent of the APIs the events are fetched from, so the services
+// only have to convert the events into the types of this package.
+package analysis
+
+import (
+	"cmp"
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+// Pull is a single pull of a boss.
+type Pull struct {
+	// ID is the ID of the pull in the report.
+	ID int
+	// Boss is the name of the boss.
+	Boss string
+	// Difficulty is the name of the difficulty, e.g. "Heroic".
+	Difficulty string
+	// Kill is whether the boss has been killed.
+	Kill bool
+	// Start is the time the pull started since the start of the report.
+	Start time.Duration
+	// End is the time the pull ended since the start of the report.
+	End time.Duration
+}
+
+// Death is the death of a player.
+type Death struct {
+	// Pull is the ID of the pull the player died in.
+	Pull int
+	// Time is the time the player died since the start of the report.
+	Time time.Duration
+	// Player is the name of the player.
+	Player string
+	// Ability is the name of the ability that killed the player.
+	// It is empty if the killing blow is unknown.
+	Ability string
+}
+
+// Wipes are the deaths of a report summarized per pull.
+type Wipes struct {
+	// Pulls are the pulls in the order they were pulled.
+	Pulls []PullDeaths `json:"pulls"`
+	// Causes are the abilities that killed players in pulls that ended in a wipe,
+	// the ones that caused the most first deaths first.
+	Causes []Cause `json:"causes"`
+}
+
+// PullDeaths are the deaths of a single pull.
+type PullDeaths struct {
+	// Boss is the name of the boss.
+	Boss string `json:"boss"`
+	// Difficulty is the name of the difficulty, e.g. "Heroic".
+	Difficulty string `json:"difficulty,omitempty"`
+	// Number is the number of the pull of the boss on its difficulty, starting at 1.
+	Number int `json:"number"`
+	// Kill is whether the boss has been killed.
+	Kill bool `json:"kill"`
+	// Duration is the duration of the pull.
+	Duration time.Duration `json:"-"`
+	// Deaths is the number of players who died in the pull.
+	Deaths int `json:"deaths"`
+	// First is the player who died first. It is nil if nobody died.
+	First *FirstDeath `json:"first_death,omitempty"`
+}
+
+// MarshalJSON encodes the pull with its duration in seconds.
+func (p PullDeaths) MarshalJSON() ([]byte, error) {
+	type pull PullDeaths
+	return json.Marshal(struct {
+		pull
+		Duration float64 `json:"duration"`
+	}{pull(p), p.Duration.Seconds()})
+}
+
+// FirstDeath is the first death of a pull.
+type FirstDeath struct {
+	// Player is the name of the player.
+	Player string `json:"player"`
+	// Ability is the name of the ability that killed the player.
+	// It is empty if the killing blow is unknown.
+	Ability string `json:"ability,omitempty"`
+	// After is the time the player died since the start of the pull.
+	After time.Duration `json:"-"`
+}
+
+// MarshalJSON encodes the death with the time since the start of the pull in seconds.
+func (d FirstDeath) MarshalJSON() ([]byte, error) {
+	type death FirstDeath
+	return json.Marshal(struct {
+		death
+		After float64 `json:"after"`
+	}{death(d), d.After.Seconds()})
+}
+
+// Cause is an ability that killed players in pulls that ended in a wipe.
+type Cause struct {
+	// Ability is the name of the ability.
+	// It is empty for all deaths with an unknown killing blow.
+	Ability string `json:"ability"`
+	// FirstDeaths is the number of wipes the ability caused the first death in.
+	FirstDeaths int `json:"first_deaths"`
+	// Deaths is the number of players the ability killed.
+	Deaths int `json:"deaths"`
+}
+
+// Analyze summarizes the deaths per pull and returns the most common causes of the wipes.
+// Deaths outside of the given pulls are ignored.
+func Analyze(pulls []Pull, deaths []Death) Wipes {
+	byPull := map[int][]Death{}
+	for _, d := range deaths {
+		byPull[d.Pull] = append(byPull[d.Pull], d)
+	}
+
+	// numbers counts the pulls per boss and difficulty.
+	type key struct{ boss, difficulty string }
+	numbers := map[key]int{}
+	// causes maps the abilities to the position of their cause.
+	causes := map[string]int{}
+
+	var res Wipes
+	for _, p := range pulls {
+		k := key{p.Boss, p.Difficulty}
+		numbers[k]++
+
+		pd := PullDeaths{Boss: p.Boss, Difficulty: p.Difficulty, Number: numbers[k], Kill: p.Kill, Duration: p.End - p.Start}
+		died := byPull[p.ID]
+		slices.SortStableFunc(died, func(a, b Death) int { return cmp.Compare(a.Time, b.Time) })
+		pd.Deaths = len(died)
+		if len(died) > 0 {
+			pd.First = &FirstDeath{Player: died[0].Player, Ability: died[0].Ability, After: died[0].Time - p.Start}
+		}
+		res.Pulls = append(res.Pulls, pd)
+
+		if p.Kill {
+			continue
+		}
+		for i, d := range died {
+			j, ok := causes[d.Ability]
+			if !ok {
+				j = len(res.Causes)
+				causes[d.Ability] = j
+				res.Causes = append(res.Causes, Cause{Ability: d.Ability})
+			}
+			res.Causes[j].Deaths++
+			if i == 0 {
+				res.Causes[j].FirstDeaths++
+			}
+		}
+	}
+
+	slices.SortStableFunc(res.Causes, func(a, b Cause) int {
+		return cmp.Or(cmp.Compare(b.FirstDeaths, a.FirstDeaths), cmp.Compare(b.Deaths, a.Deaths), cmp.Compare(a.Ability, b.Ability))
+	})
+	return res
+}
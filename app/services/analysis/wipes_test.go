@@ -0,0 +1,72 @@
+package analysis
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAnalyze(t *testing.T) {
+	pulls := []Pull{
+		{ID: 2, Boss: "Ulgrax the Devourer", Difficulty: "Heroic", Start: 1 * time.Minute, End: 6 * time.Minute},
+		{ID: 3, Boss: "Ulgrax the Devourer", Difficulty: "Heroic", Kill: true, Start: 8 * time.Minute, End: 13 * time.Minute},
+		{ID: 4, Boss: "The Bloodbound Horror", Difficulty: "Heroic", Start: 15 * time.Minute, End: 18 * time.Minute},
+		{ID: 5, Boss: "The Bloodbound Horror", Difficulty: "Heroic", Start: 20 * time.Minute, End: 22 * time.Minute},
+	}
+	deaths := []Death{
+		{Pull: 2, Time: 5 * time.Minute, Player: "Bjorn", Ability: "Digestive Acid"},
+		{Pull: 2, Time: 3 * time.Minute, Player: "Aerith", Ability: "Venomous Lash"},
+		{Pull: 3, Time: 12 * time.Minute, Player: "Tifa", Ability: "Brutal Crush"},
+		{Pull: 4, Time: 16 * time.Minute, Player: "Tifa", Ability: "Gruesome Disgorge"},
+		{Pull: 4, Time: 17 * time.Minute, Player: "Aerith", Ability: "Venomous Lash"},
+		{Pull: 5, Time: 21 * time.Minute, Player: "Bjorn", Ability: ""},
+		// Deaths on trash are not part of any pull.
+		{Pull: 1, Time: 30 * time.Second, Player: "Aerith", Ability: "Web Bolt"},
+	}
+
+	want := Wipes{
+		Pulls: []PullDeaths{
+			{Boss: "Ulgrax the Devourer", Difficulty: "Heroic", Number: 1, Duration: 5 * time.Minute, Deaths: 2,
+				First: &FirstDeath{Player: "Aerith", Ability: "Venomous Lash", After: 2 * time.Minute}},
+			{Boss: "Ulgrax the Devourer", Difficulty: "Heroic", Number: 2, Kill: true, Duration: 5 * time.Minute, Deaths: 1,
+				First: &FirstDeath{Player: "Tifa", Ability: "Brutal Crush", After: 4 * time.Minute}},
+			{Boss: "The Bloodbound Horror", Difficulty: "Heroic", Number: 1, Duration: 3 * time.Minute, Deaths: 2,
+				First: &FirstDeath{Player: "Tifa", Ability: "Gruesome Disgorge", After: time.Minute}},
+			{Boss: "The Bloodbound Horror", Difficulty: "Heroic", Number: 2, Duration: 2 * time.Minute, Deaths: 1,
+				First: &FirstDeath{Player: "Bjorn", After: time.Minute}},
+		},
+		// Deaths of kills are no wipe causes.
+		Causes: []Cause{
+			{Ability: "Venomous Lash", FirstDeaths: 1, Deaths: 2},
+			{Ability: "", FirstDeaths: 1, Deaths: 1},
+			{Ability: "Gruesome Disgorge", FirstDeaths: 1, Deaths: 1},
+			{Ability: "Digestive Acid", Deaths: 1},
+		},
+	}
+	if got := Analyze(pulls, deaths); !reflect.DeepEqual(got, want) {
+		t.Errorf("Analyze() = %+v, want %+v", got, want)
+	}
+}
+
+func TestAnalyze_noDeaths(t *testing.T) {
+	got := Analyze([]Pull{{ID: 1, Boss: "Ulgrax the Devourer", Kill: true, End: time.Minute}}, nil)
+	want := Wipes{Pulls: []PullDeaths{{Boss: "Ulgrax the Devourer", Number: 1, Kill: true, Duration: time.Minute}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Analyze() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPullDeaths_MarshalJSON(t *testing.T) {
+	p := PullDeaths{Boss: "Ulgrax the Devourer", Number: 1, Duration: 90 * time.Second, Deaths: 1,
+		First: &FirstDeath{Player: "Aerith", After: 1500 * time.Millisecond}}
+	b, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	want := `{"boss":"Ulgrax the Devourer","number":1,"kill":false,"deaths":1,"first_death":{"player":"Aerith","after":1.5},"duration":90}`
+	if string(b) != want {
+		t.Errorf("Marshal() = %s, want %s", b, want)
+	}
+}
@@ -0,0 +1,65 @@
+package character
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lvlcn-t/raid-mate/app/database/repo"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+)
+
+// Service is the interface for the character service.
+// It keeps track of the World of Warcraft characters the members of a guild registered.
+// All methods return errors of the [svcerr] package for failures the user can act on.
+type Service interface {
+	// GetMain returns the main character the user registered in the guild.
+	// It returns an [svcerr.ErrNotFound] error if the user has not registered a main character.
+	GetMain(ctx context.Context, guildID, userID snowflake.ID) (repo.Character, error)
+	// SetMain registers the character as the main character of the user in the guild.
+	// The previous main character of the user is kept as an alt.
+	SetMain(ctx context.Context, smp repo.SetMainCharacterParams) error
+}
+
+// character implements [Service] for the character service.
+type character struct {
+	// database is the database connection.
+	database *sql.DB
+}
+
+// NewService creates a new character service.
+func NewService(db *sql.DB) Service {
+	return &character{database: db}
+}
+
+func (s *character) GetMain(ctx context.Context, guildID, userID snowflake.ID) (repo.Character, error) {
+	main, err := repo.New(s.database).GetMainCharacter(ctx, repo.GetMainCharacterParams{
+		GuildID: int64(guildID), //nolint:gosec // Snowflake cannot overflow AFAIK
+		UserID:  int64(userID),  //nolint:gosec // Snowflake cannot overflow AFAIK
+	})
+	if err != nil {
+		return repo.Character{}, svcerr.FromDB(err, svcerr.ErrNotFound, "")
+	}
+	return main, nil
+}
+
+func (s *character) SetMain(ctx context.Context, smp repo.SetMainCharacterParams) error {
+	tx, err := s.database.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	q := repo.New(s.database).WithTx(tx)
+	err = q.ClearMainCharacter(ctx, repo.ClearMainCharacterParams{GuildID: smp.GuildID, UserID: smp.UserID})
+	if err != nil {
+		return fmt.Errorf("error clearing main character: %w", err)
+	}
+
+	err = q.SetMainCharacter(ctx, smp)
+	if err != nil {
+		return fmt.Errorf("error setting main character: %w", err)
+	}
+	return tx.Commit()
+}
@@ -3,14 +3,19 @@ package services
 import (
 	"database/sql"
 
+	"github.com/lvlcn-t/raid-mate/app/services/character"
 	"github.com/lvlcn-t/raid-mate/app/services/feedback"
 	"github.com/lvlcn-t/raid-mate/app/services/guild"
+	"github.com/lvlcn-t/raid-mate/app/services/state"
+	"github.com/lvlcn-t/raid-mate/app/services/upstream"
 )
 
 // Collection is the collection of services.
 type Collection struct {
-	Feedback feedback.Service
-	Guild    guild.Service
+	Character character.Service
+	Feedback  feedback.Service
+	Guild     guild.Service
+	State     state.Service
 }
 
 // Config is the configuration for the services.
@@ -19,12 +24,17 @@ type Config struct {
 	Feedback feedback.Config `yaml:"feedback" mapstructure:"feedback" validate:"required"`
 	// Guild is the configuration for the guild service.
 	Guild guild.Config `yaml:"guild" mapstructure:"guild" validate:"required"`
+	// Upstream is the configuration for the client shared by all services to talk to external APIs.
+	Upstream upstream.Config `yaml:"upstream" mapstructure:"upstream"`
 }
 
 // NewCollection creates a new collection of services.
 func NewCollection(c *Config, db *sql.DB) *Collection {
+	up := upstream.New(&c.Upstream)
 	return &Collection{
-		Feedback: feedback.NewService(&c.Feedback),
-		Guild:    guild.NewService(&c.Guild, db),
+		Character: character.NewService(db),
+		Feedback:  feedback.NewService(&c.Feedback, up),
+		Guild:     guild.NewService(&c.Guild, db, up),
+		State:     state.NewService(db),
 	}
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/disgoorg/disgo/rest"
 	"github.com/disgoorg/snowflake/v2"
 	"github.com/lvlcn-t/loggerhead/logger"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
 )
 
 // dmConfig is the configuration for the DM service.
@@ -55,7 +56,7 @@ func (s *dm) Submit(ctx context.Context, req Request, client bot.Client) error {
 	dm, err := client.Rest().CreateDMChannel(s.id, rest.WithCtx(ctx))
 	if err != nil {
 		log.ErrorContext(ctx, "Error while creating DM channel", "error", err)
-		return err
+		return svcerr.Wrap(svcerr.ErrUnavailable, err, "")
 	}
 
 	am := discord.DefaultAllowedMentions
@@ -67,7 +68,7 @@ func (s *dm) Submit(ctx context.Context, req Request, client bot.Client) error {
 		Build())
 	if err != nil {
 		log.ErrorContext(ctx, "Error while sending message", "error", err)
-		return err
+		return svcerr.Wrap(svcerr.ErrUnavailable, err, "")
 	}
 
 	log.InfoContext(ctx, "Sent direct message", "message-id", m.ID, "user", s.id)
@@ -9,6 +9,8 @@ import (
 	"github.com/disgoorg/disgo/bot"
 	"github.com/disgoorg/snowflake/v2"
 	"github.com/lvlcn-t/go-kit/config"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+	"github.com/lvlcn-t/raid-mate/app/services/upstream"
 )
 
 // Service is the interface for the service.
@@ -75,11 +77,12 @@ func (c *Config) Validate() error {
 }
 
 // NewService creates a new feedback service.
-func NewService(c *Config) Service {
+// The upstream client is used for all requests to external APIs.
+func NewService(c *Config, up *upstream.Client) Service {
 	return &feedback{
 		selected: c.Service,
 		registry: map[string]Service{
-			"github": newGitHub(&c.GitHub),
+			"github": newGitHub(&c.GitHub, up),
 			"dm":     newDM(&c.DM),
 		},
 	}
@@ -87,6 +90,10 @@ func NewService(c *Config) Service {
 
 // Submit submits the feedback.
 func (s *feedback) Submit(ctx context.Context, req Request, client bot.Client) error {
+	if err := req.Validate(); err != nil {
+		return svcerr.WrapInvalid(err, svcerr.Msg("errors.invalid.feedback_empty"))
+	}
+
 	if len(s.selected) == 0 {
 		return nil
 	}
@@ -94,7 +101,7 @@ func (s *feedback) Submit(ctx context.Context, req Request, client bot.Client) e
 	if slices.Contains(s.selected, "all") {
 		for _, svc := range s.registry {
 			if err := svc.Submit(ctx, req, client); err != nil {
-				return err
+				return svcerr.FromUpstream(err, "")
 			}
 		}
 		return nil
@@ -104,7 +111,7 @@ func (s *feedback) Submit(ctx context.Context, req Request, client bot.Client) e
 	for _, svc := range s.selected {
 		if fsvc, ok := s.registry[svc]; ok {
 			if err := fsvc.Submit(ctx, req, client); err != nil {
-				return err
+				return svcerr.FromUpstream(err, "")
 			}
 			continue
 		}
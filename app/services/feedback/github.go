@@ -5,10 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/disgoorg/disgo/bot"
 	gh "github.com/google/go-github/v68/github"
 	"github.com/lvlcn-t/loggerhead/logger"
+	"github.com/lvlcn-t/raid-mate/app/services/upstream"
 )
 
 // githubConfig is the configuration for the GitHub service.
@@ -19,6 +23,29 @@ type githubConfig struct {
 	Repo string `yaml:"repo" mapstructure:"repo" validate:"required"`
 	// Token is the GitHub token to authenticate.
 	Token string `yaml:"token" mapstructure:"token" validate:"required"`
+	// URL is the base URL of the GitHub API.
+	// Defaults to https://api.github.com.
+	URL string `yaml:"url" mapstructure:"url"`
+}
+
+// Validate validates the configuration.
+func (c githubConfig) Validate() error {
+	var errs []error
+	if c.Owner == "" {
+		errs = append(errs, errors.New("services.feedback.github.owner is required"))
+	}
+	if c.Repo == "" {
+		errs = append(errs, errors.New("services.feedback.github.repo is required"))
+	}
+	if c.Token == "" {
+		errs = append(errs, errors.New("services.feedback.github.token is required"))
+	}
+	if c.URL != "" {
+		if _, err := url.ParseRequestURI(c.URL); err != nil {
+			errs = append(errs, fmt.Errorf("services.feedback.github.url is invalid: %w", err))
+		}
+	}
+	return errors.Join(errs...)
 }
 
 type github struct {
@@ -26,10 +53,10 @@ type github struct {
 	client githubAPI
 }
 
-func newGitHub(c *githubConfig) *github {
+func newGitHub(c *githubConfig, up *upstream.Client) *github {
 	return &github{
 		config: c,
-		client: newGitHubClient(c.Token),
+		client: newGitHubClient(c.Token, c.URL, up.HTTPClient()),
 	}
 }
 
@@ -66,13 +93,13 @@ type respIssue struct {
 func (s *github) createIssue(ctx context.Context, req *reqIssue) (*respIssue, error) {
 	repo, err := s.client.GetRepository(ctx, s.config.Owner, s.config.Repo)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch repo: %v", err)
+		return nil, fmt.Errorf("failed to fetch repo: %w", err)
 	}
 
 	reqIssue := &gh.IssueRequest{Title: &req.Title, Body: &req.Body, Labels: &req.Labels}
 	issue, err := s.client.CreateIssue(ctx, repo, reqIssue)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create issue: %v", err)
+		return nil, fmt.Errorf("failed to create issue: %w", err)
 	}
 
 	return &respIssue{
@@ -89,19 +116,21 @@ type githubAPI interface {
 
 type ghClient struct{ *gh.Client }
 
-func newGitHubClient(token string) *ghClient {
-	return &ghClient{
-		Client: gh.NewClient(nil).WithAuthToken(token),
+func newGitHubClient(token, baseURL string, client *http.Client) *ghClient {
+	c := gh.NewClient(client).WithAuthToken(token)
+	if baseURL != "" {
+		// The URL is validated when the configuration is loaded.
+		if u, err := url.Parse(strings.TrimSuffix(baseURL, "/") + "/"); err == nil {
+			c.BaseURL = u
+		}
 	}
+	return &ghClient{Client: c}
 }
 
 func (g *ghClient) GetRepository(ctx context.Context, owner, repo string) (*gh.Repository, error) {
 	r, resp, err := g.Client.Repositories.Get(ctx, owner, repo)
 	if err != nil {
-		if resp.StatusCode >= http.StatusBadRequest {
-			return nil, fmt.Errorf("HTTP error %d: %w", resp.StatusCode, err)
-		}
-		return nil, err
+		return nil, githubError(ctx, resp, err)
 	}
 	return r, nil
 }
@@ -113,10 +142,44 @@ func (g *ghClient) CreateIssue(ctx context.Context, repo *gh.Repository, issue *
 
 	i, resp, err := g.Client.Issues.Create(ctx, repo.Owner.GetLogin(), repo.GetName(), issue)
 	if err != nil {
-		if resp.StatusCode >= http.StatusBadRequest {
-			return nil, fmt.Errorf("HTTP error %d: %v", resp.StatusCode, err)
-		}
-		return nil, err
+		return nil, githubError(ctx, resp, err)
 	}
 	return i, nil
 }
+
+// githubError converts an error of the GitHub SDK into a typed [upstream] error.
+func githubError(ctx context.Context, resp *gh.Response, err error) error {
+	if ctx.Err() != nil {
+		return err
+	}
+
+	var rateErr *gh.RateLimitError
+	if errors.As(err, &rateErr) {
+		return errors.Join(&upstream.StatusError{
+			Host:       rateErr.Response.Request.URL.Host,
+			StatusCode: http.StatusTooManyRequests,
+			RetryAfter: time.Until(rateErr.Rate.Reset.Time),
+		}, err)
+	}
+
+	var abuseErr *gh.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		return errors.Join(&upstream.StatusError{
+			Host:       abuseErr.Response.Request.URL.Host,
+			StatusCode: http.StatusTooManyRequests,
+			RetryAfter: abuseErr.GetRetryAfter(),
+		}, err)
+	}
+
+	if resp != nil && resp.StatusCode >= http.StatusBadRequest {
+		return errors.Join(&upstream.StatusError{
+			Host:       resp.Request.URL.Host,
+			StatusCode: resp.StatusCode,
+		}, err)
+	}
+
+	if errors.Is(err, upstream.ErrUpstreamDown) || errors.Is(err, upstream.ErrRateLimited) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", upstream.ErrUpstreamDown, err)
+}
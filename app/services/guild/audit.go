@@ -0,0 +1,213 @@
+package guild
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lvlcn-t/raid-mate/app/database/repo"
+	"github.com/lvlcn-t/raid-mate/app/services/analysis"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+)
+
+type auditService interface {
+	// AuditConsumables checks the flask or phial, the food buff, the augment rune and the weapon oil of every player
+	// on each boss pull of the report with the given code or URL.
+	// The findings are stored, so repeat offenders can be listed. Auditing a report again replaces its findings.
+	AuditConsumables(ctx context.Context, guildID snowflake.ID, code string) (*ConsumableAudit, error)
+	// GetConsumableOffenders returns the players who missed consumables in the reports audited since the given time.
+	GetConsumableOffenders(ctx context.Context, guildID snowflake.ID, since time.Time) (*ConsumableOffenders, error)
+	// AuditGear checks the equipment of every raider in the roster of the guild for missing enchants, empty sockets,
+	// items below the minimum item level and the number of tier set pieces, see [GearRules] and [Service.SetRaiderRank].
+	// If a character is given, only its equipment is audited, even if it is not a raider.
+	// It returns an [svcerr.ErrUnavailable] error if the Battle.net API is not configured.
+	AuditGear(ctx context.Context, guildID snowflake.ID, character string) (*GearAudit, error)
+}
+
+// ConsumableAudit are the consumables the players missed on the boss pulls of a report.
+type ConsumableAudit struct {
+	// Code is the code of the report.
+	Code string `json:"code"`
+	// Title is the title of the report.
+	Title string `json:"title"`
+	// URL is the URL of the report.
+	URL string `json:"url"`
+	// Start is the time the report started.
+	Start time.Time `json:"start"`
+	// Pulls is the number of audited boss pulls.
+	Pulls int `json:"pulls"`
+	// Players are the audits of all players, the players who missed the most consumables first.
+	Players []analysis.ConsumableAudit `json:"players"`
+}
+
+// ConsumableOffenders are the players who missed consumables in the audited reports of a period.
+type ConsumableOffenders struct {
+	// Since is the start of the period.
+	Since time.Time `json:"since"`
+	// Audits is the number of reports audited in the period.
+	Audits int `json:"audits"`
+	// Players are the players who missed consumables, the players who missed them in the most reports first.
+	Players []ConsumableOffender `json:"players"`
+}
+
+// ConsumableOffender is a player who missed consumables in audited reports.
+type ConsumableOffender struct {
+	// Player is the name of the player.
+	Player string `json:"player"`
+	// Reports is the number of audited reports the player missed consumables in.
+	Reports int `json:"reports"`
+	// Missing maps the consumables to the number of pulls the player missed them on.
+	Missing map[analysis.Consumable]int `json:"missing"`
+}
+
+func (s *guild) AuditConsumables(ctx context.Context, guildID snowflake.ID, code string) (*ConsumableAudit, error) {
+	code, err := reportCode(code)
+	if err != nil {
+		return nil, err
+	}
+	guild, err := s.Get(ctx, guildID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting guild: %w", err)
+	}
+
+	audit, err := s.consumableAudit(ctx, guild, code)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.database.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	q := repo.New(s.database).WithTx(tx)
+	id, err := q.UpsertConsumableAudit(ctx, repo.UpsertConsumableAuditParams{GuildID: guild.ID, ReportCode: code, LoggedAt: audit.Start})
+	if err != nil {
+		return nil, fmt.Errorf("error storing audit: %w", err)
+	}
+	if err = q.DeleteConsumableFindings(ctx, id); err != nil {
+		return nil, fmt.Errorf("error deleting previous findings: %w", err)
+	}
+	for _, p := range audit.Players {
+		for consumable, missing := range p.Missing {
+			err = q.AddConsumableFinding(ctx, repo.AddConsumableFindingParams{
+				AuditID:      id,
+				Player:       p.Player,
+				Consumable:   string(consumable),
+				MissingPulls: int32(missing), //nolint:gosec // A report has far less than 2^31 pulls
+				TotalPulls:   int32(p.Pulls), //nolint:gosec // A report has far less than 2^31 pulls
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error storing finding: %w", err)
+			}
+		}
+	}
+	return audit, tx.Commit()
+}
+
+// consumableAudit checks the consumables of every player on each boss pull of the report with the given code uploaded by the guild.
+func (s *guild) consumableAudit(ctx context.Context, guild repo.Guild, code string) (*ConsumableAudit, error) {
+	f, err := s.guildFights(ctx, guild, code)
+	if err != nil {
+		return nil, err
+	}
+
+	audit := &ConsumableAudit{
+		Code:  code,
+		Title: f.Title,
+		URL:   s.client.ReportURL(code),
+		Start: time.UnixMilli(int64(f.Start)).UTC(),
+	}
+	var pulls []analysis.Pull
+	for _, p := range f.Fights {
+		// Trash pulls are logged as fights without a boss.
+		if p.Boss != 0 {
+			pulls = append(pulls, analysis.Pull{ID: p.ID, Boss: p.Name, Start: time.Duration(p.StartTime) * time.Millisecond})
+		}
+	}
+	audit.Pulls = len(pulls)
+	if len(pulls) == 0 {
+		return audit, nil
+	}
+
+	// The state of the players is logged at the start of each pull.
+	start, end := pulls[0].Start.Milliseconds(), pulls[len(pulls)-1].Start.Milliseconds()+1
+	events, err := s.client.fetchCombatants(ctx, code, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching combatants of report %q: %w", code, svcerr.FromUpstream(err, code))
+	}
+
+	players := map[int]string{}
+	for _, p := range f.Friendlies {
+		if p.isPlayer() {
+			players[p.ID] = p.Name
+		}
+	}
+	combatants := make([]analysis.Combatant, 0, len(events))
+	for _, e := range events {
+		name, ok := players[e.SourceID]
+		if !ok {
+			continue
+		}
+		c := analysis.Combatant{Pull: e.Fight, Player: name, WeaponEnchanted: e.weaponEnchanted()}
+		for _, a := range e.Auras {
+			c.Auras = append(c.Auras, a.Name)
+		}
+		combatants = append(combatants, c)
+	}
+
+	audit.Players = analysis.AuditConsumables(pulls, combatants)
+	return audit, nil
+}
+
+func (s *guild) GetConsumableOffenders(ctx context.Context, guildID snowflake.ID, since time.Time) (*ConsumableOffenders, error) {
+	guild, err := s.Get(ctx, guildID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting guild: %w", err)
+	}
+
+	q := repo.New(s.database)
+	audits, err := q.CountConsumableAudits(ctx, repo.CountConsumableAuditsParams{GuildID: guild.ID, LoggedAt: since})
+	if err != nil {
+		return nil, fmt.Errorf("error counting audits: %w", err)
+	}
+	findings, err := q.ListConsumableFindings(ctx, repo.ListConsumableFindingsParams{GuildID: guild.ID, LoggedAt: since})
+	if err != nil {
+		return nil, fmt.Errorf("error listing findings: %w", err)
+	}
+	return &ConsumableOffenders{Since: since, Audits: int(audits), Players: consumableOffenders(findings)}, nil
+}
+
+// consumableOffenders aggregates the findings per player,
+// sorted by the number of reports the players missed consumables in, the most first.
+func consumableOffenders(findings []repo.ListConsumableFindingsRow) []ConsumableOffender {
+	// index maps the players to the position of their aggregate.
+	index := map[string]int{}
+	// reports are the reports each player missed consumables in.
+	reports := map[string]map[string]struct{}{}
+	var offenders []ConsumableOffender
+	for _, f := range findings {
+		i, ok := index[f.Player]
+		if !ok {
+			i = len(offenders)
+			index[f.Player] = i
+			reports[f.Player] = map[string]struct{}{}
+			offenders = append(offenders, ConsumableOffender{Player: f.Player, Missing: map[analysis.Consumable]int{}})
+		}
+		reports[f.Player][f.ReportCode] = struct{}{}
+		offenders[i].Missing[analysis.Consumable(f.Consumable)] += int(f.MissingPulls)
+	}
+
+	for i := range offenders {
+		offenders[i].Reports = len(reports[offenders[i].Player])
+	}
+	slices.SortStableFunc(offenders, func(a, b ConsumableOffender) int {
+		return cmp.Or(cmp.Compare(b.Reports, a.Reports), strings.Compare(a.Player, b.Player))
+	})
+	return offenders
+}
@@ -0,0 +1,54 @@
+package guild
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lvlcn-t/raid-mate/app/database/repo"
+	"github.com/lvlcn-t/raid-mate/app/services/analysis"
+)
+
+func TestGuild_consumableAudit(t *testing.T) {
+	s := newTestGuild(t)
+
+	got, err := s.consumableAudit(context.Background(), raidMate, "a1B2c3D4e5F6g7H8")
+	if err != nil {
+		t.Fatalf("consumableAudit() error = %v", err)
+	}
+
+	want := &ConsumableAudit{
+		Code:  "a1B2c3D4e5F6g7H8",
+		Title: "Nerub-ar Palace Heroic",
+		URL:   s.client.ReportURL("a1B2c3D4e5F6g7H8"),
+		Start: time.Date(2024, 5, 6, 19, 0, 0, 0, time.UTC),
+		Pulls: 4,
+		Players: []analysis.ConsumableAudit{
+			{Player: "Bjorn", Pulls: 4, Missing: map[analysis.Consumable]int{analysis.Flask: 2, analysis.AugmentRune: 4, analysis.WeaponOil: 2}},
+			{Player: "Aerith", Pulls: 4, Missing: map[analysis.Consumable]int{}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("consumableAudit() = %+v, want %+v", got, want)
+	}
+}
+
+func TestConsumableOffenders(t *testing.T) {
+	findings := []repo.ListConsumableFindingsRow{
+		{ReportCode: "a1B2", Player: "Bjorn", Consumable: "augment_rune", MissingPulls: 4, TotalPulls: 4},
+		{ReportCode: "a1B2", Player: "Bjorn", Consumable: "flask", MissingPulls: 2, TotalPulls: 4},
+		{ReportCode: "a1B2", Player: "Tifa", Consumable: "food", MissingPulls: 1, TotalPulls: 4},
+		{ReportCode: "Z9y8", Player: "Aerith", Consumable: "weapon_oil", MissingPulls: 1, TotalPulls: 2},
+		{ReportCode: "Z9y8", Player: "Bjorn", Consumable: "augment_rune", MissingPulls: 2, TotalPulls: 2},
+	}
+
+	want := []ConsumableOffender{
+		{Player: "Bjorn", Reports: 2, Missing: map[analysis.Consumable]int{analysis.AugmentRune: 6, analysis.Flask: 2}},
+		{Player: "Aerith", Reports: 1, Missing: map[analysis.Consumable]int{analysis.WeaponOil: 1}},
+		{Player: "Tifa", Reports: 1, Missing: map[analysis.Consumable]int{analysis.Food: 1}},
+	}
+	if got := consumableOffenders(findings); !reflect.DeepEqual(got, want) {
+		t.Errorf("consumableOffenders() = %+v, want %+v", got, want)
+	}
+}
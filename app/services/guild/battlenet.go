@@ -0,0 +1,366 @@
+package guild
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lvlcn-t/raid-mate/app/services/upstream"
+)
+
+const (
+	// defaultBattleNetOAuthURL is the OAuth host of all regions except China.
+	defaultBattleNetOAuthURL = "https://oauth.battle.net"
+	// chinaBattleNetOAuthURL is the OAuth host of the Chinese region.
+	chinaBattleNetOAuthURL = "https://oauth.battlenet.com.cn"
+	// chinaBattleNetAPIURL is the API host of the Chinese region.
+	chinaBattleNetAPIURL = "https://gateway.battlenet.com.cn"
+	// defaultBattleNetLocale is the locale of the API responses if none is configured.
+	defaultBattleNetLocale = "en_US"
+	// tokenExpiryMargin is the time before its expiry an access token is renewed.
+	tokenExpiryMargin = time.Minute
+)
+
+// errBattleNetDisabled is returned when the Battle.net API is requested without being configured.
+var errBattleNetDisabled = errors.New("the Battle.net API is not configured")
+
+// BattleNetConfig is the configuration for the Battle.net API.
+// The API is disabled if no client ID is configured.
+type BattleNetConfig struct {
+	// ClientID is the ID of the API client.
+	ClientID string `yaml:"clientId" mapstructure:"clientId"`
+	// ClientSecret is the secret of the API client.
+	ClientSecret string `yaml:"clientSecret" mapstructure:"clientSecret"`
+	// Locale is the default locale of the API responses.
+	// Defaults to en_US.
+	Locale string `yaml:"locale" mapstructure:"locale"`
+	// APIURL is the base URL of the API for all regions.
+	// Defaults to the host of the region, e.g. https://eu.api.blizzard.com.
+	APIURL string `yaml:"apiUrl" mapstructure:"apiUrl"`
+	// OAuthURL is the base URL of the OAuth server for all regions.
+	// Defaults to https://oauth.battle.net.
+	OAuthURL string `yaml:"oauthUrl" mapstructure:"oauthUrl"`
+}
+
+// battleNet is the state of the Battle.net API client.
+type battleNet struct {
+	clientID     string
+	clientSecret string
+	locale       string
+	apiURL       string
+	oauthURL     string
+	// mu guards the tokens.
+	mu sync.Mutex
+	// tokens are the cached access tokens by OAuth host.
+	tokens map[string]accessToken
+}
+
+// accessToken is an access token of the client credentials flow.
+type accessToken struct {
+	value   string
+	expires time.Time
+}
+
+// newBattleNet creates the Battle.net API client state from the configuration.
+func newBattleNet(c *BattleNetConfig) *battleNet {
+	locale := c.Locale
+	if locale == "" {
+		locale = defaultBattleNetLocale
+	}
+	return &battleNet{
+		clientID:     c.ClientID,
+		clientSecret: c.ClientSecret,
+		locale:       locale,
+		apiURL:       strings.TrimSuffix(c.APIURL, "/"),
+		oauthURL:     strings.TrimSuffix(c.OAuthURL, "/"),
+		tokens:       map[string]accessToken{},
+	}
+}
+
+// enabled returns true if the API client is configured.
+func (b *battleNet) enabled() bool {
+	return b.clientID != ""
+}
+
+// hosts returns the base URLs of the API and the OAuth server of the region.
+func (b *battleNet) hosts(region string) (api, oauth string) {
+	region = strings.ToLower(region)
+	api, oauth = fmt.Sprintf("https://%s.api.blizzard.com", region), defaultBattleNetOAuthURL
+	if region == "cn" {
+		api, oauth = chinaBattleNetAPIURL, chinaBattleNetOAuthURL
+	}
+	if b.apiURL != "" {
+		api = b.apiURL
+	}
+	if b.oauthURL != "" {
+		oauth = b.oauthURL
+	}
+	return api, oauth
+}
+
+// battleNetToken returns a cached access token of the OAuth server or requests a new one.
+func (c *client) battleNetToken(ctx context.Context, oauthURL string) (string, error) {
+	b := c.battleNet
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if t, ok := b.tokens[oauthURL]; ok && time.Now().Before(t.expires) {
+		return t.value, nil
+	}
+
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	form := url.Values{"grant_type": {"client_credentials"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, oauthURL+"/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("error creating token request: %w", err)
+	}
+	req.SetBasicAuth(b.clientID, b.clientSecret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var resp struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err = c.client.DoJSON(req, &resp); err != nil {
+		return "", fmt.Errorf("error requesting token: %w", err)
+	}
+	b.tokens[oauthURL] = accessToken{
+		value:   resp.AccessToken,
+		expires: time.Now().Add(time.Duration(resp.ExpiresIn)*time.Second - tokenExpiryMargin),
+	}
+	return resp.AccessToken, nil
+}
+
+// invalidateToken drops the cached access token of the OAuth server.
+func (c *client) invalidateToken(oauthURL string) {
+	c.battleNet.mu.Lock()
+	defer c.battleNet.mu.Unlock()
+	delete(c.battleNet.tokens, oauthURL)
+}
+
+// getBattleNet sends a GET request to the path of the API of the region and decodes the JSON response into v.
+// The namespace is suffixed with the region. If the locale is empty, the configured locale is used.
+// A rejected access token is renewed once.
+func (c *client) getBattleNet(ctx context.Context, region, path, namespace, locale string, v any) error {
+	if !c.battleNet.enabled() {
+		return errBattleNetDisabled
+	}
+	if locale == "" {
+		locale = c.battleNet.locale
+	}
+	region = strings.ToLower(region)
+	api, oauth := c.battleNet.hosts(region)
+
+	query := url.Values{}
+	query.Add("namespace", fmt.Sprintf("%s-%s", namespace, region))
+	query.Add("locale", locale)
+
+	for retried := false; ; retried = true {
+		token, err := c.battleNetToken(ctx, oauth)
+		if err != nil {
+			return err
+		}
+		err = c.getWithToken(ctx, token, api+path, query, v)
+		var sErr *upstream.StatusError
+		if retried || !errors.As(err, &sErr) || sErr.StatusCode != http.StatusUnauthorized {
+			return err
+		}
+		c.invalidateToken(oauth)
+	}
+}
+
+// realmSlug returns the slug the Battle.net API uses for the realm, e.g. "argent-dawn" for "Argent Dawn".
+func realmSlug(realm string) string {
+	realm = strings.ToLower(strings.TrimSpace(realm))
+	realm = strings.ReplaceAll(realm, "'", "")
+	return strings.ReplaceAll(realm, " ", "-")
+}
+
+// characterPath returns the path of the profile resource of the character.
+func characterPath(realm, name, resource string) string {
+	return fmt.Sprintf("/profile/wow/character/%s/%s/%s",
+		url.PathEscape(realmSlug(realm)), url.PathEscape(strings.ToLower(name)), resource)
+}
+
+// Ref is a reference to a game data resource.
+type Ref struct {
+	// ID is the ID of the resource.
+	ID int `json:"id"`
+	// Name is the localized name of the resource.
+	Name string `json:"name,omitempty"`
+}
+
+// TypedName is an enum value with its localized name.
+type TypedName struct {
+	// Type is the value of the enum, e.g. "HEAD".
+	Type string `json:"type"`
+	// Name is the localized name of the value.
+	Name string `json:"name"`
+}
+
+// Equipment is the equipment of a character.
+type Equipment struct {
+	// EquippedItems are the items the character has equipped.
+	EquippedItems []EquippedItem `json:"equipped_items"`
+}
+
+// EquippedItem is an item a character has equipped.
+type EquippedItem struct {
+	// Item is the reference to the item.
+	Item Ref `json:"item"`
+	// Slot is the slot the item is equipped in.
+	Slot TypedName `json:"slot"`
+	// Name is the localized name of the item.
+	Name string `json:"name"`
+	// Quality is the quality of the item.
+	Quality TypedName `json:"quality"`
+	// Level is the item level.
+	Level struct {
+		// Value is the item level.
+		Value int `json:"value"`
+	} `json:"level"`
+	// Enchantments are the permanent and temporary enchantments of the item.
+	Enchantments []Enchantment `json:"enchantments,omitempty"`
+	// Sockets are the gem sockets of the item.
+	Sockets []Socket `json:"sockets,omitempty"`
+	// Set is the item set the item belongs to, e.g. a tier set. It is nil if the item belongs to no set.
+	Set *ItemSet `json:"set,omitempty"`
+}
+
+// Enchantment is an enchantment of an item.
+type Enchantment struct {
+	// DisplayString is the localized description of the enchantment.
+	DisplayString string `json:"display_string"`
+	// EnchantmentID is the ID of the enchantment.
+	EnchantmentID int `json:"enchantment_id"`
+	// EnchantmentSlot is the slot of the enchantment, e.g. "PERMANENT" or "TEMPORARY".
+	EnchantmentSlot struct {
+		// ID is the ID of the slot.
+		ID int `json:"id"`
+		// Type is the type of the slot.
+		Type string `json:"type"`
+	} `json:"enchantment_slot"`
+	// SourceItem is the item the enchantment was applied with, if any.
+	SourceItem *Ref `json:"source_item,omitempty"`
+}
+
+// Socket is a gem socket of an item.
+type Socket struct {
+	// SocketType is the type of the socket.
+	SocketType TypedName `json:"socket_type"`
+	// Item is the gem in the socket. It is nil for empty sockets.
+	Item *Ref `json:"item,omitempty"`
+	// DisplayString is the localized description of the gem's bonus.
+	DisplayString string `json:"display_string,omitempty"`
+}
+
+// ItemSet is an item set like a tier set.
+type ItemSet struct {
+	// ItemSet is the reference to the item set.
+	ItemSet Ref `json:"item_set"`
+	// Items are all items of the set.
+	Items []SetItem `json:"items"`
+}
+
+// SetItem is an item of an item set.
+type SetItem struct {
+	// Item is the reference to the item.
+	Item Ref `json:"item"`
+	// IsEquipped is whether the character has the item equipped.
+	IsEquipped bool `json:"is_equipped"`
+}
+
+// Equipped returns the number of items of the set the character has equipped.
+func (s *ItemSet) Equipped() int {
+	var n int
+	for _, item := range s.Items {
+		if item.IsEquipped {
+			n++
+		}
+	}
+	return n
+}
+
+// FetchEquipment returns the equipment of the character in the given locale.
+func (c *client) FetchEquipment(ctx context.Context, region, realm, name, locale string) (*Equipment, error) {
+	var equipment Equipment
+	err := c.getBattleNet(ctx, region, characterPath(realm, name, "equipment"), "profile", locale, &equipment)
+	if err != nil {
+		return nil, err
+	}
+	return &equipment, nil
+}
+
+// Keys of the character media assets.
+const (
+	// MediaAvatar is the key of the small avatar of a character.
+	MediaAvatar = "avatar"
+	// MediaInset is the key of the inset portrait of a character.
+	MediaInset = "inset"
+	// MediaRender is the key of the full render of a character without a background.
+	MediaRender = "main-raw"
+)
+
+// CharacterMedia are the rendered images of a character.
+type CharacterMedia struct {
+	// Assets are the images of the character.
+	Assets []MediaAsset `json:"assets"`
+}
+
+// MediaAsset is a rendered image of a character.
+type MediaAsset struct {
+	// Key is the kind of the image, e.g. "avatar".
+	Key string `json:"key"`
+	// Value is the URL of the image.
+	Value string `json:"value"`
+}
+
+// Asset returns the URL of the image with the given key or an empty string if there is none.
+func (m *CharacterMedia) Asset(key string) string {
+	for _, a := range m.Assets {
+		if a.Key == key {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// FetchCharacterMedia returns the rendered images of the character.
+func (c *client) FetchCharacterMedia(ctx context.Context, region, realm, name string) (*CharacterMedia, error) {
+	var media CharacterMedia
+	err := c.getBattleNet(ctx, region, characterPath(realm, name, "character-media"), "profile", "", &media)
+	if err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+// Realm is a realm of a region.
+type Realm struct {
+	// ID is the ID of the realm.
+	ID int `json:"id"`
+	// Name is the localized name of the realm.
+	Name string `json:"name"`
+	// Slug is the slug of the realm used in API paths.
+	Slug string `json:"slug"`
+}
+
+// FetchRealms returns the realms of the region in the given locale.
+func (c *client) FetchRealms(ctx context.Context, region, locale string) ([]Realm, error) {
+	var index struct {
+		Realms []Realm `json:"realms"`
+	}
+	err := c.getBattleNet(ctx, region, "/data/wow/realm/index", "dynamic", locale, &index)
+	if err != nil {
+		return nil, err
+	}
+	return index.Realms, nil
+}
@@ -0,0 +1,127 @@
+package guild
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/lvlcn-t/raid-mate/app/fakeupstream"
+	"github.com/lvlcn-t/raid-mate/app/services/upstream"
+)
+
+func TestBattleNet_hosts(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    BattleNetConfig
+		region    string
+		wantAPI   string
+		wantOAuth string
+	}{
+		{name: "eu", region: "EU", wantAPI: "https://eu.api.blizzard.com", wantOAuth: "https://oauth.battle.net"},
+		{name: "us", region: "us", wantAPI: "https://us.api.blizzard.com", wantOAuth: "https://oauth.battle.net"},
+		{name: "cn", region: "cn", wantAPI: "https://gateway.battlenet.com.cn", wantOAuth: "https://oauth.battlenet.com.cn"},
+		{
+			name:      "configured",
+			config:    BattleNetConfig{APIURL: "http://localhost:8081/", OAuthURL: "http://localhost:8081"},
+			region:    "cn",
+			wantAPI:   "http://localhost:8081",
+			wantOAuth: "http://localhost:8081",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api, oauth := newBattleNet(&tt.config).hosts(tt.region)
+			if api != tt.wantAPI || oauth != tt.wantOAuth {
+				t.Errorf("hosts() = %q, %q, want %q, %q", api, oauth, tt.wantAPI, tt.wantOAuth)
+			}
+		})
+	}
+}
+
+func TestRealmSlug(t *testing.T) {
+	for realm, want := range map[string]string{
+		"Draenor":           "draenor",
+		"Argent Dawn":       "argent-dawn",
+		"Kel'Thuzad":        "kelthuzad",
+		" Twisting Nether ": "twisting-nether",
+	} {
+		if got := realmSlug(realm); got != want {
+			t.Errorf("realmSlug(%q) = %q, want %q", realm, got, want)
+		}
+	}
+}
+
+func TestClient_battleNet(t *testing.T) {
+	var tokens, rejected atomic.Int32
+	fake := fakeupstream.New(nil)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if id, _, _ := r.BasicAuth(); id != "raid-mate" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			tokens.Add(1)
+		}
+		// The first token is rejected like an expired one.
+		if r.URL.Path != "/token" && rejected.CompareAndSwap(0, 1) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fake.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	c := NewClient(&ClientConfig{BattleNet: BattleNetConfig{ClientID: "raid-mate", APIURL: srv.URL, OAuthURL: srv.URL}}, upstream.New(&upstream.Config{}))
+	ctx := context.Background()
+
+	media, err := c.FetchCharacterMedia(ctx, "EU", "Draenor", "Aerith")
+	if err != nil {
+		t.Fatalf("FetchCharacterMedia() error = %v", err)
+	}
+	if got, want := media.Asset(MediaAvatar), "https://render.worldofwarcraft.com/eu/character/draenor/103/171234567-avatar.jpg"; got != want {
+		t.Errorf("Asset(%q) = %q, want %q", MediaAvatar, got, want)
+	}
+
+	equipment, err := c.FetchEquipment(ctx, "eu", "Draenor", "Aerith", "de_DE")
+	if err != nil {
+		t.Fatalf("FetchEquipment() error = %v", err)
+	}
+	if got := len(equipment.EquippedItems); got != 5 {
+		t.Fatalf("FetchEquipment() returned %d items, want 5", got)
+	}
+	ring := equipment.EquippedItems[3]
+	if ring.Slot.Type != "FINGER_1" || ring.Level.Value != 619 || ring.Enchantments[0].EnchantmentID != 7352 || ring.Sockets[0].Item != nil {
+		t.Errorf("FetchEquipment() ring = %+v, want an enchanted ring with an empty socket", ring)
+	}
+
+	realms, err := c.FetchRealms(ctx, "eu", "")
+	if err != nil {
+		t.Fatalf("FetchRealms() error = %v", err)
+	}
+	want := []Realm{{ID: 1403, Name: "Draenor", Slug: "draenor"}, {ID: 3702, Name: "Argent Dawn", Slug: "argent-dawn"}, {ID: 1587, Name: "Kel'Thuzad", Slug: "kelthuzad"}}
+	if !reflect.DeepEqual(realms, want) {
+		t.Errorf("FetchRealms() = %+v, want %+v", realms, want)
+	}
+
+	// The rejected token is renewed once, the renewed one is reused for all further requests.
+	if got := tokens.Load(); got != 2 {
+		t.Errorf("requested %d tokens, want 2", got)
+	}
+
+	_, err = c.FetchCharacterMedia(ctx, "eu", "Draenor", "Unknown")
+	if !errors.Is(err, upstream.ErrNotFound) {
+		t.Errorf("FetchCharacterMedia() error = %v, want %v", err, upstream.ErrNotFound)
+	}
+}
+
+func TestClient_battleNetDisabled(t *testing.T) {
+	c := NewClient(&ClientConfig{}, upstream.New(&upstream.Config{}))
+	if _, err := c.FetchEquipment(context.Background(), "eu", "Draenor", "Aerith", ""); !errors.Is(err, errBattleNetDisabled) {
+		t.Errorf("FetchEquipment() error = %v, want %v", err, errBattleNetDisabled)
+	}
+}
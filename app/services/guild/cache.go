@@ -0,0 +1,68 @@
+package guild
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lvlcn-t/raid-mate/app/database/repo"
+)
+
+// cache keeps the results of expensive upstream requests for a while.
+type cache[V any] struct {
+	// ttl is the duration the values are cached.
+	ttl time.Duration
+	// mu guards the entries.
+	mu sync.Mutex
+	// entries are the cached values mapped by their keys.
+	entries map[string]cacheEntry[V]
+}
+
+// cacheEntry is a cached value.
+type cacheEntry[V any] struct {
+	// value is the cached value.
+	value V
+	// expiresAt is the time the entry expires.
+	expiresAt time.Time
+}
+
+// newCache creates a new cache keeping the values for the given duration.
+// If the duration is not positive, the fallback is used.
+func newCache[V any](ttl, fallback time.Duration) *cache[V] {
+	if ttl <= 0 {
+		ttl = fallback
+	}
+	return &cache[V]{ttl: ttl, entries: map[string]cacheEntry[V]{}}
+}
+
+// get returns the cached value if it has not expired yet.
+func (c *cache[V]) get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok || time.Now().After(e.expiresAt) {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// put caches the value and drops all expired entries.
+func (c *cache[V]) put(key string, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for k, e := range c.entries {
+		if now.After(e.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = cacheEntry[V]{value: value, expiresAt: now.Add(c.ttl)}
+}
+
+// guildKey returns the cache key of the WoW guild linked to the Discord server.
+// The WoW guild is part of the key, so changing the settings of the server does not serve the data of the old guild.
+func guildKey(g repo.Guild) string {
+	return strings.ToLower(fmt.Sprintf("%d|%s|%s|%s", g.ID, g.ServerRegion, g.ServerRealm, g.Name))
+}
@@ -0,0 +1,74 @@
+package guild
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lvlcn-t/raid-mate/app/database/repo"
+	"github.com/lvlcn-t/raid-mate/app/fakeupstream"
+	"github.com/lvlcn-t/raid-mate/app/services/upstream"
+)
+
+func TestCache(t *testing.T) {
+	c := newCache[[]string](10*time.Millisecond, time.Hour)
+	roster := []string{"Aerith"}
+	c.put("key", roster)
+
+	if got, ok := c.get("key"); !ok || !reflect.DeepEqual(got, roster) {
+		t.Errorf("get() = %v, %v, want the cached roster", got, ok)
+	}
+	if _, ok := c.get("other"); ok {
+		t.Error("get() found a roster for an unknown key")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := c.get("key"); ok {
+		t.Error("get() returned an expired roster")
+	}
+	c.put("other", roster)
+	if n := len(c.entries); n != 1 {
+		t.Errorf("got %d entries after put, want the expired one to be dropped", n)
+	}
+}
+
+func TestGuild_roster(t *testing.T) {
+	var requests atomic.Int32
+	fake := fakeupstream.New(nil)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		fake.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+	s := &guild{
+		client:  NewClient(&ClientConfig{ProfileURL: srv.URL}, upstream.New(&upstream.Config{})),
+		rosters: newCache[[]string](time.Minute, time.Minute),
+	}
+
+	g := repo.Guild{ID: 1, Name: "Raid Mate", ServerRegion: "eu", ServerRealm: "Draenor"}
+	for range 3 {
+		got, err := s.roster(context.Background(), g)
+		if err != nil {
+			t.Fatalf("roster() error = %v", err)
+		}
+		if want := []string{"Aerith", "Bjorn", "Tifa"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("roster() = %v, want %v", got, want)
+		}
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("roster() sent %d requests, want the roster to be fetched once", n)
+	}
+
+	// Another WoW guild linked to the server must not be served from the cache.
+	g.Name = "Other Guild"
+	if _, err := s.roster(context.Background(), g); err == nil {
+		t.Error("roster() returned the cached roster of the previously linked guild")
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("roster() sent %d requests, want the roster of the other guild to be fetched", n)
+	}
+}
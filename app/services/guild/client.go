@@ -2,34 +2,99 @@ package guild
 
 import (
 	"context"
-	"encoding/json"
-	"errors"
 	"fmt"
-	"io"
 	"net/http"
+	"net/url"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/lvlcn-t/raid-mate/app/database/repo"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+	"github.com/lvlcn-t/raid-mate/app/services/upstream"
 )
 
 const (
-	logsBaseURL    = "https://www.warcraftlogs.com"
-	profileBaseURL = "https://raider.io"
-	msPerSec       = 1000
+	defaultLogsURL    = "https://www.warcraftlogs.com"
+	defaultProfileURL = "https://raider.io"
+	msPerSec          = 1000
+	// userProfileFields are the optional fields of the Raider.IO character profile decoded into a [UserProfile].
+	userProfileFields = "gear,raid_progression,mythic_plus_scores_by_season:current,mythic_plus_ranks,previous_mythic_plus_ranks," +
+		"mythic_plus_recent_runs,mythic_plus_best_runs,mythic_plus_alternate_runs"
+	// guildProfileFields are the optional fields of the Raider.IO guild profile decoded into a [GuildProfile].
+	guildProfileFields = "raid_progression,raid_rankings"
 )
 
+// ClientConfig is the configuration for the client.
+type ClientConfig struct {
+	// Token is the token for the client.
+	Token string `yaml:"token" mapstructure:"token" validate:"required"`
+	// Timeout is the timeout for a single call of the client including all retries.
+	Timeout time.Duration `yaml:"timeout" mapstructure:"timeout" validate:"gte=0"`
+	// LogsURL is the base URL of the Warcraft Logs API.
+	// Defaults to https://www.warcraftlogs.com.
+	LogsURL string `yaml:"logsUrl" mapstructure:"logsUrl"`
+	// ProfileURL is the base URL of the Raider.IO API.
+	// Defaults to https://raider.io.
+	ProfileURL string `yaml:"profileUrl" mapstructure:"profileUrl"`
+	// BattleNet is the configuration for the Battle.net API.
+	BattleNet BattleNetConfig `yaml:"battlenet" mapstructure:"battlenet"`
+}
+
 type client struct {
-	client http.Client
-	token  string
+	client     *upstream.Client
+	token      string
+	timeout    time.Duration
+	logsURL    string
+	profileURL string
+	battleNet  *battleNet
 }
 
-func NewClient(token string, timeout time.Duration) *client {
+func NewClient(c *ClientConfig, up *upstream.Client) *client {
 	return &client{
-		client: http.Client{
-			Timeout: timeout,
-		},
-		token: token,
+		client:     up,
+		token:      c.Token,
+		timeout:    c.Timeout,
+		logsURL:    baseURL(c.LogsURL, defaultLogsURL),
+		profileURL: baseURL(c.ProfileURL, defaultProfileURL),
+		battleNet:  newBattleNet(&c.BattleNet),
+	}
+}
+
+// baseURL returns the configured base URL without a trailing slash or the fallback if none is configured.
+func baseURL(configured, fallback string) string {
+	if configured == "" {
+		return fallback
 	}
+	return strings.TrimSuffix(configured, "/")
+}
+
+// get sends a GET request authenticated with the token of the client to the given url and decodes the JSON response into v.
+func (c *client) get(ctx context.Context, u string, query url.Values, v any) error {
+	return c.getWithToken(ctx, c.token, u, query, v)
+}
+
+// getWithToken sends a GET request authenticated with the given bearer token to the given url and decodes the JSON response into v.
+// The timeout of the client bounds the whole request including retries.
+func (c *client) getWithToken(ctx context.Context, token, u string, query url.Values, v any) error {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, http.NoBody)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.URL.RawQuery = query.Encode()
+
+	err = c.client.DoJSON(req, v)
+	if err != nil {
+		return fmt.Errorf("error making request: %w", err)
+	}
+	return nil
 }
 
 type report struct {
@@ -41,41 +106,213 @@ type report struct {
 	End   int    `json:"end"`
 }
 
-func (c *client) FetchReports(ctx context.Context, guild repo.Guild, date time.Time) (reports []report, err error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v1/reports/guild/%s/%s/%s", logsBaseURL, guild.Name, guild.ServerName, guild.ServerRegion), http.NoBody)
+// ReportURL returns the URL of the report with the given code.
+func (c *client) ReportURL(code string) string {
+	return fmt.Sprintf("%s/reports/%s", c.logsURL, url.PathEscape(code))
+}
+
+// fetchReports returns the reports the guild uploaded between start and end.
+func (c *client) fetchReports(ctx context.Context, guild repo.Guild, start, end time.Time) (reports []report, err error) {
+	query := url.Values{}
+	query.Add("start", fmt.Sprintf("%d", start.Unix()*msPerSec))
+	query.Add("end", fmt.Sprintf("%d", end.Unix()*msPerSec))
+
+	u := fmt.Sprintf("%s/v1/reports/guild/%s/%s/%s", c.logsURL,
+		url.PathEscape(guild.Name), url.PathEscape(guild.ServerName), url.PathEscape(guild.ServerRegion))
+	err = c.get(ctx, u, query, &reports)
 	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
+		return nil, err
 	}
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.token))
+	return reports, nil
+}
 
-	query := req.URL.Query()
-	query.Add("start", fmt.Sprintf("%d", date.Unix()*msPerSec))
-	query.Add("end", fmt.Sprintf("%d", date.AddDate(0, 0, 1).Unix()*msPerSec))
-	req.URL.RawQuery = query.Encode()
+// fight is a single pull of a report.
+type fight struct {
+	ID              int    `json:"id"`
+	Boss            int    `json:"boss"`
+	Name            string `json:"name"`
+	ZoneName        string `json:"zoneName"`
+	Difficulty      int    `json:"difficulty"`
+	Kill            bool   `json:"kill"`
+	FightPercentage int    `json:"fightPercentage"`
+	StartTime       int    `json:"start_time"`
+	EndTime         int    `json:"end_time"`
+}
+
+// friendly is a participant of a report.
+type friendly struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// Type is the class of a player or "Pet" and "NPC" for other participants.
+	Type   string `json:"type"`
+	Server string `json:"server"`
+	// Icon is the class and the spec of a player, e.g. "Priest-Holy".
+	Icon string `json:"icon"`
+	// Fights are the pulls the participant took part in.
+	Fights []fightRef `json:"fights"`
+}
+
+// fightRef is the reference to a pull of a report.
+type fightRef struct {
+	ID int `json:"id"`
+}
+
+// isPlayer reports whether the participant is a player.
+func (f friendly) isPlayer() bool {
+	// Pets and NPCs are listed as friendlies as well, but only players have a class as type.
+	return f.Type != "Pet" && f.Type != "NPC"
+}
+
+// tookPart reports whether the participant took part in the pull with the given ID.
+func (f friendly) tookPart(fightID int) bool {
+	return slices.Contains(f.Fights, fightRef{ID: fightID})
+}
 
-	resp, err := c.client.Do(req)
+// fights are the pulls and the participants of a report.
+type fights struct {
+	report
+	Fights     []fight    `json:"fights"`
+	Friendlies []friendly `json:"friendlies"`
+}
+
+// fetchFights returns the pulls and the participants of the report with the given code.
+func (c *client) fetchFights(ctx context.Context, code string) (*fights, error) {
+	var f fights
+	err := c.get(ctx, fmt.Sprintf("%s/v1/report/fights/%s", c.logsURL, url.PathEscape(code)), url.Values{}, &f)
 	if err != nil {
-		return nil, fmt.Errorf("error making request: %w", err)
+		return nil, err
 	}
-	defer func() {
-		err = errors.Join(err, resp.Body.Close())
-	}()
+	f.Id = code
+	return &f, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+// FetchParticipants returns the names of the players that took part in the report with the given code.
+func (c *client) FetchParticipants(ctx context.Context, code string) ([]string, error) {
+	f, err := c.fetchFights(ctx, code)
+	if err != nil {
+		return nil, err
 	}
 
-	b, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error reading response: %w", err)
+	var names []string
+	for _, p := range f.Friendlies {
+		if p.isPlayer() {
+			names = append(names, p.Name)
+		}
 	}
+	return names, nil
+}
 
-	err = json.Unmarshal(b, &reports)
-	if err != nil {
-		return nil, fmt.Errorf("error decoding response: %w", err)
+// death is the death of a participant of a report.
+type death struct {
+	// Timestamp is the time of the death in milliseconds since the start of the report.
+	Timestamp int `json:"timestamp"`
+	// Fight is the ID of the pull the participant died in.
+	Fight int `json:"fight"`
+	// TargetID is the ID of the participant who died.
+	TargetID int `json:"targetID"`
+	// KillingAbility is the ability that dealt the killing blow. It is missing if the killing blow is unknown.
+	KillingAbility struct {
+		Name string `json:"name"`
+	} `json:"killingAbility"`
+}
+
+// fetchDeaths returns the deaths of the friendly participants of the report with the given code
+// between start and end in milliseconds since the start of the report.
+func (c *client) fetchDeaths(ctx context.Context, code string, start, end int64) ([]death, error) {
+	return fetchEvents[death](ctx, c, "deaths", code, start, end, url.Values{"hostility": {"0"}})
+}
+
+// aura is an aura a player had at the start of a pull.
+type aura struct {
+	Name string `json:"name"`
+}
+
+// gearItem is an item a player had equipped at the start of a pull.
+type gearItem struct {
+	ID int `json:"id"`
+	// TemporaryEnchant is the ID of the temporary enchant like a weapon oil. It is zero if the item has none.
+	TemporaryEnchant int `json:"temporaryEnchant"`
+}
+
+// mainHandSlot is the index of the main hand in the gear of a combatant.
+const mainHandSlot = 15
+
+// combatant is the state of a player at the start of a pull.
+type combatant struct {
+	// Fight is the ID of the pull.
+	Fight int `json:"fight"`
+	// SourceID is the ID of the player.
+	SourceID int `json:"sourceID"`
+	// Auras are the auras the player had, including the buffs of consumables.
+	Auras []aura `json:"auras"`
+	// Gear is the equipped gear indexed by the inventory slot.
+	Gear []gearItem `json:"gear"`
+}
+
+// weaponEnchanted reports whether the main hand of the player had a temporary enchant.
+func (c *combatant) weaponEnchanted() bool {
+	return len(c.Gear) > mainHandSlot && c.Gear[mainHandSlot].TemporaryEnchant != 0
+}
+
+// fetchCombatants returns the auras and the gear of the players at the start of each pull of the report
+// with the given code between start and end in milliseconds since the start of the report.
+func (c *client) fetchCombatants(ctx context.Context, code string, start, end int64) ([]combatant, error) {
+	return fetchEvents[combatant](ctx, c, "summary", code, start, end, url.Values{"filter": {`type = "combatantinfo"`}})
+}
+
+// fetchEvents returns the events of the given view of the report with the given code
+// between start and end in milliseconds since the start of the report.
+// Warcraft Logs splits the events into pages, all of them are fetched.
+func fetchEvents[T any](ctx context.Context, c *client, view, code string, start, end int64, query url.Values) ([]T, error) {
+	u := fmt.Sprintf("%s/v1/report/events/%s/%s", c.logsURL, view, url.PathEscape(code))
+
+	var events []T
+	for {
+		query.Set("start", fmt.Sprintf("%d", start))
+		query.Set("end", fmt.Sprintf("%d", end))
+
+		var page struct {
+			Events []T `json:"events"`
+			// NextPageTimestamp is the start of the next page. It is missing on the last page.
+			NextPageTimestamp int64 `json:"nextPageTimestamp"`
+		}
+		err := c.get(ctx, u, query, &page)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, page.Events...)
+		if page.NextPageTimestamp <= start {
+			return events, nil
+		}
+		start = page.NextPageTimestamp
 	}
+}
 
-	return reports, nil
+// ranking is a ranked kill of a character.
+type ranking struct {
+	EncounterID   int     `json:"encounterID"`
+	EncounterName string  `json:"encounterName"`
+	Difficulty    int     `json:"difficulty"`
+	Spec          string  `json:"spec"`
+	StartTime     int     `json:"startTime"`
+	ReportID      string  `json:"reportID"`
+	Percentile    float64 `json:"percentile"`
+	Total         float64 `json:"total"`
+}
+
+// fetchRankings returns the ranked kills of the character of the current raid tier in the given metric, either "dps" or "hps".
+func (c *client) fetchRankings(ctx context.Context, name, server, region, metric string) (rankings []ranking, err error) {
+	query := url.Values{}
+	query.Add("metric", metric)
+	query.Add("timeframe", "historical")
+
+	u := fmt.Sprintf("%s/v1/rankings/character/%s/%s/%s", c.logsURL,
+		url.PathEscape(name), url.PathEscape(server), url.PathEscape(region))
+	err = c.get(ctx, u, query, &rankings)
+	if err != nil {
+		return nil, err
+	}
+	return rankings, nil
 }
 
 type Profiles struct {
@@ -119,6 +356,20 @@ type UserProfile struct {
 	MythicPlusRecentRuns     []MythicPlusRun            `json:"mythic_plus_recent_runs"`
 	MythicPlusBestRuns       []MythicPlusRun            `json:"mythic_plus_best_runs"`
 	MythicPlusAlternateRuns  []MythicPlusRun            `json:"mythic_plus_alternate_runs"`
+	// ThumbnailURL is the URL of the avatar of the character cached by Raider.IO.
+	ThumbnailURL string `json:"thumbnail_url"`
+	// Portrait is the URL of the avatar of the character from the Battle.net API.
+	// It is empty if the Battle.net API is not configured or has no media of the character.
+	Portrait string `json:"portrait,omitempty"`
+}
+
+// CurrentSeason returns the Mythic+ scores of the current season.
+// It returns false if the character has no scores in the current season.
+func (p *UserProfile) CurrentSeason() (MythicPlusScoresBySeason, bool) {
+	if len(p.MythicPlusScoresBySeason) == 0 {
+		return MythicPlusScoresBySeason{}, false
+	}
+	return p.MythicPlusScoresBySeason[0], true
 }
 
 type Gear struct {
@@ -162,14 +413,14 @@ type MythicPlusScoresBySeason struct {
 }
 
 type Scores struct {
-	All    int `json:"all"`
-	Dps    int `json:"dps"`
-	Healer int `json:"healer"`
-	Tank   int `json:"tank"`
-	Spec0  int `json:"spec_0"`
-	Spec1  int `json:"spec_1"`
-	Spec2  int `json:"spec_2"`
-	Spec3  int `json:"spec_3"`
+	All    float64 `json:"all"`
+	Dps    float64 `json:"dps"`
+	Healer float64 `json:"healer"`
+	Tank   float64 `json:"tank"`
+	Spec0  float64 `json:"spec_0"`
+	Spec1  float64 `json:"spec_1"`
+	Spec2  float64 `json:"spec_2"`
+	Spec3  float64 `json:"spec_3"`
 }
 
 type Segments struct {
@@ -184,8 +435,8 @@ type Segments struct {
 }
 
 type All struct {
-	Score int    `json:"score"`
-	Color string `json:"color"`
+	Score float64 `json:"score"`
+	Color string  `json:"color"`
 }
 
 type RaidProgression struct {
@@ -223,80 +474,68 @@ func (c *client) FetchProfile(ctx context.Context, req *RequestProfile) (*Profil
 		}
 		return &Profiles{UserProfile: p}, nil
 	default:
-		return nil, errors.New("invalid profile type")
+		return nil, svcerr.Invalid(svcerr.Msg("errors.invalid.profile_type", "user", "guild"))
 	}
 }
 
 func (c *client) getGuildProfile(ctx context.Context, r *RequestProfile) (profile *GuildProfile, err error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v1/guilds/profile", profileBaseURL), http.NoBody)
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
-	}
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.token))
-
-	query := req.URL.Query()
+	query := url.Values{}
 	query.Add("region", r.guild.ServerRegion)
 	query.Add("realm", r.guild.ServerRealm)
 	query.Add("name", r.guild.Name)
+	query.Add("fields", guildProfileFields)
 
-	resp, err := c.client.Do(req)
+	err = c.get(ctx, fmt.Sprintf("%s/api/v1/guilds/profile", c.profileURL), query, &profile)
 	if err != nil {
-		return nil, fmt.Errorf("error making request: %w", err)
+		return nil, err
 	}
-	defer func() {
-		err = errors.Join(err, resp.Body.Close())
-	}()
+	return profile, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
+// Member is a member of the roster of a guild.
+type Member struct {
+	Rank      int `json:"rank"`
+	Character struct {
+		Name           string `json:"name"`
+		Realm          string `json:"realm"`
+		Class          string `json:"class"`
+		ActiveSpecName string `json:"active_spec_name"`
+	} `json:"character"`
+}
 
-	b, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error reading response: %w", err)
-	}
+// FetchMembers returns the roster of the guild.
+func (c *client) FetchMembers(ctx context.Context, guild repo.Guild) ([]Member, error) {
+	query := url.Values{}
+	query.Add("region", guild.ServerRegion)
+	query.Add("realm", guild.ServerRealm)
+	query.Add("name", guild.Name)
+	query.Add("fields", "members")
 
-	err = json.Unmarshal(b, &profile)
+	var profile struct {
+		Members []Member `json:"members"`
+	}
+	err := c.get(ctx, fmt.Sprintf("%s/api/v1/guilds/profile", c.profileURL), query, &profile)
 	if err != nil {
-		return nil, fmt.Errorf("error decoding response: %w", err)
+		return nil, err
 	}
-
-	return profile, nil
+	return profile.Members, nil
 }
 
 func (c *client) getUserProfile(ctx context.Context, r *RequestProfile) (profile *UserProfile, err error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/v1/characters/profile", profileBaseURL), http.NoBody)
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
+	realm := r.Realm
+	if realm == "" {
+		realm = r.guild.ServerRealm
 	}
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.token))
 
-	query := req.URL.Query()
+	query := url.Values{}
 	query.Add("region", r.guild.ServerRegion)
-	query.Add("realm", r.guild.ServerRealm)
+	query.Add("realm", realm)
 	query.Add("name", r.User)
+	query.Add("fields", userProfileFields)
 
-	resp, err := c.client.Do(req)
+	err = c.get(ctx, fmt.Sprintf("%s/api/v1/characters/profile", c.profileURL), query, &profile)
 	if err != nil {
-		return nil, fmt.Errorf("error making request: %w", err)
-	}
-	defer func() {
-		err = errors.Join(err, resp.Body.Close())
-	}()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, err
 	}
-
-	b, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error reading response: %w", err)
-	}
-
-	err = json.Unmarshal(b, &profile)
-	if err != nil {
-		return nil, fmt.Errorf("error decoding response: %w", err)
-	}
-
 	return profile, nil
 }
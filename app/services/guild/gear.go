@@ -0,0 +1,266 @@
+package guild
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lvlcn-t/raid-mate/app/database/repo"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+	"github.com/lvlcn-t/raid-mate/app/services/upstream"
+)
+
+const (
+	// MaxItemLevel is the highest minimum item level that can be required.
+	MaxItemLevel = 1000
+	// enchantSlotsNone is the input that requires no enchants at all.
+	enchantSlotsNone = "none"
+	// permanentEnchant is the type of the enchantment slot of enchants that are not temporary like weapon oils.
+	permanentEnchant = "PERMANENT"
+	// tierBonus is the number of tier set pieces needed for the full set bonus.
+	tierBonus = 4
+)
+
+// GearSlots are the slots of the equipment that are audited in the order they are shown.
+// They are the lowercase slot types of the Battle.net API, shirts and tabards are not audited.
+var GearSlots = []string{
+	"head", "neck", "shoulder", "back", "chest", "wrist", "hands", "waist", "legs", "feet",
+	"finger_1", "finger_2", "trinket_1", "trinket_2", "main_hand", "off_hand",
+}
+
+// DefaultEnchantSlots are the slots that have to be enchanted unless the guild configured others.
+var DefaultEnchantSlots = []string{"back", "chest", "wrist", "legs", "feet", "finger_1", "finger_2", "main_hand"}
+
+// GearRules are the rules the equipment of the raiders of a guild is audited against.
+type GearRules struct {
+	// MinItemLevel is the item level every item must have at least.
+	// If it is zero, the item levels are not audited.
+	MinItemLevel int `json:"min_item_level"`
+	// EnchantSlots are the slots that have to be enchanted.
+	EnchantSlots []string `json:"enchant_slots"`
+}
+
+// NewGearRules validates the minimum item level and the comma separated slots that have to be enchanted.
+// Without slots the [DefaultEnchantSlots] have to be enchanted, "none" requires no enchants at all.
+// It returns an [svcerr.ErrInvalidInput] error describing all invalid rules.
+func NewGearRules(minItemLevel int, enchantSlots string) (GearRules, error) {
+	rules := GearRules{MinItemLevel: minItemLevel, EnchantSlots: []string{}}
+
+	var problems []svcerr.Message
+	if minItemLevel < 0 || minItemLevel > MaxItemLevel {
+		problems = append(problems, svcerr.Msg("errors.invalid.item_level", MaxItemLevel))
+	}
+	switch enchantSlots = strings.TrimSpace(enchantSlots); {
+	case enchantSlots == "":
+		rules.EnchantSlots = slices.Clone(DefaultEnchantSlots)
+	case strings.EqualFold(enchantSlots, enchantSlotsNone):
+	default:
+		for _, slot := range strings.Split(enchantSlots, ",") {
+			slot = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(slot)), "-", "_")
+			switch {
+			case !slices.Contains(GearSlots, slot):
+				problems = append(problems, svcerr.Msg("errors.invalid.gear_slot", slot, strings.Join(GearSlots, ", ")))
+			case !slices.Contains(rules.EnchantSlots, slot):
+				rules.EnchantSlots = append(rules.EnchantSlots, slot)
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return GearRules{}, svcerr.Invalid(problems...)
+	}
+
+	// The slots are stored and shown in the order of the equipment.
+	slices.SortFunc(rules.EnchantSlots, func(a, b string) int {
+		return cmp.Compare(slices.Index(GearSlots, a), slices.Index(GearSlots, b))
+	})
+	return rules, nil
+}
+
+// gearRulesOf returns the gear rules stored for the Discord server.
+// Servers that never configured the enchanted slots use the [DefaultEnchantSlots].
+func gearRulesOf(g repo.Guild) GearRules {
+	rules := GearRules{MinItemLevel: int(g.GearMinItemLevel.Int16), EnchantSlots: g.GearEnchantSlots}
+	if rules.EnchantSlots == nil {
+		rules.EnchantSlots = slices.Clone(DefaultEnchantSlots)
+	}
+	return rules
+}
+
+// GearAudit is the audit of the equipment of the raiders of a guild.
+type GearAudit struct {
+	// Rules are the rules the equipment was audited against.
+	Rules GearRules `json:"rules"`
+	// Players are the audits of the raiders, the raiders with the most problems first.
+	Players []PlayerGear `json:"players"`
+	// Unavailable are the raiders whose equipment is not known to the Battle.net API,
+	// e.g. because they have not logged in for a long time.
+	Unavailable []string `json:"unavailable"`
+}
+
+// PlayerGear is the audit of the equipment of a single raider.
+type PlayerGear struct {
+	// Character is the name of the character.
+	Character string `json:"character"`
+	// Class is the class of the character.
+	Class string `json:"class"`
+	// ItemLevel is the average item level of the equipped items.
+	ItemLevel float64 `json:"item_level"`
+	// TierPieces is the number of equipped pieces of the tier set the character has the most pieces of.
+	TierPieces int `json:"tier_pieces"`
+	// MissingEnchants are the slots that have to be enchanted but are not.
+	MissingEnchants []string `json:"missing_enchants"`
+	// EmptySockets are the slots with an item that has an empty gem socket, once per empty socket.
+	EmptySockets []string `json:"empty_sockets"`
+	// LowItemLevel are the items below the minimum item level.
+	LowItemLevel []GearSlot `json:"low_item_level"`
+}
+
+// GearSlot is an item in a slot of the equipment.
+type GearSlot struct {
+	// Slot is the slot of the item, one of the [GearSlots].
+	Slot string `json:"slot"`
+	// ItemLevel is the item level of the item.
+	ItemLevel int `json:"item_level"`
+}
+
+// Problems returns the number of problems found in the equipment.
+// Missing tier pieces are not counted, because not every character can have the full set bonus.
+func (p *PlayerGear) Problems() int {
+	return len(p.MissingEnchants) + len(p.EmptySockets) + len(p.LowItemLevel)
+}
+
+// TierBonus returns true if the character has the full set bonus of a tier set.
+func (p *PlayerGear) TierBonus() bool {
+	return p.TierPieces >= tierBonus
+}
+
+// auditGear audits the equipment of the character against the rules.
+func auditGear(character, class string, equipment *Equipment, rules GearRules) PlayerGear {
+	p := PlayerGear{
+		Character:       character,
+		Class:           class,
+		MissingEnchants: []string{},
+		EmptySockets:    []string{},
+		LowItemLevel:    []GearSlot{},
+	}
+
+	items := map[string]*EquippedItem{}
+	for i := range equipment.EquippedItems {
+		item := &equipment.EquippedItems[i]
+		slot := strings.ToLower(item.Slot.Type)
+		if !slices.Contains(GearSlots, slot) {
+			continue
+		}
+		items[slot] = item
+		if item.Set != nil {
+			p.TierPieces = max(p.TierPieces, item.Set.Equipped())
+		}
+	}
+
+	var total, slots int
+	for _, slot := range GearSlots {
+		item, ok := items[slot]
+		if !ok {
+			// A two-handed weapon counts twice, because it leaves the off hand empty.
+			if main, ok := items["main_hand"]; ok && slot == "off_hand" {
+				total += main.Level.Value
+				slots++
+			}
+			continue
+		}
+		total += item.Level.Value
+		slots++
+
+		enchanted := slices.ContainsFunc(item.Enchantments, func(e Enchantment) bool { return e.EnchantmentSlot.Type == permanentEnchant })
+		if slices.Contains(rules.EnchantSlots, slot) && !enchanted {
+			p.MissingEnchants = append(p.MissingEnchants, slot)
+		}
+		for _, socket := range item.Sockets {
+			if socket.Item == nil {
+				p.EmptySockets = append(p.EmptySockets, slot)
+			}
+		}
+		if item.Level.Value < rules.MinItemLevel {
+			p.LowItemLevel = append(p.LowItemLevel, GearSlot{Slot: slot, ItemLevel: item.Level.Value})
+		}
+	}
+	if slots > 0 {
+		p.ItemLevel = float64(total) / float64(slots)
+	}
+	return p
+}
+
+func (s *guild) SetGearRules(ctx context.Context, guildID snowflake.ID, rules GearRules) error {
+	n, err := repo.New(s.database).SetGuildGearRules(ctx, repo.SetGuildGearRulesParams{
+		GearMinItemLevel: sql.NullInt16{Int16: int16(rules.MinItemLevel), Valid: true}, //nolint:gosec // The item level is validated to be at most 1000
+		GearEnchantSlots: rules.EnchantSlots,
+		ID:               int64(guildID), //nolint:gosec // Snowflake cannot overflow AFAIK
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return svcerr.New(svcerr.ErrNotConfigured, "")
+	}
+	return nil
+}
+
+func (s *guild) AuditGear(ctx context.Context, guildID snowflake.ID, character string) (*GearAudit, error) {
+	if !s.client.battleNet.enabled() {
+		return nil, svcerr.Wrap(svcerr.ErrUnavailable, errBattleNetDisabled, "Battle.net")
+	}
+	guild, err := s.Get(ctx, guildID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting guild: %w", err)
+	}
+
+	members, err := s.client.FetchMembers(ctx, guild)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching roster: %w", svcerr.FromUpstream(err, guild.Name))
+	}
+	if strings.TrimSpace(character) == "" {
+		members = raiders(guild, members)
+	}
+	members, err = filterMembers(members, character)
+	if err != nil {
+		return nil, err
+	}
+	return s.gearAudit(ctx, guild, members)
+}
+
+// gearAudit audits the equipment of the members against the rules of the guild.
+func (s *guild) gearAudit(ctx context.Context, guild repo.Guild, members []Member) (*GearAudit, error) {
+	equipment, err := fetchEach(ctx, members, func(ctx context.Context, m Member) (*Equipment, error) {
+		realm := cmp.Or(m.Character.Realm, guild.ServerRealm)
+		e, err := s.client.FetchEquipment(ctx, guild.ServerRegion, realm, m.Character.Name, "")
+		if errors.Is(err, upstream.ErrNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error fetching equipment of %q: %w", m.Character.Name, svcerr.FromUpstream(err, m.Character.Name))
+		}
+		return e, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	audit := &GearAudit{Rules: gearRulesOf(guild), Players: []PlayerGear{}, Unavailable: []string{}}
+	for i, m := range members {
+		if equipment[i] == nil {
+			audit.Unavailable = append(audit.Unavailable, m.Character.Name)
+			continue
+		}
+		audit.Players = append(audit.Players, auditGear(m.Character.Name, m.Character.Class, equipment[i], audit.Rules))
+	}
+
+	slices.SortStableFunc(audit.Players, func(a, b PlayerGear) int {
+		return cmp.Or(cmp.Compare(b.Problems(), a.Problems()), strings.Compare(a.Character, b.Character))
+	})
+	return audit, nil
+}
@@ -0,0 +1,92 @@
+package guild
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+)
+
+func TestNewGearRules(t *testing.T) {
+	tests := []struct {
+		name         string
+		minItemLevel int
+		enchantSlots string
+		want         GearRules
+		// wantErr is the key of the message of the error. Empty if no error is expected.
+		wantErr string
+	}{
+		{name: "defaults", want: GearRules{EnchantSlots: DefaultEnchantSlots}},
+		{
+			name:         "slots in equipment order",
+			minItemLevel: 610,
+			enchantSlots: " Main-Hand, back,FINGER_1 , back",
+			want:         GearRules{MinItemLevel: 610, EnchantSlots: []string{"back", "finger_1", "main_hand"}},
+		},
+		{name: "none", enchantSlots: "None", want: GearRules{EnchantSlots: []string{}}},
+		{name: "unknown slot", enchantSlots: "back,tabard", wantErr: "errors.invalid.gear_slot"},
+		{name: "item level too high", minItemLevel: 1001, wantErr: "errors.invalid.item_level"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewGearRules(tt.minItemLevel, tt.enchantSlots)
+			if (err != nil) != (tt.wantErr != "") {
+				t.Fatalf("NewGearRules() error = %v, want error: %v", err, tt.wantErr != "")
+			}
+			if err != nil {
+				if msgs := svcerr.Messages(err); !errors.Is(err, svcerr.ErrInvalidInput) || len(msgs) != 1 || msgs[0].Key != tt.wantErr {
+					t.Errorf("NewGearRules() error = %v, want an invalid input error with the message %q", err, tt.wantErr)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewGearRules() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGuild_gearAudit(t *testing.T) {
+	s := newTestGuild(t)
+	g := raidMate
+	g.GearMinItemLevel = sql.NullInt16{Int16: 615, Valid: true}
+	members := fetchTestMembers(t, s, g)
+
+	got, err := s.gearAudit(context.Background(), g, members)
+	if err != nil {
+		t.Fatalf("gearAudit() error = %v", err)
+	}
+
+	want := &GearAudit{
+		Rules: GearRules{MinItemLevel: 615, EnchantSlots: DefaultEnchantSlots},
+		Players: []PlayerGear{
+			{
+				Character: "Bjorn",
+				Class:     "Warrior",
+				// The two-handed weapon counts twice.
+				ItemLevel:       5557.0 / 9,
+				TierPieces:      4,
+				MissingEnchants: []string{"chest", "legs", "main_hand"},
+				EmptySockets:    []string{},
+				LowItemLevel:    []GearSlot{{Slot: "wrist", ItemLevel: 610}, {Slot: "legs", ItemLevel: 606}},
+			},
+			{
+				Character:       "Aerith",
+				Class:           "Priest",
+				ItemLevel:       626.5,
+				TierPieces:      2,
+				MissingEnchants: []string{},
+				EmptySockets:    []string{"finger_1"},
+				LowItemLevel:    []GearSlot{},
+			},
+		},
+		Unavailable: []string{"Tifa"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("gearAudit() = %+v, want %+v", got, want)
+	}
+}
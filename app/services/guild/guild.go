@@ -1,34 +1,98 @@
 package guild
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/disgoorg/snowflake/v2"
+	"github.com/lib/pq"
 	"github.com/lvlcn-t/raid-mate/app/database/repo"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+	"github.com/lvlcn-t/raid-mate/app/services/upstream"
+)
+
+const (
+	// uniqueViolation is the PostgreSQL error code for a violated unique constraint.
+	uniqueViolation = "23505"
+	// defaultRetention is the default duration the data of a Discord server is kept after the bot left it.
+	defaultRetention = 30 * 24 * time.Hour
+	// defaultRosterTTL is the default duration the roster of a guild is cached.
+	defaultRosterTTL = 5 * time.Minute
 )
 
 // Service is the interface for the guild service.
+// All methods return errors of the [svcerr] package for failures the user can act on.
 type Service interface {
 	guildService
 	credentialService
 	reportService
+	auditService
 	profileService
+	settingsService
 }
 
 type guildService interface {
 	// List returns a list of guilds.
 	List(ctx context.Context) ([]repo.Guild, error)
 	// Get returns the guild with the given ID.
+	// The name, realm, region and faction are the ones of the WoW guild selected by the context
+	// or of the default WoW guild if none is selected, see [WithWowGuild].
 	Get(ctx context.Context, id snowflake.ID) (repo.Guild, error)
-	// Create creates a new guild.
+	// Create sets up the Discord server with its default WoW guild.
+	// The realm is taken from the server realm and falls back to the server name.
+	// The guild is looked up on Raider.IO first, so only existing guilds can be set up.
 	Create(ctx context.Context, ngp repo.NewGuildParams) error
 	// Update updates the guild with the given parameters.
 	Update(ctx context.Context, ugp repo.UpdateGuildParams) error
-	// Delete deletes the guild with the given ID.
-	Delete(ctx context.Context, id snowflake.ID) error
+	// SetAnnouncementChannel sets the channel of the Discord server the bot posts announcements in.
+	SetAnnouncementChannel(ctx context.Context, guildID, channelID snowflake.ID) error
+	// SetRaiderRole sets the role of the raiders of the Discord server.
+	SetRaiderRole(ctx context.Context, guildID, roleID snowflake.ID) error
+	// GetLocale returns the locale the bot responds in on the Discord server.
+	// It is empty if the bot responds in the locale of each user.
+	// It returns an [svcerr.ErrNotConfigured] error if the server has not been set up.
+	GetLocale(ctx context.Context, guildID snowflake.ID) (string, error)
+	// SetLocale sets the locale the bot responds in on the Discord server.
+	// An empty locale makes the bot respond in the locale of each user.
+	SetLocale(ctx context.Context, guildID snowflake.ID, locale string) error
+	// SetSchedule sets the timezone and the raid hours of the Discord server, see [NewSchedule].
+	// It returns an [svcerr.ErrNotConfigured] error if the server has not been set up.
+	SetSchedule(ctx context.Context, guildID snowflake.ID, schedule Schedule) error
+	// SetGearRules sets the rules the equipment of the raiders of the Discord server is audited against, see [NewGearRules].
+	// It returns an [svcerr.ErrNotConfigured] error if the server has not been set up.
+	SetGearRules(ctx context.Context, guildID snowflake.ID, rules GearRules) error
+	// SetRaiderRank sets the lowest rank of the members of the WoW guild of the Discord server that count as raiders,
+	// so the data of alts and bank characters is not fetched. A negative rank makes all members count as raiders.
+	// It returns an [svcerr.ErrInvalidInput] error if the rank is above [MaxGuildRank]
+	// and an [svcerr.ErrNotConfigured] error if the server has not been set up.
+	SetRaiderRank(ctx context.Context, guildID snowflake.ID, rank int) error
+	// Reset resets the settings of the Discord server with the given ID and unlinks all WoW guilds but the default guild.
+	// Characters, credentials, audits and snapshots are kept, they are only deleted by [Service.Purge].
+	// It returns an [svcerr.ErrNotConfigured] error if the server has not been set up.
+	Reset(ctx context.Context, id snowflake.ID) error
+	// Leave marks the Discord server as left by the bot.
+	// Its data is purged once the retention period elapsed, unless the bot rejoins the server before.
+	// It returns an [svcerr.ErrNotConfigured] error if the server has not been set up.
+	Leave(ctx context.Context, id snowflake.ID) error
+	// Rejoin marks the Discord server as joined again, so its data is kept.
+	// It returns an [svcerr.ErrNotConfigured] error if the server has not been set up.
+	Rejoin(ctx context.Context, id snowflake.ID) error
+	// Purge deletes all data of the Discord server with the given ID and returns what has been deleted.
+	Purge(ctx context.Context, id snowflake.ID) (Purged, error)
+	// PurgeLeft deletes all data of the Discord servers the bot left longer than the retention period ago.
+	// It returns what has been deleted per server, including the servers purged before an error occurred.
+	PurgeLeft(ctx context.Context) ([]Purged, error)
+	// SnapshotLeaderboards stores the current Mythic+ scores of the raiders of every Discord server
+	// whose last snapshot was taken before the last weekly reset of its region, see [WeeklyReset].
+	// It returns the leaderboards of the new snapshots with the gains since the previous ones,
+	// including the ones taken before an error occurred for another server.
+	SnapshotLeaderboards(ctx context.Context, now time.Time) ([]WeeklyLeaderboard, error)
 }
 
 type credentialService interface {
@@ -36,16 +100,47 @@ type credentialService interface {
 	GetCredentials(ctx context.Context, gcp repo.GetCredentialsParams) (repo.Credential, error)
 	// SetCredentials sets the credentials for the given parameters.
 	SetCredentials(ctx context.Context, scp repo.SetCredentialsParams) error
+	// ListCredentials returns the names of the accounts the guild stored credentials for.
+	ListCredentials(ctx context.Context, guildID snowflake.ID) ([]string, error)
 }
 
 type reportService interface {
-	// GetReports returns the reports for the given guild and date.
-	GetReports(ctx context.Context, guildID snowflake.ID, date time.Time) ([]string, error)
+	// GetReports returns the reports the guild uploaded in the given period of raid days, see [ParsePeriod].
+	// The period is relative to the current raid day and the raid days are bounded by the schedule of the guild.
+	GetReports(ctx context.Context, guildID snowflake.ID, period string) (*Reports, error)
+	// GetReport returns the report with the given code or URL with a breakdown of its boss encounters.
+	GetReport(ctx context.Context, guildID snowflake.ID, code string) (*Report, error)
+	// GetParses returns the best parse per boss of every raider who took part in the reports the guild uploaded
+	// in the given period of raid days, see [ParsePeriod]. Healers are ranked by their healing, all others by their damage.
+	// If a character is given, only its parses are returned.
+	// The parses are cached, because collecting them takes a request per raider.
+	GetParses(ctx context.Context, guildID snowflake.ID, character, period string) (*Parses, error)
+	// GetWipes returns who died first in each boss pull of the report with the given code or URL, the ability that killed them
+	// and the most common causes of the wipes. If a boss is given, only its pulls are included.
+	// It returns an [svcerr.ErrNotFound] error if the boss has not been pulled in the report.
+	GetWipes(ctx context.Context, guildID snowflake.ID, code, boss string) (*Wipes, error)
+	// GetAttendance returns how many of the raids the guild logged since the given time the character took part in.
+	GetAttendance(ctx context.Context, guildID snowflake.ID, character string, since time.Time) (*Attendance, error)
 }
 
 type profileService interface {
 	// GetProfile returns the profile for the given parameters.
 	GetProfile(ctx context.Context, req *RequestProfile) (*Profiles, error)
+	// GetRoster returns the names of the characters in the roster of the guild.
+	// The roster is cached for a while, because it is suggested on every keystroke of an autocompleted option.
+	GetRoster(ctx context.Context, guildID snowflake.ID) ([]string, error)
+	// GetLeaderboard returns the Mythic+ leaderboard of the raiders in the roster of the guild in the current season
+	// with the gains since the last weekly snapshot. If a role is given, the characters are ranked by their score in it.
+	// The raiders are the members up to the rank set by [Service.SetRaiderRank].
+	// It returns an [svcerr.ErrInvalidInput] error if the role is not one of the [Roles].
+	GetLeaderboard(ctx context.Context, guildID snowflake.ID, role string) (*Leaderboard, error)
+	// GetScoreHistory returns the Mythic+ scores of the character in all weekly snapshots, the oldest first.
+	// It returns an [svcerr.ErrNotFound] error if the character is in none of the snapshots.
+	GetScoreHistory(ctx context.Context, guildID snowflake.ID, character string) ([]ScoreSnapshot, error)
+	// GetVault returns the progress of the raiders in the roster of the guild towards the slots of the Great Vault
+	// since the last weekly reset of its region, counting the Mythic+ runs from Raider.IO and the raid bosses from Warcraft Logs.
+	// If a character is given, only its progress is returned, even if it is not a raider, see [Service.SetRaiderRank].
+	GetVault(ctx context.Context, guildID snowflake.ID, character string) (*Vault, error)
 }
 
 // RequestProfile is the request for the profile.
@@ -53,33 +148,100 @@ type RequestProfile struct {
 	Type    string
 	GuildID snowflake.ID
 	User    string
-	guild   repo.Guild
+	// Realm is the realm of the user's character.
+	// Defaults to the realm of the guild.
+	Realm string
+	guild repo.Guild
+}
+
+// Reports are the reports a guild uploaded in a period of raid days.
+type Reports struct {
+	// Period is the period of raid days the reports were uploaded in.
+	Period Period
+	// Reports are the reports with a breakdown of their boss encounters.
+	Reports []Report
+}
+
+// Attendance is the raid attendance of a character.
+type Attendance struct {
+	// Character is the name of the character.
+	Character string `json:"character"`
+	// Since is the start of the period the attendance was tracked in.
+	Since time.Time `json:"since"`
+	// Raids is the number of raids the guild logged in the period.
+	Raids int `json:"raids"`
+	// Attended is the number of raids the character took part in.
+	Attended int `json:"attended"`
+}
+
+// Rate returns the share of raids the character took part in between 0 and 1.
+func (a *Attendance) Rate() float64 {
+	if a.Raids == 0 {
+		return 0
+	}
+	return float64(a.Attended) / float64(a.Raids)
+}
+
+// Purged is the data deleted for a Discord server.
+type Purged struct {
+	// GuildID is the ID of the Discord server.
+	GuildID snowflake.ID
+	// WowGuilds is the number of deleted WoW guilds.
+	WowGuilds int64
+	// Characters is the number of deleted characters.
+	Characters int64
+	// Credentials is the number of deleted credentials.
+	Credentials int64
+	// ConsumableAudits is the number of deleted consumable audits.
+	ConsumableAudits int64
+	// MplusSnapshots is the number of deleted snapshots of the Mythic+ leaderboard.
+	MplusSnapshots int64
 }
 
 // guild implements [Service] for the guild service.
 type guild struct {
-	// database is the database repository.
-	database repo.DBTX
+	// database is the database connection.
+	database *sql.DB
 	// client is the http client.
 	client *client
+	// retention is the duration the data of a Discord server is kept after the bot left it.
+	retention time.Duration
+	// parses caches the parses of the guilds, because collecting them takes a request per raider to Warcraft Logs.
+	parses *cache[*Parses]
+	// rosters caches the names of the characters in the rosters of the guilds,
+	// because they are suggested on every keystroke of an autocompleted option.
+	rosters *cache[[]string]
 }
 
 // Config is the configuration for the guild service.
 type Config struct {
 	// Client is the configuration for the client.
-	Client struct {
-		// Token is the token for the client.
-		Token string `yaml:"token" mapstructure:"token" validate:"required"`
-		// Timeout is the timeout for the client.
-		Timeout time.Duration `yaml:"timeout" mapstructure:"timeout" validate:"gte=0"`
-	} `yaml:"client" mapstructure:"client"`
+	Client ClientConfig `yaml:"client" mapstructure:"client"`
+	// Retention is the duration the data of a Discord server is kept after the bot left it.
+	// Defaults to 30 days.
+	Retention time.Duration `yaml:"retention" mapstructure:"retention" validate:"gte=0"`
+	// ParsesTTL is the duration the parses of the raiders are cached.
+	// Defaults to 15 minutes.
+	ParsesTTL time.Duration `yaml:"parsesTTL" mapstructure:"parsesTTL" validate:"gte=0"`
+	// RosterTTL is the duration the rosters of the guilds are cached.
+	// Defaults to 5 minutes.
+	RosterTTL time.Duration `yaml:"rosterTTL" mapstructure:"rosterTTL" validate:"gte=0"`
 }
 
 // NewService creates a new guild service.
-func NewService(c *Config, db *sql.DB) Service {
+// The upstream client is used for all requests to external APIs.
+func NewService(c *Config, db *sql.DB, up *upstream.Client) Service {
+	retention := c.Retention
+	if retention <= 0 {
+		retention = defaultRetention
+	}
+
 	return &guild{
-		database: db,
-		client:   NewClient(c.Client.Token, c.Client.Timeout),
+		database:  db,
+		client:    NewClient(&c.Client, up),
+		retention: retention,
+		parses:    newCache[*Parses](c.ParsesTTL, defaultParsesTTL),
+		rosters:   newCache[[]string](c.RosterTTL, defaultRosterTTL),
 	}
 }
 
@@ -88,54 +250,399 @@ func (s *guild) List(ctx context.Context) ([]repo.Guild, error) {
 }
 
 func (s *guild) Get(ctx context.Context, id snowflake.ID) (repo.Guild, error) {
-	return repo.New(s.database).GetGuild(ctx, int64(id)) //nolint:gosec // Snowflake cannot overflow AFAIK
+	q := repo.New(s.database)
+	guild, err := q.GetGuild(ctx, int64(id)) //nolint:gosec // Snowflake cannot overflow AFAIK
+	if err != nil {
+		return repo.Guild{}, svcerr.FromDB(err, svcerr.ErrNotConfigured, "")
+	}
+
+	wowGuildID, ok := WowGuildFromContext(ctx)
+	if !ok {
+		return guild, nil
+	}
+	linked, err := q.ListWowGuilds(ctx, guild.ID)
+	if err != nil {
+		return repo.Guild{}, err
+	}
+	return selectWowGuild(guild, linked, wowGuildID)
 }
 
 func (s *guild) Create(ctx context.Context, ngp repo.NewGuildParams) error {
-	return repo.New(s.database).NewGuild(ctx, ngp)
+	settings, err := s.verify(ctx, ngp.Name, cmp.Or(ngp.ServerRealm, ngp.ServerName), ngp.ServerRegion, ngp.Faction)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.database.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	q := repo.New(s.database).WithTx(tx)
+	err = q.NewGuild(ctx, repo.NewGuildParams{
+		ID:           ngp.ID,
+		Name:         settings.name,
+		ServerName:   settings.realm,
+		ServerRegion: settings.region,
+		ServerRealm:  settings.realm,
+		Faction:      settings.faction,
+	})
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return svcerr.WrapInvalid(err, svcerr.Msg("errors.invalid.already_set_up"))
+	}
+	if err != nil {
+		return err
+	}
+
+	err = q.AddWowGuild(ctx, repo.AddWowGuildParams{
+		GuildID:   ngp.ID,
+		Name:      settings.name,
+		Realm:     settings.realm,
+		Region:    settings.region,
+		Faction:   settings.faction,
+		IsDefault: true,
+	})
+	if err != nil {
+		return fmt.Errorf("error linking guild: %w", err)
+	}
+	return tx.Commit()
 }
 
 func (s *guild) Update(ctx context.Context, ugp repo.UpdateGuildParams) error {
 	return repo.New(s.database).UpdateGuild(ctx, ugp)
 }
 
-func (s *guild) Delete(ctx context.Context, id snowflake.ID) error {
-	return repo.New(s.database).DeleteGuild(ctx, int64(id)) //nolint:gosec // Snowflake cannot overflow AFAIK
+func (s *guild) SetAnnouncementChannel(ctx context.Context, guildID, channelID snowflake.ID) error {
+	n, err := repo.New(s.database).SetGuildAnnouncementChannel(ctx, repo.SetGuildAnnouncementChannelParams{
+		AnnouncementChannelID: sql.NullInt64{Int64: int64(channelID), Valid: true}, //nolint:gosec // Snowflake cannot overflow AFAIK
+		ID:                    int64(guildID),                                      //nolint:gosec // Snowflake cannot overflow AFAIK
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return svcerr.New(svcerr.ErrNotConfigured, "")
+	}
+	return nil
+}
+
+func (s *guild) SetRaiderRole(ctx context.Context, guildID, roleID snowflake.ID) error {
+	n, err := repo.New(s.database).SetGuildRaiderRole(ctx, repo.SetGuildRaiderRoleParams{
+		RaiderRoleID: sql.NullInt64{Int64: int64(roleID), Valid: true}, //nolint:gosec // Snowflake cannot overflow AFAIK
+		ID:           int64(guildID),                                   //nolint:gosec // Snowflake cannot overflow AFAIK
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return svcerr.New(svcerr.ErrNotConfigured, "")
+	}
+	return nil
+}
+
+func (s *guild) GetLocale(ctx context.Context, guildID snowflake.ID) (string, error) {
+	locale, err := repo.New(s.database).GetGuildLocale(ctx, int64(guildID)) //nolint:gosec // Snowflake cannot overflow AFAIK
+	if err != nil {
+		return "", svcerr.FromDB(err, svcerr.ErrNotConfigured, "")
+	}
+	return locale.String, nil
+}
+
+func (s *guild) SetLocale(ctx context.Context, guildID snowflake.ID, locale string) error {
+	n, err := repo.New(s.database).SetGuildLocale(ctx, repo.SetGuildLocaleParams{
+		Locale: sql.NullString{String: locale, Valid: locale != ""},
+		ID:     int64(guildID), //nolint:gosec // Snowflake cannot overflow AFAIK
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return svcerr.New(svcerr.ErrNotConfigured, "")
+	}
+	return nil
+}
+
+func (s *guild) SetSchedule(ctx context.Context, guildID snowflake.ID, schedule Schedule) error {
+	raidHours := schedule.RaidStart != 0 || schedule.RaidEnd != 0
+	n, err := repo.New(s.database).SetGuildSchedule(ctx, repo.SetGuildScheduleParams{
+		Timezone:  sql.NullString{String: schedule.location().String(), Valid: true},
+		RaidStart: sql.NullInt16{Int16: int16(schedule.RaidStart / time.Minute), Valid: raidHours}, //nolint:gosec // A day has less than 1440 minutes
+		RaidEnd:   sql.NullInt16{Int16: int16(schedule.RaidEnd / time.Minute), Valid: raidHours},   //nolint:gosec // A day has less than 1440 minutes
+		ID:        int64(guildID),                                                                  //nolint:gosec // Snowflake cannot overflow AFAIK
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return svcerr.New(svcerr.ErrNotConfigured, "")
+	}
+	return nil
+}
+
+func (s *guild) Reset(ctx context.Context, id snowflake.ID) error {
+	gid := int64(id) //nolint:gosec // Snowflake cannot overflow AFAIK
+	tx, err := s.database.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	q := repo.New(s.database).WithTx(tx)
+	n, err := q.ResetGuildSettings(ctx, gid)
+	if err != nil {
+		return fmt.Errorf("error resetting settings: %w", err)
+	}
+	if n == 0 {
+		return svcerr.New(svcerr.ErrNotConfigured, "")
+	}
+	_, err = q.DeleteOtherWowGuilds(ctx, gid)
+	if err != nil {
+		return fmt.Errorf("error deleting linked guilds: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (s *guild) Leave(ctx context.Context, id snowflake.ID) error {
+	return s.setLeftAt(ctx, id, sql.NullTime{Time: time.Now(), Valid: true})
+}
+
+func (s *guild) Rejoin(ctx context.Context, id snowflake.ID) error {
+	return s.setLeftAt(ctx, id, sql.NullTime{})
+}
+
+// setLeftAt sets the time the bot left the Discord server.
+func (s *guild) setLeftAt(ctx context.Context, id snowflake.ID, leftAt sql.NullTime) error {
+	n, err := repo.New(s.database).SetGuildLeftAt(ctx, repo.SetGuildLeftAtParams{
+		LeftAt: leftAt,
+		ID:     int64(id), //nolint:gosec // Snowflake cannot overflow AFAIK
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return svcerr.New(svcerr.ErrNotConfigured, "")
+	}
+	return nil
+}
+
+func (s *guild) Purge(ctx context.Context, id snowflake.ID) (Purged, error) {
+	gid := int64(id) //nolint:gosec // Snowflake cannot overflow AFAIK
+	tx, err := s.database.BeginTx(ctx, nil)
+	if err != nil {
+		return Purged{}, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	q := repo.New(s.database).WithTx(tx)
+	purged := Purged{GuildID: id}
+	purged.Characters, err = q.DeleteGuildCharacters(ctx, gid)
+	if err != nil {
+		return Purged{}, fmt.Errorf("error deleting characters: %w", err)
+	}
+	purged.Credentials, err = q.DeleteGuildCredentials(ctx, gid)
+	if err != nil {
+		return Purged{}, fmt.Errorf("error deleting credentials: %w", err)
+	}
+	purged.ConsumableAudits, err = q.DeleteGuildConsumableAudits(ctx, gid)
+	if err != nil {
+		return Purged{}, fmt.Errorf("error deleting consumable audits: %w", err)
+	}
+	purged.MplusSnapshots, err = q.DeleteGuildMplusSnapshots(ctx, gid)
+	if err != nil {
+		return Purged{}, fmt.Errorf("error deleting Mythic+ snapshots: %w", err)
+	}
+	purged.WowGuilds, err = q.DeleteGuildWowGuilds(ctx, gid)
+	if err != nil {
+		return Purged{}, fmt.Errorf("error deleting linked guilds: %w", err)
+	}
+	_, err = q.DeleteGuild(ctx, gid)
+	if err != nil {
+		return Purged{}, fmt.Errorf("error deleting guild: %w", err)
+	}
+	return purged, tx.Commit()
+}
+
+func (s *guild) PurgeLeft(ctx context.Context) ([]Purged, error) {
+	ids, err := repo.New(s.database).ListLeftGuilds(ctx, sql.NullTime{Time: time.Now().Add(-s.retention), Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("error listing left guilds: %w", err)
+	}
+
+	purged := make([]Purged, 0, len(ids))
+	for _, id := range ids {
+		p, err := s.Purge(ctx, snowflake.ID(id)) //nolint:gosec // Snowflake cannot overflow AFAIK
+		if err != nil {
+			return purged, fmt.Errorf("error purging guild %d: %w", id, err)
+		}
+		purged = append(purged, p)
+	}
+	return purged, nil
 }
 
 func (s *guild) GetCredentials(ctx context.Context, gcp repo.GetCredentialsParams) (repo.Credential, error) {
-	return repo.New(s.database).GetCredentials(ctx, gcp)
+	credentials, err := repo.New(s.database).GetCredentials(ctx, gcp)
+	if err != nil {
+		return repo.Credential{}, svcerr.FromDB(err, svcerr.ErrNotFound, gcp.Name)
+	}
+	return credentials, nil
 }
 
 func (s *guild) SetCredentials(ctx context.Context, scp repo.SetCredentialsParams) error {
 	return repo.New(s.database).SetCredentials(ctx, scp)
 }
 
-func (s *guild) GetReports(ctx context.Context, guildID snowflake.ID, date time.Time) ([]string, error) {
-	guild, err := repo.New(s.database).GetGuild(ctx, int64(guildID)) //nolint:gosec // Snowflake cannot overflow AFAIK
+func (s *guild) ListCredentials(ctx context.Context, guildID snowflake.ID) ([]string, error) {
+	return repo.New(s.database).ListCredentialNames(ctx, int64(guildID)) //nolint:gosec // Snowflake cannot overflow AFAIK
+}
+
+func (s *guild) GetReports(ctx context.Context, guildID snowflake.ID, period string) (*Reports, error) {
+	guild, err := s.Get(ctx, guildID)
 	if err != nil {
 		return nil, fmt.Errorf("error getting guild: %w", err)
 	}
 
-	reports, err := s.client.FetchReports(ctx, guild, date)
+	schedule := scheduleOf(guild)
+	p, err := ParsePeriod(period, schedule.Today(time.Now()))
 	if err != nil {
-		return nil, fmt.Errorf("error fetching reports: %w", err)
+		return nil, err
 	}
 
-	var reportUrls []string
+	start, end := schedule.Span(p)
+	reports, err := s.client.fetchReports(ctx, guild, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching reports: %w", svcerr.FromUpstream(err, guild.Name))
+	}
+
+	res := &Reports{Period: p}
 	for _, r := range reports {
-		reportUrls = append(reportUrls, fmt.Sprintf("%s/reports/%s", logsBaseURL, r.Id))
+		f, err := s.client.fetchFights(ctx, r.Id)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching fights of report %q: %w", r.Id, svcerr.FromUpstream(err, r.Id))
+		}
+		f.report = r
+		res.Reports = append(res.Reports, f.summary(s.client.ReportURL(r.Id)))
 	}
+	return res, nil
+}
 
-	return reportUrls, nil
+func (s *guild) GetReport(ctx context.Context, guildID snowflake.ID, code string) (*Report, error) {
+	code, err := reportCode(code)
+	if err != nil {
+		return nil, err
+	}
+	guild, err := s.Get(ctx, guildID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting guild: %w", err)
+	}
+
+	f, err := s.guildFights(ctx, guild, code)
+	if err != nil {
+		return nil, err
+	}
+	r := f.summary(s.client.ReportURL(code))
+	return &r, nil
+}
+
+// guildFights returns the pulls and the participants of the report with the given code.
+// Warcraft Logs does not tell the guild of a report, so the report is looked up in the reports
+// the guild uploaded while it was logged. It returns an [svcerr.ErrInvalidInput] error if the guild did not upload it.
+func (s *guild) guildFights(ctx context.Context, guild repo.Guild, code string) (*fights, error) {
+	f, err := s.client.fetchFights(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching fights of report %q: %w", code, svcerr.FromUpstream(err, code))
+	}
+
+	reports, err := s.client.fetchReports(ctx, guild, time.UnixMilli(int64(f.Start)), time.UnixMilli(int64(f.End)))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching reports: %w", svcerr.FromUpstream(err, guild.Name))
+	}
+	if !slices.ContainsFunc(reports, func(r report) bool { return r.Id == code }) {
+		return nil, svcerr.Invalid(svcerr.Msg("errors.invalid.report_of_other_guild", code, guild.Name))
+	}
+	return f, nil
+}
+
+func (s *guild) GetAttendance(ctx context.Context, guildID snowflake.ID, character string, since time.Time) (*Attendance, error) {
+	guild, err := s.Get(ctx, guildID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting guild: %w", err)
+	}
+
+	reports, err := s.client.fetchReports(ctx, guild, since, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("error fetching reports: %w", svcerr.FromUpstream(err, guild.Name))
+	}
+
+	attendance := &Attendance{Character: character, Since: since, Raids: len(reports)}
+	for _, r := range reports {
+		participants, err := s.client.FetchParticipants(ctx, r.Id)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching participants of report %q: %w", r.Id, svcerr.FromUpstream(err, r.Id))
+		}
+		if slices.ContainsFunc(participants, func(p string) bool { return strings.EqualFold(p, character) }) {
+			attendance.Attended++
+		}
+	}
+	return attendance, nil
 }
 
 func (s *guild) GetProfile(ctx context.Context, req *RequestProfile) (*Profiles, error) {
+	if req.Type == "user" && req.User == "" {
+		return nil, svcerr.Invalid(svcerr.Msg("errors.invalid.username_required"))
+	}
+
 	guild, err := s.Get(ctx, req.GuildID)
 	if err != nil {
 		return nil, fmt.Errorf("error getting guild: %w", err)
 	}
 	req.guild = guild
 
-	return s.client.FetchProfile(ctx, req)
+	profile, err := s.client.FetchProfile(ctx, req)
+	if err != nil {
+		name := req.User
+		if req.Type == "guild" {
+			name = guild.Name
+		}
+		return nil, fmt.Errorf("error fetching profile: %w", svcerr.FromUpstream(err, name))
+	}
+
+	// The portrait is a decoration, so the profile is returned without it if the media cannot be fetched.
+	if profile.IsUser() && s.client.battleNet.enabled() {
+		p := profile.UserProfile
+		media, err := s.client.FetchCharacterMedia(ctx, p.Region, p.Realm, p.Name)
+		if err == nil {
+			p.Portrait = media.Asset(MediaAvatar)
+		}
+	}
+	return profile, nil
+}
+
+func (s *guild) GetRoster(ctx context.Context, guildID snowflake.ID) ([]string, error) {
+	guild, err := s.Get(ctx, guildID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting guild: %w", err)
+	}
+	return s.roster(ctx, guild)
+}
+
+// roster returns the names of the characters in the roster of the guild from the cache or Raider.IO.
+func (s *guild) roster(ctx context.Context, guild repo.Guild) ([]string, error) {
+	key := guildKey(guild)
+	if names, ok := s.rosters.get(key); ok {
+		return names, nil
+	}
+
+	members, err := s.client.FetchMembers(ctx, guild)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching roster: %w", svcerr.FromUpstream(err, guild.Name))
+	}
+
+	names := make([]string, 0, len(members))
+	for _, m := range members {
+		names = append(names, m.Character.Name)
+	}
+	s.rosters.put(key, names)
+	return names, nil
 }
@@ -0,0 +1,71 @@
+package guild
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lvlcn-t/raid-mate/app/database/repo"
+	"github.com/lvlcn-t/raid-mate/app/fakeupstream"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+	"github.com/lvlcn-t/raid-mate/app/services/upstream"
+)
+
+// raidMate is the guild whose data is served by the fake upstream server.
+var raidMate = repo.Guild{ID: 1, Name: "Raid Mate", ServerName: "Draenor", ServerRealm: "Draenor", ServerRegion: "eu"}
+
+// newTestGuild returns a guild service whose client requests the fixtures of a fake upstream server.
+// The server is closed when the test finishes.
+func newTestGuild(t *testing.T) *guild {
+	t.Helper()
+	srv := httptest.NewServer(fakeupstream.New(nil))
+	t.Cleanup(srv.Close)
+	return &guild{client: NewClient(&ClientConfig{
+		LogsURL:    srv.URL,
+		ProfileURL: srv.URL,
+		BattleNet:  BattleNetConfig{ClientID: "raid-mate", APIURL: srv.URL, OAuthURL: srv.URL},
+	}, upstream.New(&upstream.Config{}))}
+}
+
+func TestGuild_guildFights(t *testing.T) {
+	s := newTestGuild(t)
+
+	tests := []struct {
+		name    string
+		code    string
+		wantErr error
+	}{
+		{name: "report of the guild", code: "a1B2c3D4e5F6g7H8"},
+		{name: "report of another guild", code: "q1w2e3r4t5y6u7i8", wantErr: svcerr.ErrInvalidInput},
+		{name: "unknown report", code: "unknown", wantErr: svcerr.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := s.guildFights(context.Background(), raidMate, tt.code)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("guildFights() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("guildFights() error = %v", err)
+			}
+			if len(f.Fights) == 0 {
+				t.Error("guildFights() returned no pulls")
+			}
+		})
+	}
+}
+
+// fetchTestMembers returns the members of the guild served by the fake upstream server of the service.
+func fetchTestMembers(t *testing.T, s *guild, g repo.Guild) []Member {
+	t.Helper()
+	members, err := s.client.FetchMembers(context.Background(), g)
+	if err != nil {
+		t.Fatalf("FetchMembers() error = %v", err)
+	}
+	return members
+}
@@ -0,0 +1,376 @@
+package guild
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lvlcn-t/raid-mate/app/database/repo"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+	"github.com/lvlcn-t/raid-mate/app/services/upstream"
+)
+
+// The roles the Mythic+ leaderboard can be filtered by.
+const (
+	RoleTank   = "tank"
+	RoleHealer = "healer"
+	RoleDPS    = "dps"
+)
+
+// Roles are the roles the Mythic+ leaderboard can be filtered by.
+var Roles = []string{RoleTank, RoleHealer, RoleDPS}
+
+// weeklyResets are the weekly resets per region as duration since the start of the week on Sunday 00:00 UTC.
+var weeklyResets = map[string]time.Duration{
+	// The US reset on Tuesday at 15:00 UTC.
+	"us": 2*24*time.Hour + 15*time.Hour,
+	// The EU reset on Wednesday at 04:00 UTC.
+	"eu": 3*24*time.Hour + 4*time.Hour,
+}
+
+// WeeklyReset returns the last weekly reset of the region at or before the given time.
+// Regions other than the EU reset at the time of the US.
+func WeeklyReset(region string, t time.Time) time.Time {
+	offset, ok := weeklyResets[strings.ToLower(region)]
+	if !ok {
+		offset = weeklyResets["us"]
+	}
+
+	t = t.UTC()
+	reset := time.Date(t.Year(), t.Month(), t.Day()-int(t.Weekday()), 0, 0, 0, 0, time.UTC).Add(offset)
+	if reset.After(t) {
+		reset = reset.AddDate(0, 0, -7)
+	}
+	return reset
+}
+
+// Leaderboard is the Mythic+ leaderboard of the raiders of a guild in the current season.
+type Leaderboard struct {
+	// Season is the slug of the season, e.g. "season-tww-1".
+	Season string `json:"season"`
+	// Role is the role the characters are ranked by, one of the [Roles].
+	// If it is empty, the characters are ranked by their overall score.
+	Role string `json:"role,omitempty"`
+	// Since is the time of the snapshot the gains are relative to.
+	// It is zero if there is no snapshot of the season yet.
+	Since time.Time `json:"since,omitzero"`
+	// Players are the characters with a score, the best first.
+	Players []LeaderboardEntry `json:"players"`
+}
+
+// LeaderboardEntry is a character in the Mythic+ leaderboard.
+type LeaderboardEntry struct {
+	// Character is the name of the character.
+	Character string `json:"character"`
+	// Realm is the realm of the character.
+	Realm string `json:"realm"`
+	// Class is the class of the character.
+	Class string `json:"class"`
+	// Spec is the active spec of the character.
+	Spec string `json:"spec"`
+	// Score is the score of the character in the role of the leaderboard.
+	Score float64 `json:"score"`
+	// Gain is the score gained since the snapshot of the leaderboard.
+	// It is nil if the character had no score in the snapshot.
+	Gain *float64 `json:"gain,omitempty"`
+}
+
+// WeeklyLeaderboard is the leaderboard of a guild taken as snapshot after the weekly reset.
+type WeeklyLeaderboard struct {
+	// Guild is the Discord server the leaderboard belongs to.
+	Guild repo.Guild
+	// Leaderboard is the leaderboard with the gains since the previous snapshot.
+	Leaderboard *Leaderboard
+}
+
+// ScoreSnapshot is the Mythic+ score of a character in a snapshot of the leaderboard.
+type ScoreSnapshot struct {
+	// Season is the slug of the season of the snapshot.
+	Season string `json:"season"`
+	// TakenAt is the time the snapshot was taken.
+	TakenAt time.Time `json:"taken_at"`
+	// Character is the name of the character.
+	Character string `json:"character"`
+	// Realm is the realm of the character.
+	Realm string `json:"realm"`
+	// Score is the overall score of the character.
+	Score float64 `json:"score"`
+	// Tank is the score of the character as tank.
+	Tank float64 `json:"tank"`
+	// Healer is the score of the character as healer.
+	Healer float64 `json:"healer"`
+	// DPS is the score of the character as damage dealer.
+	DPS float64 `json:"dps"`
+}
+
+// characterScores are the current Mythic+ scores of a character of the roster.
+type characterScores struct {
+	// name is the name of the character.
+	name string
+	// realm is the realm of the character.
+	realm string
+	// class is the class of the character.
+	class string
+	// spec is the active spec of the character.
+	spec string
+	// scores are the scores of the character in the current season.
+	scores Scores
+}
+
+// role returns the score in the role or the overall score if no role is given.
+func (s *Scores) role(role string) float64 {
+	switch role {
+	case RoleTank:
+		return s.Tank
+	case RoleHealer:
+		return s.Healer
+	case RoleDPS:
+		return s.Dps
+	default:
+		return s.All
+	}
+}
+
+// scoresOf returns the scores stored in the snapshot.
+func scoresOf(s *repo.MplusScore) Scores {
+	return Scores{All: s.ScoreAll, Tank: s.ScoreTank, Healer: s.ScoreHealer, Dps: s.ScoreDps}
+}
+
+// validateRole returns an [svcerr.ErrInvalidInput] error if the role is neither empty nor one of the [Roles].
+func validateRole(role string) error {
+	if role != "" && !slices.Contains(Roles, role) {
+		return svcerr.Invalid(svcerr.Msg("errors.invalid.role", strings.Join(Roles, ", ")))
+	}
+	return nil
+}
+
+func (s *guild) GetLeaderboard(ctx context.Context, guildID snowflake.ID, role string) (*Leaderboard, error) {
+	role = strings.ToLower(strings.TrimSpace(role))
+	if err := validateRole(role); err != nil {
+		return nil, err
+	}
+	guild, err := s.Get(ctx, guildID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting guild: %w", err)
+	}
+
+	season, current, err := s.fetchScores(ctx, guild)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, previous, err := s.latestSnapshot(ctx, guild.ID)
+	if err != nil {
+		return nil, err
+	}
+	return newLeaderboard(season, role, current, snapshot, previous), nil
+}
+
+func (s *guild) GetScoreHistory(ctx context.Context, guildID snowflake.ID, character string) ([]ScoreSnapshot, error) {
+	guild, err := s.Get(ctx, guildID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting guild: %w", err)
+	}
+
+	character = strings.TrimSpace(character)
+	rows, err := repo.New(s.database).ListMplusScoreHistory(ctx, repo.ListMplusScoreHistoryParams{GuildID: guild.ID, Name: character})
+	if err != nil {
+		return nil, fmt.Errorf("error listing snapshots: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, svcerr.New(svcerr.ErrNotFound, character)
+	}
+
+	history := make([]ScoreSnapshot, 0, len(rows))
+	for _, r := range rows {
+		history = append(history, ScoreSnapshot{
+			Season:    r.Season,
+			TakenAt:   r.TakenAt,
+			Character: r.Name,
+			Realm:     r.Realm,
+			Score:     r.ScoreAll,
+			Tank:      r.ScoreTank,
+			Healer:    r.ScoreHealer,
+			DPS:       r.ScoreDps,
+		})
+	}
+	return history, nil
+}
+
+func (s *guild) SnapshotLeaderboards(ctx context.Context, now time.Time) ([]WeeklyLeaderboard, error) {
+	guilds, err := s.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing guilds: %w", err)
+	}
+
+	var (
+		leaderboards []WeeklyLeaderboard
+		errs         []error
+	)
+	for _, g := range guilds {
+		if g.LeftAt.Valid {
+			continue
+		}
+		lb, err := s.snapshotLeaderboard(ctx, g, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error taking snapshot of guild %d: %w", g.ID, err))
+			continue
+		}
+		if lb != nil {
+			leaderboards = append(leaderboards, WeeklyLeaderboard{Guild: g, Leaderboard: lb})
+		}
+	}
+	return leaderboards, errors.Join(errs...)
+}
+
+// snapshotLeaderboard stores the current scores of the raiders of the guild
+// if no snapshot has been taken since the last weekly reset of its region.
+// It returns the leaderboard with the gains since the previous snapshot or nil if no snapshot was due.
+func (s *guild) snapshotLeaderboard(ctx context.Context, guild repo.Guild, now time.Time) (*Leaderboard, error) {
+	snapshot, previous, err := s.latestSnapshot(ctx, guild.ID)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot != nil && !snapshot.TakenAt.Before(WeeklyReset(guild.ServerRegion, now)) {
+		return nil, nil //nolint:nilnil // No snapshot is due
+	}
+
+	season, current, err := s.fetchScores(ctx, guild)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.database.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	q := repo.New(s.database).WithTx(tx)
+	id, err := q.CreateMplusSnapshot(ctx, repo.CreateMplusSnapshotParams{GuildID: guild.ID, Season: season, TakenAt: now})
+	if err != nil {
+		return nil, fmt.Errorf("error storing snapshot: %w", err)
+	}
+	for _, c := range current {
+		err = q.AddMplusScore(ctx, repo.AddMplusScoreParams{
+			SnapshotID:  id,
+			Name:        c.name,
+			Realm:       c.realm,
+			Class:       c.class,
+			Spec:        c.spec,
+			ScoreAll:    c.scores.All,
+			ScoreTank:   c.scores.Tank,
+			ScoreHealer: c.scores.Healer,
+			ScoreDps:    c.scores.Dps,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error storing score of %q: %w", c.name, err)
+		}
+	}
+	return newLeaderboard(season, "", current, snapshot, previous), tx.Commit()
+}
+
+// latestSnapshot returns the latest snapshot of the guild with its scores.
+// It returns a nil snapshot if none has been taken yet.
+func (s *guild) latestSnapshot(ctx context.Context, guildID int64) (*repo.MplusSnapshot, []repo.MplusScore, error) {
+	q := repo.New(s.database)
+	snapshot, err := q.GetLatestMplusSnapshot(ctx, guildID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting latest snapshot: %w", err)
+	}
+	scores, err := q.ListMplusScores(ctx, snapshot.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error listing scores of snapshot: %w", err)
+	}
+	return &snapshot, scores, nil
+}
+
+// fetchScores returns the current season and the scores of the raiders in the roster of the guild in it.
+// Characters unknown to Raider.IO or without a score in the current season are left out.
+func (s *guild) fetchScores(ctx context.Context, guild repo.Guild) (string, []characterScores, error) {
+	members, err := s.client.FetchMembers(ctx, guild)
+	if err != nil {
+		return "", nil, fmt.Errorf("error fetching roster: %w", svcerr.FromUpstream(err, guild.Name))
+	}
+	members = raiders(guild, members)
+
+	profiles, err := fetchEach(ctx, members, func(ctx context.Context, m Member) (*UserProfile, error) {
+		p, err := s.client.getUserProfile(ctx, &RequestProfile{User: m.Character.Name, Realm: m.Character.Realm, guild: guild})
+		if errors.Is(err, upstream.ErrNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error fetching profile of %q: %w", m.Character.Name, svcerr.FromUpstream(err, m.Character.Name))
+		}
+		return p, nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	var (
+		season string
+		scores []characterScores
+	)
+	for i, m := range members {
+		p := profiles[i]
+		if p == nil {
+			continue
+		}
+		current, ok := p.CurrentSeason()
+		if !ok || current.Scores.All <= 0 {
+			continue
+		}
+		season = cmp.Or(season, current.Season)
+		scores = append(scores, characterScores{
+			name:   p.Name,
+			realm:  cmp.Or(p.Realm, m.Character.Realm, guild.ServerRealm),
+			class:  p.Class,
+			spec:   p.ActiveSpecName,
+			scores: current.Scores,
+		})
+	}
+	return season, scores, nil
+}
+
+// newLeaderboard ranks the characters by their score in the role.
+// The gains are relative to the snapshot with the given scores if it was taken in the same season.
+func newLeaderboard(season, role string, current []characterScores, snapshot *repo.MplusSnapshot, previous []repo.MplusScore) *Leaderboard {
+	lb := &Leaderboard{Season: season, Role: role, Players: []LeaderboardEntry{}}
+
+	// before maps the characters to their score in the snapshot.
+	before := map[string]float64{}
+	if snapshot != nil && snapshot.Season == season {
+		lb.Since = snapshot.TakenAt
+		for i := range previous {
+			p := scoresOf(&previous[i])
+			before[strings.ToLower(previous[i].Name+"-"+previous[i].Realm)] = p.role(role)
+		}
+	}
+
+	for _, c := range current {
+		score := c.scores.role(role)
+		if score <= 0 {
+			continue
+		}
+		e := LeaderboardEntry{Character: c.name, Realm: c.realm, Class: c.class, Spec: c.spec, Score: score}
+		if b, ok := before[strings.ToLower(c.name+"-"+c.realm)]; ok && b > 0 {
+			gain := score - b
+			e.Gain = &gain
+		}
+		lb.Players = append(lb.Players, e)
+	}
+
+	slices.SortStableFunc(lb.Players, func(a, b LeaderboardEntry) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), strings.Compare(a.Character, b.Character))
+	})
+	return lb
+}
@@ -0,0 +1,160 @@
+package guild
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lvlcn-t/raid-mate/app/database/repo"
+)
+
+func TestWeeklyReset(t *testing.T) {
+	tests := []struct {
+		name   string
+		region string
+		t      time.Time
+		want   time.Time
+	}{
+		{
+			name:   "eu before the reset",
+			region: "eu",
+			t:      time.Date(2024, 9, 18, 3, 59, 0, 0, time.UTC),
+			want:   time.Date(2024, 9, 11, 4, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "eu at the reset",
+			region: "EU",
+			t:      time.Date(2024, 9, 18, 4, 0, 0, 0, time.UTC),
+			want:   time.Date(2024, 9, 18, 4, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "us after the reset",
+			region: "us",
+			t:      time.Date(2024, 9, 18, 3, 0, 0, 0, time.UTC),
+			want:   time.Date(2024, 9, 17, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "us on sunday",
+			region: "us",
+			t:      time.Date(2024, 9, 15, 12, 0, 0, 0, time.UTC),
+			want:   time.Date(2024, 9, 10, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "other regions reset like the us",
+			region: "kr",
+			t:      time.Date(2024, 9, 17, 14, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60)),
+			want:   time.Date(2024, 9, 10, 15, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WeeklyReset(tt.region, tt.t); !got.Equal(tt.want) {
+				t.Errorf("WeeklyReset() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewLeaderboard(t *testing.T) {
+	current := []characterScores{
+		{name: "Aerith", realm: "Draenor", class: "Paladin", spec: "Holy", scores: Scores{All: 2874, Healer: 2874}},
+		{name: "Bjorn", realm: "Draenor", class: "Warrior", spec: "Protection", scores: Scores{All: 3012.5, Tank: 3012.5, Dps: 1204}},
+		{name: "Tifa", realm: "Draenor", class: "Monk", spec: "Windwalker", scores: Scores{All: 1500, Dps: 1500}},
+	}
+	takenAt := time.Date(2024, 9, 18, 4, 0, 0, 0, time.UTC)
+	snapshot := &repo.MplusSnapshot{ID: 1, Season: "season-tww-1", TakenAt: takenAt}
+	previous := []repo.MplusScore{
+		{Name: "aerith", Realm: "Draenor", ScoreAll: 2874, ScoreHealer: 2874},
+		{Name: "Bjorn", Realm: "Draenor", ScoreAll: 2900, ScoreTank: 2900, ScoreDps: 1204},
+	}
+	gain := func(g float64) *float64 { return &g }
+
+	tests := []struct {
+		name     string
+		season   string
+		role     string
+		snapshot *repo.MplusSnapshot
+		want     *Leaderboard
+	}{
+		{
+			name:     "overall with gains",
+			season:   "season-tww-1",
+			snapshot: snapshot,
+			want: &Leaderboard{Season: "season-tww-1", Since: takenAt, Players: []LeaderboardEntry{
+				{Character: "Bjorn", Realm: "Draenor", Class: "Warrior", Spec: "Protection", Score: 3012.5, Gain: gain(112.5)},
+				{Character: "Aerith", Realm: "Draenor", Class: "Paladin", Spec: "Holy", Score: 2874, Gain: gain(0)},
+				{Character: "Tifa", Realm: "Draenor", Class: "Monk", Spec: "Windwalker", Score: 1500},
+			}},
+		},
+		{
+			name:     "by role",
+			season:   "season-tww-1",
+			role:     RoleDPS,
+			snapshot: snapshot,
+			want: &Leaderboard{Season: "season-tww-1", Role: RoleDPS, Since: takenAt, Players: []LeaderboardEntry{
+				{Character: "Tifa", Realm: "Draenor", Class: "Monk", Spec: "Windwalker", Score: 1500},
+				{Character: "Bjorn", Realm: "Draenor", Class: "Warrior", Spec: "Protection", Score: 1204, Gain: gain(0)},
+			}},
+		},
+		{
+			name:     "snapshot of another season",
+			season:   "season-tww-2",
+			role:     RoleTank,
+			snapshot: snapshot,
+			want: &Leaderboard{Season: "season-tww-2", Role: RoleTank, Players: []LeaderboardEntry{
+				{Character: "Bjorn", Realm: "Draenor", Class: "Warrior", Spec: "Protection", Score: 3012.5},
+			}},
+		},
+		{
+			name:   "without snapshot",
+			season: "season-tww-1",
+			role:   RoleHealer,
+			want: &Leaderboard{Season: "season-tww-1", Role: RoleHealer, Players: []LeaderboardEntry{
+				{Character: "Aerith", Realm: "Draenor", Class: "Paladin", Spec: "Holy", Score: 2874},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newLeaderboard(tt.season, tt.role, current, tt.snapshot, previous)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newLeaderboard() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGuild_fetchScores(t *testing.T) {
+	s := newTestGuild(t)
+
+	season, got, err := s.fetchScores(context.Background(), raidMate)
+	if err != nil {
+		t.Fatalf("fetchScores() error = %v", err)
+	}
+
+	if season != "season-tww-1" {
+		t.Errorf("fetchScores() season = %q, want %q", season, "season-tww-1")
+	}
+	// Tifa is unknown to Raider.IO and left out.
+	want := []characterScores{
+		{name: "Aerith", realm: "Draenor", class: "Paladin", spec: "Holy", scores: Scores{All: 2874, Healer: 2874, Spec0: 2874}},
+		{name: "Bjorn", realm: "Draenor", class: "Warrior", spec: "Protection", scores: Scores{All: 3012.6, Tank: 3012.6, Dps: 1204.3, Spec2: 3012.6}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("fetchScores() = %+v, want %+v", got, want)
+	}
+
+	g := raidMate
+	g.RaiderMaxRank = sql.NullInt16{Int16: 0, Valid: true}
+	_, got, err = s.fetchScores(context.Background(), g)
+	if err != nil {
+		t.Fatalf("fetchScores() error = %v", err)
+	}
+	if len(got) != 1 || got[0].name != "Aerith" {
+		t.Errorf("fetchScores() = %+v, want the scores of the guild master only", got)
+	}
+}
@@ -0,0 +1,98 @@
+package guild
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"sync"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lvlcn-t/raid-mate/app/database/repo"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+)
+
+const (
+	// MaxGuildRank is the lowest rank of a WoW guild. The ranks count from 0 for the guild master.
+	MaxGuildRank = 9
+	// memberWorkers is the maximum number of members whose data is fetched at the same time.
+	// The upstream client limits the request rate per host anyway, so more workers would mostly wait.
+	memberWorkers = 4
+)
+
+func (s *guild) SetRaiderRank(ctx context.Context, guildID snowflake.ID, rank int) error {
+	if rank > MaxGuildRank {
+		return svcerr.Invalid(svcerr.Msg("errors.invalid.raider_rank", MaxGuildRank))
+	}
+	n, err := repo.New(s.database).SetGuildRaiderMaxRank(ctx, repo.SetGuildRaiderMaxRankParams{
+		RaiderMaxRank: sql.NullInt16{Int16: int16(rank), Valid: rank >= 0}, //nolint:gosec // The rank is validated to be at most 9
+		ID:            int64(guildID),                                      //nolint:gosec // Snowflake cannot overflow AFAIK
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return svcerr.New(svcerr.ErrNotConfigured, "")
+	}
+	return nil
+}
+
+// raiders returns the members of the guild up to its lowest raider rank, e.g. without alts and bank characters.
+// All members are raiders if the guild did not set a raider rank.
+func raiders(guild repo.Guild, members []Member) []Member {
+	if !guild.RaiderMaxRank.Valid {
+		return members
+	}
+	return slices.DeleteFunc(slices.Clone(members), func(m Member) bool {
+		return m.Rank > int(guild.RaiderMaxRank.Int16)
+	})
+}
+
+// fetchEach calls fetch for every member with at most [memberWorkers] calls at the same time
+// and returns the results in the order of the members.
+// If a call fails, the calls not started yet are skipped and the first error that occurred is returned.
+func fetchEach[T any](ctx context.Context, members []Member, fetch func(ctx context.Context, m Member) (T, error)) ([]T, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		results  = make([]T, len(members))
+		next     = make(chan int)
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	for range min(memberWorkers, len(members)) {
+		wg.Go(func() {
+			for i := range next {
+				r, err := fetch(ctx, members[i])
+				if err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+						cancel()
+					}
+					mu.Unlock()
+					continue
+				}
+				results[i] = r
+			}
+		})
+	}
+
+	for i := range members {
+		if ctx.Err() != nil {
+			break
+		}
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
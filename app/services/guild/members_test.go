@@ -0,0 +1,93 @@
+package guild
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRaiders(t *testing.T) {
+	s := newTestGuild(t)
+	members := fetchTestMembers(t, s, raidMate)
+
+	tests := []struct {
+		name string
+		rank sql.NullInt16
+		want []string
+	}{
+		{name: "no raider rank", want: []string{"Aerith", "Bjorn", "Tifa"}},
+		{name: "guild master only", rank: sql.NullInt16{Int16: 0, Valid: true}, want: []string{"Aerith"}},
+		{name: "up to rank 1", rank: sql.NullInt16{Int16: 1, Valid: true}, want: []string{"Aerith", "Bjorn"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := raidMate
+			g.RaiderMaxRank = tt.rank
+
+			var got []string
+			for _, m := range raiders(g, members) {
+				got = append(got, m.Character.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("raiders() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchEach(t *testing.T) {
+	members := make([]Member, 10)
+	for i := range members {
+		members[i].Rank = i
+	}
+
+	t.Run("results in the order of the members", func(t *testing.T) {
+		var running, most atomic.Int32
+		got, err := fetchEach(context.Background(), members, func(_ context.Context, m Member) (int, error) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				old := most.Load()
+				if n <= old || most.CompareAndSwap(old, n) {
+					break
+				}
+			}
+			// The later members finish first.
+			time.Sleep(time.Duration(len(members)-m.Rank) * time.Millisecond)
+			return m.Rank, nil
+		})
+		if err != nil {
+			t.Fatalf("fetchEach() error = %v", err)
+		}
+		if want := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}; !reflect.DeepEqual(got, want) {
+			t.Errorf("fetchEach() = %v, want %v", got, want)
+		}
+		if n := most.Load(); n > memberWorkers {
+			t.Errorf("fetchEach() ran %d calls at the same time, want at most %d", n, memberWorkers)
+		}
+	})
+
+	t.Run("first error", func(t *testing.T) {
+		errBoom := errors.New("boom")
+		var calls atomic.Int32
+		_, err := fetchEach(context.Background(), members, func(ctx context.Context, m Member) (int, error) {
+			calls.Add(1)
+			if m.Rank == 0 {
+				return 0, errBoom
+			}
+			<-ctx.Done()
+			return 0, ctx.Err()
+		})
+		if !errors.Is(err, errBoom) {
+			t.Errorf("fetchEach() error = %v, want %v", err, errBoom)
+		}
+		if n := calls.Load(); n == int32(len(members)) {
+			t.Errorf("fetchEach() made all %d calls after the first one failed", n)
+		}
+	})
+}
@@ -0,0 +1,211 @@
+package guild
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lvlcn-t/raid-mate/app/database/repo"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+)
+
+// defaultParsesTTL is the default duration the parses of a period are cached.
+const defaultParsesTTL = 15 * time.Minute
+
+// The metrics characters are ranked in.
+const (
+	metricDPS = "dps"
+	metricHPS = "hps"
+)
+
+// healerSpecs are the specs ranked by their healing instead of their damage.
+var healerSpecs = map[string]struct{}{
+	"Holy":         {},
+	"Discipline":   {},
+	"Restoration":  {},
+	"Mistweaver":   {},
+	"Preservation": {},
+}
+
+// Parses are the parses of the raiders of a guild in a period of raid days.
+type Parses struct {
+	// Period is the period of raid days the parses were logged in.
+	Period Period `json:"-"`
+	// Players are the raiders with at least one ranked kill sorted by their average parse, the best first.
+	Players []PlayerParses `json:"players"`
+}
+
+// PlayerParses are the parses of a single raider.
+type PlayerParses struct {
+	// Character is the name of the character.
+	Character string `json:"character"`
+	// Server is the realm of the character.
+	Server string `json:"server"`
+	// Class is the class of the character.
+	Class string `json:"class"`
+	// Spec is the spec the character played.
+	Spec string `json:"spec"`
+	// Metric is the metric the character is ranked in, either "dps" or "hps".
+	Metric string `json:"metric"`
+	// Average is the average percentile of all bosses.
+	Average float64 `json:"average"`
+	// Bosses are the best parses per boss in the order the bosses were killed.
+	Bosses []BossParse `json:"bosses"`
+}
+
+// BossParse is the best parse of a raider on a boss.
+type BossParse struct {
+	// Boss is the name of the boss.
+	Boss string `json:"boss"`
+	// Difficulty is the name of the difficulty, e.g. "Heroic".
+	Difficulty string `json:"difficulty,omitempty"`
+	// Percentile is the percentile of the parse between 0 and 100.
+	Percentile float64 `json:"percentile"`
+	// Amount is the damage or healing per second.
+	Amount float64 `json:"amount"`
+	// Report is the code of the report the kill was logged in.
+	Report string `json:"report"`
+}
+
+// parsesKey returns the cache key of the parses of the guild in the period.
+// The schedule is part of the key, because it decides which kills belong to the period.
+func parsesKey(guild repo.Guild, schedule Schedule, p Period) string {
+	return fmt.Sprintf("%s|%s|%s", guildKey(guild), schedule, p)
+}
+
+func (s *guild) GetParses(ctx context.Context, guildID snowflake.ID, character, period string) (*Parses, error) {
+	guild, err := s.Get(ctx, guildID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting guild: %w", err)
+	}
+
+	schedule := scheduleOf(guild)
+	p, err := ParsePeriod(period, schedule.Today(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+
+	key := parsesKey(guild, schedule, p)
+	parses, ok := s.parses.get(key)
+	if !ok {
+		parses, err = s.collectParses(ctx, guild, schedule, p)
+		if err != nil {
+			return nil, err
+		}
+		s.parses.put(key, parses)
+	}
+
+	character = strings.TrimSpace(character)
+	if character == "" {
+		return parses, nil
+	}
+	i := slices.IndexFunc(parses.Players, func(pp PlayerParses) bool { return strings.EqualFold(pp.Character, character) })
+	if i < 0 {
+		return nil, svcerr.New(svcerr.ErrNotFound, character)
+	}
+	return &Parses{Period: p, Players: parses.Players[i : i+1]}, nil
+}
+
+// collectParses returns the parses of all raiders who took part in the reports the guild uploaded in the period.
+func (s *guild) collectParses(ctx context.Context, guild repo.Guild, schedule Schedule, p Period) (*Parses, error) {
+	start, end := schedule.Span(p)
+	reports, err := s.client.fetchReports(ctx, guild, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching reports: %w", svcerr.FromUpstream(err, guild.Name))
+	}
+
+	codes := map[string]struct{}{}
+	var players []friendly
+	for _, r := range reports {
+		codes[r.Id] = struct{}{}
+		f, err := s.client.fetchFights(ctx, r.Id)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching fights of report %q: %w", r.Id, svcerr.FromUpstream(err, r.Id))
+		}
+		for _, player := range f.Friendlies {
+			seen := slices.ContainsFunc(players, func(o friendly) bool {
+				return strings.EqualFold(o.Name, player.Name) && strings.EqualFold(o.Server, player.Server)
+			})
+			if player.isPlayer() && !seen {
+				players = append(players, player)
+			}
+		}
+	}
+
+	res := &Parses{Period: p}
+	for _, player := range players {
+		pp, err := s.playerParses(ctx, guild, player, codes)
+		if err != nil {
+			return nil, err
+		}
+		if len(pp.Bosses) > 0 {
+			res.Players = append(res.Players, pp)
+		}
+	}
+	slices.SortStableFunc(res.Players, func(a, b PlayerParses) int {
+		return cmp.Or(cmp.Compare(b.Average, a.Average), strings.Compare(a.Character, b.Character))
+	})
+	return res, nil
+}
+
+// playerParses returns the best parses of the player per boss in the given reports.
+// Healers are ranked by their healing, all others by their damage.
+func (s *guild) playerParses(ctx context.Context, guild repo.Guild, player friendly, codes map[string]struct{}) (PlayerParses, error) {
+	_, spec, _ := strings.Cut(player.Icon, "-")
+	pp := PlayerParses{Character: player.Name, Server: player.Server, Class: player.Type, Spec: spec, Metric: metricDPS}
+	if _, ok := healerSpecs[spec]; ok {
+		pp.Metric = metricHPS
+	}
+
+	rankings, err := s.client.fetchRankings(ctx, player.Name, cmp.Or(player.Server, guild.ServerRealm), guild.ServerRegion, pp.Metric)
+	if err != nil {
+		err = svcerr.FromUpstream(err, player.Name)
+		// Characters that have never been ranked are unknown to Warcraft Logs.
+		if errors.Is(err, svcerr.ErrNotFound) {
+			return pp, nil
+		}
+		return pp, fmt.Errorf("error fetching rankings of %q: %w", player.Name, err)
+	}
+
+	slices.SortStableFunc(rankings, func(a, b ranking) int { return cmp.Compare(a.StartTime, b.StartTime) })
+	// index maps the boss and the difficulty to the position of the best parse.
+	type key struct{ boss, difficulty int }
+	index := map[key]int{}
+	for _, r := range rankings {
+		if _, ok := codes[r.ReportID]; !ok {
+			continue
+		}
+
+		k := key{r.EncounterID, r.Difficulty}
+		i, ok := index[k]
+		if !ok {
+			i = len(pp.Bosses)
+			index[k] = i
+			pp.Bosses = append(pp.Bosses, BossParse{Percentile: -1})
+		}
+		if r.Percentile <= pp.Bosses[i].Percentile {
+			continue
+		}
+		pp.Bosses[i] = BossParse{
+			Boss:       r.EncounterName,
+			Difficulty: difficulties[r.Difficulty],
+			Percentile: r.Percentile,
+			Amount:     r.Total,
+			Report:     r.ReportID,
+		}
+	}
+
+	var sum float64
+	for _, b := range pp.Bosses {
+		sum += b.Percentile
+	}
+	if len(pp.Bosses) > 0 {
+		pp.Average = sum / float64(len(pp.Bosses))
+	}
+	return pp, nil
+}
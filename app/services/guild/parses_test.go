@@ -0,0 +1,71 @@
+package guild
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lvlcn-t/raid-mate/app/database/repo"
+)
+
+func TestGuild_collectParses(t *testing.T) {
+	s := newTestGuild(t)
+	p := Period{From: time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC), To: time.Date(2024, 5, 7, 0, 0, 0, 0, time.UTC)}
+
+	got, err := s.collectParses(context.Background(), raidMate, Schedule{}, p)
+	if err != nil {
+		t.Fatalf("collectParses() error = %v", err)
+	}
+
+	// Tifa has never been ranked and kills of other reports are ignored.
+	want := &Parses{Period: p, Players: []PlayerParses{
+		{
+			Character: "Aerith", Server: "Draenor", Class: "Priest", Spec: "Holy", Metric: metricHPS, Average: 87.4,
+			Bosses: []BossParse{
+				{Boss: "Ulgrax the Devourer", Difficulty: "Heroic", Percentile: 87.4, Amount: 412345.6, Report: "a1B2c3D4e5F6g7H8"},
+			},
+		},
+		{
+			Character: "Bjorn", Server: "Draenor", Class: "Warrior", Spec: "Fury", Metric: metricDPS, Average: 80,
+			Bosses: []BossParse{
+				{Boss: "Ulgrax the Devourer", Difficulty: "Heroic", Percentile: 64.2, Amount: 1023456.7, Report: "a1B2c3D4e5F6g7H8"},
+				{Boss: "Ulgrax the Devourer", Difficulty: "Mythic", Percentile: 95.8, Amount: 1187654.3, Report: "Z9y8X7w6V5u4T3s2"},
+			},
+		},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("collectParses() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParsesKey(t *testing.T) {
+	day := time.Date(2024, 5, 7, 0, 0, 0, 0, time.UTC)
+	p := Period{From: day, To: day}
+	schedule := Schedule{Location: time.UTC}
+	key := parsesKey(raidMate, schedule, p)
+
+	tests := []struct {
+		name     string
+		guild    repo.Guild
+		schedule Schedule
+		p        Period
+		wantSame bool
+	}{
+		{name: "same guild", guild: raidMate, schedule: schedule, p: p, wantSame: true},
+		{name: "other name", guild: repo.Guild{ID: 1, Name: "Alt Mate", ServerName: "Draenor", ServerRealm: "Draenor", ServerRegion: "eu"}, schedule: schedule, p: p},
+		{name: "other realm", guild: repo.Guild{ID: 1, Name: "Raid Mate", ServerName: "Silvermoon", ServerRealm: "Silvermoon", ServerRegion: "eu"}, schedule: schedule, p: p},
+		{name: "other region", guild: repo.Guild{ID: 1, Name: "Raid Mate", ServerName: "Draenor", ServerRealm: "Draenor", ServerRegion: "us"}, schedule: schedule, p: p},
+		{name: "other server", guild: repo.Guild{ID: 2, Name: "Raid Mate", ServerName: "Draenor", ServerRealm: "Draenor", ServerRegion: "eu"}, schedule: schedule, p: p},
+		{name: "other schedule", guild: raidMate, schedule: Schedule{Location: time.UTC, RaidStart: 20 * time.Hour, RaidEnd: 23 * time.Hour}, p: p},
+		{name: "other period", guild: raidMate, schedule: schedule, p: Period{From: day.AddDate(0, 0, -1), To: day}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parsesKey(tt.guild, tt.schedule, tt.p); (got == key) != tt.wantSame {
+				t.Errorf("parsesKey() = %q, want it to equal %q: %v", got, key, tt.wantSame)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package guild
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+)
+
+// reportCodePattern matches the codes of Warcraft Logs reports.
+var reportCodePattern = regexp.MustCompile(`^[a-zA-Z0-9]+$`)
+
+// difficulties maps the difficulty IDs of Warcraft Logs to their names.
+var difficulties = map[int]string{
+	1:  "LFR",
+	3:  "Normal",
+	4:  "Heroic",
+	5:  "Mythic",
+	10: "Mythic+",
+}
+
+// Report is a report uploaded to Warcraft Logs with a breakdown of its boss encounters.
+type Report struct {
+	// Code is the code of the report.
+	Code string `json:"code"`
+	// Title is the title of the report.
+	Title string `json:"title"`
+	// Owner is the name of the user who uploaded the report.
+	Owner string `json:"owner"`
+	// ZoneID is the ID of the zone the report was logged in.
+	ZoneID int `json:"zone_id"`
+	// Zone is the name of the zone the report was logged in.
+	// It is empty if the report contains no boss encounters.
+	Zone string `json:"zone,omitempty"`
+	// Start is the time the report started.
+	Start time.Time `json:"start"`
+	// End is the time the report ended.
+	End time.Time `json:"end"`
+	// URL is the URL of the report.
+	URL string `json:"url"`
+	// Encounters are the boss encounters of the report in the order they were first pulled.
+	Encounters []Encounter `json:"encounters"`
+}
+
+// Encounter are all pulls of a boss on one difficulty in a report.
+type Encounter struct {
+	// Boss is the name of the boss.
+	Boss string `json:"boss"`
+	// Difficulty is the name of the difficulty, e.g. "Heroic".
+	// It is empty if Warcraft Logs reports an unknown difficulty.
+	Difficulty string `json:"difficulty,omitempty"`
+	// Kill is whether the boss has been killed.
+	Kill bool `json:"kill"`
+	// BestPercent is the lowest health of the boss in percent reached by any pull.
+	// It is zero if the boss has been killed.
+	BestPercent float64 `json:"best_percent"`
+	// Duration is the combined duration of all pulls.
+	Duration time.Duration `json:"-"`
+	// Pulls is the number of pulls.
+	Pulls int `json:"pulls"`
+}
+
+// MarshalJSON encodes the encounter with its duration in seconds.
+func (e Encounter) MarshalJSON() ([]byte, error) {
+	type encounter Encounter
+	return json.Marshal(struct {
+		encounter
+		Duration float64 `json:"duration"`
+	}{encounter(e), e.Duration.Seconds()})
+}
+
+// summary returns the report with its pulls summarized as encounters.
+func (f *fights) summary(url string) Report {
+	r := Report{
+		Code:   f.Id,
+		Title:  f.Title,
+		Owner:  f.Owner,
+		ZoneID: f.Zone,
+		Start:  time.UnixMilli(int64(f.Start)).UTC(),
+		End:    time.UnixMilli(int64(f.End)).UTC(),
+		URL:    url,
+	}
+
+	// index maps the boss and the difficulty to the position of the encounter.
+	type key struct{ boss, difficulty int }
+	index := map[key]int{}
+	for _, p := range f.Fights {
+		// Trash pulls are logged as fights without a boss.
+		if p.Boss == 0 {
+			continue
+		}
+		if r.Zone == "" {
+			r.Zone = p.ZoneName
+		}
+
+		k := key{p.Boss, p.Difficulty}
+		i, ok := index[k]
+		if !ok {
+			i = len(r.Encounters)
+			index[k] = i
+			r.Encounters = append(r.Encounters, Encounter{Boss: p.Name, Difficulty: difficulties[p.Difficulty], BestPercent: 100})
+		}
+
+		e := &r.Encounters[i]
+		e.Pulls++
+		e.Duration += time.Duration(p.EndTime-p.StartTime) * time.Millisecond
+		switch {
+		case p.Kill:
+			e.Kill, e.BestPercent = true, 0
+		case !e.Kill:
+			// Warcraft Logs reports the percentage in hundredths of a percent.
+			e.BestPercent = min(e.BestPercent, float64(p.FightPercentage)/100)
+		}
+	}
+	return r
+}
+
+// reportCode returns the code of a report given either as code or as URL like https://www.warcraftlogs.com/reports/a1B2c3D4#fight=3.
+// It returns an [svcerr.ErrInvalidInput] error if the input is neither.
+func reportCode(input string) (string, error) {
+	code := strings.TrimSpace(input)
+	if _, after, ok := strings.Cut(code, "/reports/"); ok {
+		code = after
+	}
+	if i := strings.IndexAny(code, "/?#"); i >= 0 {
+		code = code[:i]
+	}
+	if !reportCodePattern.MatchString(code) {
+		return "", svcerr.Invalid(svcerr.Msg("errors.invalid.report", input))
+	}
+	return code, nil
+}
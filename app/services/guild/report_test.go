@@ -0,0 +1,79 @@
+package guild
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+)
+
+func TestFights_summary(t *testing.T) {
+	f := &fights{
+		report: report{Id: "a1B2c3D4", Title: "Nerub-ar Palace Heroic", Owner: "Aerith", Zone: 38, Start: 1715022000000, End: 1715032800000},
+		Fights: []fight{
+			{Boss: 0, Name: "Trash", ZoneName: "Nerub-ar Palace", StartTime: 0, EndTime: 60000},
+			{Boss: 2902, Name: "Ulgrax the Devourer", ZoneName: "Nerub-ar Palace", Difficulty: 4, FightPercentage: 4523, StartTime: 60000, EndTime: 360000},
+			{Boss: 2917, Name: "The Bloodbound Horror", ZoneName: "Nerub-ar Palace", Difficulty: 4, FightPercentage: 8000, StartTime: 400000, EndTime: 460000},
+			{Boss: 2902, Name: "Ulgrax the Devourer", ZoneName: "Nerub-ar Palace", Difficulty: 4, FightPercentage: 1234, StartTime: 500000, EndTime: 800000},
+			{Boss: 2902, Name: "Ulgrax the Devourer", ZoneName: "Nerub-ar Palace", Difficulty: 4, Kill: true, StartTime: 900000, EndTime: 1200000},
+			{Boss: 2917, Name: "The Bloodbound Horror", ZoneName: "Nerub-ar Palace", Difficulty: 4, FightPercentage: 6543, StartTime: 1300000, EndTime: 1400000},
+			{Boss: 2902, Name: "Ulgrax the Devourer", ZoneName: "Nerub-ar Palace", Difficulty: 5, FightPercentage: 9999, StartTime: 1500000, EndTime: 1510000},
+		},
+	}
+
+	want := Report{
+		Code:   "a1B2c3D4",
+		Title:  "Nerub-ar Palace Heroic",
+		Owner:  "Aerith",
+		ZoneID: 38,
+		Zone:   "Nerub-ar Palace",
+		Start:  time.Date(2024, 5, 6, 19, 0, 0, 0, time.UTC),
+		End:    time.Date(2024, 5, 6, 22, 0, 0, 0, time.UTC),
+		URL:    "https://www.warcraftlogs.com/reports/a1B2c3D4",
+		Encounters: []Encounter{
+			{Boss: "Ulgrax the Devourer", Difficulty: "Heroic", Kill: true, Duration: 15 * time.Minute, Pulls: 3},
+			{Boss: "The Bloodbound Horror", Difficulty: "Heroic", BestPercent: 65.43, Duration: 160 * time.Second, Pulls: 2},
+			{Boss: "Ulgrax the Devourer", Difficulty: "Mythic", BestPercent: 99.99, Duration: 10 * time.Second, Pulls: 1},
+		},
+	}
+	if got := f.summary("https://www.warcraftlogs.com/reports/a1B2c3D4"); !reflect.DeepEqual(got, want) {
+		t.Errorf("summary() = %+v, want %+v", got, want)
+	}
+}
+
+func TestReportCode(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+		// wantErr is the key of the message of the error. Empty if no error is expected.
+		wantErr string
+	}{
+		{name: "code", input: " a1B2c3D4e5F6g7H8 ", want: "a1B2c3D4e5F6g7H8"},
+		{name: "url", input: "https://www.warcraftlogs.com/reports/a1B2c3D4e5F6g7H8", want: "a1B2c3D4e5F6g7H8"},
+		{name: "url of a fight", input: "https://www.warcraftlogs.com/reports/a1B2c3D4e5F6g7H8#fight=3&type=damage-done", want: "a1B2c3D4e5F6g7H8"},
+		{name: "url with query", input: "https://www.warcraftlogs.com/reports/a1B2c3D4e5F6g7H8?fight=last", want: "a1B2c3D4e5F6g7H8"},
+		{name: "empty", input: "", wantErr: "errors.invalid.report"},
+		{name: "path traversal", input: "../../v1/zones", wantErr: "errors.invalid.report"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := reportCode(tt.input)
+			if (err != nil) != (tt.wantErr != "") {
+				t.Fatalf("reportCode() error = %v, want error: %v", err, tt.wantErr != "")
+			}
+			if err != nil {
+				if msgs := svcerr.Messages(err); !errors.Is(err, svcerr.ErrInvalidInput) || len(msgs) != 1 || msgs[0].Key != tt.wantErr {
+					t.Errorf("reportCode() error = %v, want an invalid input error with the message %q", err, tt.wantErr)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("reportCode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package guild
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	// The container image has no timezone database, so it is embedded into the binary.
+	_ "time/tzdata"
+
+	"github.com/lvlcn-t/raid-mate/app/database/repo"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+)
+
+const (
+	// MaxPeriodDays is the maximum number of raid days a period may span.
+	MaxPeriodDays = 31
+	// periodSeparator separates the first and the last day of a period.
+	periodSeparator = ".."
+	// clockLayout is the layout of the raid hours.
+	clockLayout = "15:04"
+)
+
+// Timezones are common timezones of World of Warcraft players, suggested when setting the timezone of a Discord server.
+// Any timezone of the IANA database is accepted.
+var Timezones = []string{
+	"UTC",
+	"Europe/London", "Europe/Dublin", "Europe/Lisbon", "Europe/Paris", "Europe/Berlin", "Europe/Amsterdam",
+	"Europe/Brussels", "Europe/Madrid", "Europe/Rome", "Europe/Vienna", "Europe/Zurich", "Europe/Stockholm",
+	"Europe/Oslo", "Europe/Copenhagen", "Europe/Warsaw", "Europe/Prague", "Europe/Helsinki", "Europe/Athens",
+	"Europe/Istanbul", "Europe/Moscow",
+	"America/New_York", "America/Chicago", "America/Denver", "America/Phoenix", "America/Los_Angeles",
+	"America/Anchorage", "America/Sao_Paulo", "America/Mexico_City", "Pacific/Honolulu",
+	"Australia/Perth", "Australia/Adelaide", "Australia/Brisbane", "Australia/Sydney", "Pacific/Auckland",
+	"Asia/Seoul", "Asia/Taipei", "Asia/Shanghai", "Asia/Singapore",
+}
+
+// dateLayouts are the layouts of the dates accepted in periods.
+var dateLayouts = []string{
+	time.DateOnly,
+	strings.ReplaceAll(time.DateOnly, "-", "."),
+	"02.01.2006",
+	"02-01-2006",
+}
+
+// weekdays maps the English and German names of the weekdays to them.
+var weekdays = map[string]time.Weekday{
+	"monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday, "thursday": time.Thursday,
+	"friday": time.Friday, "saturday": time.Saturday, "sunday": time.Sunday,
+	"montag": time.Monday, "dienstag": time.Tuesday, "mittwoch": time.Wednesday, "donnerstag": time.Thursday,
+	"freitag": time.Friday, "samstag": time.Saturday, "sonntag": time.Sunday,
+}
+
+// Schedule is when the raids of a guild take place.
+type Schedule struct {
+	// Location is the timezone of the guild.
+	// Defaults to UTC.
+	Location *time.Location
+	// RaidStart is the time of day raids start at as duration since midnight.
+	RaidStart time.Duration
+	// RaidEnd is the time of day raids end at as duration since midnight.
+	// If it is not after the start, raids end on the next day.
+	// If both are zero, a raid day is the whole calendar day.
+	RaidEnd time.Duration
+}
+
+// NewSchedule validates the timezone and the raid hours in the format HH:MM and returns the schedule.
+// The raid hours are optional, but either both or none of them have to be given.
+// It returns an [svcerr.ErrInvalidInput] error describing the invalid input.
+func NewSchedule(timezone, raidStart, raidEnd string) (Schedule, error) {
+	timezone = strings.TrimSpace(timezone)
+	loc, err := time.LoadLocation(timezone)
+	if err != nil || strings.EqualFold(timezone, "local") {
+		return Schedule{}, svcerr.Invalid(svcerr.Msg("errors.invalid.timezone", timezone))
+	}
+
+	raidStart, raidEnd = strings.TrimSpace(raidStart), strings.TrimSpace(raidEnd)
+	if (raidStart == "") != (raidEnd == "") {
+		return Schedule{}, svcerr.Invalid(svcerr.Msg("errors.invalid.raid_hours"))
+	}
+	s := Schedule{Location: loc}
+	if raidStart == "" {
+		return s, nil
+	}
+
+	s.RaidStart, err = parseClock(raidStart)
+	if err != nil {
+		return Schedule{}, err
+	}
+	s.RaidEnd, err = parseClock(raidEnd)
+	if err != nil {
+		return Schedule{}, err
+	}
+	return s, nil
+}
+
+// parseClock parses a time of day in the format HH:MM and returns it as duration since midnight.
+func parseClock(clock string) (time.Duration, error) {
+	t, err := time.Parse(clockLayout, clock)
+	if err != nil {
+		return 0, svcerr.Invalid(svcerr.Msg("errors.invalid.clock", clock))
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// scheduleOf returns the schedule stored for the Discord server.
+// Timezones are validated before they are stored, so an unknown timezone falls back to UTC.
+func scheduleOf(g repo.Guild) Schedule {
+	loc, err := time.LoadLocation(g.Timezone.String)
+	if err != nil {
+		loc = time.UTC
+	}
+	return Schedule{
+		Location:  loc,
+		RaidStart: time.Duration(g.RaidStart.Int16) * time.Minute,
+		RaidEnd:   time.Duration(g.RaidEnd.Int16) * time.Minute,
+	}
+}
+
+// location returns the timezone of the schedule.
+func (s Schedule) location() *time.Location {
+	if s.Location == nil {
+		return time.UTC
+	}
+	return s.Location
+}
+
+// crossesMidnight reports whether raids end on the day after they started.
+func (s Schedule) crossesMidnight() bool {
+	return s.RaidEnd > 0 && s.RaidEnd <= s.RaidStart
+}
+
+// Today returns the raid day at the given time as date in UTC.
+// While a raid that crosses midnight is still running, the raid day is the day before.
+func (s Schedule) Today(now time.Time) time.Time {
+	local := now.In(s.location())
+	y, m, d := local.Date()
+	clock := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+	if s.crossesMidnight() && clock < s.RaidEnd {
+		d--
+	}
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// Window returns the start and the end of the raids on the given day.
+func (s Schedule) Window(day time.Time) (start, end time.Time) {
+	y, m, d := day.Date()
+	start = s.at(y, m, d, s.RaidStart)
+	if s.RaidEnd <= s.RaidStart {
+		d++
+	}
+	return start, s.at(y, m, d, s.RaidEnd)
+}
+
+// at returns the time of day on the given date in the timezone of the schedule.
+func (s Schedule) at(year int, month time.Month, day int, clock time.Duration) time.Time {
+	return time.Date(year, month, day, int(clock/time.Hour), int(clock%time.Hour/time.Minute), 0, 0, s.location())
+}
+
+// Span returns the start of the first and the end of the last raid of the period.
+func (s Schedule) Span(p Period) (start, end time.Time) {
+	start, _ = s.Window(p.From)
+	_, end = s.Window(p.To)
+	return start, end
+}
+
+// String returns the timezone and the raid hours of the schedule.
+func (s Schedule) String() string {
+	if s.RaidStart == 0 && s.RaidEnd == 0 {
+		return s.location().String()
+	}
+	midnight := time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC)
+	return fmt.Sprintf("%s, %s-%s", s.location(), midnight.Add(s.RaidStart).Format(clockLayout), midnight.Add(s.RaidEnd).Format(clockLayout))
+}
+
+// Period is a span of raid days.
+type Period struct {
+	// From is the first raid day as date in UTC.
+	From time.Time
+	// To is the last raid day as date in UTC.
+	// It equals From if the period is a single day.
+	To time.Time
+}
+
+// ParsePeriod parses a single raid day or a range of raid days separated by "..", relative to the given raid day.
+// A day is either a date like 2024-05-07, "today", "yesterday", a weekday like "wednesday"
+// for the last one including today, or "last wednesday" for the last one before today.
+// An empty input is the given day itself.
+// It returns an [svcerr.ErrInvalidInput] error if the input is invalid or the period spans more than [MaxPeriodDays] days.
+func ParsePeriod(input string, today time.Time) (Period, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	if input == "" {
+		return Period{From: today, To: today}, nil
+	}
+
+	first, last, isRange := strings.Cut(input, periodSeparator)
+	from, err := parseDay(first, today)
+	if err != nil {
+		return Period{}, err
+	}
+	to := from
+	if isRange {
+		to, err = parseDay(last, today)
+		if err != nil {
+			return Period{}, err
+		}
+	}
+
+	switch {
+	case to.Before(from):
+		return Period{}, svcerr.Invalid(svcerr.Msg("errors.invalid.period_order"))
+	case to.Sub(from) >= MaxPeriodDays*24*time.Hour:
+		return Period{}, svcerr.Invalid(svcerr.Msg("errors.invalid.period_length", MaxPeriodDays))
+	}
+	return Period{From: from, To: to}, nil
+}
+
+// parseDay parses a single raid day relative to the given raid day.
+func parseDay(input string, today time.Time) (time.Time, error) {
+	input = strings.TrimSpace(input)
+	switch input {
+	case "today", "heute":
+		return today, nil
+	case "yesterday", "gestern":
+		return today.AddDate(0, 0, -1), nil
+	}
+
+	if words := strings.Fields(input); len(words) == 1 || len(words) == 2 {
+		wd, ok := weekdays[words[len(words)-1]]
+		switch {
+		case !ok:
+		case len(words) == 1:
+			return lastWeekday(today, wd, 0), nil
+		case words[0] == "last" || strings.HasPrefix(words[0], "letzte"):
+			return lastWeekday(today, wd, 1), nil
+		}
+	}
+
+	for _, layout := range dateLayouts {
+		d, err := time.Parse(layout, input)
+		if err == nil {
+			return d, nil
+		}
+	}
+	return time.Time{}, svcerr.Invalid(svcerr.Msg("errors.invalid.date", input))
+}
+
+// lastWeekday returns the last given weekday at least skip days before the given day.
+func lastWeekday(day time.Time, wd time.Weekday, skip int) time.Time {
+	day = day.AddDate(0, 0, -skip)
+	return day.AddDate(0, 0, -((int(day.Weekday()) - int(wd) + 7) % 7))
+}
+
+// String returns the period as a single date or as a range of dates.
+func (p Period) String() string {
+	if p.From.Equal(p.To) {
+		return p.From.Format(time.DateOnly)
+	}
+	return p.From.Format(time.DateOnly) + periodSeparator + p.To.Format(time.DateOnly)
+}
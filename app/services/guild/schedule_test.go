@@ -0,0 +1,170 @@
+package guild
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+)
+
+// date returns the given date in UTC.
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParsePeriod(t *testing.T) {
+	// today is a Wednesday.
+	today := date(2024, 5, 8)
+	tests := []struct {
+		name     string
+		input    string
+		from, to time.Time
+		// wantErr is the key of the message of the error. Empty if no error is expected.
+		wantErr string
+	}{
+		{name: "empty", input: "", from: today, to: today},
+		{name: "today", input: "today", from: today, to: today},
+		{name: "yesterday", input: " Yesterday ", from: date(2024, 5, 7), to: date(2024, 5, 7)},
+		{name: "weekday including today", input: "wednesday", from: today, to: today},
+		{name: "weekday", input: "monday", from: date(2024, 5, 6), to: date(2024, 5, 6)},
+		{name: "last weekday", input: "last wednesday", from: date(2024, 5, 1), to: date(2024, 5, 1)},
+		{name: "german weekday", input: "letzten Montag", from: date(2024, 5, 6), to: date(2024, 5, 6)},
+		{name: "date", input: "07.05.2024", from: date(2024, 5, 7), to: date(2024, 5, 7)},
+		{name: "range of dates", input: "2024.05.01..2024-05-07", from: date(2024, 5, 1), to: date(2024, 5, 7)},
+		{name: "relative range", input: "last monday..today", from: date(2024, 5, 6), to: today},
+		{name: "range ending before it starts", input: "2024-05-07..2024-05-01", wantErr: "errors.invalid.period_order"},
+		{name: "range too long", input: "2024-01-01..2024-05-07", wantErr: "errors.invalid.period_length"},
+		{name: "open range", input: "2024-05-01..", wantErr: "errors.invalid.date"},
+		{name: "unknown day", input: "someday", wantErr: "errors.invalid.date"},
+		{name: "last without weekday", input: "last", wantErr: "errors.invalid.date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePeriod(tt.input, today)
+			if (err != nil) != (tt.wantErr != "") {
+				t.Fatalf("ParsePeriod() error = %v, want error: %v", err, tt.wantErr != "")
+			}
+			if err != nil {
+				if msgs := svcerr.Messages(err); !errors.Is(err, svcerr.ErrInvalidInput) || len(msgs) != 1 || msgs[0].Key != tt.wantErr {
+					t.Errorf("ParsePeriod() error = %v, want an invalid input error with the message %q", err, tt.wantErr)
+				}
+				return
+			}
+			if !got.From.Equal(tt.from) || !got.To.Equal(tt.to) {
+				t.Errorf("ParsePeriod() = %s, want %s", got, Period{From: tt.from, To: tt.to})
+			}
+		})
+	}
+}
+
+func TestSchedule_Window(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		schedule   Schedule
+		start, end time.Time
+	}{
+		{
+			name:     "whole day in UTC",
+			schedule: Schedule{},
+			start:    time.Date(2024, 5, 7, 0, 0, 0, 0, time.UTC),
+			end:      time.Date(2024, 5, 8, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "whole day in the timezone of the guild",
+			schedule: Schedule{Location: berlin},
+			start:    time.Date(2024, 5, 6, 22, 0, 0, 0, time.UTC),
+			end:      time.Date(2024, 5, 7, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "raid hours",
+			schedule: Schedule{Location: berlin, RaidStart: 19*time.Hour + 30*time.Minute, RaidEnd: 23 * time.Hour},
+			start:    time.Date(2024, 5, 7, 17, 30, 0, 0, time.UTC),
+			end:      time.Date(2024, 5, 7, 21, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "raid hours crossing midnight",
+			schedule: Schedule{Location: berlin, RaidStart: 20 * time.Hour, RaidEnd: time.Hour},
+			start:    time.Date(2024, 5, 7, 18, 0, 0, 0, time.UTC),
+			end:      time.Date(2024, 5, 7, 23, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := tt.schedule.Window(date(2024, 5, 7))
+			if !start.Equal(tt.start) || !end.Equal(tt.end) {
+				t.Errorf("Window() = %s - %s, want %s - %s", start.UTC(), end.UTC(), tt.start, tt.end)
+			}
+		})
+	}
+}
+
+func TestSchedule_Today(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+	raidNight := Schedule{Location: berlin, RaidStart: 20 * time.Hour, RaidEnd: time.Hour}
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		now      time.Time
+		want     time.Time
+	}{
+		{name: "UTC before midnight", schedule: Schedule{}, now: time.Date(2024, 5, 8, 22, 30, 0, 0, time.UTC), want: date(2024, 5, 8)},
+		{name: "after midnight in the timezone of the guild", schedule: Schedule{Location: berlin}, now: time.Date(2024, 5, 8, 22, 30, 0, 0, time.UTC), want: date(2024, 5, 9)},
+		{name: "raid crossing midnight still running", schedule: raidNight, now: time.Date(2024, 5, 8, 22, 30, 0, 0, time.UTC), want: date(2024, 5, 8)},
+		{name: "raid crossing midnight is over", schedule: raidNight, now: time.Date(2024, 5, 8, 23, 30, 0, 0, time.UTC), want: date(2024, 5, 9)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.Today(tt.now); !got.Equal(tt.want) {
+				t.Errorf("Today() = %s, want %s", got.Format(time.DateOnly), tt.want.Format(time.DateOnly))
+			}
+		})
+	}
+}
+
+func TestNewSchedule(t *testing.T) {
+	tests := []struct {
+		name                         string
+		timezone, raidStart, raidEnd string
+		want                         string
+		// wantErr is the key of the message of the error. Empty if no error is expected.
+		wantErr string
+	}{
+		{name: "timezone only", timezone: "Europe/Berlin", want: "Europe/Berlin"},
+		{name: "raid hours", timezone: " America/New_York ", raidStart: "20:00", raidEnd: "1:00", want: "America/New_York, 20:00-01:00"},
+		{name: "unknown timezone", timezone: "Mars/Olympus", wantErr: "errors.invalid.timezone"},
+		{name: "local timezone", timezone: "Local", wantErr: "errors.invalid.timezone"},
+		{name: "start without end", timezone: "UTC", raidStart: "20:00", wantErr: "errors.invalid.raid_hours"},
+		{name: "invalid time", timezone: "UTC", raidStart: "20:00", raidEnd: "25:00", wantErr: "errors.invalid.clock"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewSchedule(tt.timezone, tt.raidStart, tt.raidEnd)
+			if (err != nil) != (tt.wantErr != "") {
+				t.Fatalf("NewSchedule() error = %v, want error: %v", err, tt.wantErr != "")
+			}
+			if err != nil {
+				if msgs := svcerr.Messages(err); !errors.Is(err, svcerr.ErrInvalidInput) || len(msgs) != 1 || msgs[0].Key != tt.wantErr {
+					t.Errorf("NewSchedule() error = %v, want an invalid input error with the message %q", err, tt.wantErr)
+				}
+				return
+			}
+			if got.String() != tt.want {
+				t.Errorf("NewSchedule() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,298 @@
+package guild
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lib/pq"
+	"github.com/lvlcn-t/raid-mate/app/database/repo"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+)
+
+const (
+	// foreignKeyViolation is the PostgreSQL error code for a violated foreign key constraint.
+	foreignKeyViolation = "23503"
+	// MaxWowGuilds is the maximum number of WoW guilds that can be linked to a Discord server.
+	MaxWowGuilds = 10
+	// MinNameLength is the minimum length of a WoW guild name.
+	MinNameLength = 2
+	// MaxNameLength is the maximum length of a WoW guild name.
+	MaxNameLength = 24
+	// MinRealmLength is the minimum length of a realm name.
+	MinRealmLength = 2
+	// MaxRealmLength is the maximum length of a realm name.
+	MaxRealmLength = 30
+)
+
+// Regions are the regions of World of Warcraft.
+var Regions = []string{"us", "eu", "kr", "tw", "cn"}
+
+// Factions are the factions of World of Warcraft.
+var Factions = []string{"alliance", "horde"}
+
+// realmPattern matches valid realm names like "Aman'Thul", "Die Aldor" or "Azjol-Nerub".
+var realmPattern = regexp.MustCompile(`^[\p{L}\p{N}' -]+$`)
+
+type settingsService interface {
+	// ListWowGuilds returns the WoW guilds linked to the Discord server, the default guild first.
+	// It returns an [svcerr.ErrNotConfigured] error if the server has not been set up yet.
+	ListWowGuilds(ctx context.Context, guildID snowflake.ID) ([]repo.WowGuild, error)
+	// AddWowGuild links another WoW guild to the Discord server.
+	// The guild becomes the default guild if it is the first guild of the server.
+	// The guild is looked up on Raider.IO first, so only existing guilds can be linked.
+	AddWowGuild(ctx context.Context, awp repo.AddWowGuildParams) error
+	// UpdateWowGuild updates the settings of a WoW guild linked to the Discord server.
+	// The guild is looked up on Raider.IO first, so only existing guilds can be linked.
+	UpdateWowGuild(ctx context.Context, uwp repo.UpdateWowGuildParams) error
+	// RemoveWowGuild unlinks a WoW guild from the Discord server.
+	// The default guild cannot be removed, another guild has to be made the default first.
+	RemoveWowGuild(ctx context.Context, guildID snowflake.ID, id int64) error
+	// SetDefaultWowGuild makes the WoW guild the default guild of the Discord server.
+	// The default guild is used by all commands unless another linked guild is selected, see [WithWowGuild].
+	SetDefaultWowGuild(ctx context.Context, guildID snowflake.ID, id int64) error
+}
+
+// settings are the settings of a WoW guild.
+type settings struct {
+	// name is the name of the guild.
+	name string
+	// realm is the realm of the guild.
+	realm string
+	// region is the lowercase region of the guild.
+	region string
+	// faction is the lowercase faction of the guild.
+	faction string
+}
+
+// newSettings validates and normalizes the settings of a WoW guild.
+// It returns an [svcerr.ErrInvalidInput] error describing all invalid settings.
+func newSettings(name, realm, region, faction string) (settings, error) {
+	s := settings{
+		name:    strings.TrimSpace(name),
+		realm:   strings.Join(strings.Fields(realm), " "),
+		region:  strings.ToLower(strings.TrimSpace(region)),
+		faction: strings.ToLower(strings.TrimSpace(faction)),
+	}
+
+	var problems []svcerr.Message
+	if n := utf8.RuneCountInString(s.name); n < MinNameLength || n > MaxNameLength {
+		problems = append(problems, svcerr.Msg("errors.invalid.guild_name", MinNameLength, MaxNameLength))
+	}
+	if n := utf8.RuneCountInString(s.realm); n < MinRealmLength || n > MaxRealmLength || !realmPattern.MatchString(s.realm) {
+		problems = append(problems, svcerr.Msg("errors.invalid.realm", s.realm))
+	}
+	if !slices.Contains(Regions, s.region) {
+		problems = append(problems, svcerr.Msg("errors.invalid.region", strings.ToUpper(strings.Join(Regions, ", "))))
+	}
+	if !slices.Contains(Factions, s.faction) {
+		problems = append(problems, svcerr.Msg("errors.invalid.faction", strings.Join(Factions, ", ")))
+	}
+	if len(problems) > 0 {
+		return settings{}, svcerr.Invalid(problems...)
+	}
+	return s, nil
+}
+
+// verify validates the settings of a WoW guild and looks the guild up on Raider.IO.
+// The name, realm and faction are taken from Raider.IO, so they are stored as shown in game.
+func (s *guild) verify(ctx context.Context, name, realm, region, faction string) (settings, error) {
+	settings, err := newSettings(name, realm, region, faction)
+	if err != nil {
+		return settings, err
+	}
+
+	profile, err := s.client.getGuildProfile(ctx, &RequestProfile{guild: repo.Guild{
+		Name:         settings.name,
+		ServerRegion: settings.region,
+		ServerRealm:  settings.realm,
+	}})
+	if err != nil {
+		return settings, fmt.Errorf("error looking up guild: %w", svcerr.FromUpstream(err, fmt.Sprintf("%s-%s", settings.name, settings.realm)))
+	}
+
+	settings.name = cmp.Or(profile.Name, settings.name)
+	settings.realm = cmp.Or(profile.Realm, settings.realm)
+	if f := strings.ToLower(profile.Faction); slices.Contains(Factions, f) {
+		settings.faction = f
+	}
+	return settings, nil
+}
+
+// fromWrite converts the error of a statement writing a WoW guild to an error of the [svcerr] package.
+func fromWrite(err error, s settings) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+	switch pqErr.Code {
+	case uniqueViolation:
+		return svcerr.WrapInvalid(err, svcerr.Msg("errors.invalid.guild_linked", s.name, s.realm, strings.ToUpper(s.region)))
+	case foreignKeyViolation:
+		return svcerr.Wrap(svcerr.ErrNotConfigured, err, "")
+	default:
+		return err
+	}
+}
+
+// wowGuildKey is the context key of the selected WoW guild.
+type wowGuildKey struct{}
+
+// WithWowGuild returns a copy of the context that selects the linked WoW guild with the given ID.
+// The service reads the data of the selected guild instead of the default guild of the Discord server.
+func WithWowGuild(ctx context.Context, id int64) context.Context {
+	return context.WithValue(ctx, wowGuildKey{}, id)
+}
+
+// WowGuildFromContext returns the ID of the WoW guild selected by the context, see [WithWowGuild].
+func WowGuildFromContext(ctx context.Context) (int64, bool) {
+	id, ok := ctx.Value(wowGuildKey{}).(int64)
+	return id, ok
+}
+
+// selectWowGuild returns the guild with the name, realm, region and faction of the linked WoW guild with the given ID.
+// It returns an [svcerr.ErrInvalidInput] error if the WoW guild is not linked to the Discord server.
+func selectWowGuild(g repo.Guild, linked []repo.WowGuild, id int64) (repo.Guild, error) {
+	for _, w := range linked {
+		if w.ID == id {
+			g.Name, g.ServerName, g.ServerRealm, g.ServerRegion, g.Faction = w.Name, w.Realm, w.Realm, w.Region, w.Faction
+			return g, nil
+		}
+	}
+	return repo.Guild{}, svcerr.Invalid(svcerr.Msg("errors.invalid.guild_not_linked"))
+}
+
+func (s *guild) ListWowGuilds(ctx context.Context, guildID snowflake.ID) ([]repo.WowGuild, error) {
+	guilds, err := repo.New(s.database).ListWowGuilds(ctx, int64(guildID)) //nolint:gosec // Snowflake cannot overflow AFAIK
+	if err != nil {
+		return nil, err
+	}
+	if len(guilds) == 0 {
+		return nil, svcerr.New(svcerr.ErrNotConfigured, "")
+	}
+	return guilds, nil
+}
+
+func (s *guild) AddWowGuild(ctx context.Context, awp repo.AddWowGuildParams) error {
+	settings, err := s.verify(ctx, awp.Name, awp.Realm, awp.Region, awp.Faction)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.database.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	q := repo.New(s.database).WithTx(tx)
+	linked, err := q.ListWowGuilds(ctx, awp.GuildID)
+	if err != nil {
+		return fmt.Errorf("error listing guilds: %w", err)
+	}
+	if len(linked) >= MaxWowGuilds {
+		return svcerr.Invalid(svcerr.Msg("errors.invalid.too_many_guilds", MaxWowGuilds))
+	}
+
+	err = q.AddWowGuild(ctx, repo.AddWowGuildParams{
+		GuildID:   awp.GuildID,
+		Name:      settings.name,
+		Realm:     settings.realm,
+		Region:    settings.region,
+		Faction:   settings.faction,
+		IsDefault: len(linked) == 0,
+	})
+	if err != nil {
+		return fromWrite(err, settings)
+	}
+
+	err = q.SyncDefaultWowGuild(ctx, awp.GuildID)
+	if err != nil {
+		return fmt.Errorf("error syncing default guild: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (s *guild) UpdateWowGuild(ctx context.Context, uwp repo.UpdateWowGuildParams) error {
+	settings, err := s.verify(ctx, uwp.Name, uwp.Realm, uwp.Region, uwp.Faction)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.database.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	q := repo.New(s.database).WithTx(tx)
+	n, err := q.UpdateWowGuild(ctx, repo.UpdateWowGuildParams{
+		Name:    settings.name,
+		Realm:   settings.realm,
+		Region:  settings.region,
+		Faction: settings.faction,
+		ID:      uwp.ID,
+		GuildID: uwp.GuildID,
+	})
+	if err != nil {
+		return fromWrite(err, settings)
+	}
+	if n == 0 {
+		return svcerr.New(svcerr.ErrNotFound, settings.name)
+	}
+
+	err = q.SyncDefaultWowGuild(ctx, uwp.GuildID)
+	if err != nil {
+		return fmt.Errorf("error syncing default guild: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (s *guild) RemoveWowGuild(ctx context.Context, guildID snowflake.ID, id int64) error {
+	n, err := repo.New(s.database).DeleteWowGuild(ctx, repo.DeleteWowGuildParams{
+		ID:      id,
+		GuildID: int64(guildID), //nolint:gosec // Snowflake cannot overflow AFAIK
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return svcerr.Invalid(svcerr.Msg("errors.invalid.guild_not_removable"))
+	}
+	return nil
+}
+
+func (s *guild) SetDefaultWowGuild(ctx context.Context, guildID snowflake.ID, id int64) error {
+	gid := int64(guildID) //nolint:gosec // Snowflake cannot overflow AFAIK
+	tx, err := s.database.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	q := repo.New(s.database).WithTx(tx)
+	err = q.ClearDefaultWowGuild(ctx, gid)
+	if err != nil {
+		return fmt.Errorf("error clearing default guild: %w", err)
+	}
+
+	n, err := q.SetDefaultWowGuild(ctx, repo.SetDefaultWowGuildParams{ID: id, GuildID: gid})
+	if err != nil {
+		return fmt.Errorf("error setting default guild: %w", err)
+	}
+	if n == 0 {
+		return svcerr.New(svcerr.ErrNotFound, "")
+	}
+
+	err = q.SyncDefaultWowGuild(ctx, gid)
+	if err != nil {
+		return fmt.Errorf("error syncing default guild: %w", err)
+	}
+	return tx.Commit()
+}
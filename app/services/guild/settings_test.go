@@ -0,0 +1,116 @@
+package guild
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/lvlcn-t/raid-mate/app/database/repo"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+)
+
+func TestNewSettings(t *testing.T) {
+	tests := []struct {
+		name                          string
+		guild, realm, region, faction string
+		want                          settings
+		// wantErr are the messages of the error. Empty if no error is expected.
+		wantErr []svcerr.Message
+	}{
+		{
+			name:  "normalized",
+			guild: " Raid Mate ", realm: " Aman'Thul ", region: "EU", faction: "Horde",
+			want: settings{name: "Raid Mate", realm: "Aman'Thul", region: "eu", faction: "horde"},
+		},
+		{
+			name:  "realm with repeated spaces",
+			guild: "Raid Mate", realm: "Die   Aldor", region: "eu", faction: "alliance",
+			want: settings{name: "Raid Mate", realm: "Die Aldor", region: "eu", faction: "alliance"},
+		},
+		{
+			name:  "all invalid",
+			guild: "R", realm: "Draenor!", region: "XX", faction: "Scourge",
+			wantErr: []svcerr.Message{
+				svcerr.Msg("errors.invalid.guild_name", MinNameLength, MaxNameLength),
+				svcerr.Msg("errors.invalid.realm", "Draenor!"),
+				svcerr.Msg("errors.invalid.region", "US, EU, KR, TW, CN"),
+				svcerr.Msg("errors.invalid.faction", "alliance, horde"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newSettings(tt.guild, tt.realm, tt.region, tt.faction)
+			if (err != nil) != (len(tt.wantErr) > 0) {
+				t.Fatalf("newSettings() error = %v, want error: %v", err, len(tt.wantErr) > 0)
+			}
+			if err != nil && !errors.Is(err, svcerr.ErrInvalidInput) {
+				t.Errorf("newSettings() error = %v, want an invalid input error", err)
+			}
+			if msgs := svcerr.Messages(err); !reflect.DeepEqual(msgs, tt.wantErr) {
+				t.Errorf("newSettings() messages = %v, want %v", msgs, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("newSettings() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectWowGuild(t *testing.T) {
+	g := repo.Guild{ID: 1, Name: "Raid Mate", ServerName: "Draenor", ServerRealm: "Draenor", ServerRegion: "eu", Faction: "horde", Locale: sql.NullString{String: "de", Valid: true}}
+	linked := []repo.WowGuild{
+		{ID: 10, GuildID: 1, Name: "Raid Mate", Realm: "Draenor", Region: "eu", Faction: "horde", IsDefault: true},
+		{ID: 11, GuildID: 1, Name: "Alt Raid", Realm: "Aman'Thul", Region: "us", Faction: "alliance"},
+	}
+
+	tests := []struct {
+		name    string
+		id      int64
+		want    repo.Guild
+		wantErr bool
+	}{
+		{
+			name: "default guild",
+			id:   10,
+			want: g,
+		},
+		{
+			name: "other linked guild",
+			id:   11,
+			want: repo.Guild{ID: 1, Name: "Alt Raid", ServerName: "Aman'Thul", ServerRealm: "Aman'Thul", ServerRegion: "us", Faction: "alliance", Locale: g.Locale},
+		},
+		{
+			name:    "guild of another server",
+			id:      12,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := selectWowGuild(g, linked, tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("selectWowGuild() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, svcerr.ErrInvalidInput) {
+				t.Errorf("selectWowGuild() error = %v, want an invalid input error", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("selectWowGuild() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWowGuildFromContext(t *testing.T) {
+	if id, ok := WowGuildFromContext(context.Background()); ok {
+		t.Errorf("WowGuildFromContext() = %d, want no guild without a selection", id)
+	}
+	if id, ok := WowGuildFromContext(WithWowGuild(context.Background(), 11)); !ok || id != 11 {
+		t.Errorf("WowGuildFromContext() = %d, %v, want 11, true", id, ok)
+	}
+}
@@ -0,0 +1,199 @@
+package guild
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lvlcn-t/raid-mate/app/database/repo"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+	"github.com/lvlcn-t/raid-mate/app/services/upstream"
+)
+
+var (
+	// VaultRuns are the numbers of Mythic+ runs that unlock the slots of the Great Vault.
+	VaultRuns = []int{1, 4, 8}
+	// VaultBosses are the numbers of raid bosses that unlock the slots of the Great Vault.
+	VaultBosses = []int{2, 4, 6}
+)
+
+// raidDifficulties are the IDs of the raid difficulties of Warcraft Logs, they ascend from the lowest to the highest.
+var raidDifficulties = []int{1, 3, 4, 5}
+
+// Vault is the progress of the raiders of a guild towards the slots of the Great Vault in the current week.
+type Vault struct {
+	// Reset is the weekly reset of the region of the guild the week started with, see [WeeklyReset].
+	Reset time.Time `json:"reset"`
+	// Players are the progress of the raiders, the raiders with the most unlocked slots first.
+	Players []PlayerVault `json:"players"`
+	// Unavailable are the raiders unknown to Raider.IO, so only their raid bosses are counted.
+	Unavailable []string `json:"unavailable"`
+}
+
+// PlayerVault is the progress of a single raider towards the slots of the Great Vault.
+type PlayerVault struct {
+	// Character is the name of the character.
+	Character string `json:"character"`
+	// Class is the class of the character.
+	Class string `json:"class"`
+	// Runs are the key levels of the Mythic+ runs completed since the reset, the highest first.
+	// Raider.IO only knows the ten most recent runs of a character.
+	Runs []int `json:"runs"`
+	// Bosses are the difficulties of the raid bosses killed since the reset, the highest first.
+	// Every boss is counted once with the highest difficulty it has been killed on.
+	Bosses []string `json:"bosses"`
+	// Dungeons are the key levels the unlocked Mythic+ slots are rewarded for, one per slot.
+	Dungeons []int `json:"dungeons"`
+	// Raid are the difficulties the unlocked raid slots are rewarded for, one per slot.
+	Raid []string `json:"raid"`
+}
+
+// Slots returns the number of unlocked slots of the Great Vault.
+func (p *PlayerVault) Slots() int {
+	return len(p.Dungeons) + len(p.Raid)
+}
+
+// newPlayerVault returns the progress of the character with the runs and the boss kills since the reset.
+// The kills map the bosses to the highest difficulty they have been killed on.
+// Each slot is rewarded for the lowest of the runs or bosses needed to unlock it, so the n-th best one.
+func newPlayerVault(character, class string, runs []MythicPlusRun, kills map[int]int, reset time.Time) PlayerVault {
+	p := PlayerVault{Character: character, Class: class, Runs: []int{}, Bosses: []string{}, Dungeons: []int{}, Raid: []string{}}
+	for _, r := range runs {
+		if !r.CompletedAt.Before(reset) {
+			p.Runs = append(p.Runs, r.MythicLevel)
+		}
+	}
+	slices.SortFunc(p.Runs, func(a, b int) int { return cmp.Compare(b, a) })
+
+	bosses := slices.Sorted(maps.Values(kills))
+	slices.Reverse(bosses)
+	for _, difficulty := range bosses {
+		p.Bosses = append(p.Bosses, difficulties[difficulty])
+	}
+
+	for _, n := range VaultRuns {
+		if len(p.Runs) >= n {
+			p.Dungeons = append(p.Dungeons, p.Runs[n-1])
+		}
+	}
+	for _, n := range VaultBosses {
+		if len(p.Bosses) >= n {
+			p.Raid = append(p.Raid, p.Bosses[n-1])
+		}
+	}
+	return p
+}
+
+// filterMembers returns the member with the given name or all members if no name is given.
+// It returns an [svcerr.ErrNotFound] error if no member has the name.
+func filterMembers(members []Member, character string) ([]Member, error) {
+	if character = strings.TrimSpace(character); character == "" {
+		return members, nil
+	}
+	i := slices.IndexFunc(members, func(m Member) bool { return strings.EqualFold(m.Character.Name, character) })
+	if i < 0 {
+		return nil, svcerr.New(svcerr.ErrNotFound, character)
+	}
+	return members[i : i+1], nil
+}
+
+func (s *guild) GetVault(ctx context.Context, guildID snowflake.ID, character string) (*Vault, error) {
+	guild, err := s.Get(ctx, guildID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting guild: %w", err)
+	}
+
+	members, err := s.client.FetchMembers(ctx, guild)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching roster: %w", svcerr.FromUpstream(err, guild.Name))
+	}
+	if strings.TrimSpace(character) == "" {
+		members = raiders(guild, members)
+	}
+	members, err = filterMembers(members, character)
+	if err != nil {
+		return nil, err
+	}
+	return s.vault(ctx, guild, members, time.Now())
+}
+
+// vault returns the progress of the members towards the Great Vault since the last weekly reset before now.
+// The Mythic+ runs are taken from Raider.IO and the raid bosses from the reports the guild uploaded to Warcraft Logs.
+func (s *guild) vault(ctx context.Context, guild repo.Guild, members []Member, now time.Time) (*Vault, error) {
+	reset := WeeklyReset(guild.ServerRegion, now)
+	kills, err := s.raidKills(ctx, guild, reset, now)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles, err := fetchEach(ctx, members, func(ctx context.Context, m Member) (*UserProfile, error) {
+		p, err := s.client.getUserProfile(ctx, &RequestProfile{User: m.Character.Name, Realm: m.Character.Realm, guild: guild})
+		if errors.Is(err, upstream.ErrNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error fetching profile of %q: %w", m.Character.Name, svcerr.FromUpstream(err, m.Character.Name))
+		}
+		return p, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	vault := &Vault{Reset: reset, Players: []PlayerVault{}, Unavailable: []string{}}
+	for i, m := range members {
+		var runs []MythicPlusRun
+		if p := profiles[i]; p != nil {
+			runs = p.MythicPlusRecentRuns
+		} else {
+			vault.Unavailable = append(vault.Unavailable, m.Character.Name)
+		}
+		vault.Players = append(vault.Players, newPlayerVault(m.Character.Name, m.Character.Class, runs, kills[strings.ToLower(m.Character.Name)], reset))
+	}
+
+	slices.SortStableFunc(vault.Players, func(a, b PlayerVault) int {
+		return cmp.Or(cmp.Compare(b.Slots(), a.Slots()), strings.Compare(a.Character, b.Character))
+	})
+	return vault, nil
+}
+
+// raidKills returns the raid bosses the players killed in the reports the guild uploaded between start and end.
+// The bosses of each player, keyed by the lowercase name, are mapped to the highest difficulty they have been killed on.
+func (s *guild) raidKills(ctx context.Context, guild repo.Guild, start, end time.Time) (map[string]map[int]int, error) {
+	reports, err := s.client.fetchReports(ctx, guild, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching reports: %w", svcerr.FromUpstream(err, guild.Name))
+	}
+
+	kills := map[string]map[int]int{}
+	for _, r := range reports {
+		f, err := s.client.fetchFights(ctx, r.Id)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching fights of report %q: %w", r.Id, svcerr.FromUpstream(err, r.Id))
+		}
+
+		for _, fight := range f.Fights {
+			// Dungeon bosses of Mythic+ runs are logged as boss fights as well, but only raid bosses count.
+			if fight.Boss == 0 || !fight.Kill || !slices.Contains(raidDifficulties, fight.Difficulty) {
+				continue
+			}
+			for _, p := range f.Friendlies {
+				if !p.isPlayer() || !p.tookPart(fight.ID) {
+					continue
+				}
+				name := strings.ToLower(p.Name)
+				if kills[name] == nil {
+					kills[name] = map[int]int{}
+				}
+				kills[name][fight.Boss] = max(kills[name][fight.Boss], fight.Difficulty)
+			}
+		}
+	}
+	return kills, nil
+}
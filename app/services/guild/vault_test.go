@@ -0,0 +1,88 @@
+package guild
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewPlayerVault(t *testing.T) {
+	reset := time.Date(2024, 5, 1, 4, 0, 0, 0, time.UTC)
+	run := func(level int, completed time.Time) MythicPlusRun {
+		return MythicPlusRun{MythicLevel: level, CompletedAt: completed}
+	}
+
+	tests := []struct {
+		name  string
+		runs  []MythicPlusRun
+		kills map[int]int
+		want  PlayerVault
+	}{
+		{
+			name: "nothing done",
+			want: PlayerVault{Runs: []int{}, Bosses: []string{}, Dungeons: []int{}, Raid: []string{}},
+		},
+		{
+			name: "runs before the reset do not count",
+			runs: []MythicPlusRun{
+				run(10, reset.Add(time.Hour)),
+				run(15, reset.Add(-time.Minute)),
+				run(12, reset),
+				run(9, reset.Add(48*time.Hour)),
+				run(11, reset.Add(24*time.Hour)),
+			},
+			want: PlayerVault{Runs: []int{12, 11, 10, 9}, Bosses: []string{}, Dungeons: []int{12, 9}, Raid: []string{}},
+		},
+		{
+			name: "all slots",
+			runs: []MythicPlusRun{
+				run(10, reset), run(10, reset), run(9, reset), run(8, reset), run(8, reset),
+				run(7, reset), run(7, reset), run(6, reset), run(5, reset),
+			},
+			kills: map[int]int{2902: 5, 2917: 4, 2898: 4, 2918: 3, 2919: 3, 2920: 1, 2921: 1},
+			want: PlayerVault{
+				Runs:     []int{10, 10, 9, 8, 8, 7, 7, 6, 5},
+				Bosses:   []string{"Mythic", "Heroic", "Heroic", "Normal", "Normal", "LFR", "LFR"},
+				Dungeons: []int{10, 8, 6},
+				Raid:     []string{"Heroic", "Normal", "LFR"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.want.Character, tt.want.Class = "Bjorn", "Warrior"
+			got := newPlayerVault("Bjorn", "Warrior", tt.runs, tt.kills, reset)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newPlayerVault() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGuild_vault(t *testing.T) {
+	s := newTestGuild(t)
+	members := fetchTestMembers(t, s, raidMate)
+
+	// The raids of the reports took place on Monday and Tuesday before the reset of the EU on Wednesday.
+	got, err := s.vault(context.Background(), raidMate, members, time.Date(2024, 5, 8, 3, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("vault() error = %v", err)
+	}
+
+	want := &Vault{
+		Reset: time.Date(2024, 5, 1, 4, 0, 0, 0, time.UTC),
+		Players: []PlayerVault{
+			// The Mythic kill of Ulgrax replaces the Heroic one and the run before the reset does not count.
+			{Character: "Bjorn", Class: "Warrior", Runs: []int{12, 10, 9, 8}, Bosses: []string{"Mythic"}, Dungeons: []int{12, 8}, Raid: []string{}},
+			{Character: "Aerith", Class: "Priest", Runs: []int{11}, Bosses: []string{"Heroic"}, Dungeons: []int{11}, Raid: []string{}},
+			// Tifa left before the kill of Ulgrax.
+			{Character: "Tifa", Class: "Monk", Runs: []int{}, Bosses: []string{}, Dungeons: []int{}, Raid: []string{}},
+		},
+		Unavailable: []string{"Tifa"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("vault() = %+v, want %+v", got, want)
+	}
+}
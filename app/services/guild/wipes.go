@@ -0,0 +1,108 @@
+package guild
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	"github.com/lvlcn-t/raid-mate/app/database/repo"
+	"github.com/lvlcn-t/raid-mate/app/services/analysis"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+)
+
+// Wipes are the deaths of the boss pulls of a report.
+type Wipes struct {
+	// Code is the code of the report.
+	Code string `json:"code"`
+	// Title is the title of the report.
+	Title string `json:"title"`
+	// URL is the URL of the report.
+	URL string `json:"url"`
+	// Boss is the name of the boss the pulls are limited to.
+	// It is empty if the pulls of all bosses are included.
+	Boss string `json:"boss,omitempty"`
+	// Wipes are the deaths summarized per pull and the most common causes of the wipes.
+	analysis.Wipes
+}
+
+func (s *guild) GetWipes(ctx context.Context, guildID snowflake.ID, code, boss string) (*Wipes, error) {
+	code, err := reportCode(code)
+	if err != nil {
+		return nil, err
+	}
+	guild, err := s.Get(ctx, guildID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting guild: %w", err)
+	}
+	return s.wipes(ctx, guild, code, boss)
+}
+
+// wipes returns the deaths of the boss pulls of the report with the given code uploaded by the guild.
+// If a boss is given, only its pulls are included.
+func (s *guild) wipes(ctx context.Context, guild repo.Guild, code, boss string) (*Wipes, error) {
+	f, err := s.guildFights(ctx, guild, code)
+	if err != nil {
+		return nil, err
+	}
+
+	boss = strings.TrimSpace(boss)
+	var pulls []analysis.Pull
+	for _, p := range f.Fights {
+		// Trash pulls are logged as fights without a boss.
+		if p.Boss == 0 || (boss != "" && !strings.EqualFold(p.Name, boss)) {
+			continue
+		}
+		// The name of the boss is taken from the report, so it is spelled correctly.
+		if boss != "" {
+			boss = p.Name
+		}
+		pulls = append(pulls, analysis.Pull{
+			ID:         p.ID,
+			Boss:       p.Name,
+			Difficulty: difficulties[p.Difficulty],
+			Kill:       p.Kill,
+			Start:      time.Duration(p.StartTime) * time.Millisecond,
+			End:        time.Duration(p.EndTime) * time.Millisecond,
+		})
+	}
+	if boss != "" && len(pulls) == 0 {
+		return nil, svcerr.New(svcerr.ErrNotFound, boss)
+	}
+
+	res := &Wipes{Code: code, Title: f.Title, URL: s.client.ReportURL(code), Boss: boss}
+	if len(pulls) == 0 {
+		return res, nil
+	}
+
+	start, end := pulls[0].Start.Milliseconds(), pulls[len(pulls)-1].End.Milliseconds()
+	events, err := s.client.fetchDeaths(ctx, code, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching deaths of report %q: %w", code, svcerr.FromUpstream(err, code))
+	}
+
+	players := map[int]string{}
+	for _, p := range f.Friendlies {
+		if p.isPlayer() {
+			players[p.ID] = p.Name
+		}
+	}
+	deaths := make([]analysis.Death, 0, len(events))
+	for _, e := range events {
+		// Pets and NPCs die as well, but only the deaths of players matter for the wipes.
+		name, ok := players[e.TargetID]
+		if !ok {
+			continue
+		}
+		deaths = append(deaths, analysis.Death{
+			Pull:    e.Fight,
+			Time:    time.Duration(e.Timestamp) * time.Millisecond,
+			Player:  name,
+			Ability: e.KillingAbility.Name,
+		})
+	}
+
+	res.Wipes = analysis.Analyze(pulls, deaths)
+	return res, nil
+}
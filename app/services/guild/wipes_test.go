@@ -0,0 +1,97 @@
+package guild
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lvlcn-t/raid-mate/app/services/analysis"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+)
+
+func TestGuild_wipes(t *testing.T) {
+	s := newTestGuild(t)
+
+	ulgrax := []analysis.PullDeaths{
+		{Boss: "Ulgrax the Devourer", Difficulty: "Heroic", Number: 1, Duration: 301 * time.Second, Deaths: 2,
+			First: &analysis.FirstDeath{Player: "Bjorn", Ability: "Digestive Acid", After: 192 * time.Second}},
+		{Boss: "Ulgrax the Devourer", Difficulty: "Heroic", Number: 2, Kill: true, Duration: 289 * time.Second},
+	}
+	bloodbound := []analysis.PullDeaths{
+		{Boss: "The Bloodbound Horror", Difficulty: "Heroic", Number: 1, Duration: 250 * time.Second, Deaths: 2,
+			First: &analysis.FirstDeath{Player: "Aerith", Ability: "Gruesome Disgorge", After: 61 * time.Second}},
+		{Boss: "The Bloodbound Horror", Difficulty: "Heroic", Number: 2, Duration: 312 * time.Second, Deaths: 2,
+			First: &analysis.FirstDeath{Player: "Bjorn", After: 123 * time.Second}},
+	}
+
+	tests := []struct {
+		name    string
+		code    string
+		boss    string
+		want    *Wipes
+		wantErr error
+	}{
+		{
+			name: "all bosses",
+			want: &Wipes{
+				Wipes: analysis.Wipes{
+					Pulls: append(append([]analysis.PullDeaths{}, ulgrax...), bloodbound...),
+					// The death of the pet is ignored.
+					Causes: []analysis.Cause{
+						{Ability: "Gruesome Disgorge", FirstDeaths: 1, Deaths: 3},
+						{Ability: "", FirstDeaths: 1, Deaths: 1},
+						{Ability: "Digestive Acid", FirstDeaths: 1, Deaths: 1},
+						{Ability: "Venomous Lash", Deaths: 1},
+					},
+				},
+			},
+		},
+		{
+			name: "single boss",
+			boss: " the bloodbound horror ",
+			want: &Wipes{
+				Boss: "The Bloodbound Horror",
+				Wipes: analysis.Wipes{
+					Pulls: bloodbound,
+					Causes: []analysis.Cause{
+						{Ability: "Gruesome Disgorge", FirstDeaths: 1, Deaths: 3},
+						{Ability: "", FirstDeaths: 1, Deaths: 1},
+					},
+				},
+			},
+		},
+		{
+			name:    "unknown boss",
+			boss:    "Sikran",
+			wantErr: svcerr.ErrNotFound,
+		},
+		{
+			name:    "report of another guild",
+			code:    "q1w2e3r4t5y6u7i8",
+			wantErr: svcerr.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.wipes(context.Background(), raidMate, cmp.Or(tt.code, "a1B2c3D4e5F6g7H8"), tt.boss)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("wipes() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("wipes() error = %v", err)
+			}
+
+			tt.want.Code, tt.want.Title, tt.want.URL = "a1B2c3D4e5F6g7H8", "Nerub-ar Palace Heroic", s.client.ReportURL("a1B2c3D4e5F6g7H8")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("wipes() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package state
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/lvlcn-t/raid-mate/app/database/repo"
+	"github.com/lvlcn-t/raid-mate/app/services/svcerr"
+)
+
+// Service is the interface for the state service.
+// It stores state of message components that does not fit into their custom IDs.
+// All methods return errors of the [svcerr] package for failures the user can act on.
+type Service interface {
+	// Save stores the payload until the ttl elapsed and returns the key to load it.
+	Save(ctx context.Context, payload string, ttl time.Duration) (string, error)
+	// Load returns the payload stored under the given key.
+	// It returns an [svcerr.ErrNotFound] error if the payload does not exist or has expired.
+	Load(ctx context.Context, key string) (string, error)
+	// Purge deletes all expired payloads and returns how many were deleted.
+	Purge(ctx context.Context) (int64, error)
+}
+
+// state implements [Service] for the state service.
+type state struct {
+	// database is the database repository.
+	database repo.DBTX
+}
+
+// NewService creates a new state service.
+func NewService(db *sql.DB) Service {
+	return &state{database: db}
+}
+
+func (s *state) Save(ctx context.Context, payload string, ttl time.Duration) (string, error) {
+	id, err := repo.New(s.database).CreateComponentState(ctx, repo.CreateComponentStateParams{
+		Payload:   payload,
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(id, 36), nil
+}
+
+func (s *state) Load(ctx context.Context, key string) (string, error) {
+	id, err := strconv.ParseInt(key, 36, 64)
+	if err != nil {
+		return "", svcerr.Wrap(svcerr.ErrNotFound, err, "")
+	}
+
+	payload, err := repo.New(s.database).GetComponentState(ctx, id)
+	if err != nil {
+		return "", svcerr.FromDB(err, svcerr.ErrNotFound, "")
+	}
+	return payload, nil
+}
+
+func (s *state) Purge(ctx context.Context) (int64, error) {
+	return repo.New(s.database).DeleteExpiredComponentStates(ctx)
+}
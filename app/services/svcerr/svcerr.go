@@ -0,0 +1,205 @@
+// Package svcerr provides the domain errors of the services.
+//
+// Every error a service returns to its callers is either one of the kinds below
+// or an unexpected internal error. Callers check the kind with [errors.Is] and
+// translate it into a user facing reply, e.g. a Discord message or an HTTP status.
+//
+// Example:
+//
+//	guild, err := repo.New(db).GetGuild(ctx, id)
+//	if err != nil {
+//		return svcerr.FromDB(err, svcerr.ErrNotConfigured, "")
+//	}
+//
+// Errors describing why the input of the user is invalid carry the keys of their messages
+// in the catalogs of the bot instead of English texts, so callers can translate them:
+//
+//	return svcerr.Invalid(svcerr.Msg("errors.invalid.timezone", timezone))
+package svcerr
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lvlcn-t/raid-mate/app/services/upstream"
+)
+
+var (
+	// ErrNotConfigured is returned when the guild has not been set up for the requested feature.
+	ErrNotConfigured = errors.New("not configured")
+	// ErrNotFound is returned when the requested resource does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrInvalidInput is returned when the request of the user is invalid.
+	ErrInvalidInput = errors.New("invalid input")
+	// ErrForbidden is returned when the user is not allowed to perform the request.
+	ErrForbidden = errors.New("forbidden")
+	// ErrUnavailable is returned when an upstream API is unreachable or failing.
+	ErrUnavailable = errors.New("upstream unavailable")
+	// ErrRateLimited is returned when an upstream API rejected the request due to rate limiting.
+	ErrRateLimited = errors.New("rate limited")
+)
+
+// Error is a domain error of the services.
+type Error struct {
+	// Kind is one of the sentinel errors of this package.
+	Kind error
+	// Detail is an optional detail that is safe to show to users, e.g. the name of the resource that was not found.
+	Detail string
+	// Messages are the optional user facing messages describing the error, see [Message].
+	Messages []Message
+	// RetryAfter is the duration after which the request may succeed if retried.
+	// It is zero if retrying does not help or no hint is known.
+	RetryAfter time.Duration
+	// Err is the underlying error.
+	Err error
+}
+
+// Error returns the error message.
+func (e *Error) Error() string {
+	parts := []string{e.Kind.Error()}
+	if e.Detail != "" {
+		parts = append(parts, e.Detail)
+	}
+	for _, m := range e.Messages {
+		parts = append(parts, m.String())
+	}
+	if e.Err != nil {
+		parts = append(parts, e.Err.Error())
+	}
+	return strings.Join(parts, ": ")
+}
+
+// Unwrap returns the kind and the underlying error.
+func (e *Error) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// Message is a user facing message of an error.
+// It is given by the key of the message in the catalogs of the bot, so callers can translate it.
+type Message struct {
+	// Key is the key of the message.
+	Key string
+	// Args are the arguments of the message, if it is a format string.
+	Args []any
+}
+
+// Msg returns the message with the given key and arguments.
+func Msg(key string, args ...any) Message {
+	return Message{Key: key, Args: args}
+}
+
+// String returns the key and the arguments of the message, e.g. for logs.
+func (m Message) String() string {
+	if len(m.Args) == 0 {
+		return m.Key
+	}
+	return m.Key + fmt.Sprint(m.Args)
+}
+
+// New creates a new error of the given kind.
+func New(kind error, detail string) error {
+	return &Error{Kind: kind, Detail: detail}
+}
+
+// Wrap wraps the error with the given kind.
+// It returns nil if err is nil.
+func Wrap(kind, err error, detail string) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: kind, Detail: detail, Err: err}
+}
+
+// Invalid creates a new [ErrInvalidInput] error with the messages describing why the input is invalid.
+func Invalid(msgs ...Message) error {
+	return &Error{Kind: ErrInvalidInput, Messages: msgs}
+}
+
+// WrapInvalid wraps the error with an [ErrInvalidInput] error with the messages describing why the input is invalid.
+// It returns nil if err is nil.
+func WrapInvalid(err error, msgs ...Message) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: ErrInvalidInput, Messages: msgs, Err: err}
+}
+
+// FromDB converts an error of the database into a domain error.
+// A missing row is reported as the given kind, any other error is returned as is.
+func FromDB(err, kind error, detail string) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return Wrap(kind, err, detail)
+	}
+	return err
+}
+
+// FromUpstream converts an error of an upstream API into a domain error.
+// Errors that were not caused by an upstream API and canceled requests are returned as is.
+func FromUpstream(err error, detail string) error {
+	var e *Error
+	if err == nil || errors.As(err, &e) || errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, upstream.ErrNotFound):
+		return Wrap(ErrNotFound, err, detail)
+	case errors.Is(err, upstream.ErrRateLimited):
+		return &Error{Kind: ErrRateLimited, Detail: detail, RetryAfter: upstream.RetryAfter(err), Err: err}
+	case errors.Is(err, upstream.ErrUpstreamDown), errors.Is(err, context.DeadlineExceeded):
+		return &Error{Kind: ErrUnavailable, Detail: detail, RetryAfter: upstream.RetryAfter(err), Err: err}
+	}
+
+	// Any other unexpected status means we cannot use the upstream right now,
+	// e.g. because our token was revoked. There is nothing the user can do about it.
+	var se *upstream.StatusError
+	if errors.As(err, &se) {
+		return Wrap(ErrUnavailable, err, detail)
+	}
+	return err
+}
+
+// Kind returns the kind of the error or nil if it is an unexpected internal error.
+func Kind(err error) error {
+	for _, kind := range []error{ErrNotConfigured, ErrNotFound, ErrInvalidInput, ErrForbidden, ErrUnavailable, ErrRateLimited} {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+	return nil
+}
+
+// RetryAfter returns the duration after which the failed request may succeed if retried.
+// It returns zero if the error does not carry a hint.
+func RetryAfter(err error) time.Duration {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.RetryAfter
+	}
+	return 0
+}
+
+// Detail returns the user facing detail of the error or an empty string if it has none.
+func Detail(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Detail
+	}
+	return ""
+}
+
+// Messages returns the user facing messages of the error or nil if it has none.
+func Messages(err error) []Message {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Messages
+	}
+	return nil
+}
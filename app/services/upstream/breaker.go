@@ -0,0 +1,94 @@
+package upstream
+
+import (
+	"sync"
+	"time"
+)
+
+// breakerState is the state of a circuit breaker.
+type breakerState int
+
+const (
+	// stateClosed lets all requests pass.
+	stateClosed breakerState = iota
+	// stateOpen rejects all requests until the cooldown has passed.
+	stateOpen
+	// stateHalfOpen lets a single probe request pass.
+	stateHalfOpen
+)
+
+// breaker is a circuit breaker for a single host.
+// It opens after a number of consecutive failures and lets a single probe
+// request through once the cooldown has passed.
+type breaker struct {
+	// mu guards the fields below.
+	mu sync.Mutex
+	// threshold is the number of consecutive failures that open the breaker.
+	threshold int
+	// cooldown is the duration the breaker stays open.
+	cooldown time.Duration
+	// state is the current state of the breaker.
+	state breakerState
+	// failures is the number of consecutive failures.
+	failures int
+	// openedAt is the time the breaker was opened.
+	openedAt time.Time
+	// probedAt is the time the last probe request was let through.
+	probedAt time.Time
+}
+
+// newBreaker creates a new closed circuit breaker.
+func newBreaker(threshold int, cooldown time.Duration) *breaker {
+	return &breaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		state:     stateClosed,
+	}
+}
+
+// allow reports whether a request may be sent.
+// If not, it returns the duration until the breaker allows requests again.
+func (b *breaker) allow(now time.Time) (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case stateOpen:
+		if wait := b.openedAt.Add(b.cooldown).Sub(now); wait > 0 {
+			return false, wait
+		}
+		b.state = stateHalfOpen
+		b.probedAt = now
+		return true, 0
+	case stateHalfOpen:
+		// Only the probe request is allowed until it has reported back.
+		// A probe that never reported back (e.g. a canceled request) is replaced after the cooldown.
+		if wait := b.probedAt.Add(b.cooldown).Sub(now); wait > 0 {
+			return false, wait
+		}
+		b.probedAt = now
+		return true, 0
+	default:
+		return true, 0
+	}
+}
+
+// success records a successful request and closes the breaker.
+func (b *breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state = stateClosed
+	b.failures = 0
+}
+
+// failure records a failed request and opens the breaker if the threshold is reached.
+func (b *breaker) failure(now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == stateHalfOpen || (b.threshold > 0 && b.failures >= b.threshold) {
+		b.state = stateOpen
+		b.openedAt = now
+	}
+}
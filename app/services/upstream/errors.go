@@ -0,0 +1,109 @@
+package upstream
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when the upstream does not know the requested resource.
+	ErrNotFound = errors.New("upstream resource not found")
+	// ErrRateLimited is returned when the upstream rejected the request due to rate limiting.
+	ErrRateLimited = errors.New("upstream rate limit exceeded")
+	// ErrUpstreamDown is returned when the upstream is unreachable, failing or the circuit breaker is open.
+	ErrUpstreamDown = errors.New("upstream unavailable")
+)
+
+// StatusError is the error for an unexpected upstream response status.
+type StatusError struct {
+	// Host is the host of the upstream.
+	Host string
+	// StatusCode is the status code of the response.
+	StatusCode int
+	// RetryAfter is the duration the upstream asked to wait before retrying.
+	// It is zero if the upstream did not send a hint.
+	RetryAfter time.Duration
+}
+
+// Error returns the error message.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code from %s: %d", e.Host, e.StatusCode)
+}
+
+// Is checks if the target is a [StatusError] or the sentinel error matching the status code.
+func (e *StatusError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrUpstreamDown:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+	_, ok := target.(*StatusError)
+	return ok
+}
+
+// BreakerError is the error returned when the circuit breaker of a host is open.
+type BreakerError struct {
+	// Host is the host of the upstream.
+	Host string
+	// RetryAfter is the duration until the breaker allows requests again.
+	RetryAfter time.Duration
+}
+
+// Error returns the error message.
+func (e *BreakerError) Error() string {
+	return fmt.Sprintf("circuit breaker for %s is open, retry in %s", e.Host, e.RetryAfter.Round(time.Second))
+}
+
+// Is checks if the target is a [BreakerError] or [ErrUpstreamDown].
+func (e *BreakerError) Is(target error) bool {
+	if target == ErrUpstreamDown {
+		return true
+	}
+	_, ok := target.(*BreakerError)
+	return ok
+}
+
+// RateLimitError is the error returned when an upstream asked us to wait longer than we are willing to.
+type RateLimitError struct {
+	// Host is the host of the upstream.
+	Host string
+	// RetryAfter is the duration the upstream asked to wait before sending the next request.
+	RetryAfter time.Duration
+}
+
+// Error returns the error message.
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limit of %s exceeded, retry in %s", e.Host, e.RetryAfter.Round(time.Second))
+}
+
+// Is checks if the target is a [RateLimitError] or [ErrRateLimited].
+func (e *RateLimitError) Is(target error) bool {
+	if target == ErrRateLimited {
+		return true
+	}
+	_, ok := target.(*RateLimitError)
+	return ok
+}
+
+// RetryAfter returns the duration the caller should wait before retrying the failed request.
+// It returns zero if the error does not carry a hint.
+func RetryAfter(err error) time.Duration {
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.RetryAfter
+	}
+	var be *BreakerError
+	if errors.As(err, &be) {
+		return be.RetryAfter
+	}
+	var re *RateLimitError
+	if errors.As(err, &re) {
+		return re.RetryAfter
+	}
+	return 0
+}
@@ -0,0 +1,89 @@
+package upstream
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// bucket is a token bucket limiting the request rate to a single host.
+type bucket struct {
+	// mu guards the fields below.
+	mu sync.Mutex
+	// rate is the number of tokens refilled per second.
+	rate float64
+	// burst is the maximum number of tokens.
+	burst float64
+	// tokens is the number of tokens currently available.
+	tokens float64
+	// last is the time the tokens were last refilled.
+	last time.Time
+	// pausedUntil is the time until which the upstream asked us to stop sending requests.
+	pausedUntil time.Time
+}
+
+// newBucket creates a new full token bucket.
+func newBucket(rate float64, burst int) *bucket {
+	return &bucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// wait blocks until a token is available or the context is done.
+// If the upstream asked us to pause for longer than maxWait, it returns the remaining pause without waiting.
+func (b *bucket) wait(ctx context.Context, maxWait time.Duration) (time.Duration, error) {
+	for {
+		delay, paused := b.reserve(time.Now())
+		if delay <= 0 {
+			return 0, nil
+		}
+		if paused && delay > maxWait {
+			return delay, nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return 0, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve takes a token if one is available and returns zero.
+// Otherwise it returns the duration until the next token becomes available
+// and whether that is because the upstream asked us to pause.
+func (b *bucket) reserve(now time.Time) (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if now.Before(b.pausedUntil) {
+		return b.pausedUntil.Sub(now), true
+	}
+
+	if b.rate <= 0 {
+		return 0, false
+	}
+
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, false
+	}
+
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second)), false
+}
+
+// pause stops handing out tokens until the given time.
+func (b *bucket) pause(until time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+}
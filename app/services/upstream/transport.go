@@ -0,0 +1,255 @@
+package upstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// unixTimestampThreshold is the value above which a rate limit reset header is treated
+// as a unix timestamp instead of a number of seconds.
+const unixTimestampThreshold = 1_000_000_000
+
+// transport is a [http.RoundTripper] that applies a per-host token bucket and circuit breaker
+// and retries idempotent requests with jittered exponential backoff.
+type transport struct {
+	// next is the underlying round tripper.
+	next http.RoundTripper
+	// cfg is the configuration of the transport.
+	cfg Config
+	// mu guards the hosts map.
+	mu sync.Mutex
+	// hosts holds the limiter and breaker per host.
+	hosts map[string]*hostState
+}
+
+// hostState is the resilience state for a single upstream host.
+type hostState struct {
+	// bucket is the token bucket of the host.
+	bucket *bucket
+	// breaker is the circuit breaker of the host.
+	breaker *breaker
+}
+
+// newTransport creates a new resilient transport.
+func newTransport(cfg Config, next http.RoundTripper) *transport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{
+		next:  next,
+		cfg:   cfg,
+		hosts: map[string]*hostState{},
+	}
+}
+
+// RoundTrip executes a single HTTP transaction including all retries.
+// It fails with a [*RateLimitError] instead of waiting if the upstream asks us to wait longer than the configured maximum.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	host := t.host(req.URL.Host)
+	retryable := isIdempotent(req)
+
+	for attempt := 0; ; attempt++ {
+		if ok, wait := host.breaker.allow(time.Now()); !ok {
+			return nil, &BreakerError{Host: req.URL.Host, RetryAfter: wait}
+		}
+
+		pause, err := host.bucket.wait(ctx, t.cfg.MaxWait)
+		if err != nil {
+			return nil, err
+		}
+		if pause > 0 {
+			return nil, &RateLimitError{Host: req.URL.Host, RetryAfter: pause}
+		}
+
+		r, err := rewind(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := t.next.RoundTrip(r)
+		t.record(host, resp, err)
+		if resp != nil {
+			if until, ok := rateLimitReset(resp.Header, time.Now()); ok {
+				host.bucket.pause(until)
+			}
+		}
+
+		if !retryable || attempt >= t.cfg.MaxRetries || !shouldRetry(resp, err) {
+			return resp, err
+		}
+
+		delay := t.backoff(attempt)
+		if resp != nil {
+			if hint := retryAfter(resp.Header, time.Now()); hint > 0 {
+				if hint > t.cfg.MaxWait {
+					drain(resp)
+					return nil, &RateLimitError{Host: req.URL.Host, RetryAfter: hint}
+				}
+				delay = max(delay, hint)
+			}
+			drain(resp)
+		}
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// host returns the resilience state for the given host.
+func (t *transport) host(name string) *hostState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h, ok := t.hosts[name]
+	if !ok {
+		h = &hostState{
+			bucket:  newBucket(t.cfg.RateLimit, t.cfg.Burst),
+			breaker: newBreaker(t.cfg.BreakerThreshold, t.cfg.BreakerCooldown),
+		}
+		t.hosts[name] = h
+	}
+	return h
+}
+
+// record reports the outcome of a request to the circuit breaker of the host.
+// Canceled requests and client errors do not count as failures of the upstream.
+func (t *transport) record(host *hostState, resp *http.Response, err error) {
+	switch {
+	case err != nil:
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return
+		}
+		host.breaker.failure(time.Now())
+	case resp.StatusCode >= http.StatusInternalServerError:
+		host.breaker.failure(time.Now())
+	default:
+		host.breaker.success()
+	}
+}
+
+// backoff returns the jittered exponential backoff for the given attempt.
+func (t *transport) backoff(attempt int) time.Duration {
+	ceiling := t.cfg.MaxBackoff
+	if exp := t.cfg.MinBackoff << attempt; exp > 0 && exp < ceiling {
+		ceiling = exp
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	// Full jitter: https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/
+	return rand.N(ceiling) //nolint:gosec // No need for a cryptographically secure random number here.
+}
+
+// isIdempotent reports whether the request may safely be sent more than once.
+func isIdempotent(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return req.Header.Get("Idempotency-Key") != ""
+	}
+}
+
+// shouldRetry reports whether the outcome of a request is worth retrying.
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// rewind returns the request to send for the given attempt with a fresh body.
+func rewind(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 0 || req.GetBody == nil {
+		return req, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("error rewinding request body: %w", err)
+	}
+	r := req.Clone(req.Context())
+	r.Body = body
+	return r, nil
+}
+
+// retryAfter returns the duration the upstream asked us to wait.
+// It understands the Retry-After header and the common rate limit reset headers.
+func retryAfter(header http.Header, now time.Time) time.Duration {
+	if v := header.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil {
+			return time.Duration(secs) * time.Second
+		}
+		if at, err := http.ParseTime(v); err == nil {
+			return at.Sub(now)
+		}
+	}
+
+	if until, ok := rateLimitReset(header, now); ok {
+		return until.Sub(now)
+	}
+	return 0
+}
+
+// rateLimitReset returns the time the rate limit window resets if the upstream
+// signaled that no requests are remaining in the current window.
+func rateLimitReset(header http.Header, now time.Time) (time.Time, bool) {
+	for _, prefix := range []string{"X-RateLimit-", "RateLimit-"} {
+		remaining, err := strconv.Atoi(header.Get(prefix + "Remaining"))
+		if err != nil || remaining > 0 {
+			continue
+		}
+
+		reset, err := strconv.ParseInt(header.Get(prefix+"Reset"), 10, 64)
+		if err != nil {
+			continue
+		}
+		if reset > unixTimestampThreshold {
+			return time.Unix(reset, 0), true
+		}
+		return now.Add(time.Duration(reset) * time.Second), true
+	}
+	return time.Time{}, false
+}
+
+// drain discards and closes the body of the response so the connection can be reused.
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+}
+
+// sleep waits for the given duration or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,172 @@
+// Package upstream provides a resilient HTTP client shared by all services that talk to external APIs.
+//
+// The client applies a token bucket and a circuit breaker per upstream host,
+// retries idempotent requests with jittered exponential backoff while honoring
+// Retry-After and rate limit headers, and turns unexpected responses into typed errors.
+package upstream
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	// defaultTimeout is the default time to wait for the response headers of a single request attempt.
+	defaultTimeout = 10 * time.Second
+	// defaultMaxRetries is the default number of retries for idempotent requests.
+	defaultMaxRetries = 3
+	// defaultMinBackoff is the default base duration of the exponential backoff.
+	defaultMinBackoff = 250 * time.Millisecond
+	// defaultMaxBackoff is the default upper bound of the exponential backoff.
+	defaultMaxBackoff = 5 * time.Second
+	// defaultMaxWait is the default maximum duration we wait if an upstream asks us to.
+	defaultMaxWait = 10 * time.Second
+	// defaultRateLimit is the default number of requests per second per host.
+	defaultRateLimit = 5
+	// defaultBurst is the default burst of requests per host.
+	defaultBurst = 10
+	// defaultBreakerThreshold is the default number of consecutive failures that open the circuit breaker.
+	defaultBreakerThreshold = 5
+	// defaultBreakerCooldown is the default duration the circuit breaker stays open.
+	defaultBreakerCooldown = 30 * time.Second
+)
+
+// Config is the configuration for the upstream client.
+// Zero values are replaced with sensible defaults.
+type Config struct {
+	// Timeout is the time to wait for the response headers of a single request attempt.
+	// The overall duration of a request including retries is bound by the request's context.
+	Timeout time.Duration `yaml:"timeout" mapstructure:"timeout" validate:"gte=0"`
+	// MaxRetries is the maximum number of retries for idempotent requests.
+	// Zero means the default, a negative value disables retries.
+	MaxRetries int `yaml:"maxRetries" mapstructure:"maxRetries" validate:"gte=-1"`
+	// MinBackoff is the base duration of the exponential backoff.
+	MinBackoff time.Duration `yaml:"minBackoff" mapstructure:"minBackoff" validate:"gte=0"`
+	// MaxBackoff is the upper bound of the exponential backoff.
+	MaxBackoff time.Duration `yaml:"maxBackoff" mapstructure:"maxBackoff" validate:"gte=0"`
+	// MaxWait is the maximum duration to wait if an upstream asks us to via Retry-After or rate limit headers.
+	// If an upstream asks for a longer wait, the request fails with [ErrRateLimited].
+	MaxWait time.Duration `yaml:"maxWait" mapstructure:"maxWait" validate:"gte=0"`
+	// RateLimit is the number of requests per second allowed per host.
+	RateLimit float64 `yaml:"rateLimit" mapstructure:"rateLimit" validate:"gte=0"`
+	// Burst is the number of requests per host that may exceed the rate limit at once.
+	Burst int `yaml:"burst" mapstructure:"burst" validate:"gte=0"`
+	// BreakerThreshold is the number of consecutive failures after which requests to a host are rejected.
+	BreakerThreshold int `yaml:"breakerThreshold" mapstructure:"breakerThreshold" validate:"gte=0"`
+	// BreakerCooldown is the duration requests to a failing host are rejected before probing it again.
+	BreakerCooldown time.Duration `yaml:"breakerCooldown" mapstructure:"breakerCooldown" validate:"gte=0"`
+}
+
+// withDefaults returns a copy of the configuration with zero values replaced by defaults.
+func (c Config) withDefaults() Config {
+	if c.Timeout == 0 {
+		c.Timeout = defaultTimeout
+	}
+	switch {
+	case c.MaxRetries < 0:
+		c.MaxRetries = 0
+	case c.MaxRetries == 0:
+		c.MaxRetries = defaultMaxRetries
+	}
+	if c.MinBackoff == 0 {
+		c.MinBackoff = defaultMinBackoff
+	}
+	if c.MaxBackoff == 0 {
+		c.MaxBackoff = defaultMaxBackoff
+	}
+	if c.MaxWait == 0 {
+		c.MaxWait = defaultMaxWait
+	}
+	if c.RateLimit == 0 {
+		c.RateLimit = defaultRateLimit
+	}
+	if c.Burst == 0 {
+		c.Burst = defaultBurst
+	}
+	if c.BreakerThreshold == 0 {
+		c.BreakerThreshold = defaultBreakerThreshold
+	}
+	if c.BreakerCooldown == 0 {
+		c.BreakerCooldown = defaultBreakerCooldown
+	}
+	return c
+}
+
+// Client is a resilient HTTP client for upstream APIs.
+// It is safe for concurrent use and should be shared between services,
+// so the rate limits and circuit breakers apply across all callers of a host.
+type Client struct {
+	// http is the underlying HTTP client using the resilient transport.
+	http *http.Client
+}
+
+// New creates a new upstream client.
+func New(c *Config) *Client {
+	cfg := c.withDefaults()
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	base.ResponseHeaderTimeout = cfg.Timeout
+	return &Client{
+		http: &http.Client{Transport: newTransport(cfg, base)},
+	}
+}
+
+// HTTPClient returns a plain [http.Client] using the resilient transport.
+// Use it for third party SDKs that do their own response handling.
+func (c *Client) HTTPClient() *http.Client {
+	return c.http
+}
+
+// Do sends the request and returns the response if the upstream answered with a 2xx status.
+// Any other status is returned as a [*StatusError] and the response body is closed.
+// Transport failures are wrapped with [ErrUpstreamDown] unless the request's context was done.
+// If the upstream asked us to wait longer than the configured maximum, a [*RateLimitError] is returned.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	resp, err := c.http.Do(req)
+	if err != nil {
+		if ctxErr := req.Context().Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		if errors.Is(err, ErrUpstreamDown) || errors.Is(err, ErrRateLimited) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %w", ErrUpstreamDown, err)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		drain(resp)
+		return nil, &StatusError{
+			Host:       req.URL.Host,
+			StatusCode: resp.StatusCode,
+			RetryAfter: retryAfter(resp.Header, time.Now()),
+		}
+	}
+
+	return resp, nil
+}
+
+// DoJSON sends the request and decodes the JSON response body into v.
+func (c *Client) DoJSON(req *http.Request, v any) (err error) {
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, resp.Body.Close())
+	}()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response: %w", err)
+	}
+
+	err = json.Unmarshal(b, v)
+	if err != nil {
+		return fmt.Errorf("error decoding response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,400 @@
+package upstream
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testConfig is the configuration of the clients under test with backoffs short enough to not slow down the tests.
+var testConfig = Config{
+	MinBackoff: time.Millisecond,
+	MaxBackoff: 5 * time.Millisecond,
+	MaxWait:    2 * time.Second,
+	RateLimit:  1000,
+}
+
+// respond writes the response with the given status and headers for the n-th request, starting at zero.
+type respond func(w http.ResponseWriter, n int)
+
+// status responds with the status codes in the given order and repeats the last one for further requests.
+func status(codes ...int) respond {
+	return func(w http.ResponseWriter, n int) {
+		w.WriteHeader(codes[min(n, len(codes)-1)])
+	}
+}
+
+// newServer starts a test server that responds with respond and counts the requests.
+func newServer(t *testing.T, respond respond) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	hits := &atomic.Int32{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		respond(w, int(hits.Add(1)-1))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, hits
+}
+
+func TestClient_Do(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     func(c *Config)
+		respond respond
+		method  string
+		header  http.Header
+		// wantErr is the error the request must fail with. Nil if the request must succeed.
+		wantErr    error
+		wantHits   int32
+		wantRetry  time.Duration
+		wantMinDur time.Duration
+	}{
+		{
+			name:     "success",
+			respond:  status(http.StatusOK),
+			wantHits: 1,
+		},
+		{
+			name:     "5xx is retried",
+			respond:  status(http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK),
+			wantHits: 3,
+		},
+		{
+			name:     "5xx after all retries",
+			respond:  status(http.StatusInternalServerError),
+			wantErr:  ErrUpstreamDown,
+			wantHits: 4,
+		},
+		{
+			name:     "retries disabled",
+			cfg:      func(c *Config) { c.MaxRetries = -1 },
+			respond:  status(http.StatusInternalServerError),
+			wantErr:  ErrUpstreamDown,
+			wantHits: 1,
+		},
+		{
+			name:     "not found is not retried",
+			respond:  status(http.StatusNotFound),
+			wantErr:  ErrNotFound,
+			wantHits: 1,
+		},
+		{
+			name:     "post is not retried",
+			method:   http.MethodPost,
+			respond:  status(http.StatusServiceUnavailable, http.StatusOK),
+			wantErr:  ErrUpstreamDown,
+			wantHits: 1,
+		},
+		{
+			name:     "post with idempotency key is retried",
+			method:   http.MethodPost,
+			header:   http.Header{"Idempotency-Key": {"1"}},
+			respond:  status(http.StatusServiceUnavailable, http.StatusOK),
+			wantHits: 2,
+		},
+		{
+			name: "429 waits for Retry-After",
+			respond: func(w http.ResponseWriter, n int) {
+				if n == 0 {
+					w.Header().Set("Retry-After", "1")
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			},
+			wantHits:   2,
+			wantMinDur: time.Second,
+		},
+		{
+			name: "429 with Retry-After longer than MaxWait",
+			respond: func(w http.ResponseWriter, _ int) {
+				w.Header().Set("Retry-After", "60")
+				w.WriteHeader(http.StatusTooManyRequests)
+			},
+			wantErr:   ErrRateLimited,
+			wantHits:  1,
+			wantRetry: time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, hits := newServer(t, tt.respond)
+			cfg := testConfig
+			if tt.cfg != nil {
+				tt.cfg(&cfg)
+			}
+			c := New(&cfg)
+
+			req, err := http.NewRequestWithContext(context.Background(), cmp.Or(tt.method, http.MethodGet), srv.URL, http.NoBody)
+			if err != nil {
+				t.Fatalf("NewRequest() error = %v", err)
+			}
+			for k, v := range tt.header {
+				req.Header[k] = v
+			}
+
+			start := time.Now()
+			resp, err := c.Do(req)
+			if resp != nil {
+				drain(resp)
+			}
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Do() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := hits.Load(); got != tt.wantHits {
+				t.Errorf("Do() sent %d requests, want %d", got, tt.wantHits)
+			}
+			if got := RetryAfter(err); got != tt.wantRetry {
+				t.Errorf("RetryAfter() = %v, want %v", got, tt.wantRetry)
+			}
+			if d := time.Since(start); d < tt.wantMinDur {
+				t.Errorf("Do() took %v, want at least %v", d, tt.wantMinDur)
+			}
+		})
+	}
+}
+
+func TestClient_Do_pause(t *testing.T) {
+	srv, hits := newServer(t, func(w http.ResponseWriter, _ int) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "60")
+		w.WriteHeader(http.StatusOK)
+	})
+	c := New(&testConfig)
+
+	get := func() error {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, http.NoBody)
+		if err != nil {
+			t.Fatalf("NewRequest() error = %v", err)
+		}
+		resp, err := c.Do(req)
+		if err == nil {
+			drain(resp)
+		}
+		return err
+	}
+
+	if err := get(); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	// The upstream asked us to pause for longer than MaxWait, so the next request must fail without being sent.
+	start := time.Now()
+	err := get()
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("Do() error = %v, want %v", err, ErrRateLimited)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("Do() blocked for %v, want it to fail immediately", d)
+	}
+	if got := RetryAfter(err); got <= 55*time.Second || got > time.Minute {
+		t.Errorf("RetryAfter() = %v, want about a minute", got)
+	}
+	if got := hits.Load(); got != 1 {
+		t.Errorf("Do() sent %d requests, want 1", got)
+	}
+}
+
+func TestClient_Do_breaker(t *testing.T) {
+	var healthy atomic.Bool
+	srv, hits := newServer(t, func(w http.ResponseWriter, _ int) {
+		if healthy.Load() {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	cfg := testConfig
+	cfg.MaxRetries, cfg.BreakerThreshold, cfg.BreakerCooldown = -1, 2, 50*time.Millisecond
+	c := New(&cfg)
+
+	get := func() error {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL, http.NoBody)
+		if err != nil {
+			t.Fatalf("NewRequest() error = %v", err)
+		}
+		resp, err := c.Do(req)
+		if err == nil {
+			drain(resp)
+		}
+		return err
+	}
+
+	for range cfg.BreakerThreshold {
+		if err := get(); !errors.Is(err, ErrUpstreamDown) {
+			t.Fatalf("Do() error = %v, want %v", err, ErrUpstreamDown)
+		}
+	}
+
+	// The breaker is open now and rejects requests without sending them.
+	var be *BreakerError
+	if err := get(); !errors.As(err, &be) || !errors.Is(err, ErrUpstreamDown) {
+		t.Fatalf("Do() error = %v, want a breaker error", err)
+	}
+	if be.RetryAfter <= 0 || be.RetryAfter > cfg.BreakerCooldown {
+		t.Errorf("BreakerError.RetryAfter = %v, want at most %v", be.RetryAfter, cfg.BreakerCooldown)
+	}
+	if got := hits.Load(); got != 2 {
+		t.Errorf("Do() sent %d requests, want 2", got)
+	}
+
+	// After the cooldown a probe is let through and closes the breaker again.
+	time.Sleep(cfg.BreakerCooldown)
+	healthy.Store(true)
+	for range 2 {
+		if err := get(); err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+	}
+	if got := hits.Load(); got != 4 {
+		t.Errorf("Do() sent %d requests, want 4", got)
+	}
+}
+
+func TestBreaker(t *testing.T) {
+	now := time.Date(2024, 9, 18, 4, 0, 0, 0, time.UTC)
+	b := newBreaker(2, time.Minute)
+
+	b.failure(now)
+	if ok, _ := b.allow(now); !ok {
+		t.Fatal("allow() = false after a single failure, want true")
+	}
+	b.failure(now)
+	if ok, wait := b.allow(now.Add(time.Second)); ok || wait != 59*time.Second {
+		t.Fatalf("allow() = %v, %v, want false, 59s", ok, wait)
+	}
+
+	// Half-open: only a single probe passes until it reports back.
+	if ok, _ := b.allow(now.Add(time.Minute)); !ok || b.state != stateHalfOpen {
+		t.Fatalf("allow() = %v in state %d, want the probe to pass in the half-open state", ok, b.state)
+	}
+	if ok, _ := b.allow(now.Add(time.Minute)); ok {
+		t.Fatal("allow() = true while probing, want false")
+	}
+
+	// A failed probe opens the breaker right away.
+	b.failure(now.Add(time.Minute))
+	if ok, _ := b.allow(now.Add(time.Minute + time.Second)); ok || b.state != stateOpen {
+		t.Fatalf("allow() = %v in state %d, want the breaker to be open after a failed probe", ok, b.state)
+	}
+
+	if ok, _ := b.allow(now.Add(2 * time.Minute)); !ok {
+		t.Fatal("allow() = false after the cooldown, want true")
+	}
+	b.success()
+	if ok, _ := b.allow(now.Add(2 * time.Minute)); !ok || b.state != stateClosed {
+		t.Fatalf("allow() = %v in state %d, want the breaker to be closed after a successful probe", ok, b.state)
+	}
+}
+
+func TestBucket_reserve(t *testing.T) {
+	now := time.Now()
+	b := newBucket(2, 2)
+	b.last = now
+
+	for range 2 {
+		if d, _ := b.reserve(now); d != 0 {
+			t.Fatalf("reserve() = %v within the burst, want 0", d)
+		}
+	}
+	if d, paused := b.reserve(now); d != 500*time.Millisecond || paused {
+		t.Errorf("reserve() = %v, %v with an empty bucket, want 500ms, false", d, paused)
+	}
+	if d, _ := b.reserve(now.Add(500 * time.Millisecond)); d != 0 {
+		t.Errorf("reserve() = %v after the refill, want 0", d)
+	}
+
+	b.pause(now.Add(time.Minute))
+	b.pause(now.Add(time.Second))
+	if d, paused := b.reserve(now.Add(10 * time.Second)); d != 50*time.Second || !paused {
+		t.Errorf("reserve() = %v, %v while paused, want 50s, true", d, paused)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 9, 18, 4, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+	}{
+		{
+			name: "no hint",
+			want: 0,
+		},
+		{
+			name:   "retry after seconds",
+			header: http.Header{"Retry-After": {"30"}},
+			want:   30 * time.Second,
+		},
+		{
+			name:   "retry after date",
+			header: http.Header{"Retry-After": {now.Add(time.Minute).Format(http.TimeFormat)}},
+			want:   time.Minute,
+		},
+		{
+			name:   "rate limit reset seconds",
+			header: http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {"15"}},
+			want:   15 * time.Second,
+		},
+		{
+			name:   "rate limit reset timestamp",
+			header: http.Header{"Ratelimit-Remaining": {"0"}, "Ratelimit-Reset": {"1726632120"}},
+			want:   2 * time.Minute,
+		},
+		{
+			name:   "requests remaining",
+			header: http.Header{"X-Ratelimit-Remaining": {"3"}, "X-Ratelimit-Reset": {"15"}},
+			want:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryAfter(tt.header, now); got != tt.want {
+				t.Errorf("retryAfter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsIdempotent(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		key    string
+		body   bool
+		want   bool
+	}{
+		{name: "get", method: http.MethodGet, want: true},
+		{name: "put", method: http.MethodPut, want: true},
+		{name: "post", method: http.MethodPost, want: false},
+		{name: "post with idempotency key", method: http.MethodPost, key: "1", want: true},
+		{name: "put with a body that cannot be rewound", method: http.MethodPut, body: true, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "http://upstream.test", http.NoBody)
+			if tt.key != "" {
+				req.Header.Set("Idempotency-Key", tt.key)
+			}
+			if tt.body {
+				req.Body, req.GetBody = io.NopCloser(strings.NewReader("{}")), nil
+			}
+			if got := isIdempotent(req); got != tt.want {
+				t.Errorf("isIdempotent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
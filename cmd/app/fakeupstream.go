@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io/fs"
+	"os"
+	"os/signal"
+
+	"github.com/lvlcn-t/loggerhead/logger"
+	"github.com/lvlcn-t/raid-mate/app/fakeupstream"
+	"golang.org/x/sys/unix"
+)
+
+// runFakeUpstream runs the fake upstream server until a signal is received.
+func runFakeUpstream(ctx context.Context, args []string) {
+	log := logger.FromContext(ctx)
+
+	fset := flag.NewFlagSet("fake-upstream", flag.ExitOnError)
+	address := fset.String("address", ":8081", "Address the fake upstream server listens on")
+	fixtures := fset.String("fixtures", "", "Path to a directory with fixtures. Defaults to the bundled fixtures")
+	_ = fset.Parse(args)
+
+	var fsys fs.FS
+	if *fixtures != "" {
+		fsys = os.DirFS(*fixtures)
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, unix.SIGINT, unix.SIGTERM)
+	defer stop()
+
+	err := fakeupstream.New(fsys).Run(ctx, *address)
+	if err != nil {
+		log.FatalContext(ctx, "Failed to run fake upstream server", "error", err)
+	}
+}
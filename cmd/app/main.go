@@ -20,6 +20,11 @@ func main() {
 	ctx, cancel := logger.NewContextWithLogger(logger.IntoContext(context.Background(), log))
 	defer cancel()
 
+	if len(os.Args) > 1 && os.Args[1] == "fake-upstream" {
+		runFakeUpstream(ctx, os.Args[2:])
+		return
+	}
+
 	var cfgPath string
 	flag.StringVar(&cfgPath, "config", "", "Path to the configuration file")
 	flag.Parse()
@@ -4,6 +4,7 @@ go 1.25.0
 
 require (
 	github.com/disgoorg/disgo v0.18.16
+	github.com/disgoorg/json v1.2.0
 	github.com/disgoorg/snowflake/v2 v2.0.3
 	github.com/gofiber/fiber/v3 v3.0.0-rc.1
 	github.com/google/go-github/v68 v68.0.0
@@ -22,7 +23,6 @@ require (
 	github.com/charmbracelet/log v0.4.0 // indirect
 	github.com/charmbracelet/x/ansi v0.7.0 // indirect
 	github.com/coreos/go-oidc/v3 v3.12.0 // indirect
-	github.com/disgoorg/json v1.2.0 // indirect
 	github.com/fsnotify/fsnotify v1.8.0 // indirect
 	github.com/go-jose/go-jose/v4 v4.0.5 // indirect
 	github.com/go-logfmt/logfmt v0.6.0 // indirect